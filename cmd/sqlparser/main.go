@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -13,9 +15,13 @@ import (
 	"github.com/Chahine-tech/sql-parser-go/internal/performance"
 	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
 	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/fix"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lineage"
 	"github.com/Chahine-tech/sql-parser-go/pkg/logger"
 	"github.com/Chahine-tech/sql-parser-go/pkg/monitor"
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/privileges"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
 )
 
 const banner = `
@@ -39,10 +45,27 @@ func main() {
 		verbose       = flag.Bool("verbose", false, "Verbose mode")
 		configFile    = flag.String("config", "", "Configuration file path")
 		dialectFlag   = flag.String("dialect", "", "SQL dialect (mysql, postgresql, sqlserver, sqlite, oracle)")
+		versionFlag   = flag.String("dialect-version", "", "Server version to check syntax against (e.g. 5.7, 2016)")
 		showHelp      = flag.Bool("help", false, "Show help")
 		watchMode     = flag.Bool("watch", false, "Watch log file for real-time monitoring")
 		tailLines     = flag.Int("tail", 10, "Number of lines to tail when starting watch mode")
 		slowThreshold = flag.Float64("slow", 1.0, "Slow query threshold in seconds")
+		schemaFile    = flag.String("schema", "", "Schema file (JSON, YAML or catalog query CSV)")
+		introspect    = flag.Bool("introspect", false, "Print the catalog queries that export a schema for the dialect")
+		exportFormat  = flag.String("export-schema", "", "Export the -schema file (json, yaml, ddl, dot, mermaid)")
+		lineageFormat = flag.String("lineage", "", "Print the column lineage of -sql/-query (json, openlineage)")
+		baselineFile  = flag.String("baseline", "", "Baseline file of accepted violations")
+		writeBaseline = flag.Bool("update-baseline", false, "Record the current violations in the -baseline file")
+		failOn        = flag.String("fail-on", "", "Exit with status 2 when a suggestion has this severity or higher")
+		fixFormat     = flag.String("fix", "", "Apply automatic fixes to -sql/-query and print the result (sql, diff)")
+		fixUnsafe     = flag.Bool("fix-unsafe", false, "Also apply fixes that change what a statement does")
+		adviseIndexes = flag.Bool("advise-indexes", false, "Propose indexes for the queries of -sql, -query or -log")
+		privilegeMode = flag.Bool("privileges", false, "Report the privileges the statements of -sql, -query or -log need")
+		grantee       = flag.String("grantee", "app_role", "Role or user the -privileges GRANT statements name")
+		cacheFile     = flag.String("cache", "", "File that keeps analyses between runs (default: analyzer.cache.file)")
+		dirPath       = flag.String("dir", "", "Directory whose .sql files are analyzed, recursively")
+		workers       = flag.Int("workers", 0, "Files -dir analyzes at once (default: number of CPUs)")
+		jobTimeout    = flag.Duration("job-timeout", 30*time.Second, "Time limit for analyzing each -dir file")
 	)
 	flag.Parse()
 
@@ -66,14 +89,69 @@ func main() {
 	if *dialectFlag != "" {
 		cfg.Parser.Dialect = *dialectFlag
 	}
+	if *versionFlag != "" {
+		cfg.Parser.DialectVersion = *versionFlag
+	}
+	if *baselineFile != "" {
+		cfg.Lint.Baseline = *baselineFile
+	}
+	if *cacheFile != "" {
+		cfg.Analyzer.Cache.File = *cacheFile
+	}
+	if *failOn != "" {
+		cfg.Lint.FailOn = *failOn
+	}
+	if *writeBaseline && cfg.Lint.Baseline == "" {
+		fmt.Println("Error: -update-baseline requires -baseline or lint.baseline")
+		os.Exit(1)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	if *queryFile != "" {
-		if err := analyzeQueryFile(*queryFile, cfg, *verbose); err != nil {
+	if *adviseIndexes {
+		if err := printIndexAdvice(*queryFile, *queryText, *logFile, *schemaFile, cfg, *verbose); err != nil {
+			fmt.Printf("Error advising indexes: %v\n", err)
+			os.Exit(1)
+		}
+	} else if *privilegeMode {
+		if err := printPrivileges(*queryFile, *queryText, *logFile, *schemaFile, *grantee, cfg, *verbose); err != nil {
+			fmt.Printf("Error reporting privileges: %v\n", err)
+			os.Exit(1)
+		}
+	} else if *fixFormat != "" {
+		if err := fixQuery(*queryFile, *queryText, *schemaFile, *fixFormat, *fixUnsafe, cfg); err != nil {
+			fmt.Printf("Error fixing query: %v\n", err)
+			os.Exit(1)
+		}
+	} else if *lineageFormat != "" {
+		if err := printLineage(*queryFile, *queryText, *schemaFile, *lineageFormat, cfg); err != nil {
+			fmt.Printf("Error extracting lineage: %v\n", err)
+			os.Exit(1)
+		}
+	} else if *dirPath != "" {
+		if err := analyzeDirectory(*dirPath, *schemaFile, cfg, *workers, *jobTimeout, *verbose, *writeBaseline); err != nil {
+			if errors.Is(err, errLintFailed) {
+				os.Exit(2)
+			}
+			fmt.Printf("Error analyzing directory: %v\n", err)
+			os.Exit(1)
+		}
+	} else if *queryFile != "" {
+		if err := analyzeQueryFile(*queryFile, *schemaFile, cfg, *verbose, *writeBaseline); err != nil {
+			if errors.Is(err, errLintFailed) {
+				os.Exit(2)
+			}
 			fmt.Printf("Error analyzing query file: %v\n", err)
 			os.Exit(1)
 		}
 	} else if *queryText != "" {
-		if err := analyzeQueryString(*queryText, cfg, *verbose); err != nil {
+		if err := analyzeQueryString(*queryText, "", *schemaFile, cfg, *verbose, *writeBaseline); err != nil {
+			if errors.Is(err, errLintFailed) {
+				os.Exit(2)
+			}
 			fmt.Printf("Error analyzing query: %v\n", err)
 			os.Exit(1)
 		}
@@ -89,6 +167,16 @@ func main() {
 				os.Exit(1)
 			}
 		}
+	} else if *introspect {
+		if err := printIntrospectionQueries(cfg); err != nil {
+			fmt.Printf("Error generating introspection queries: %v\n", err)
+			os.Exit(1)
+		}
+	} else if *exportFormat != "" {
+		if err := exportSchema(*schemaFile, *exportFormat, cfg); err != nil {
+			fmt.Printf("Error exporting schema: %v\n", err)
+			os.Exit(1)
+		}
 	} else {
 		showUsage()
 		os.Exit(1)
@@ -101,37 +189,76 @@ func showUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  sqlparser -query file.sql          Analyze SQL query from file")
 	fmt.Println("  sqlparser -sql \"SELECT * FROM...\"   Analyze SQL query from string")
+	fmt.Println("  sqlparser -dir migrations/          Analyze every .sql file of a directory")
 	fmt.Println("  sqlparser -log logfile.log          Parse SQL Server log file")
 	fmt.Println("  sqlparser -log logfile.log -watch   Watch log file in real-time")
+	fmt.Println("  sqlparser -introspect -dialect D    Print catalog queries for exporting a schema")
+	fmt.Println("  sqlparser -schema F -export-schema FORMAT  Convert a schema file")
 	fmt.Println()
 	fmt.Println("Options:")
 	fmt.Println("  -output FORMAT    Output format: json, table (default: json)")
-	fmt.Println("  -dialect DIALECT  SQL dialect: mysql, postgresql, sqlserver, sqlite, oracle (default: sqlserver)")
+	fmt.Println("  -dialect DIALECT  SQL dialect: mysql, postgresql, sqlserver, sqlite, oracle, auto or a registered one (default: sqlserver)")
+	fmt.Println("  -dialect-version V  Server version, e.g. 5.7 or 2016; newer syntax is rejected (default: latest)")
 	fmt.Println("  -verbose          Enable verbose output")
 	fmt.Println("  -config FILE      Configuration file path")
 	fmt.Println("  -watch            Enable real-time log monitoring (use with -log)")
 	fmt.Println("  -tail N           Number of lines to tail when starting watch (default: 10)")
 	fmt.Println("  -slow SECONDS     Slow query threshold in seconds (default: 1.0)")
+	fmt.Println("  -schema FILE      Schema file: JSON, YAML or saved catalog query result (.csv);")
+	fmt.Println("                    with -sql/-query, migration rules check references to altered columns")
+	fmt.Println("  -introspect       Print the catalog queries whose results load as a schema")
+	fmt.Println("  -export-schema F  Export the -schema file as json, yaml, ddl, dot or mermaid")
+	fmt.Println("  -lineage FORMAT   Print the column lineage of -sql/-query as json or openlineage;")
+	fmt.Println("                    -schema expands * and places unqualified columns")
+	fmt.Println("  -baseline FILE    Ignore the violations recorded in FILE (default: lint.baseline)")
+	fmt.Println("  -update-baseline  Record the current violations of -sql/-query in the baseline")
+	fmt.Println("  -fail-on SEVERITY Exit with status 2 on a suggestion of SEVERITY or higher")
+	fmt.Println("  -fix FORMAT       Apply automatic fixes to -sql/-query and print the sql or a diff;")
+	fmt.Println("                    -schema lets SELECT * expand, lint-disabled rules are not fixed")
+	fmt.Println("  -fix-unsafe       Also apply fixes that change results, such as = NULL to IS NULL")
+	fmt.Println("  -advise-indexes   Propose CREATE INDEX statements for -sql, -query or every query of -log,")
+	fmt.Println("                    ranked by how many queries use them; -schema skips existing indexes")
+	fmt.Println("  -privileges       Report the privileges -sql, -query or -log need, per object and column,")
+	fmt.Println("                    with the GRANT statements of the dialect; -schema places columns")
+	fmt.Println("  -grantee NAME     Role or user the GRANT statements name (default: app_role)")
+	fmt.Println("  -cache FILE       Keep analyses and suggestions in FILE so that later runs skip")
+	fmt.Println("                    analyzing and linting unchanged queries")
+	fmt.Println("  -dir DIR          Analyze, lint and, with -schema, validate every .sql file under DIR;")
+	fmt.Println("                    prints a JSON line or table row per file as it finishes")
+	fmt.Println("  -workers N        Files -dir analyzes at once (default: number of CPUs)")
+	fmt.Println("  -job-timeout D    Time limit per -dir file, e.g. 10s (default: 30s)")
 	fmt.Println("  -help             Show this help")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  sqlparser -query complex_query.sql -output json -dialect mysql")
+	fmt.Println("  sqlparser -query unknown_source.sql -dialect auto -verbose")
+	fmt.Println("  sqlparser -sql \"SELECT RANK() OVER (ORDER BY total) FROM orders\" -dialect mysql -dialect-version 5.7")
 	fmt.Println("  sqlparser -sql \"SELECT u.name, o.total FROM users u JOIN orders o ON u.id = o.user_id\" -dialect postgresql")
 	fmt.Println("  sqlparser -log sqlserver.log -output table -verbose")
 	fmt.Println("  sqlparser -log sqlserver.log -watch -tail 20 -slow 2.0 -dialect mysql")
+	fmt.Println("  sqlparser -introspect -dialect postgresql")
+	fmt.Println("  sqlparser -schema columns.csv -export-schema mermaid")
+	fmt.Println("  sqlparser -query report_view.sql -schema schema.json -lineage openlineage -dialect postgresql")
+	fmt.Println("  sqlparser -query legacy/report.sql -config config.yaml -baseline lint-baseline.json -fail-on WARNING")
+	fmt.Println("  sqlparser -query report.sql -schema schema.json -fix diff -dialect mysql")
+	fmt.Println("  sqlparser -log sqlserver.log -schema schema.json -advise-indexes -output table")
+	fmt.Println("  sqlparser -dir sql/ -schema schema.json -dialect postgresql -fail-on ERROR -cache .sqlens-cache.json")
+	fmt.Println("  sqlparser -query procedures.sql -privileges -grantee reporting -dialect postgresql -output table")
 }
 
-func analyzeQueryFile(filename string, cfg *config.Config, verbose bool) error {
+func analyzeQueryFile(filename, schemaFile string, cfg *config.Config, verbose, updateBaseline bool) error {
 	// Read the file
 	content, err := os.ReadFile(filename)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %v", err)
 	}
 
-	return analyzeQueryString(string(content), cfg, verbose)
+	return analyzeQueryString(string(content), filename, schemaFile, cfg, verbose, updateBaseline)
 }
 
-func analyzeQueryString(sql string, cfg *config.Config, verbose bool) error {
+// analyzeQueryString analyzes sql read from path, which is empty for -sql
+// and selects the lint overrides and baseline entries that apply
+func analyzeQueryString(sql, path, schemaFile string, cfg *config.Config, verbose, updateBaseline bool) error {
 	monitor := performance.NewPerformanceMonitor()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -144,11 +271,24 @@ func analyzeQueryString(sql string, cfg *config.Config, verbose bool) error {
 		fmt.Printf("Dialect: %s\n\n", cfg.Parser.Dialect)
 	}
 
-	// Get the dialect
-	d := dialect.GetDialect(cfg.Parser.Dialect)
+	// Get the dialect, detecting it from the query for -dialect auto
+	var guess *dialect.Guess
+	var d dialect.Dialect
+	var err error
+	if cfg.Parser.Dialect == dialect.Auto {
+		var g dialect.Guess
+		d, g = dialect.DetectDialect(sql)
+		guess = &g
+		if verbose {
+			fmt.Printf("Detected dialect: %s (%.0f%% confidence, %s)\n\n", g.Dialect, g.Confidence*100, strings.Join(g.Evidence, ", "))
+		}
+	} else if d, err = dialect.GetDialectVersion(cfg.Parser.Dialect, cfg.Parser.DialectVersion); err != nil {
+		return err
+	}
 
 	// Create parser with dialect
 	p := parser.NewWithDialect(ctx, sql, d)
+	p.EnableSpans()
 	stmt, err := p.ParseStatement()
 	if err != nil {
 		return fmt.Errorf("failed to parse query: %w", err)
@@ -164,23 +304,63 @@ func analyzeQueryString(sql string, cfg *config.Config, verbose bool) error {
 
 	// Create analyzer with dialect for enhanced optimization suggestions
 	a := analyzer.NewWithDialect(d)
-	analysis := a.Analyze(stmt)
-
-	var suggestions []analyzer.OptimizationSuggestion
-	var enhancedSuggestions []analyzer.EnhancedOptimizationSuggestion
+	a.SetComplexityModel(cfg.Analyzer.ComplexityWeights, cfg.Analyzer.ComplexityThreshold)
+	engine := analyzer.NewOptimizationEngine(d)
+	if err := engine.ConfigureRules(cfg.Lint.RulesFor(path)); err != nil {
+		return err
+	}
 
+	// A schema lets migration rules see foreign keys and indexes on altered columns
+	if schemaFile != "" {
+		s, err := schema.NewSchemaLoader().LoadFromFile(schemaFile)
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		engine.SetSchema(s)
+		a.SetSchema(s)
+	}
+	a.SetOptimizationEngine(engine)
+
+	// A cache file lets repeated runs, e.g. in CI, reuse the analyses and
+	// suggestions of unchanged queries. The statement's own text is
+	// fingerprinted, as -dir does for baselines.
+	fingerprint := analyzer.QueryFingerprint(sql, d)
+	if span, ok := p.Span(stmt); ok {
+		fingerprint = analyzer.QueryFingerprint(sql[span.Start:span.End], d)
+	}
+	cacheKey := ""
+	var cache *analyzer.AnalysisCache
+	if cfg.Analyzer.Cache.File != "" {
+		if cache, err = loadAnalysisCache(cfg); err != nil {
+			return err
+		}
+		a.SetCache(cache)
+		cacheKey = fingerprint
+	}
+	var analysis analyzer.QueryAnalysis
 	if cfg.Analyzer.EnableOptimizations {
-		// Get enhanced optimization suggestions
-		enhancedSuggestions = a.GetEnhancedOptimizations(stmt)
-
-		// For backward compatibility, also get legacy suggestions
-		if selectStmt, ok := stmt.(*parser.SelectStatement); ok {
-			suggestions = a.SuggestOptimizations(selectStmt)
+		analysis = a.OptimizeWithCache(stmt, cacheKey)
+	} else {
+		analysis = a.AnalyzeWithCache(stmt, cacheKey)
+	}
+	if cache != nil {
+		if err := cache.Save(cfg.Analyzer.Cache.File); err != nil {
+			return err
 		}
+	}
+	analysis.Warnings = p.Warnings()
+	analysis.DetectedDialect = guess
 
-		// Add enhanced suggestions to analysis for output
+	// Enhanced suggestions, less suppressed and baselined ones, and the
+	// legacy suggestions for backward compatibility
+	suggestions := analysis.Suggestions
+	enhancedSuggestions := analysis.EnhancedSuggestions
+	if cfg.Analyzer.EnableOptimizations {
+		enhancedSuggestions = analyzer.ParseSuppressions(p.Comments()).Filter(enhancedSuggestions, 1, strings.Count(sql, "\n")+1)
+		if enhancedSuggestions, err = applyBaseline(path, fingerprint, enhancedSuggestions, cfg, updateBaseline); err != nil {
+			return err
+		}
 		analysis.EnhancedSuggestions = enhancedSuggestions
-		analysis.Suggestions = suggestions
 	}
 
 	if verbose {
@@ -195,7 +375,616 @@ func analyzeQueryString(sql string, cfg *config.Config, verbose bool) error {
 		}
 	}
 
-	return outputAnalysis(analysis, suggestions, cfg)
+	if err := outputAnalysis(analysis, suggestions, cfg); err != nil {
+		return err
+	}
+	return checkFailOn(enhancedSuggestions, cfg)
+}
+
+// loadAnalysisCache creates the configured cache with the entries of its
+// file, which doesn't exist on the first run
+func loadAnalysisCache(cfg *config.Config) (*analyzer.AnalysisCache, error) {
+	cache := analyzer.NewAnalysisCache(cfg.Analyzer.Cache.AnalysisCache())
+	if err := cache.Load(cfg.Analyzer.Cache.File); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// fileAnalysis is the -dir output for a file
+type fileAnalysis struct {
+	File       string                   `json:"file"`
+	Dialect    string                   `json:"dialect,omitempty"`
+	Statements []analyzer.QueryAnalysis `json:"statements,omitempty"`
+	Validation []string                 `json:"validation_errors,omitempty"`
+	DurationMs float64                  `json:"duration_ms"`
+	Error      string                   `json:"error,omitempty"`
+}
+
+// analyzeDirectory streams every .sql file under dir through the analysis
+// pipeline and prints each file's result as soon as it and the files
+// before it are done. The lint baseline applies to each statement; with
+// updateBaseline, the files' violations are recorded in it instead.
+func analyzeDirectory(dir, schemaFile string, cfg *config.Config, workers int, timeout time.Duration, verbose, updateBaseline bool) error {
+	if info, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("failed to read directory: %v", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	opts := analyzer.PipelineOptions{
+		Workers:   workers,
+		Timeout:   timeout,
+		Ordered:   true,
+		Weights:   cfg.Analyzer.ComplexityWeights,
+		Threshold: cfg.Analyzer.ComplexityThreshold,
+		Optimize:  cfg.Analyzer.EnableOptimizations,
+		Rules:     cfg.Lint.RulesFor,
+	}
+	if cfg.Parser.Dialect != dialect.Auto {
+		d, err := dialect.GetDialectVersion(cfg.Parser.Dialect, cfg.Parser.DialectVersion)
+		if err != nil {
+			return err
+		}
+		opts.Dialect = d
+	}
+	if schemaFile != "" {
+		s, err := schema.NewSchemaLoader().LoadFromFile(schemaFile)
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+		opts.Schema = s
+	}
+
+	ca := analyzer.NewConcurrentAnalyzer(workers)
+	cache := analyzer.NewAnalysisCache(cfg.Analyzer.Cache.AnalysisCache())
+	if cfg.Analyzer.Cache.File != "" {
+		var err error
+		if cache, err = loadAnalysisCache(cfg); err != nil {
+			return err
+		}
+	}
+	ca.SetCache(cache)
+
+	var baseline *analyzer.Baseline
+	if cfg.Lint.Baseline != "" && opts.Optimize {
+		var err error
+		if baseline, err = loadLintBaseline(cfg, updateBaseline); err != nil {
+			return err
+		}
+	}
+
+	// Files are read as the pipeline takes them, not up front
+	var walkErr error
+	jobs := func(yield func(analyzer.AnalysisJob) bool) {
+		walkErr = filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".sql") {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read file: %v", err)
+			}
+			if !yield(analyzer.AnalysisJob{ID: path, Query: string(content)}) {
+				return filepath.SkipAll
+			}
+			return nil
+		})
+	}
+
+	start := time.Now()
+	files, statements, suggestions, failed, invalid := 0, 0, 0, 0, 0
+	var lintErr error
+	if cfg.Output.Format == "table" {
+		fmt.Printf("%-50s %-12s %10s %11s %7s %9s\n", "FILE", "DIALECT", "STATEMENTS", "SUGGESTIONS", "ERRORS", "TIME")
+	}
+	for result := range ca.AnalyzeSeq(context.Background(), jobs, opts) {
+		if baseline != nil && result.Error == nil {
+			if updateBaseline {
+				baseline.Remove(result.ID)
+			}
+			for i := range result.Analyses {
+				analysis := &result.Analyses[i]
+				if updateBaseline {
+					baseline.Add(result.ID, result.Fingerprints[i], analysis.EnhancedSuggestions)
+					analysis.EnhancedSuggestions = nil
+				} else {
+					analysis.EnhancedSuggestions = baseline.Filter(result.ID, result.Fingerprints[i], analysis.EnhancedSuggestions)
+				}
+			}
+		}
+
+		files++
+		statements += len(result.Analyses)
+		out := fileAnalysis{
+			File:       result.ID,
+			Dialect:    result.Dialect,
+			Statements: result.Analyses,
+			DurationMs: float64(result.Duration.Microseconds()) / 1000,
+		}
+		count := 0
+		for _, analysis := range result.Analyses {
+			count += len(analysis.EnhancedSuggestions)
+			if err := checkFailOn(analysis.EnhancedSuggestions, cfg); err != nil {
+				lintErr = err
+			}
+		}
+		suggestions += count
+		for _, ve := range result.Validation {
+			out.Validation = append(out.Validation, ve.Error())
+		}
+		if len(out.Validation) > 0 {
+			invalid++
+		}
+		if result.Error != nil {
+			out.Error = result.Error.Error()
+			failed++
+		}
+
+		if cfg.Output.Format == "table" {
+			fmt.Printf("%-50s %-12s %10d %11d %7d %8.1fms\n", out.File, out.Dialect, len(out.Statements), count, len(out.Validation), out.DurationMs)
+			if out.Error != "" {
+				fmt.Printf("  error: %s\n", out.Error)
+			}
+			if verbose {
+				for _, msg := range out.Validation {
+					fmt.Printf("  %s\n", msg)
+				}
+			}
+			continue
+		}
+		// One line per file, whatever output.pretty_json says
+		if err := outputJSON(out, false); err != nil {
+			return err
+		}
+	}
+	if walkErr != nil {
+		return fmt.Errorf("failed to walk directory: %v", walkErr)
+	}
+
+	if cfg.Output.Format == "table" || verbose {
+		fmt.Printf("\n%d files, %d statements, %d suggestions, %d files with validation errors, %d failed in %v\n",
+			files, statements, suggestions, invalid, failed, time.Since(start).Round(time.Millisecond))
+		if verbose {
+			printCacheStats(cache.Stats())
+		}
+	}
+	if cfg.Analyzer.Cache.File != "" {
+		if err := cache.Save(cfg.Analyzer.Cache.File); err != nil {
+			return err
+		}
+	}
+	if baseline != nil && updateBaseline {
+		if err := baseline.Save(cfg.Lint.Baseline); err != nil {
+			return err
+		}
+	}
+	if lintErr != nil {
+		return lintErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, files)
+	}
+	return nil
+}
+
+// errLintFailed reports suggestions at or above lint.fail_on
+var errLintFailed = errors.New("lint failed")
+
+// applyBaseline drops the suggestions accepted by the lint baseline for
+// the statement with a QueryFingerprint. With update, it records them in
+// the baseline instead, replacing the entries of the same file.
+func applyBaseline(path, fingerprint string, suggestions []analyzer.EnhancedOptimizationSuggestion, cfg *config.Config, update bool) ([]analyzer.EnhancedOptimizationSuggestion, error) {
+	if cfg.Lint.Baseline == "" {
+		return suggestions, nil
+	}
+
+	baseline, err := loadLintBaseline(cfg, update)
+	if err != nil {
+		return nil, err
+	}
+	if !update {
+		return baseline.Filter(path, fingerprint, suggestions), nil
+	}
+
+	baseline.Remove(path)
+	baseline.Add(path, fingerprint, suggestions)
+	if err := baseline.Save(cfg.Lint.Baseline); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// loadLintBaseline reads the lint baseline. An update starts a new one in
+// place of a missing baseline.
+func loadLintBaseline(cfg *config.Config, update bool) (*analyzer.Baseline, error) {
+	baseline, err := analyzer.LoadBaseline(cfg.Lint.Baseline)
+	if err != nil {
+		if !update || !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		baseline = analyzer.NewBaseline()
+	}
+	return baseline, nil
+}
+
+// checkFailOn returns errLintFailed when a suggestion is at least as
+// severe as lint.fail_on
+func checkFailOn(suggestions []analyzer.EnhancedOptimizationSuggestion, cfg *config.Config) error {
+	if cfg.Lint.FailOn == "" {
+		return nil
+	}
+	threshold := analyzer.SeverityRank(cfg.Lint.FailOn)
+	for _, s := range suggestions {
+		if analyzer.SeverityRank(s.Severity) >= threshold {
+			return errLintFailed
+		}
+	}
+	return nil
+}
+
+func printIntrospectionQueries(cfg *config.Config) error {
+	d, err := dialect.Lookup(cfg.Parser.Dialect)
+	if err != nil {
+		return err
+	}
+
+	queries, err := schema.IntrospectionQueries(d)
+	if err != nil {
+		return err
+	}
+
+	for i, q := range queries {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("-- %s: %s\n", q.Name, q.Description)
+		fmt.Println(q.SQL)
+	}
+
+	return nil
+}
+
+func exportSchema(filename, format string, cfg *config.Config) error {
+	if filename == "" {
+		return fmt.Errorf("-export-schema requires -schema")
+	}
+
+	exportFormat, err := schema.ParseExportFormat(format)
+	if err != nil {
+		return err
+	}
+
+	s, err := schema.NewSchemaLoader().LoadFromFile(filename)
+	if err != nil {
+		return err
+	}
+
+	d, err := dialect.Lookup(cfg.Parser.Dialect)
+	if err != nil {
+		return err
+	}
+
+	exporter := schema.NewExporter(d)
+	return exporter.Export(s, exportFormat, os.Stdout)
+}
+
+func printLineage(queryFile, queryText, schemaFile, format string, cfg *config.Config) error {
+	if format != "json" && format != "openlineage" {
+		return fmt.Errorf("unknown lineage format %q (available: json, openlineage)", format)
+	}
+
+	sql := queryText
+	if queryFile != "" {
+		content, err := os.ReadFile(queryFile)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %v", err)
+		}
+		sql = string(content)
+	}
+	if sql == "" {
+		return fmt.Errorf("-lineage requires -sql or -query")
+	}
+
+	d, name, err := queryDialect(sql, cfg)
+	if err != nil {
+		return err
+	}
+
+	var s *schema.Schema
+	if schemaFile != "" {
+		if s, err = schema.NewSchemaLoader().LoadFromFile(schemaFile); err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+	}
+
+	stmt, err := parser.NewWithDialect(context.Background(), sql, d).ParseStatement()
+	if err != nil {
+		return fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	l, err := lineage.NewExtractor(d, s).Extract(stmt)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if format == "openlineage" {
+		// The dialect name stands in for the data source namespace
+		return encoder.Encode(l.OpenLineage(name))
+	}
+	return encoder.Encode(l)
+}
+
+// queryDialect returns the configured dialect and its name, detecting it
+// from the query for -dialect auto
+func queryDialect(sql string, cfg *config.Config) (dialect.Dialect, string, error) {
+	name := cfg.Parser.Dialect
+	if name == dialect.Auto {
+		d, guess := dialect.DetectDialect(sql)
+		return d, guess.Dialect, nil
+	}
+	d, err := dialect.GetDialectVersion(name, cfg.Parser.DialectVersion)
+	return d, name, err
+}
+
+// fixQuery applies the automatic fixes to a query and prints the fixed SQL
+// or a unified diff. The fixes applied and the unsafe ones left out are
+// listed on stderr.
+func fixQuery(queryFile, queryText, schemaFile, format string, unsafe bool, cfg *config.Config) error {
+	if format != "sql" && format != "diff" {
+		return fmt.Errorf("unknown fix format %q (available: sql, diff)", format)
+	}
+
+	sql, name := queryText, "query.sql"
+	if queryFile != "" {
+		content, err := os.ReadFile(queryFile)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %v", err)
+		}
+		sql, name = string(content), queryFile
+	}
+	if sql == "" {
+		return fmt.Errorf("-fix requires -sql or -query")
+	}
+
+	d, _, err := queryDialect(sql, cfg)
+	if err != nil {
+		return err
+	}
+
+	var s *schema.Schema
+	if schemaFile != "" {
+		if s, err = schema.NewSchemaLoader().LoadFromFile(schemaFile); err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+	}
+
+	fixer := fix.NewFixer(d, s)
+	fixer.AllowUnsafe(unsafe)
+	for id, rc := range cfg.Lint.RulesFor(queryFile) {
+		if rc.Enabled != nil && !*rc.Enabled {
+			fixer.DisableRule(id)
+		}
+	}
+
+	result, err := fixer.Fix(context.Background(), sql)
+	if err != nil {
+		return fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	for _, applied := range result.Applied {
+		fmt.Fprintf(os.Stderr, "fixed %s: %s\n", applied.Rule, applied.Description)
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Fprintf(os.Stderr, "not fixed %s (needs -fix-unsafe): %s\n", skipped.Rule, skipped.Description)
+	}
+
+	if format == "diff" {
+		fmt.Print(fix.Diff(strings.TrimPrefix(filepath.ToSlash(name), "./"), sql, result.SQL))
+		return nil
+	}
+	fmt.Print(result.SQL)
+	if !strings.HasSuffix(result.SQL, "\n") {
+		fmt.Println()
+	}
+	return nil
+}
+
+// loadWorkload reads the statements of a query file or string, or every
+// query of a log file
+func loadWorkload(queryFile, queryText, logFile string) ([]string, error) {
+	switch {
+	case queryFile != "":
+		content, err := os.ReadFile(queryFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file: %v", err)
+		}
+		return []string{string(content)}, nil
+	case queryText != "":
+		return []string{queryText}, nil
+	case logFile != "":
+		file, err := os.Open(logFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %v", err)
+		}
+		defer file.Close()
+		entries, err := logger.NewSQLServerLogParser().ParseLog(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse log: %v", err)
+		}
+		var queries []string
+		for _, entry := range entries {
+			queries = append(queries, entry.Query)
+		}
+		return queries, nil
+	}
+	return nil, nil
+}
+
+// parseWorkload parses each query of a workload in its dialect and passes
+// its statements to add
+func parseWorkload(queries []string, cfg *config.Config, verbose bool, add func(dialect.Dialect, parser.Statement)) error {
+	statements, skipped := 0, 0
+	for _, sql := range queries {
+		d, _, err := queryDialect(sql, cfg)
+		if err != nil {
+			return err
+		}
+		stmts, err := parser.NewWithDialect(context.Background(), sql, d).ParseStatements()
+		if err != nil {
+			// A workload is analyzed even if some of its queries don't parse
+			if len(queries) == 1 {
+				return fmt.Errorf("failed to parse query: %w", err)
+			}
+			skipped++
+			if verbose {
+				fmt.Printf("Skipping query: %v\n", err)
+			}
+			continue
+		}
+		for _, stmt := range stmts {
+			add(d, stmt)
+			statements++
+		}
+	}
+	if verbose {
+		fmt.Printf("Analyzed %d statements, skipped %d queries that did not parse\n", statements, skipped)
+	}
+	return nil
+}
+
+// printIndexAdvice proposes indexes for a workload: the statements of a
+// query file or string, or every query of a log file
+func printIndexAdvice(queryFile, queryText, logFile, schemaFile string, cfg *config.Config, verbose bool) error {
+	queries, err := loadWorkload(queryFile, queryText, logFile)
+	if err != nil {
+		return err
+	}
+	if queries == nil {
+		return fmt.Errorf("-advise-indexes requires -sql, -query or -log")
+	}
+
+	var s *schema.Schema
+	if schemaFile != "" {
+		if s, err = schema.NewSchemaLoader().LoadFromFile(schemaFile); err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+	}
+
+	var advisor *analyzer.IndexAdvisor
+	err = parseWorkload(queries, cfg, verbose, func(d dialect.Dialect, stmt parser.Statement) {
+		if advisor == nil {
+			advisor = analyzer.NewIndexAdvisor(d, s)
+		}
+		advisor.Add(stmt)
+	})
+	if err != nil {
+		return err
+	}
+
+	var recs []analyzer.IndexRecommendation
+	if advisor != nil {
+		recs = advisor.Recommendations()
+	}
+	if cfg.Output.Format != "table" {
+		return outputJSON(recs, cfg.Output.PrettyJSON)
+	}
+
+	fmt.Println("=== Index Recommendations ===")
+	if len(recs) == 0 {
+		fmt.Println("No indexes to recommend.")
+		return nil
+	}
+	for _, rec := range recs {
+		fmt.Printf("\n%s\n", rec.DDL)
+		fmt.Printf("  Queries: %d\n", rec.Frequency)
+		fmt.Printf("  Serves: %s\n", rec.Reason)
+		if rec.Supersede != "" {
+			fmt.Printf("  Extends: %s\n", rec.Supersede)
+		}
+	}
+	return nil
+}
+
+// printPrivileges reports the privileges a workload needs and the GRANT
+// statements that give them to grantee
+func printPrivileges(queryFile, queryText, logFile, schemaFile, grantee string, cfg *config.Config, verbose bool) error {
+	queries, err := loadWorkload(queryFile, queryText, logFile)
+	if err != nil {
+		return err
+	}
+	if queries == nil {
+		return fmt.Errorf("-privileges requires -sql, -query or -log")
+	}
+
+	var s *schema.Schema
+	if schemaFile != "" {
+		if s, err = schema.NewSchemaLoader().LoadFromFile(schemaFile); err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+	}
+
+	var d dialect.Dialect
+	var collector *privileges.Collector
+	err = parseWorkload(queries, cfg, verbose, func(queryDialect dialect.Dialect, stmt parser.Statement) {
+		if collector == nil {
+			d = queryDialect
+			collector = privileges.NewCollector(d, s)
+		}
+		collector.Add(stmt)
+	})
+	if err != nil {
+		return err
+	}
+	if collector == nil {
+		return fmt.Errorf("no statement to analyze")
+	}
+
+	report := collector.Report()
+	grants, err := report.Grants(d, grantee)
+	if err != nil {
+		report.Warnings = append(report.Warnings, err.Error())
+	}
+	if cfg.Output.Format != "table" {
+		return outputJSON(struct {
+			*privileges.Report
+			Grants []string `json:"grants,omitempty"`
+		}{report, grants}, cfg.Output.PrettyJSON)
+	}
+
+	fmt.Println("=== Required Privileges ===")
+	fmt.Printf("%-25s %-10s %-11s %s\n", "Object", "Type", "Privilege", "Columns")
+	fmt.Println(strings.Repeat("-", 70))
+	for _, req := range report.Requirements {
+		columns := strings.Join(req.Columns, ", ")
+		if columns == "" && req.Privilege.OnColumns() {
+			columns = "(all)"
+		}
+		fmt.Printf("%-25s %-10s %-11s %s\n", req.Object, req.ObjectType, req.Privilege, columns)
+	}
+	if len(grants) > 0 {
+		fmt.Println("\n=== GRANT Statements ===")
+		for _, grant := range grants {
+			fmt.Println(grant)
+		}
+	}
+	if len(report.Warnings) > 0 {
+		fmt.Println("\n=== Warnings ===")
+		for _, warning := range report.Warnings {
+			fmt.Printf("- %s\n", warning)
+		}
+	}
+	return nil
+}
+
+func printCacheStats(stats analyzer.CacheStats) {
+	fmt.Printf("Analysis cache: %d entries (%.1f KB), %.0f%% hits, %d evictions, %d expired\n",
+		stats.Entries, float64(stats.Bytes)/1024, stats.HitRate()*100, stats.Evictions, stats.Expirations)
 }
 
 func watchLogFile(filename string, cfg *config.Config, verbose bool, tailLines int, slowThreshold float64) error {
@@ -239,6 +1028,8 @@ func watchLogFile(filename string, cfg *config.Config, verbose bool, tailLines i
 
 	// Create processor
 	processor := monitor.NewLogProcessor(cfg.Parser.Dialect)
+	cache := analyzer.NewAnalysisCache(cfg.Analyzer.Cache.AnalysisCache())
+	processor.SetCache(cache)
 	processor.SetQueryHandler(func(pq *monitor.ProcessedQuery) {
 		// Check alerts first
 		alertMgr.Check(pq)
@@ -301,6 +1092,7 @@ func watchLogFile(filename string, cfg *config.Config, verbose bool, tailLines i
 			fmt.Println()
 			fmt.Println(strings.Repeat("=", 80))
 			fmt.Println(stats.String())
+			printCacheStats(cache.Stats())
 
 			// Print alert counts
 			alertCounts := alertMgr.GetAlertCounts()
@@ -323,6 +1115,7 @@ func watchLogFile(filename string, cfg *config.Config, verbose bool, tailLines i
 			fmt.Println()
 			fmt.Println("Final Statistics:")
 			fmt.Println(stats.String())
+			printCacheStats(cache.Stats())
 			return nil
 		}
 	}
@@ -419,15 +1212,27 @@ func outputJSON(data interface{}, pretty bool) error {
 func outputTable(analysis analyzer.QueryAnalysis, suggestions []analyzer.OptimizationSuggestion) error {
 	fmt.Println("=== SQL Query Analysis ===")
 	fmt.Printf("Query Type: %s\n", analysis.QueryType)
-	fmt.Printf("Complexity: %d\n\n", analysis.Complexity)
+	fmt.Printf("Complexity: %d\n", analysis.Complexity)
+	if perf := analysis.Performance; perf != nil {
+		fmt.Printf("Risk Level: %s\n", perf.RiskLevel)
+		if perf.EstimatedCost > 0 {
+			fmt.Printf("Estimated Rows: %d\n", perf.EstimatedRows)
+			fmt.Printf("Estimated Cost: %.0f\n", perf.EstimatedCost)
+		}
+	}
+	fmt.Println()
 
 	// Tables
 	if len(analysis.Tables) > 0 {
 		fmt.Println("Tables:")
-		fmt.Printf("%-20s %-10s %-10s %s\n", "Name", "Schema", "Alias", "Usage")
-		fmt.Println(strings.Repeat("-", 60))
+		fmt.Printf("%-20s %-10s %-10s %-10s %s\n", "Name", "Schema", "Alias", "Usage", "Scope")
+		fmt.Println(strings.Repeat("-", 70))
 		for _, table := range analysis.Tables {
-			fmt.Printf("%-20s %-10s %-10s %s\n", table.Name, table.Schema, table.Alias, table.Usage)
+			usage := table.Usage
+			if table.CTE {
+				usage += " CTE"
+			}
+			fmt.Printf("%-20s %-10s %-10s %-10s %s\n", table.Name, table.Schema, table.Alias, usage, table.Scope)
 		}
 		fmt.Println()
 	}
@@ -435,10 +1240,10 @@ func outputTable(analysis analyzer.QueryAnalysis, suggestions []analyzer.Optimiz
 	// Columns
 	if len(analysis.Columns) > 0 {
 		fmt.Println("Columns:")
-		fmt.Printf("%-20s %-10s %s\n", "Name", "Table", "Usage")
-		fmt.Println(strings.Repeat("-", 40))
+		fmt.Printf("%-20s %-10s %-10s %s\n", "Name", "Table", "Usage", "Scope")
+		fmt.Println(strings.Repeat("-", 60))
 		for _, column := range analysis.Columns {
-			fmt.Printf("%-20s %-10s %s\n", column.Name, column.Table, column.Usage)
+			fmt.Printf("%-20s %-10s %-10s %s\n", column.Name, column.Table, column.Usage, column.Scope)
 		}
 		fmt.Println()
 	}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
 	"gopkg.in/yaml.v3"
 )
 
@@ -14,18 +18,121 @@ type Config struct {
 	Analyzer AnalyzerConfig `json:"analyzer" yaml:"analyzer"`
 	Logger   LoggerConfig   `json:"logger" yaml:"logger"`
 	Output   OutputConfig   `json:"output" yaml:"output"`
+	Lint     LintConfig     `json:"lint" yaml:"lint"`
 }
 
 type ParserConfig struct {
 	StrictMode   bool   `json:"strict_mode" yaml:"strict_mode"`
 	MaxQuerySize int    `json:"max_query_size" yaml:"max_query_size"`
 	Dialect      string `json:"dialect" yaml:"dialect"`
+	// DialectVersion pins the server version, e.g. "5.7"; empty means latest
+	DialectVersion string `json:"dialect_version,omitempty" yaml:"dialect_version,omitempty"`
 }
 
 type AnalyzerConfig struct {
 	EnableOptimizations bool `json:"enable_optimizations" yaml:"enable_optimizations"`
 	ComplexityThreshold int  `json:"complexity_threshold" yaml:"complexity_threshold"`
 	DetailedAnalysis    bool `json:"detailed_analysis" yaml:"detailed_analysis"`
+
+	// Points per structural feature of the complexity score
+	ComplexityWeights analyzer.ComplexityWeights `json:"complexity_weights" yaml:"complexity_weights"`
+
+	// Cache of analyses, keyed by query fingerprint, dialect and settings
+	Cache CacheConfig `json:"cache" yaml:"cache"`
+}
+
+type CacheConfig struct {
+	// Maximum number of cached analyses
+	MaxEntries int `json:"max_entries" yaml:"max_entries"`
+
+	// Maximum memory of the cached analyses (in MB, 0 for no limit)
+	MaxSizeMB int `json:"max_size_mb" yaml:"max_size_mb"`
+
+	// Time an analysis stays cached (in seconds, 0 for no expiry)
+	TTLSeconds int64 `json:"ttl_seconds" yaml:"ttl_seconds"`
+
+	// Eviction policy: lru or arc
+	Policy string `json:"policy" yaml:"policy"`
+
+	// File the cache is kept in between runs (empty for none)
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+}
+
+// AnalysisCache returns the analyzer's cache settings
+func (c CacheConfig) AnalysisCache() analyzer.CacheConfig {
+	return analyzer.CacheConfig{
+		MaxEntries: c.MaxEntries,
+		MaxBytes:   int64(c.MaxSizeMB) << 20,
+		TTL:        time.Duration(c.TTLSeconds) * time.Second,
+		Policy:     c.Policy,
+	}
+}
+
+type LintConfig struct {
+	// Per-rule settings keyed by rule ID, e.g. SELECT_STAR: {enabled: false}
+	Rules map[string]analyzer.RuleConfig `json:"rules,omitempty" yaml:"rules,omitempty"`
+
+	// Rule settings for files matching path patterns, applied in order
+	Overrides []LintOverride `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+
+	// Baseline file of accepted violations (empty for none)
+	Baseline string `json:"baseline,omitempty" yaml:"baseline,omitempty"`
+
+	// Minimum severity that makes the run fail (empty never fails)
+	FailOn string `json:"fail_on,omitempty" yaml:"fail_on,omitempty"`
+}
+
+type LintOverride struct {
+	// Glob patterns matched against the file path, e.g. "migrations/*.sql";
+	// a pattern without a slash also matches the base name
+	Paths []string                       `json:"paths" yaml:"paths"`
+	Rules map[string]analyzer.RuleConfig `json:"rules" yaml:"rules"`
+}
+
+// RulesFor returns the rule settings for a file, with the overrides whose
+// paths match it applied over the project-wide settings
+func (c LintConfig) RulesFor(path string) map[string]analyzer.RuleConfig {
+	rules := make(map[string]analyzer.RuleConfig, len(c.Rules))
+	merge := func(from map[string]analyzer.RuleConfig) {
+		for id, rc := range from {
+			id = strings.ToUpper(id)
+			current := rules[id]
+			if rc.Enabled != nil {
+				current.Enabled = rc.Enabled
+			}
+			if rc.Severity != "" {
+				current.Severity = rc.Severity
+			}
+			rules[id] = current
+		}
+	}
+
+	merge(c.Rules)
+	for _, o := range c.Overrides {
+		if path != "" && o.matches(path) {
+			merge(o.Rules)
+		}
+	}
+	return rules
+}
+
+func (o LintOverride) matches(path string) bool {
+	path = filepath.ToSlash(filepath.Clean(path))
+	for _, pattern := range o.Paths {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+				return true
+			}
+		}
+		// A directory pattern matches everything below it
+		if dir := strings.TrimSuffix(pattern, "/"); dir != pattern && strings.HasPrefix(path, dir+"/") {
+			return true
+		}
+	}
+	return false
 }
 
 type LoggerConfig struct {
@@ -76,8 +183,14 @@ func DefaultConfig() *Config {
 		},
 		Analyzer: AnalyzerConfig{
 			EnableOptimizations: true,
-			ComplexityThreshold: 10,
+			ComplexityThreshold: analyzer.DefaultComplexityThreshold,
 			DetailedAnalysis:    true,
+			ComplexityWeights:   analyzer.DefaultComplexityWeights(),
+			Cache: CacheConfig{
+				MaxEntries: analyzer.DefaultCacheEntries,
+				MaxSizeMB:  64,
+				Policy:     analyzer.CachePolicyLRU,
+			},
 		},
 		Logger: LoggerConfig{
 			DefaultFormat: "profiler",
@@ -169,6 +282,18 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("analyzer.complexity_threshold must be non-negative")
 	}
 
+	if c.Analyzer.Cache.MaxEntries <= 0 {
+		return fmt.Errorf("analyzer.cache.max_entries must be positive")
+	}
+
+	if c.Analyzer.Cache.MaxSizeMB < 0 || c.Analyzer.Cache.TTLSeconds < 0 {
+		return fmt.Errorf("analyzer.cache.max_size_mb and ttl_seconds must be non-negative")
+	}
+
+	if policy := strings.ToLower(c.Analyzer.Cache.Policy); policy != analyzer.CachePolicyLRU && policy != analyzer.CachePolicyARC {
+		return fmt.Errorf("invalid analyzer.cache.policy: %s", c.Analyzer.Cache.Policy)
+	}
+
 	if c.Logger.MaxFileSizeMB <= 0 {
 		return fmt.Errorf("logger.max_file_size_mb must be positive")
 	}
@@ -183,21 +308,58 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid output format: %s", c.Output.Format)
 	}
 
-	validDialects := map[string]bool{
-		"sqlserver":  true,
-		"mysql":      true,
-		"postgresql": true,
-		"oracle":     true,
-		"sqlite":     true,
+	if c.Parser.Dialect == dialect.Auto {
+		if c.Parser.DialectVersion != "" {
+			return fmt.Errorf("dialect_version requires an explicit dialect")
+		}
+	} else if _, err := dialect.Lookup(c.Parser.Dialect); err != nil {
+		return err
+	}
+
+	if c.Parser.DialectVersion != "" {
+		if _, err := dialect.ParseVersion(c.Parser.DialectVersion); err != nil {
+			return fmt.Errorf("invalid dialect version: %w", err)
+		}
 	}
 
-	if !validDialects[c.Parser.Dialect] {
-		return fmt.Errorf("invalid SQL dialect: %s", c.Parser.Dialect)
+	if err := c.Lint.validate(); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+func (c LintConfig) validate() error {
+	ruleSets := []map[string]analyzer.RuleConfig{c.Rules}
+	for i, o := range c.Overrides {
+		if len(o.Paths) == 0 {
+			return fmt.Errorf("lint.overrides[%d] has no paths", i)
+		}
+		for _, pattern := range o.Paths {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("lint.overrides[%d]: invalid path pattern %q", i, pattern)
+			}
+		}
+		ruleSets = append(ruleSets, o.Rules)
+	}
+
+	for _, rules := range ruleSets {
+		for id, rc := range rules {
+			if !analyzer.IsRuleID(id) {
+				return fmt.Errorf("lint: unknown rule %s", id)
+			}
+			if rc.Severity != "" && analyzer.SeverityRank(rc.Severity) == 0 {
+				return fmt.Errorf("lint: invalid severity %s for rule %s", rc.Severity, id)
+			}
+		}
+	}
+
+	if c.FailOn != "" && analyzer.SeverityRank(c.FailOn) == 0 {
+		return fmt.Errorf("lint.fail_on: invalid severity %s", c.FailOn)
+	}
+	return nil
+}
+
 // GetConfigPath returns the default configuration file path
 func GetConfigPath() string {
 	homeDir, err := os.UserHomeDir()
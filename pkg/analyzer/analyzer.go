@@ -1,11 +1,16 @@
 package analyzer
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
 )
 
 const (
@@ -13,14 +18,28 @@ const (
 	defaultColumnCapacity    = 16
 	defaultJoinCapacity      = 4
 	defaultConditionCapacity = 8
-	defaultCacheCapacity     = 64
+
+	// DefaultComplexityThreshold is the score above which a query is HIGH risk
+	DefaultComplexityThreshold = 10
 )
 
 type Analyzer struct {
 	analysis           QueryAnalysis
-	cache              map[string]QueryAnalysis
+	cache              *AnalysisCache
+	settings           string // hash of the settings below, for cache keys
 	mu                 sync.RWMutex
 	optimizationEngine *OptimizationEngine
+	dialect            dialect.Dialect
+	schema             *schema.Schema
+	weights            ComplexityWeights
+	threshold          int
+
+	// Scope of the query being analyzed; see scope.go
+	scope         []string
+	cteNames      []map[string]bool
+	scopeCounts   map[string]int
+	taggedTables  int
+	taggedColumns int
 }
 
 func New() *Analyzer {
@@ -31,7 +50,9 @@ func New() *Analyzer {
 			Joins:      make([]JoinInfo, 0, defaultJoinCapacity),
 			Conditions: make([]ConditionInfo, 0, defaultConditionCapacity),
 		},
-		cache: make(map[string]QueryAnalysis, defaultCacheCapacity),
+		cache:     NewAnalysisCache(CacheConfig{}),
+		weights:   DefaultComplexityWeights(),
+		threshold: DefaultComplexityThreshold,
 	}
 }
 
@@ -43,96 +64,256 @@ func NewWithDialect(d dialect.Dialect) *Analyzer {
 			Joins:      make([]JoinInfo, 0, defaultJoinCapacity),
 			Conditions: make([]ConditionInfo, 0, defaultConditionCapacity),
 		},
-		cache:              make(map[string]QueryAnalysis, defaultCacheCapacity),
+		cache:              NewAnalysisCache(CacheConfig{}),
 		optimizationEngine: NewOptimizationEngine(d),
+		dialect:            d,
+		weights:            DefaultComplexityWeights(),
+		threshold:          DefaultComplexityThreshold,
 	}
 }
 
+// SetSchema sets the schema whose statistics estimate rows and cost, and
+// whose indexes the index recommendations take into account
+func (a *Analyzer) SetSchema(s *schema.Schema) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.schema = s
+	a.settings = ""
+}
+
+// SetComplexityModel sets the complexity weights and the score above which
+// a query is HIGH risk; up to half of it is LOW
+func (a *Analyzer) SetComplexityModel(weights ComplexityWeights, threshold int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.weights = weights
+	a.threshold = threshold
+	a.settings = ""
+}
+
+// SetCache sets the cache AnalyzeWithCache uses, e.g. one shared with
+// other analyzers
+func (a *Analyzer) SetCache(cache *AnalysisCache) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cache = cache
+}
+
 func (a *Analyzer) Analyze(stmt parser.Statement) QueryAnalysis {
 	a.analysis.Tables = a.analysis.Tables[:0]
 	a.analysis.Columns = a.analysis.Columns[:0]
 	a.analysis.Joins = a.analysis.Joins[:0]
 	a.analysis.Conditions = a.analysis.Conditions[:0]
+	a.analysis.Pagination = nil
+	a.analysis.GroupingSets = nil
+
+	a.analysis.CTEs = nil
+	a.resetScopes()
+
+	a.analysis.QueryType = a.analyzeStatement(stmt)
+	a.tagScope()
+
+	report := MeasureComplexity(stmt, a.weights)
+	a.analysis.Complexity = report.Score
+	a.analysis.Performance = a.performance(stmt, report)
+	return a.analysis
+}
 
+// analyzeStatement collects the tables and columns of a statement in the
+// current scope and returns its query type
+func (a *Analyzer) analyzeStatement(stmt parser.Statement) string {
 	switch s := stmt.(type) {
 	case *parser.SelectStatement:
 		a.analyzeSelectStatement(s)
-		a.analysis.QueryType = "SELECT"
+		a.analyzeRowLimit(s.Limit)
+		return "SELECT"
+	case *parser.WithStatement:
+		return a.analyzeWithStatement(s)
+	case *parser.SetOperation:
+		a.analyzeSetOperation(s)
+		return "SELECT"
 	case *parser.InsertStatement:
 		a.analyzeInsertStatement(s)
-		a.analysis.QueryType = "INSERT"
+		return "INSERT"
 	case *parser.UpdateStatement:
 		a.analyzeUpdateStatement(s)
-		a.analysis.QueryType = "UPDATE"
+		a.analyzeRowLimit(s.Limit)
+		return "UPDATE"
 	case *parser.DeleteStatement:
 		a.analyzeDeleteStatement(s)
-		a.analysis.QueryType = "DELETE"
+		a.analyzeRowLimit(s.Limit)
+		return "DELETE"
+	case *parser.MergeStatement:
+		a.analyzeMergeStatement(s)
+		return "MERGE"
 	case *parser.CreateTableStatement:
 		a.analyzeCreateTableStatement(s)
-		a.analysis.QueryType = "CREATE TABLE"
+		return "CREATE TABLE"
+	case *parser.CreateViewStatement:
+		a.analyzeCreateViewStatement(s)
+		if s.Materialized {
+			return "CREATE MATERIALIZED VIEW"
+		}
+		return "CREATE VIEW"
+	case *parser.CreateProcedureStatement:
+		a.analyzeRoutineBody("procedure:"+s.Name, s.Body)
+		return "CREATE PROCEDURE"
+	case *parser.CreateFunctionStatement:
+		a.analyzeRoutineBody("function:"+s.Name, s.Body)
+		return "CREATE FUNCTION"
+	case *parser.CreateTriggerStatement:
+		a.analysis.Tables = append(a.analysis.Tables, tableInfo(&s.TableName, "TRIGGER"))
+		a.enterScope("trigger:" + s.TriggerName)
+		a.analyzeExpression(s.WhenCondition, "CONDITION")
+		a.leaveScope()
+		a.analyzeRoutineBody("trigger:"+s.TriggerName, s.Body)
+		return "CREATE TRIGGER"
 	case *parser.DropStatement:
 		a.analyzeDropStatement(s)
-		a.analysis.QueryType = "DROP " + s.ObjectType
+		return "DROP " + s.ObjectType
 	case *parser.AlterTableStatement:
 		a.analyzeAlterTableStatement(s)
-		a.analysis.QueryType = "ALTER TABLE"
+		return "ALTER TABLE"
 	case *parser.CreateIndexStatement:
 		a.analyzeCreateIndexStatement(s)
-		a.analysis.QueryType = "CREATE INDEX"
+		return "CREATE INDEX"
+	case *parser.DeclareStatement:
+		if s.Cursor != nil && s.Cursor.Query != nil {
+			a.analyzeSelectStatement(s.Cursor.Query)
+		}
+		return "DECLARE"
+	case *parser.SetOptionStatement, *parser.SetTransactionStatement:
+		return "SET"
+	case *parser.ExecStatement:
+		return "EXEC"
+	case *parser.UseStatement:
+		return "USE"
+	case *parser.PrintStatement:
+		return "PRINT"
+	case *parser.GoStatement:
+		return "GO"
 	}
-
-	a.analysis.Complexity = a.calculateComplexity()
-	return a.analysis
+	return ""
 }
 
+// AnalyzeWithCache analyzes a statement unless its analysis is cached.
+// cacheKey identifies the query, normally its QueryFingerprint; the
+// analyzer's dialect and settings are added to it, so that analyzers
+// configured differently can share a cache. An empty key skips the cache.
 func (a *Analyzer) AnalyzeWithCache(stmt parser.Statement, cacheKey string) QueryAnalysis {
-	if cacheKey != "" {
-		a.mu.RLock()
-		if cached, exists := a.cache[cacheKey]; exists {
-			a.mu.RUnlock()
-			return cached
-		}
-		a.mu.RUnlock()
+	if cacheKey == "" || a.cache == nil {
+		return a.Analyze(stmt)
 	}
 
+	key := a.settingsHash() + "|" + cacheKey
+	if cached, ok := a.cache.Get(key); ok {
+		return cached
+	}
 	analysis := a.Analyze(stmt)
+	a.cache.Put(key, analysis)
+	return analysis
+}
+
+// OptimizeWithCache is AnalyzeWithCache for an analysis with its
+// EnhancedSuggestions and, for SELECT, its Suggestions filled in, so that
+// a cached query skips the optimization rules too
+func (a *Analyzer) OptimizeWithCache(stmt parser.Statement, cacheKey string) QueryAnalysis {
+	if cacheKey == "" || a.cache == nil {
+		return a.optimize(stmt)
+	}
 
-	if cacheKey != "" {
-		a.mu.Lock()
-		a.cache[cacheKey] = analysis
-		a.mu.Unlock()
+	key := a.settingsHash() + "|optimized|" + cacheKey
+	if cached, ok := a.cache.Get(key); ok {
+		return cached
 	}
+	analysis := a.optimize(stmt)
+	a.cache.Put(key, analysis)
+	return analysis
+}
 
+func (a *Analyzer) optimize(stmt parser.Statement) QueryAnalysis {
+	// The analyzer reuses its buffers for the next statement
+	analysis := cloneAnalysis(a.Analyze(stmt))
+	analysis.EnhancedSuggestions = a.GetEnhancedOptimizations(stmt)
+	if selectStmt, ok := stmt.(*parser.SelectStatement); ok {
+		analysis.Suggestions = a.SuggestOptimizations(selectStmt)
+	}
 	return analysis
 }
 
+// settingsHash hashes what changes an analysis besides the query: the
+// dialect and its version, the complexity model, the schema and the
+// configuration of the optimization rules
+func (a *Analyzer) settingsHash() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.settings != "" {
+		return a.settings
+	}
+
+	h := sha256.New()
+	if a.dialect != nil {
+		fmt.Fprintf(h, "%s %s;", a.dialect.Name(), a.dialect.GetVersion())
+	}
+	fmt.Fprintf(h, "%+v %d;", a.weights, a.threshold)
+	if a.schema != nil {
+		if data, err := json.Marshal(a.schema); err == nil {
+			h.Write(data)
+		}
+	}
+	if a.optimizationEngine != nil {
+		for _, rule := range a.optimizationEngine.Rules() {
+			fmt.Fprintf(h, "%s %v %s;", rule.ID, rule.Enabled, rule.Severity)
+		}
+	}
+	a.settings = hex.EncodeToString(h.Sum(nil)[:8])
+	return a.settings
+}
+
 func (a *Analyzer) analyzeSelectStatement(stmt *parser.SelectStatement) {
+	if stmt.Into != nil {
+		a.analysis.Tables = append(a.analysis.Tables, TableInfo{
+			Catalog: stmt.Into.Catalog,
+			Schema:  stmt.Into.Schema,
+			Name:    stmt.Into.Name,
+			Usage:   "INSERT",
+		})
+	}
+
 	if stmt.From != nil {
-		for _, table := range stmt.From.Tables {
-			a.analysis.Tables = append(a.analysis.Tables, TableInfo{
-				Schema: table.Schema,
-				Name:   table.Name,
-				Alias:  table.Alias,
-				Usage:  "SELECT",
-			})
+		for i := range stmt.From.Tables {
+			a.addTableReference(&stmt.From.Tables[i], "SELECT")
 		}
 	}
 
 	for _, join := range stmt.Joins {
-		a.analysis.Tables = append(a.analysis.Tables, TableInfo{
-			Schema: join.Table.Schema,
-			Name:   join.Table.Name,
-			Alias:  join.Table.Alias,
-			Usage:  "SELECT",
-		})
+		a.addTableReference(&join.Table, "SELECT")
 
-		a.analysis.Joins = append(a.analysis.Joins, JoinInfo{
+		info := JoinInfo{
 			Type:       join.JoinType,
 			RightTable: join.Table.Name,
-			Condition:  join.Condition.String(),
-		})
+			Using:      join.Using,
+			Natural:    join.Natural,
+			Lateral:    join.Lateral,
+		}
+		if join.Condition != nil {
+			info.Condition = join.Condition.String()
+			a.analyzeExpression(join.Condition, "JOIN")
+		} else if len(join.Using) > 0 {
+			info.Condition = "USING (" + strings.Join(join.Using, ", ") + ")"
+		}
+		a.analysis.Joins = append(a.analysis.Joins, info)
 
-		a.analyzeExpression(join.Condition, "JOIN")
+		for _, col := range join.Using {
+			a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
+				Name:  col,
+				Usage: "JOIN",
+			})
+		}
+	}
+
+	for _, expr := range stmt.DistinctOn {
+		a.analyzeExpression(expr, "DISTINCT_ON")
 	}
 
 	for _, col := range stmt.Columns {
@@ -146,16 +327,181 @@ func (a *Analyzer) analyzeSelectStatement(stmt *parser.SelectStatement) {
 	for _, expr := range stmt.GroupBy {
 		a.analyzeExpression(expr, "GROUP_BY")
 	}
+	a.analyzeGroupingSets(stmt)
 
 	if stmt.Having != nil {
 		a.analyzeExpression(stmt.Having, "HAVING")
 	}
 
+	for _, window := range stmt.Windows {
+		a.analyzeOverClause(window.Spec, "WINDOW")
+	}
+
+	if stmt.Qualify != nil {
+		a.analyzeExpression(stmt.Qualify, "QUALIFY")
+	}
+
 	for _, orderBy := range stmt.OrderBy {
 		a.analyzeExpression(orderBy.Expression, "ORDER_BY")
 	}
 }
 
+// addTableReference records a table, or analyzes a derived table in its
+// own scope
+func (a *Analyzer) addTableReference(ref *parser.TableReference, usage string) {
+	if ref.Subquery != nil {
+		a.analyzeDerivedTable(ref)
+		return
+	}
+	if ref.Function != nil {
+		// A table-valued function reads the columns of its arguments
+		a.analyzeExpression(ref.Function, usage)
+		return
+	}
+	a.analysis.Tables = append(a.analysis.Tables, tableInfo(ref, usage))
+}
+
+func tableInfo(ref *parser.TableReference, usage string) TableInfo {
+	return TableInfo{
+		Server:  ref.Server,
+		Catalog: ref.Catalog,
+		Schema:  ref.Schema,
+		Name:    ref.Name,
+		Alias:   ref.Alias,
+		Usage:   usage,
+	}
+}
+
+// analyzeRowLimit records pagination the same way for every limit syntax.
+// Limits of nested queries are left out.
+func (a *Analyzer) analyzeRowLimit(limit *parser.RowLimit) {
+	if limit == nil || len(a.scope) > 0 {
+		return
+	}
+
+	info := &PaginationInfo{
+		Syntax:   limit.Syntax.String(),
+		Percent:  limit.Percent,
+		WithTies: limit.WithTies,
+	}
+	if limit.Count != nil {
+		info.Count = limit.Count.String()
+	}
+	if limit.Offset != nil {
+		info.Offset = limit.Offset.String()
+	}
+	a.analysis.Pagination = info
+}
+
+// maxGroupingSets bounds the expansion of CUBE and nested grouping sets
+const maxGroupingSets = 4096
+
+// analyzeGroupingSets records the grouping sets a GROUP BY produces when it
+// uses ROLLUP, CUBE, GROUPING SETS or WITH ROLLUP
+func (a *Analyzer) analyzeGroupingSets(stmt *parser.SelectStatement) {
+	if len(a.scope) > 0 {
+		return
+	}
+	if stmt.WithRollup {
+		rollup := &parser.GroupingSetsExpression{Kind: "ROLLUP"}
+		for _, expr := range stmt.GroupBy {
+			rollup.Sets = append(rollup.Sets, []parser.Expression{expr})
+		}
+		a.analysis.GroupingSets = expandGroupingElements([]parser.Expression{rollup})
+		return
+	}
+
+	for _, expr := range stmt.GroupBy {
+		if _, ok := expr.(*parser.GroupingSetsExpression); ok {
+			a.analysis.GroupingSets = expandGroupingElements(stmt.GroupBy)
+			return
+		}
+	}
+}
+
+// expandGroupingElements returns the cross product of the grouping sets of
+// each GROUP BY element
+func expandGroupingElements(elements []parser.Expression) [][]string {
+	result := [][]string{{}}
+	for _, element := range elements {
+		sets := expandGroupingElement(element)
+		product := make([][]string, 0, len(result)*len(sets))
+		for _, prefix := range result {
+			for _, set := range sets {
+				if len(product) == maxGroupingSets {
+					break
+				}
+				combined := append(append([]string{}, prefix...), set...)
+				product = append(product, combined)
+			}
+		}
+		result = product
+	}
+	return result
+}
+
+// expandGroupingElement returns the grouping sets of a single GROUP BY element
+func expandGroupingElement(element parser.Expression) [][]string {
+	gse, ok := element.(*parser.GroupingSetsExpression)
+	if !ok {
+		return [][]string{{element.String()}}
+	}
+
+	var sets [][]string
+	switch gse.Kind {
+	case "ROLLUP":
+		// ROLLUP(a, b) = (a, b), (a), ()
+		for n := len(gse.Sets); n >= 0; n-- {
+			sets = append(sets, flattenGroupingSets(gse.Sets[:n]))
+		}
+	case "CUBE":
+		// CUBE(a, b) = (a, b), (a), (b), ()
+		n := len(gse.Sets)
+		for mask := (1 << n) - 1; mask >= 0 && len(sets) < maxGroupingSets; mask-- {
+			var subset [][]parser.Expression
+			for i := 0; i < n; i++ {
+				if mask&(1<<(n-1-i)) != 0 {
+					subset = append(subset, gse.Sets[i])
+				}
+			}
+			sets = append(sets, flattenGroupingSets(subset))
+		}
+	default:
+		// GROUPING SETS: the union of each entry's sets
+		for _, entry := range gse.Sets {
+			sets = append(sets, expandGroupingElements(entry)...)
+			if len(sets) >= maxGroupingSets {
+				break
+			}
+		}
+	}
+	return sets
+}
+
+// flattenGroupingSets joins parenthesized ROLLUP/CUBE elements into one set
+func flattenGroupingSets(sets [][]parser.Expression) []string {
+	columns := []string{}
+	for _, set := range sets {
+		for _, expr := range set {
+			columns = append(columns, expr.String())
+		}
+	}
+	return columns
+}
+
+// analyzeOverClause records the columns of a window specification
+func (a *Analyzer) analyzeOverClause(oc *parser.OverClause, usage string) {
+	if oc == nil {
+		return
+	}
+	for _, expr := range oc.PartitionBy {
+		a.analyzeExpression(expr, usage)
+	}
+	for _, ob := range oc.OrderBy {
+		a.analyzeExpression(ob.Expression, usage)
+	}
+}
+
 func (a *Analyzer) analyzeExpression(expr parser.Expression, usage string) {
 	switch e := expr.(type) {
 	case *parser.ColumnReference:
@@ -176,12 +522,41 @@ func (a *Analyzer) analyzeExpression(expr parser.Expression, usage string) {
 		for _, arg := range e.Arguments {
 			a.analyzeExpression(arg, usage)
 		}
+		for _, ob := range e.OrderBy {
+			a.analyzeExpression(ob.Expression, usage)
+		}
+		for _, ob := range e.WithinGroup {
+			a.analyzeExpression(ob.Expression, usage)
+		}
+		if e.Filter != nil {
+			a.analyzeExpression(e.Filter, usage)
+		}
+	case *parser.WindowFunction:
+		a.analyzeExpression(e.Function, usage)
+		a.analyzeOverClause(e.OverClause, usage)
+	case *parser.GroupingSetsExpression:
+		for _, set := range e.Sets {
+			for _, expr := range set {
+				a.analyzeExpression(expr, usage)
+			}
+		}
+	case *parser.CastExpression:
+		a.analyzeExpression(e.Expression, usage)
+	case *parser.ExtractExpression:
+		a.analyzeExpression(e.Source, usage)
+	case *parser.TrimExpression:
+		a.analyzeExpression(e.Source, usage)
+		if e.Characters != nil {
+			a.analyzeExpression(e.Characters, usage)
+		}
 	case *parser.StarExpression:
 		a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
 			Table: e.Table,
 			Name:  "*",
 			Usage: usage,
 		})
+	case *parser.AliasedExpression:
+		a.analyzeExpression(e.Expression, usage)
 	case *parser.UnaryExpression:
 		a.analyzeExpression(e.Operand, usage)
 	case *parser.InExpression:
@@ -189,6 +564,23 @@ func (a *Analyzer) analyzeExpression(expr parser.Expression, usage string) {
 		for _, val := range e.Values {
 			a.analyzeExpression(val, usage)
 		}
+	case *parser.BetweenExpression:
+		a.analyzeExpression(e.Expression, usage)
+		a.analyzeExpression(e.Low, usage)
+		a.analyzeExpression(e.High, usage)
+	case *parser.CaseExpression:
+		a.analyzeExpression(e.Input, usage)
+		for _, when := range e.WhenClauses {
+			a.analyzeExpression(when.Condition, usage)
+			a.analyzeExpression(when.Result, usage)
+		}
+		a.analyzeExpression(e.ElseResult, usage)
+	case *parser.SubqueryExpression:
+		if e.Query != nil {
+			a.analyzeSubquery(e.Query, usage)
+		}
+	case *parser.ExistsExpression:
+		a.analyzeSubquery(e.Subquery, usage)
 	}
 }
 
@@ -207,12 +599,7 @@ func (a *Analyzer) extractCondition(expr *parser.BinaryExpression, _ string) {
 }
 
 func (a *Analyzer) analyzeInsertStatement(stmt *parser.InsertStatement) {
-	a.analysis.Tables = append(a.analysis.Tables, TableInfo{
-		Schema: stmt.Table.Schema,
-		Name:   stmt.Table.Name,
-		Alias:  stmt.Table.Alias,
-		Usage:  "INSERT",
-	})
+	a.analysis.Tables = append(a.analysis.Tables, tableInfo(&stmt.Table, "INSERT"))
 
 	// Analyze column list
 	for _, col := range stmt.Columns {
@@ -221,61 +608,126 @@ func (a *Analyzer) analyzeInsertStatement(stmt *parser.InsertStatement) {
 			Usage: "INSERT",
 		})
 	}
-}
 
-func (a *Analyzer) analyzeUpdateStatement(stmt *parser.UpdateStatement) {
-	a.analysis.Tables = append(a.analysis.Tables, TableInfo{
-		Schema: stmt.Table.Schema,
-		Name:   stmt.Table.Name,
-		Alias:  stmt.Table.Alias,
-		Usage:  "UPDATE",
-	})
+	for _, row := range stmt.Values {
+		for _, value := range row {
+			a.analyzeExpression(value, "VALUES")
+		}
+	}
 
-	// Analyze SET clause
-	for _, assignment := range stmt.Set {
+	// INSERT ... SELECT reads its source tables
+	if stmt.Select != nil {
+		a.analyzeNested("source", stmt.Select)
+	}
+
+	// Analyze ON CONFLICT target and DO UPDATE clause
+	if oc := stmt.OnConflict; oc != nil {
+		for _, col := range oc.Columns {
+			a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
+				Name:  col,
+				Usage: "CONFLICT",
+			})
+		}
+		a.analyzeAssignments(oc.Set)
+		if oc.Where != nil {
+			a.analyzeExpression(oc.Where, "WHERE")
+		}
+	}
+
+	// Analyze ON DUPLICATE KEY UPDATE
+	a.analyzeAssignments(stmt.OnDuplicateKeyUpdate)
+
+	a.analyzeReturning(stmt.Returning, stmt.Output)
+}
+
+// analyzeAssignments records the columns and values of SET-style assignments
+func (a *Analyzer) analyzeAssignments(assignments []*parser.Assignment) {
+	for _, assignment := range assignments {
 		a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
 			Name:  assignment.Column,
 			Usage: "UPDATE",
 		})
 		a.analyzeExpression(assignment.Value, "UPDATE")
 	}
+}
+
+// analyzeReturning records the columns of RETURNING and OUTPUT clauses
+func (a *Analyzer) analyzeReturning(returning *parser.ReturningClause, output *parser.OutputClause) {
+	if returning != nil {
+		for _, col := range returning.Columns {
+			a.analyzeExpression(col, "RETURNING")
+		}
+	}
+
+	if output != nil {
+		for _, col := range output.Columns {
+			a.analyzeExpression(col, "OUTPUT")
+		}
+		if output.Into != nil {
+			a.analysis.Tables = append(a.analysis.Tables, TableInfo{
+				Server:  output.Into.Server,
+				Catalog: output.Into.Catalog,
+				Schema:  output.Into.Schema,
+				Name:    output.Into.Name,
+				Usage:   "INSERT",
+			})
+		}
+	}
+}
+
+func (a *Analyzer) analyzeUpdateStatement(stmt *parser.UpdateStatement) {
+	a.analysis.Tables = append(a.analysis.Tables, tableInfo(&stmt.Table, "UPDATE"))
+
+	// Analyze SET clause
+	a.analyzeAssignments(stmt.Set)
 
 	// Analyze WHERE clause
 	if stmt.Where != nil {
 		a.analyzeExpression(stmt.Where, "WHERE")
 	}
+
+	a.analyzeReturning(stmt.Returning, stmt.Output)
 }
 
 func (a *Analyzer) analyzeDeleteStatement(stmt *parser.DeleteStatement) {
-	a.analysis.Tables = append(a.analysis.Tables, TableInfo{
-		Schema: stmt.From.Schema,
-		Name:   stmt.From.Name,
-		Alias:  stmt.From.Alias,
-		Usage:  "DELETE",
-	})
+	a.analysis.Tables = append(a.analysis.Tables, tableInfo(&stmt.From, "DELETE"))
 
 	// Analyze WHERE clause
 	if stmt.Where != nil {
 		a.analyzeExpression(stmt.Where, "WHERE")
 	}
-}
-
-func (a *Analyzer) calculateComplexity() int {
-	complexity := 0
 
-	// Base complexity
-	complexity += len(a.analysis.Tables)
-	complexity += len(a.analysis.Joins) * 2
-	complexity += len(a.analysis.Conditions)
+	a.analyzeReturning(stmt.Returning, stmt.Output)
+}
 
-	// Additional complexity for functions
-	for _, col := range a.analysis.Columns {
-		if col.Usage == "SELECT" {
-			complexity++
-		}
+// performance fills the performance metrics of a statement: its join
+// complexity and risk, the indexes it could use, and rows and cost when
+// the schema has statistics
+func (a *Analyzer) performance(stmt parser.Statement, report ComplexityReport) *PerformanceMetrics {
+	metrics := &PerformanceMetrics{
+		JoinComplexity: report.Points("joins") + report.Points("star_join") + report.Points("cyclic_join") + report.Points("disconnected_join"),
+		RiskLevel:      riskLevel(report, a.threshold),
+	}
+	if estimate, ok := EstimateCost(stmt, a.dialect, a.schema); ok {
+		metrics.EstimatedRows = estimate.Rows
+		metrics.EstimatedCost = estimate.Cost
+	}
+	for _, rec := range NewIndexAdvisor(a.dialect, a.schema).Advise(stmt) {
+		metrics.IndexRecommendations = append(metrics.IndexRecommendations, rec.DDL)
 	}
+	return metrics
+}
 
-	return complexity
+// riskLevel rates a complexity score against the threshold. A Cartesian
+// product is always HIGH.
+func riskLevel(report ComplexityReport, threshold int) string {
+	switch {
+	case report.JoinShape == JoinShapeDisconnected || report.Score > threshold:
+		return "HIGH"
+	case report.Score*2 > threshold:
+		return "MEDIUM"
+	}
+	return "LOW"
 }
 
 // SuggestOptimizations provides optimization suggestions for a query
@@ -342,6 +794,7 @@ func (a *Analyzer) SetOptimizationEngine(engine *OptimizationEngine) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.optimizationEngine = engine
+	a.settings = ""
 }
 
 func (a *Analyzer) hasSelectAll(stmt *parser.SelectStatement) bool {
@@ -366,6 +819,13 @@ func (a *Analyzer) hasCartesianProduct(stmt *parser.SelectStatement) bool {
 		return true
 	}
 
+	// CROSS JOIN, or a join without ON/USING/NATURAL/LATERAL
+	for _, join := range stmt.Joins {
+		if !join.HasJoinCondition() {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -373,9 +833,11 @@ func (a *Analyzer) hasCartesianProduct(stmt *parser.SelectStatement) bool {
 
 func (a *Analyzer) analyzeCreateTableStatement(stmt *parser.CreateTableStatement) {
 	a.analysis.Tables = append(a.analysis.Tables, TableInfo{
-		Schema: stmt.Table.Schema,
-		Name:   stmt.Table.Name,
-		Usage:  "CREATE",
+		Server:  stmt.Table.Server,
+		Catalog: stmt.Table.Catalog,
+		Schema:  stmt.Table.Schema,
+		Name:    stmt.Table.Name,
+		Usage:   "CREATE",
 	})
 
 	// Analyze columns
@@ -385,6 +847,9 @@ func (a *Analyzer) analyzeCreateTableStatement(stmt *parser.CreateTableStatement
 			Usage: "CREATE",
 		})
 	}
+
+	// CREATE TABLE ... AS SELECT reads its source tables
+	a.analyzeNested("source", stmt.Query)
 }
 
 func (a *Analyzer) analyzeDropStatement(stmt *parser.DropStatement) {
@@ -398,27 +863,80 @@ func (a *Analyzer) analyzeDropStatement(stmt *parser.DropStatement) {
 
 func (a *Analyzer) analyzeAlterTableStatement(stmt *parser.AlterTableStatement) {
 	a.analysis.Tables = append(a.analysis.Tables, TableInfo{
-		Schema: stmt.Table.Schema,
-		Name:   stmt.Table.Name,
-		Usage:  "ALTER",
+		Server:  stmt.Table.Server,
+		Catalog: stmt.Table.Catalog,
+		Schema:  stmt.Table.Schema,
+		Name:    stmt.Table.Name,
+		Usage:   "ALTER",
 	})
 
-	// Analyze action
-	if stmt.Action != nil {
-		if stmt.Action.Column != nil {
+	// Analyze actions
+	for _, action := range stmt.Actions {
+		usage := "ALTER " + action.ActionType
+
+		for _, col := range action.Columns {
+			a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
+				Table: stmt.Table.Name,
+				Name:  col.Name,
+				Usage: usage,
+			})
+		}
+		if len(action.Columns) == 0 && action.ColumnName != "" {
 			a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
-				Name:  stmt.Action.Column.Name,
-				Usage: "ALTER " + stmt.Action.ActionType,
+				Table: stmt.Table.Name,
+				Name:  action.ColumnName,
+				Usage: usage,
 			})
 		}
+
+		switch action.ActionType {
+		case "CHANGE":
+			if action.NewColumn != nil {
+				a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
+					Table: stmt.Table.Name,
+					Name:  action.NewColumn.Name,
+					Usage: usage,
+				})
+			}
+		case "RENAME_COLUMN":
+			a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
+				Table: stmt.Table.Name,
+				Name:  action.NewName,
+				Usage: usage,
+			})
+		case "RENAME":
+			a.analysis.Tables = append(a.analysis.Tables, TableInfo{
+				Schema: stmt.Table.Schema,
+				Name:   action.NewName,
+				Usage:  "RENAME",
+			})
+		}
+
+		if action.Constraint != nil {
+			for _, col := range action.Constraint.Columns {
+				a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
+					Table: stmt.Table.Name,
+					Name:  col,
+					Usage: usage,
+				})
+			}
+			if ref := action.Constraint.References; ref != nil {
+				a.analysis.Tables = append(a.analysis.Tables, TableInfo{
+					Name:  ref.Table,
+					Usage: "REFERENCES",
+				})
+			}
+		}
 	}
 }
 
 func (a *Analyzer) analyzeCreateIndexStatement(stmt *parser.CreateIndexStatement) {
 	a.analysis.Tables = append(a.analysis.Tables, TableInfo{
-		Schema: stmt.Table.Schema,
-		Name:   stmt.Table.Name,
-		Usage:  "INDEX",
+		Server:  stmt.Table.Server,
+		Catalog: stmt.Table.Catalog,
+		Schema:  stmt.Table.Schema,
+		Name:    stmt.Table.Name,
+		Usage:   "INDEX",
 	})
 
 	// Analyze indexed columns
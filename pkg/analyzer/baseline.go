@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// baselineVersion is the version of the baseline file format
+const baselineVersion = 1
+
+// Baseline records accepted violations, such as those already present in
+// a legacy codebase, so that only new ones are reported
+type Baseline struct {
+	Version    int             `json:"version"`
+	Violations []BaselineEntry `json:"violations"`
+}
+
+// BaselineEntry is an accepted violation of a rule by a statement.
+// The fingerprint is the statement's QueryFingerprint, a hash of its
+// normalized tokens, so the entry survives reformatting and moving the
+// statement within its file.
+type BaselineEntry struct {
+	Path        string `json:"path"`
+	Rule        string `json:"rule"`
+	Fingerprint string `json:"fingerprint"`
+	Count       int    `json:"count"`
+}
+
+// NewBaseline creates an empty baseline
+func NewBaseline() *Baseline {
+	return &Baseline{Version: baselineVersion}
+}
+
+// LoadBaseline reads a baseline file
+func LoadBaseline(filename string) (*Baseline, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	b := NewBaseline()
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %v", err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d", b.Version)
+	}
+	return b, nil
+}
+
+// Save writes the baseline, with entries sorted so that it diffs well
+func (b *Baseline) Save(filename string) error {
+	sort.Slice(b.Violations, func(i, j int) bool {
+		x, y := b.Violations[i], b.Violations[j]
+		if x.Path != y.Path {
+			return x.Path < y.Path
+		}
+		if x.Rule != y.Rule {
+			return x.Rule < y.Rule
+		}
+		return x.Fingerprint < y.Fingerprint
+	})
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %v", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline: %v", err)
+	}
+	return nil
+}
+
+// Add accepts the violations of the statement with a QueryFingerprint
+func (b *Baseline) Add(path, fingerprint string, suggestions []EnhancedOptimizationSuggestion) {
+	path = baselinePath(path)
+	for _, s := range suggestions {
+		if entry := b.find(path, s.Rule, fingerprint); entry != nil {
+			entry.Count++
+			continue
+		}
+		b.Violations = append(b.Violations, BaselineEntry{Path: path, Rule: s.Rule, Fingerprint: fingerprint, Count: 1})
+	}
+}
+
+// Remove drops the entries of a file
+func (b *Baseline) Remove(path string) {
+	path = baselinePath(path)
+	kept := b.Violations[:0]
+	for _, entry := range b.Violations {
+		if entry.Path != path {
+			kept = append(kept, entry)
+		}
+	}
+	b.Violations = kept
+}
+
+// Filter drops the violations that the baseline accepts of the statement
+// with a QueryFingerprint. Each entry accepts up to Count violations of
+// its rule.
+func (b *Baseline) Filter(path, fingerprint string, suggestions []EnhancedOptimizationSuggestion) []EnhancedOptimizationSuggestion {
+	path = baselinePath(path)
+	used := make(map[string]int)
+	kept := suggestions[:0:0]
+	for _, s := range suggestions {
+		if entry := b.find(path, s.Rule, fingerprint); entry != nil && used[s.Rule] < entry.Count {
+			used[s.Rule]++
+			continue
+		}
+		kept = append(kept, s)
+	}
+	return kept
+}
+
+func (b *Baseline) find(path, rule, fingerprint string) *BaselineEntry {
+	for i := range b.Violations {
+		entry := &b.Violations[i]
+		if entry.Path == path && entry.Rule == rule && entry.Fingerprint == fingerprint {
+			return entry
+		}
+	}
+	return nil
+}
+
+// baselinePath normalizes a file path for use as a baseline key
+func baselinePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
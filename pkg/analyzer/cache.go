@@ -0,0 +1,363 @@
+package analyzer
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache eviction policies
+const (
+	CachePolicyLRU = "lru" // least recently used
+	CachePolicyARC = "arc" // adaptive replacement: balances recency and frequency
+)
+
+// DefaultCacheEntries is the capacity of a cache configured without one
+const DefaultCacheEntries = 1024
+
+// cacheVersion is the version of the cache file format
+const cacheVersion = 1
+
+// CacheConfig bounds an AnalysisCache
+type CacheConfig struct {
+	MaxEntries int           // 0 for DefaultCacheEntries
+	MaxBytes   int64         // approximate memory of the analyses; 0 for no limit
+	TTL        time.Duration // 0 never expires
+	Policy     string        // CachePolicyLRU (default) or CachePolicyARC
+}
+
+// CacheStats are the counters of an AnalysisCache
+type CacheStats struct {
+	Entries     int    `json:"entries"`
+	Bytes       int64  `json:"bytes"`
+	Hits        uint64 `json:"hits"`
+	Misses      uint64 `json:"misses"`
+	Evictions   uint64 `json:"evictions"`
+	Expirations uint64 `json:"expirations"`
+}
+
+// HitRate returns the share of lookups that hit
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// AnalysisCache is a bounded cache of analyses, safe for concurrent use,
+// so that analyzers, ConcurrentAnalyzer workers and log processors can
+// share one. Keys are built by the analyzer from a QueryFingerprint, the
+// dialect and a hash of its settings.
+type AnalysisCache struct {
+	mu       sync.Mutex
+	capacity int
+	maxBytes int64
+	ttl      time.Duration
+	arc      bool
+	now      func() time.Time
+
+	entries  map[string]*list.Element
+	recent   *list.List // entries hit once; every entry under LRU
+	frequent *list.List // entries hit more than once (ARC)
+
+	// ARC remembers evicted keys: a miss on one moves target, the share
+	// of the capacity given to recent entries
+	ghosts         map[string]*list.Element
+	recentGhosts   *list.List
+	frequentGhosts *list.List
+	target         int
+
+	bytes int64
+	stats CacheStats
+}
+
+type cacheEntry struct {
+	key      string
+	analysis QueryAnalysis
+	size     int64
+	expires  time.Time // zero never expires
+	frequent bool
+}
+
+type cacheGhost struct {
+	key      string
+	frequent bool
+}
+
+// NewAnalysisCache creates an empty cache
+func NewAnalysisCache(cfg CacheConfig) *AnalysisCache {
+	c := &AnalysisCache{
+		capacity: cfg.MaxEntries,
+		maxBytes: cfg.MaxBytes,
+		ttl:      cfg.TTL,
+		arc:      strings.EqualFold(cfg.Policy, CachePolicyARC),
+		now:      time.Now,
+	}
+	if c.capacity <= 0 {
+		c.capacity = DefaultCacheEntries
+	}
+	c.reset()
+	return c
+}
+
+func (c *AnalysisCache) reset() {
+	c.entries = make(map[string]*list.Element)
+	c.recent = list.New()
+	c.frequent = list.New()
+	c.ghosts = make(map[string]*list.Element)
+	c.recentGhosts = list.New()
+	c.frequentGhosts = list.New()
+	c.target = 0
+	c.bytes = 0
+}
+
+// Get returns the analysis cached under key
+func (c *AnalysisCache) Get(key string) (QueryAnalysis, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return QueryAnalysis{}, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !e.expires.IsZero() && c.now().After(e.expires) {
+		c.remove(el)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return QueryAnalysis{}, false
+	}
+	c.stats.Hits++
+	c.promote(el)
+	return cloneAnalysis(e.analysis), true
+}
+
+// Put caches an analysis under key, evicting entries to stay in bounds.
+// An analysis larger than the whole memory bound is not cached.
+func (c *AnalysisCache) Put(key string, analysis QueryAnalysis) {
+	size := analysisSize(analysis)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(key, cloneAnalysis(analysis), size, c.expiry())
+}
+
+func (c *AnalysisCache) put(key string, analysis QueryAnalysis, size int64, expires time.Time) {
+	if c.maxBytes > 0 && size > c.maxBytes {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*cacheEntry)
+		c.bytes += size - e.size
+		e.analysis, e.size, e.expires = analysis, size, expires
+		c.promote(el)
+		for c.bytes > c.maxBytes && c.maxBytes > 0 && len(c.entries) > 1 {
+			c.evict()
+		}
+		return
+	}
+
+	e := &cacheEntry{key: key, analysis: analysis, size: size, expires: expires}
+	if g, ok := c.ghosts[key]; ok {
+		// A miss on a recently evicted key: the list it left was too short
+		ghost := g.Value.(*cacheGhost)
+		if ghost.frequent {
+			c.target = max(c.target-max(c.recentGhosts.Len()/c.frequentGhosts.Len(), 1), 0)
+		} else {
+			c.target = min(c.target+max(c.frequentGhosts.Len()/c.recentGhosts.Len(), 1), c.capacity)
+		}
+		c.forget(g)
+		e.frequent = true
+	}
+
+	for len(c.entries) > 0 && (len(c.entries) >= c.capacity || (c.maxBytes > 0 && c.bytes+size > c.maxBytes)) {
+		c.evict()
+	}
+	if e.frequent {
+		c.entries[key] = c.frequent.PushFront(e)
+	} else {
+		c.entries[key] = c.recent.PushFront(e)
+	}
+	c.bytes += size
+}
+
+func (c *AnalysisCache) expiry() time.Time {
+	if c.ttl <= 0 {
+		return time.Time{}
+	}
+	return c.now().Add(c.ttl)
+}
+
+// promote marks an entry as just used
+func (c *AnalysisCache) promote(el *list.Element) {
+	e := el.Value.(*cacheEntry)
+	switch {
+	case !c.arc:
+		c.recent.MoveToFront(el)
+	case e.frequent:
+		c.frequent.MoveToFront(el)
+	default:
+		c.recent.Remove(el)
+		e.frequent = true
+		c.entries[e.key] = c.frequent.PushFront(e)
+	}
+}
+
+// evict drops the least recently used entry; under ARC, of the recent
+// entries while they exceed their target share
+func (c *AnalysisCache) evict() {
+	el := c.recent.Back()
+	if c.arc && (el == nil || (c.recent.Len() <= c.target && c.frequent.Len() > 0)) {
+		el = c.frequent.Back()
+	}
+	if el == nil {
+		return
+	}
+	e := el.Value.(*cacheEntry)
+	c.remove(el)
+	c.stats.Evictions++
+
+	if c.arc {
+		ghosts := c.recentGhosts
+		if e.frequent {
+			ghosts = c.frequentGhosts
+		}
+		c.ghosts[e.key] = ghosts.PushFront(&cacheGhost{key: e.key, frequent: e.frequent})
+		for ghosts.Len() > c.capacity {
+			c.forget(ghosts.Back())
+		}
+	}
+}
+
+func (c *AnalysisCache) remove(el *list.Element) {
+	e := el.Value.(*cacheEntry)
+	if e.frequent {
+		c.frequent.Remove(el)
+	} else {
+		c.recent.Remove(el)
+	}
+	delete(c.entries, e.key)
+	c.bytes -= e.size
+}
+
+func (c *AnalysisCache) forget(el *list.Element) {
+	ghost := el.Value.(*cacheGhost)
+	if ghost.frequent {
+		c.frequentGhosts.Remove(el)
+	} else {
+		c.recentGhosts.Remove(el)
+	}
+	delete(c.ghosts, ghost.key)
+}
+
+// Stats returns the cache's size and counters
+func (c *AnalysisCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Bytes = c.bytes
+	return stats
+}
+
+// Clear drops every entry; the counters are kept
+func (c *AnalysisCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reset()
+}
+
+type cacheFile struct {
+	Version int              `json:"version"`
+	Entries []cacheFileEntry `json:"entries"`
+}
+
+type cacheFileEntry struct {
+	Key      string        `json:"key"`
+	Expires  *time.Time    `json:"expires,omitempty"`
+	Analysis QueryAnalysis `json:"analysis"`
+}
+
+// Save writes the unexpired entries to a file, the most used first
+func (c *AnalysisCache) Save(filename string) error {
+	c.mu.Lock()
+	file := cacheFile{Version: cacheVersion, Entries: []cacheFileEntry{}}
+	now := c.now()
+	for _, l := range []*list.List{c.frequent, c.recent} {
+		for el := l.Front(); el != nil; el = el.Next() {
+			e := el.Value.(*cacheEntry)
+			entry := cacheFileEntry{Key: e.key, Analysis: e.analysis}
+			if !e.expires.IsZero() {
+				if now.After(e.expires) {
+					continue
+				}
+				expires := e.expires
+				entry.Expires = &expires
+			}
+			file.Entries = append(file.Entries, entry)
+		}
+	}
+	data, err := json.Marshal(file)
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %v", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// Load adds the unexpired entries of a file written by Save
+func (c *AnalysisCache) Load(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read cache: %w", err)
+	}
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse cache: %v", err)
+	}
+	if file.Version != cacheVersion {
+		return fmt.Errorf("unsupported cache version %d", file.Version)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	// Least used first, so that the order survives
+	for i := len(file.Entries) - 1; i >= 0; i-- {
+		entry := file.Entries[i]
+		var expires time.Time
+		if entry.Expires != nil {
+			if now.After(*entry.Expires) {
+				continue
+			}
+			expires = *entry.Expires
+		}
+		c.put(entry.Key, entry.Analysis, analysisSize(entry.Analysis), expires)
+	}
+	return nil
+}
+
+// cloneAnalysis copies the slices an analyzer reuses between analyses
+func cloneAnalysis(a QueryAnalysis) QueryAnalysis {
+	a.Tables = append(make([]TableInfo, 0, len(a.Tables)), a.Tables...)
+	a.Columns = append(make([]ColumnInfo, 0, len(a.Columns)), a.Columns...)
+	a.Joins = append(make([]JoinInfo, 0, len(a.Joins)), a.Joins...)
+	a.Conditions = append(make([]ConditionInfo, 0, len(a.Conditions)), a.Conditions...)
+	return a
+}
+
+// analysisSize approximates the memory of an analysis by its JSON size
+func analysisSize(a QueryAnalysis) int64 {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
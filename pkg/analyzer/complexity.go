@@ -0,0 +1,547 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+// ComplexityWeights are the points each structural feature of a query adds
+// to its complexity score. A zero weight leaves a feature out. The score of
+// a statement is the sum over its query blocks, CTEs and subqueries:
+//
+//	tables × Table + joins × Join + predicates × Predicate
+//	+ select columns × SelectColumn
+//	+ Σ subquery nesting depth × Subquery + correlated subqueries × CorrelatedSubquery
+//	+ CTEs × CTE + CTE references × CTEReference + recursive CTEs × RecursiveCTE
+//	+ window functions × WindowFunction + set operations × SetOperation
+//	+ CASE branches × CaseBranch
+//	+ the join graph's shape: StarJoin, CyclicJoin or DisconnectedJoin
+type ComplexityWeights struct {
+	Table              int `json:"table" yaml:"table"`
+	Join               int `json:"join" yaml:"join"`
+	Predicate          int `json:"predicate" yaml:"predicate"` // each comparison in WHERE, ON, HAVING and QUALIFY
+	SelectColumn       int `json:"select_column" yaml:"select_column"`
+	Subquery           int `json:"subquery" yaml:"subquery"` // times the subquery's nesting depth
+	CorrelatedSubquery int `json:"correlated_subquery" yaml:"correlated_subquery"`
+	CTE                int `json:"cte" yaml:"cte"`
+	CTEReference       int `json:"cte_reference" yaml:"cte_reference"` // each time a CTE is read: its fan-out
+	RecursiveCTE       int `json:"recursive_cte" yaml:"recursive_cte"`
+	WindowFunction     int `json:"window_function" yaml:"window_function"`
+	SetOperation       int `json:"set_operation" yaml:"set_operation"`
+	CaseBranch         int `json:"case_branch" yaml:"case_branch"` // each WHEN and ELSE
+	StarJoin           int `json:"star_join" yaml:"star_join"`     // one table joined to three or more others
+	CyclicJoin         int `json:"cyclic_join" yaml:"cyclic_join"` // join conditions that form a cycle
+	DisconnectedJoin   int `json:"disconnected_join" yaml:"disconnected_join"`
+}
+
+// DefaultComplexityWeights returns the weights used unless configured
+func DefaultComplexityWeights() ComplexityWeights {
+	return ComplexityWeights{
+		Table:              1,
+		Join:               2,
+		Predicate:          1,
+		SelectColumn:       1,
+		Subquery:           2,
+		CorrelatedSubquery: 3,
+		CTE:                2,
+		CTEReference:       1,
+		RecursiveCTE:       5,
+		WindowFunction:     2,
+		SetOperation:       2,
+		CaseBranch:         1,
+		StarJoin:           2,
+		CyclicJoin:         4,
+		DisconnectedJoin:   10,
+	}
+}
+
+// Join graph shapes, from the simplest
+const (
+	JoinShapeNone         = ""             // at most one table
+	JoinShapeChain        = "chain"        // each table joins the next
+	JoinShapeStar         = "star"         // a table joins three or more others
+	JoinShapeCyclic       = "cyclic"       // join conditions form a cycle
+	JoinShapeDisconnected = "disconnected" // some tables aren't joined: a Cartesian product
+)
+
+var joinShapeRank = map[string]int{
+	JoinShapeNone: 0, JoinShapeChain: 1, JoinShapeStar: 2, JoinShapeCyclic: 3, JoinShapeDisconnected: 4,
+}
+
+// ComplexityFactor is one feature's share of a complexity score
+type ComplexityFactor struct {
+	Name   string `json:"name"`
+	Count  int    `json:"count"`
+	Points int    `json:"points"`
+}
+
+// ComplexityReport is the complexity score of a statement and what it is
+// made of
+type ComplexityReport struct {
+	Score            int                `json:"score"`
+	Factors          []ComplexityFactor `json:"factors,omitempty"`
+	JoinShape        string             `json:"join_shape,omitempty"` // of the most complex query block
+	MaxSubqueryDepth int                `json:"max_subquery_depth,omitempty"`
+}
+
+// Points returns the points of a factor, 0 when it doesn't occur
+func (r ComplexityReport) Points(name string) int {
+	for _, f := range r.Factors {
+		if f.Name == name {
+			return f.Points
+		}
+	}
+	return 0
+}
+
+// MeasureComplexity scores a statement under a set of weights
+func MeasureComplexity(stmt parser.Statement, w ComplexityWeights) ComplexityReport {
+	cw := &complexityWalker{
+		weights: w,
+		counts:  make(map[string]int),
+		points:  make(map[string]int),
+		ctes:    make(map[string]bool),
+	}
+	cw.statement(stmt, 0, nil)
+
+	report := ComplexityReport{JoinShape: cw.shape, MaxSubqueryDepth: cw.maxDepth}
+	for name, count := range cw.counts {
+		report.Factors = append(report.Factors, ComplexityFactor{Name: name, Count: count, Points: cw.points[name]})
+		report.Score += cw.points[name]
+	}
+	sort.Slice(report.Factors, func(i, j int) bool {
+		if report.Factors[i].Points != report.Factors[j].Points {
+			return report.Factors[i].Points > report.Factors[j].Points
+		}
+		return report.Factors[i].Name < report.Factors[j].Name
+	})
+	return report
+}
+
+// complexityWalker counts the features of a statement. Scopes are the
+// qualifiers of the enclosing query blocks, innermost last, to tell
+// correlated subqueries.
+type complexityWalker struct {
+	weights  ComplexityWeights
+	counts   map[string]int
+	points   map[string]int
+	ctes     map[string]bool // lower-case CTE names in scope
+	shape    string
+	maxDepth int
+}
+
+func (cw *complexityWalker) add(name string, count, weight int) {
+	if count == 0 {
+		return
+	}
+	cw.counts[name] += count
+	cw.points[name] += count * weight
+}
+
+func (cw *complexityWalker) statement(stmt parser.Statement, depth int, scopes []map[string]bool) {
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		cw.query(s, depth, scopes)
+	case *parser.SetOperation:
+		cw.add("set_operations", 1, cw.weights.SetOperation)
+		cw.statement(s.Left, depth, scopes)
+		cw.statement(s.Right, depth, scopes)
+	case *parser.WithStatement:
+		for _, cte := range s.CTEs {
+			cw.ctes[strings.ToLower(cte.Name)] = true
+		}
+		cw.add("ctes", len(s.CTEs), cw.weights.CTE)
+		for _, cte := range s.CTEs {
+			if s.Recursive && cteIsRecursive(cte) {
+				cw.add("recursive_ctes", 1, cw.weights.RecursiveCTE)
+			}
+			cw.statement(cte.Query, depth, scopes)
+		}
+		cw.statement(s.Query, depth, scopes)
+	case *parser.InsertStatement:
+		cw.table(&s.Table)
+		for _, row := range s.Values {
+			for _, value := range row {
+				cw.expression(value, depth, scopes, false)
+			}
+		}
+		if s.Select != nil {
+			cw.query(s.Select, depth, scopes)
+		}
+	case *parser.UpdateStatement:
+		scope := map[string]bool{}
+		addQualifier(scope, &s.Table)
+		cw.table(&s.Table)
+		scopes = append(scopes, scope)
+		for _, assignment := range s.Set {
+			cw.expression(assignment.Value, depth, scopes, false)
+		}
+		cw.expression(s.Where, depth, scopes, true)
+	case *parser.DeleteStatement:
+		scope := map[string]bool{}
+		addQualifier(scope, &s.From)
+		cw.table(&s.From)
+		cw.expression(s.Where, depth, append(scopes, scope), true)
+	case *parser.MergeStatement:
+		scope := map[string]bool{}
+		addQualifier(scope, &s.TargetTable)
+		cw.table(&s.TargetTable)
+		switch source := s.SourceTable.(type) {
+		case *parser.TableReference:
+			cw.table(source)
+			addQualifier(scope, source)
+		case parser.TableReference:
+			cw.table(&source)
+			addQualifier(scope, &source)
+		case *parser.SelectStatement:
+			cw.subquery(source, depth, scopes)
+		}
+		if s.SourceAlias != "" {
+			scope[strings.ToLower(s.SourceAlias)] = true
+		}
+		cw.add("joins", 1, cw.weights.Join)
+		scopes = append(scopes, scope)
+		cw.expression(s.OnCondition, depth, scopes, true)
+		for _, clauses := range [][]*parser.MergeWhenClause{s.WhenMatched, s.WhenNotMatched, s.WhenNotMatchedBy} {
+			for _, when := range clauses {
+				cw.expression(when.Condition, depth, scopes, true)
+				if when.Action != nil {
+					for _, value := range when.Action.Values {
+						cw.expression(value, depth, scopes, false)
+					}
+				}
+			}
+		}
+	}
+}
+
+// cteIsRecursive reports whether a CTE reads itself
+func cteIsRecursive(cte *parser.CommonTableExpression) bool {
+	var reads func(parser.Statement) bool
+	reads = func(stmt parser.Statement) bool {
+		switch s := stmt.(type) {
+		case *parser.SetOperation:
+			return reads(s.Left) || reads(s.Right)
+		case *parser.SelectStatement:
+			for _, t := range queryTableRefs(s) {
+				if strings.EqualFold(t.Name, cte.Name) && t.Schema == "" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return reads(cte.Query)
+}
+
+// table counts a table reference: a table, a CTE read or a derived table
+func (cw *complexityWalker) table(ref *parser.TableReference) {
+	if ref.Subquery != nil || ref.Name == "" {
+		return
+	}
+	if ref.Schema == "" && cw.ctes[strings.ToLower(ref.Name)] {
+		cw.add("cte_references", 1, cw.weights.CTEReference)
+		return
+	}
+	cw.add("tables", 1, cw.weights.Table)
+}
+
+func addQualifier(scope map[string]bool, ref *parser.TableReference) {
+	if ref.Alias != "" {
+		scope[strings.ToLower(ref.Alias)] = true
+	} else if ref.Name != "" {
+		scope[strings.ToLower(ref.Name)] = true
+	}
+}
+
+func queryTableRefs(sel *parser.SelectStatement) []*parser.TableReference {
+	var refs []*parser.TableReference
+	if sel.From != nil {
+		for i := range sel.From.Tables {
+			refs = append(refs, &sel.From.Tables[i])
+		}
+	}
+	for _, join := range sel.Joins {
+		refs = append(refs, &join.Table)
+	}
+	return refs
+}
+
+// query counts a query block at a subquery nesting depth
+func (cw *complexityWalker) query(sel *parser.SelectStatement, depth int, scopes []map[string]bool) {
+	refs := queryTableRefs(sel)
+	scope := make(map[string]bool)
+	for _, ref := range refs {
+		addQualifier(scope, ref)
+	}
+	scopes = append(scopes, scope)
+
+	for _, ref := range refs {
+		if ref.Subquery != nil {
+			// A derived table is a subquery; LATERAL ones may see this block
+			cw.subquery(ref.Subquery, depth, scopes[:len(scopes)-1])
+			continue
+		}
+		cw.table(ref)
+	}
+	cw.add("joins", len(sel.Joins), cw.weights.Join)
+	cw.joinShape(sel, refs)
+
+	cw.add("select_columns", len(sel.Columns), cw.weights.SelectColumn)
+	for _, col := range sel.Columns {
+		cw.expression(col, depth, scopes, false)
+	}
+	for _, join := range sel.Joins {
+		cw.expression(join.Condition, depth, scopes, true)
+	}
+	cw.expression(sel.Where, depth, scopes, true)
+	for _, expr := range sel.GroupBy {
+		cw.expression(expr, depth, scopes, false)
+	}
+	cw.expression(sel.Having, depth, scopes, true)
+	cw.expression(sel.Qualify, depth, scopes, true)
+	for _, item := range sel.OrderBy {
+		cw.expression(item.Expression, depth, scopes, false)
+	}
+}
+
+// subquery counts a nested query one level below depth
+func (cw *complexityWalker) subquery(sub parser.Statement, depth int, scopes []map[string]bool) {
+	if sub == nil {
+		return
+	}
+	depth++
+	cw.maxDepth = max(cw.maxDepth, depth)
+	cw.add("subqueries", 1, 0)
+	cw.points["subqueries"] += depth * cw.weights.Subquery
+	if len(scopes) > 0 && isCorrelated(sub, scopes) {
+		cw.add("correlated_subqueries", 1, cw.weights.CorrelatedSubquery)
+	}
+	cw.statement(sub, depth, scopes)
+}
+
+// isCorrelated reports whether a subquery's qualified columns name a table
+// of an enclosing query block rather than one of its own
+func isCorrelated(sub parser.Statement, outer []map[string]bool) bool {
+	sel, ok := sub.(*parser.SelectStatement)
+	if !ok {
+		return false
+	}
+	own := make(map[string]bool)
+	for _, ref := range queryTableRefs(sel) {
+		addQualifier(own, ref)
+	}
+
+	correlated := false
+	visit := func(expr parser.Expression) bool {
+		if col, ok := expr.(*parser.ColumnReference); ok && col.Table != "" {
+			q := strings.ToLower(col.Table)
+			if !own[q] {
+				for _, scope := range outer {
+					if scope[q] {
+						correlated = true
+					}
+				}
+			}
+		}
+		return !correlated
+	}
+	exprs := append([]parser.Expression{sel.Where, sel.Having}, sel.Columns...)
+	for _, join := range sel.Joins {
+		exprs = append(exprs, join.Condition)
+	}
+	for _, expr := range exprs {
+		walkPredicates(expr, visit)
+	}
+	return correlated
+}
+
+// expression counts the predicates, subqueries, window functions and CASE
+// branches of an expression. In a condition, comparisons count as
+// predicates.
+func (cw *complexityWalker) expression(expr parser.Expression, depth int, scopes []map[string]bool, condition bool) {
+	if expr == nil {
+		return
+	}
+	switch e := expr.(type) {
+	case *parser.BinaryExpression:
+		switch e.Operator {
+		case "AND", "OR":
+		default:
+			if condition && isPredicateOperator(e.Operator) {
+				cw.add("predicates", 1, cw.weights.Predicate)
+			}
+		}
+		cw.expression(e.Left, depth, scopes, condition)
+		cw.expression(e.Right, depth, scopes, condition)
+	case *parser.UnaryExpression:
+		cw.expression(e.Operand, depth, scopes, condition)
+	case *parser.InExpression:
+		if condition {
+			cw.add("predicates", 1, cw.weights.Predicate)
+		}
+		cw.expression(e.Expression, depth, scopes, false)
+		for _, value := range e.Values {
+			cw.expression(value, depth, scopes, false)
+		}
+	case *parser.BetweenExpression:
+		if condition {
+			cw.add("predicates", 1, cw.weights.Predicate)
+		}
+		cw.expression(e.Expression, depth, scopes, false)
+		cw.expression(e.Low, depth, scopes, false)
+		cw.expression(e.High, depth, scopes, false)
+	case *parser.ExistsExpression:
+		if condition {
+			cw.add("predicates", 1, cw.weights.Predicate)
+		}
+		cw.subquery(e.Subquery, depth, scopes)
+	case *parser.SubqueryExpression:
+		cw.subquery(e.Query, depth, scopes)
+	case *parser.AliasedExpression:
+		cw.expression(e.Expression, depth, scopes, condition)
+	case *parser.FunctionCall:
+		for _, arg := range e.Arguments {
+			cw.expression(arg, depth, scopes, false)
+		}
+		cw.expression(e.Filter, depth, scopes, true)
+	case *parser.WindowFunction:
+		cw.add("window_functions", 1, cw.weights.WindowFunction)
+		if e.Function != nil {
+			cw.expression(e.Function, depth, scopes, false)
+		}
+	case *parser.CaseExpression:
+		branches := len(e.WhenClauses)
+		if e.ElseResult != nil {
+			branches++
+		}
+		cw.add("case_branches", branches, cw.weights.CaseBranch)
+		cw.expression(e.Input, depth, scopes, false)
+		for _, when := range e.WhenClauses {
+			cw.expression(when.Condition, depth, scopes, e.Input == nil)
+			cw.expression(when.Result, depth, scopes, false)
+		}
+		cw.expression(e.ElseResult, depth, scopes, false)
+	case *parser.CastExpression:
+		cw.expression(e.Expression, depth, scopes, false)
+	}
+}
+
+func isPredicateOperator(op string) bool {
+	if predicateComparisons[op] {
+		return true
+	}
+	switch op {
+	case "IS", "IS NOT", "IS DISTINCT FROM", "IS NOT DISTINCT FROM", "REGEXP", "RLIKE", "GLOB", "SIMILAR TO":
+		return true
+	}
+	return false
+}
+
+// joinShape classifies the graph whose nodes are a query block's tables
+// and whose edges are the conditions between two of them, and keeps the
+// most complex shape seen
+func (cw *complexityWalker) joinShape(sel *parser.SelectStatement, refs []*parser.TableReference) {
+	if len(refs) < 2 {
+		return
+	}
+
+	index := make(map[string]int)
+	for i, ref := range refs {
+		if ref.Alias != "" {
+			index[strings.ToLower(ref.Alias)] = i
+		} else {
+			index[strings.ToLower(ref.Name)] = i
+		}
+	}
+	edges := make(map[[2]int]bool)
+	connect := func(a, b int) {
+		if a > b {
+			a, b = b, a
+		}
+		if a != b {
+			edges[[2]int{a, b}] = true
+		}
+	}
+
+	conditions := splitConjuncts(sel.Where)
+	for i, join := range sel.Joins {
+		right := len(refs) - len(sel.Joins) + i
+		switch {
+		case len(join.Using) > 0 || join.Natural:
+			// USING and NATURAL join the previous tables
+			connect(right-1, right)
+		case join.Lateral || join.IsApply():
+			connect(right-1, right)
+		}
+		conditions = append(conditions, splitConjuncts(join.Condition)...)
+	}
+	for _, cond := range conditions {
+		cols, ok := expressionColumns(cond)
+		if !ok {
+			continue
+		}
+		var tables []int
+		for _, col := range cols {
+			if i, ok := index[strings.ToLower(col.Table)]; ok && col.Table != "" {
+				tables = append(tables, i)
+			}
+		}
+		for i := 1; i < len(tables); i++ {
+			connect(tables[0], tables[i])
+		}
+	}
+
+	// Connected components, and the largest degree
+	parent := make([]int, len(refs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	degree := make([]int, len(refs))
+	for edge := range edges {
+		degree[edge[0]]++
+		degree[edge[1]]++
+		parent[find(edge[0])] = find(edge[1])
+	}
+	components := 0
+	for i := range parent {
+		if find(i) == i {
+			components++
+		}
+	}
+	maxDegree := 0
+	for _, d := range degree {
+		maxDegree = max(maxDegree, d)
+	}
+
+	shape := JoinShapeChain
+	switch {
+	case components > 1:
+		shape = JoinShapeDisconnected
+	case len(edges) >= len(refs):
+		shape = JoinShapeCyclic
+	case maxDegree >= 3:
+		shape = JoinShapeStar
+	}
+	if joinShapeRank[shape] > joinShapeRank[cw.shape] {
+		cw.shape = shape
+	}
+
+	switch shape {
+	case JoinShapeStar:
+		cw.add("star_join", 1, cw.weights.StarJoin)
+	case JoinShapeCyclic:
+		cw.add("cyclic_join", 1, cw.weights.CyclicJoin)
+	case JoinShapeDisconnected:
+		cw.add("disconnected_join", 1, cw.weights.DisconnectedJoin)
+	}
+}
@@ -3,15 +3,19 @@ package analyzer
 import (
 	"context"
 	"runtime"
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
 )
 
 // ConcurrentAnalyzer performs analysis of multiple queries concurrently
 type ConcurrentAnalyzer struct {
 	workerCount int
-	cache       map[string]QueryAnalysis
+	cache       *AnalysisCache
 	mu          sync.RWMutex
 }
 
@@ -23,110 +27,58 @@ func NewConcurrentAnalyzer(workerCount int) *ConcurrentAnalyzer {
 
 	return &ConcurrentAnalyzer{
 		workerCount: workerCount,
-		cache:       make(map[string]QueryAnalysis),
+		cache:       NewAnalysisCache(CacheConfig{}),
 	}
 }
 
-// AnalysisJob represents a single analysis job
+// SetCache sets the cache the workers share, e.g. one also used by a
+// monitor.LogProcessor
+func (ca *ConcurrentAnalyzer) SetCache(cache *AnalysisCache) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	ca.cache = cache
+}
+
+// AnalysisJob represents a single analysis job: the statements of Query,
+// or the pre-parsed Stmt of the text Query. The analysis of a statement
+// is cached under the fingerprint of its text, or under ID when Query is
+// empty.
 type AnalysisJob struct {
-	ID    string
-	Query string
-	Stmt  parser.Statement
+	ID      string
+	Query   string
+	Stmt    parser.Statement
+	Dialect dialect.Dialect // nil for the pipeline's dialect
 }
 
 // AnalysisResult represents the result of an analysis job
 type AnalysisResult struct {
 	ID       string
+	Index    int    // position of the job in the stream
+	Dialect  string // dialect the query was parsed with
 	Analysis QueryAnalysis
-	Error    error
+	Analyses []QueryAnalysis // one per statement; Analysis is the first
+	// QueryFingerprint of each statement's text, for baselines; empty
+	// for a job's pre-parsed Stmt without Query
+	Fingerprints []string
+	// Names and types the schema doesn't have, when the pipeline has one
+	Validation []*schema.ValidationError
+	Duration   time.Duration
+	Error      error
 }
 
-// AnalyzeConcurrently analyzes multiple queries concurrently
+// AnalyzeConcurrently analyzes multiple queries concurrently. Jobs cancelled
+// by ctx before they ran have its error.
 func (ca *ConcurrentAnalyzer) AnalyzeConcurrently(ctx context.Context, jobs []AnalysisJob) []AnalysisResult {
-	jobChan := make(chan AnalysisJob, len(jobs))
-	resultChan := make(chan AnalysisResult, len(jobs))
-
-	// Start workers
-	var wg sync.WaitGroup
-	for i := 0; i < ca.workerCount; i++ {
-		wg.Add(1)
-		go ca.worker(ctx, &wg, jobChan, resultChan)
-	}
-
-	// Send jobs
-	go func() {
-		defer close(jobChan)
-		for _, job := range jobs {
-			select {
-			case jobChan <- job:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-
-	// Collect results
 	results := make([]AnalysisResult, 0, len(jobs))
-	for i := 0; i < len(jobs); i++ {
-		select {
-		case result := <-resultChan:
-			results = append(results, result)
-		case <-ctx.Done():
-			results = append(results, AnalysisResult{
-				Error: ctx.Err(),
-			})
-		}
+	for result := range ca.AnalyzeSeq(ctx, slices.Values(jobs), PipelineOptions{}) {
+		results = append(results, result)
 	}
-
-	// Wait for workers to finish
-	wg.Wait()
-	close(resultChan)
-
-	return results
-}
-
-// worker processes analysis jobs
-func (ca *ConcurrentAnalyzer) worker(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan AnalysisJob, resultChan chan<- AnalysisResult) {
-	defer wg.Done()
-
-	analyzer := New()
-
-	for {
-		select {
-		case job, ok := <-jobChan:
-			if !ok {
-				return
-			}
-
-			// Check cache first
-			ca.mu.RLock()
-			if cached, exists := ca.cache[job.ID]; exists {
-				ca.mu.RUnlock()
-				resultChan <- AnalysisResult{
-					ID:       job.ID,
-					Analysis: cached,
-				}
-				continue
-			}
-			ca.mu.RUnlock()
-
-			// Perform analysis
-			analysis := analyzer.Analyze(job.Stmt)
-
-			// Cache result
-			ca.mu.Lock()
-			ca.cache[job.ID] = analysis
-			ca.mu.Unlock()
-
-			resultChan <- AnalysisResult{
-				ID:       job.ID,
-				Analysis: analysis,
-			}
-
-		case <-ctx.Done():
-			return
-		}
+	for len(results) < len(jobs) {
+		results = append(results, AnalysisResult{
+			Error: ctx.Err(),
+		})
 	}
+	return results
 }
 
 // GetCacheStats returns cache statistics
@@ -134,16 +86,22 @@ func (ca *ConcurrentAnalyzer) GetCacheStats() map[string]interface{} {
 	ca.mu.RLock()
 	defer ca.mu.RUnlock()
 
+	stats := ca.cache.Stats()
 	return map[string]interface{}{
-		"cache_size":   len(ca.cache),
-		"worker_count": ca.workerCount,
+		"cache_size":        stats.Entries,
+		"cache_bytes":       stats.Bytes,
+		"cache_hits":        stats.Hits,
+		"cache_misses":      stats.Misses,
+		"cache_evictions":   stats.Evictions,
+		"cache_expirations": stats.Expirations,
+		"worker_count":      ca.workerCount,
 	}
 }
 
 // ClearCache clears the analysis cache
 func (ca *ConcurrentAnalyzer) ClearCache() {
-	ca.mu.Lock()
-	defer ca.mu.Unlock()
+	ca.mu.RLock()
+	defer ca.mu.RUnlock()
 
-	ca.cache = make(map[string]QueryAnalysis)
+	ca.cache.Clear()
 }
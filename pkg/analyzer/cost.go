@@ -0,0 +1,248 @@
+package analyzer
+
+import (
+	"math"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// The cost estimate reads the row counts and distinct value counts of a
+// schema file. Each table's rows are scaled by the selectivity of the
+// WHERE conjuncts comparing one of its columns with constants:
+//
+//	=, <=>           1 / distinct values (1 / rows for a unique column)
+//	IN (n values)    n / distinct values
+//	<>, NOT IN       1 - the selectivity of = or IN
+//	<, >, <=, >=     1/3
+//	BETWEEN          1/4
+//	LIKE, IS NULL    1/10
+//
+// A column without distinct values counts 10 of them. Joined tables are
+// multiplied and scaled by 1 / the larger distinct value count of each
+// equality between them. The cost is the rows read: a table whose filter
+// can seek an index reads its filtered rows plus log2 of its rows, other
+// tables are scanned. Subqueries are left out.
+
+const (
+	defaultDistinctValues = 10
+	rangeSelectivity      = 1.0 / 3
+	betweenSelectivity    = 1.0 / 4
+	defaultSelectivity    = 1.0 / 10
+)
+
+// CostEstimate is the estimated result size and work of a statement
+type CostEstimate struct {
+	Rows   int64           `json:"rows"`
+	Cost   float64         `json:"cost"`
+	Tables []TableEstimate `json:"tables,omitempty"`
+}
+
+// TableEstimate is the estimate for one table of a statement
+type TableEstimate struct {
+	Table       string  `json:"table"`
+	Rows        int64   `json:"rows"`
+	Selectivity float64 `json:"selectivity"`
+	Seek        bool    `json:"seek"` // the filter can seek an index
+	Cost        float64 `json:"cost"`
+}
+
+// EstimateCost estimates a SELECT, UPDATE or DELETE from schema statistics.
+// It returns false when a table it reads has no row count. Set operations
+// add their sides; a WITH statement is estimated by its main query.
+func EstimateCost(stmt parser.Statement, d dialect.Dialect, s *schema.Schema) (CostEstimate, bool) {
+	if s == nil {
+		return CostEstimate{}, false
+	}
+	switch st := stmt.(type) {
+	case *parser.WithStatement:
+		return EstimateCost(st.Query, d, s)
+	case *parser.SetOperation:
+		left, ok := EstimateCost(st.Left, d, s)
+		if !ok {
+			return CostEstimate{}, false
+		}
+		right, ok := EstimateCost(st.Right, d, s)
+		if !ok {
+			return CostEstimate{}, false
+		}
+		return CostEstimate{
+			Rows:   left.Rows + right.Rows,
+			Cost:   left.Cost + right.Cost,
+			Tables: append(left.Tables, right.Tables...),
+		}, true
+	}
+
+	accesses, conditions, joins := statementAccesses(stmt, schema.NewResolver(d, s))
+	if len(accesses) == 0 {
+		return CostEstimate{}, false
+	}
+	for _, ta := range accesses {
+		if ta.table == nil || ta.table.Rows <= 0 {
+			return CostEstimate{}, false
+		}
+	}
+
+	selectivity := make([]float64, len(accesses))
+	seek := make([]bool, len(accesses))
+	for i := range selectivity {
+		selectivity[i] = 1
+	}
+	joinSelectivity := 1.0
+	for _, cond := range append(conditions, joins...) {
+		if sel, ok := equiJoinSelectivity(cond, accesses); ok {
+			joinSelectivity *= sel
+			continue
+		}
+		c, ok := asComparison(cond)
+		if !ok {
+			if bin, isBinary := cond.(*parser.BinaryExpression); isBinary && (bin.Operator == "IS" || bin.Operator == "IS NOT") {
+				c = comparison{bin.Operator, bin.Left, []parser.Expression{bin.Right}, false}
+			} else {
+				continue
+			}
+		}
+		col, isColumn := c.operand.(*parser.ColumnReference)
+		values := c.values
+		if !isColumn && c.binary {
+			col, isColumn = c.values[0].(*parser.ColumnReference)
+			values = []parser.Expression{c.operand}
+		}
+		if !isColumn || !allConstant(values) {
+			continue
+		}
+		ta := columnOwner(col, accesses)
+		if ta == nil {
+			continue
+		}
+		i := accessIndex(accesses, ta)
+		sel, seekable := predicateSelectivity(ta.table, col.Column, c.operator, values)
+		selectivity[i] *= sel
+		seek[i] = seek[i] || (seekable && leadsIndex(ta.table, col.Column))
+	}
+
+	var estimate CostEstimate
+	rows := 1.0
+	for i, ta := range accesses {
+		tableRows := float64(ta.table.Rows)
+		filtered := tableRows * selectivity[i]
+		cost := tableRows
+		if seek[i] {
+			cost = filtered + math.Log2(tableRows+1)
+		}
+		rows *= filtered
+		estimate.Cost += cost
+		estimate.Tables = append(estimate.Tables, TableEstimate{
+			Table:       ta.name(),
+			Rows:        int64(math.Ceil(filtered)),
+			Selectivity: selectivity[i],
+			Seek:        seek[i],
+			Cost:        cost,
+		})
+	}
+	if len(accesses) > 1 {
+		rows *= joinSelectivity
+	}
+	estimate.Rows = int64(math.Ceil(rows))
+	if sel, ok := stmt.(*parser.SelectStatement); ok {
+		if limit, ok := rowLimit(sel.Limit); ok && limit < estimate.Rows {
+			estimate.Rows = limit
+		}
+	}
+	return estimate, true
+}
+
+func allConstant(exprs []parser.Expression) bool {
+	for _, expr := range exprs {
+		if !isConstantExpression(expr) {
+			return false
+		}
+	}
+	return true
+}
+
+// distinctValues returns the number of distinct values of a column
+func distinctValues(table *schema.Table, column string) float64 {
+	col, ok := table.GetColumn(column)
+	switch {
+	case !ok:
+		return defaultDistinctValues
+	case col.Distinct > 0:
+		return float64(col.Distinct)
+	case col.IsPrimaryKey || col.IsUnique:
+		return float64(max(table.Rows, 1))
+	}
+	return defaultDistinctValues
+}
+
+// predicateSelectivity returns the fraction of rows a comparison of a
+// column with constants keeps, and whether an index on the column can seek
+// it
+func predicateSelectivity(table *schema.Table, column, operator string, values []parser.Expression) (float64, bool) {
+	equality := 1 / distinctValues(table, column)
+	switch operator {
+	case "=", "<=>":
+		return equality, true
+	case "<>", "!=":
+		return 1 - equality, false
+	case "IN":
+		return math.Min(1, float64(len(values))*equality), true
+	case "NOT IN":
+		return math.Max(0, 1-float64(len(values))*equality), false
+	case "<", ">", "<=", ">=":
+		return rangeSelectivity, true
+	case "BETWEEN":
+		return betweenSelectivity, true
+	case "NOT BETWEEN":
+		return 1 - betweenSelectivity, false
+	case "LIKE", "ILIKE":
+		lit, ok := values[0].(*parser.Literal)
+		pattern, isString := "", false
+		if ok {
+			pattern, isString = lit.Value.(string)
+		}
+		return defaultSelectivity, isString && pattern != "" && !strings.HasPrefix(pattern, "%") && !strings.HasPrefix(pattern, "_")
+	case "NOT LIKE", "NOT ILIKE", "IS NOT":
+		return 1 - defaultSelectivity, false
+	case "IS":
+		return defaultSelectivity, true
+	}
+	return 1, false
+}
+
+// equiJoinSelectivity returns the selectivity of an equality between
+// columns of two different tables
+func equiJoinSelectivity(cond parser.Expression, accesses []*tableAccess) (float64, bool) {
+	bin, ok := cond.(*parser.BinaryExpression)
+	if !ok || bin.Operator != "=" {
+		return 0, false
+	}
+	left, ok := bin.Left.(*parser.ColumnReference)
+	if !ok {
+		return 0, false
+	}
+	right, ok := bin.Right.(*parser.ColumnReference)
+	if !ok {
+		return 0, false
+	}
+	lt, rt := columnOwner(left, accesses), columnOwner(right, accesses)
+	if lt == nil || rt == nil || lt == rt {
+		return 0, false
+	}
+	return 1 / math.Max(distinctValues(lt.table, left.Column), distinctValues(rt.table, right.Column)), true
+}
+
+// rowLimit returns a constant LIMIT, TOP or FETCH count
+func rowLimit(limit *parser.RowLimit) (int64, bool) {
+	if limit == nil || limit.Percent {
+		return 0, false
+	}
+	lit, ok := limit.Count.(*parser.Literal)
+	if !ok {
+		return 0, false
+	}
+	n, ok := lit.Value.(int64)
+	return n, ok
+}
@@ -1,12 +1,24 @@
 package analyzer
 
+import "github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+
 type QueryAnalysis struct {
 	Tables     []TableInfo     `json:"tables"`
 	Columns    []ColumnInfo    `json:"columns"`
 	Joins      []JoinInfo      `json:"joins"`
 	Conditions []ConditionInfo `json:"conditions"`
-	QueryType  string          `json:"query_type"`
-	Complexity int             `json:"complexity"`
+	// CTEs defined by the statement
+	CTEs       []CTEInfo `json:"ctes,omitempty"`
+	QueryType  string    `json:"query_type"`
+	Complexity int       `json:"complexity"`
+	// Row limit in any dialect syntax (LIMIT, TOP, FETCH, ROWNUM)
+	Pagination *PaginationInfo `json:"pagination,omitempty"`
+	// Grouping sets produced by ROLLUP, CUBE, GROUPING SETS or WITH ROLLUP
+	GroupingSets [][]string `json:"grouping_sets,omitempty"`
+	// Parser warnings, e.g. a built-in function the dialect version lacks
+	Warnings []string `json:"warnings,omitempty"`
+	// Set when the dialect was detected from the query text
+	DetectedDialect *dialect.Guess `json:"detected_dialect,omitempty"`
 	// Performance metrics
 	Performance *PerformanceMetrics `json:"performance,omitempty"`
 	// Enhanced optimization suggestions
@@ -15,24 +27,40 @@ type QueryAnalysis struct {
 	Suggestions []OptimizationSuggestion `json:"suggestions,omitempty"`
 }
 
+type PaginationInfo struct {
+	Syntax   string `json:"syntax"` // LIMIT, TOP, FETCH, ROWNUM
+	Count    string `json:"count,omitempty"`
+	Offset   string `json:"offset,omitempty"`
+	Percent  bool   `json:"percent,omitempty"`
+	WithTies bool   `json:"with_ties,omitempty"`
+}
+
 type TableInfo struct {
-	Schema string `json:"schema,omitempty"`
-	Name   string `json:"name"`
-	Alias  string `json:"alias,omitempty"`
-	Usage  string `json:"usage"` // SELECT, UPDATE, DELETE, INSERT
+	Server  string `json:"server,omitempty"`
+	Catalog string `json:"catalog,omitempty"`
+	Schema  string `json:"schema,omitempty"`
+	Name    string `json:"name"`
+	Alias   string `json:"alias,omitempty"`
+	Usage   string `json:"usage"`           // SELECT, UPDATE, DELETE, INSERT
+	CTE     bool   `json:"cte,omitempty"`   // reads a CTE rather than a table
+	Scope   string `json:"scope,omitempty"` // nested query path; see scope.go
 }
 
 type ColumnInfo struct {
 	Table string `json:"table,omitempty"`
 	Name  string `json:"name"`
 	Usage string `json:"usage"` // SELECT, WHERE, JOIN, ORDER_BY, GROUP_BY
+	Scope string `json:"scope,omitempty"`
 }
 
 type JoinInfo struct {
-	Type       string `json:"type"`
-	LeftTable  string `json:"left_table"`
-	RightTable string `json:"right_table"`
-	Condition  string `json:"condition"`
+	Type       string   `json:"type"`
+	LeftTable  string   `json:"left_table"`
+	RightTable string   `json:"right_table"`
+	Condition  string   `json:"condition"`
+	Using      []string `json:"using,omitempty"`
+	Natural    bool     `json:"natural,omitempty"`
+	Lateral    bool     `json:"lateral,omitempty"`
 }
 
 type ConditionInfo struct {
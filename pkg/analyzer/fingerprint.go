@@ -0,0 +1,30 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+)
+
+// QueryFingerprint hashes the tokens of a query, so that whitespace,
+// comments and the case of keywords don't change it. Literals are kept:
+// queries that differ in a value are analyzed apart.
+func QueryFingerprint(sql string, d dialect.Dialect) string {
+	if d == nil {
+		d = dialect.GetDialect("sqlserver")
+	}
+	h := sha256.New()
+	l := lexer.NewWithDialect(sql, d)
+	for tok := l.NextToken(); tok.Type != lexer.EOF; tok = l.NextToken() {
+		literal := tok.Literal
+		if tok.Type != lexer.IDENT && tok.Type != lexer.STRING {
+			literal = strings.ToUpper(literal)
+		}
+		fmt.Fprintf(h, "%d:%d:%s\x00", tok.Type, tok.Kind, literal)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
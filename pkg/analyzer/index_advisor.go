@@ -0,0 +1,704 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// maxIncludeColumns bounds the covering columns proposed for one index
+const maxIncludeColumns = 5
+
+// maxIndexNameLength is the longest index name generated (PostgreSQL's limit)
+const maxIndexNameLength = 63
+
+// IndexRecommendation is an index proposed for the queries that would use it
+type IndexRecommendation struct {
+	Table     string   `json:"table"`
+	Columns   []string `json:"columns"`           // key columns: equality, then range, then sort
+	Include   []string `json:"include,omitempty"` // covering columns, where the dialect has INCLUDE
+	DDL       string   `json:"ddl"`
+	Reason    string   `json:"reason"`
+	Supersede string   `json:"supersedes,omitempty"` // existing index the new one extends
+	Frequency int      `json:"frequency"`            // queries that would use it
+}
+
+// IndexAdvisor proposes indexes from the sargable predicates, join
+// conditions and sort columns of queries. With a schema it resolves
+// unqualified columns and leaves out indexes whose leftmost columns an
+// existing index already provides.
+type IndexAdvisor struct {
+	dialect  dialect.Dialect
+	resolver *schema.Resolver
+	workload map[string]*IndexRecommendation // table + key columns -> recommendation
+}
+
+// NewIndexAdvisor creates an index advisor; the schema may be nil
+func NewIndexAdvisor(d dialect.Dialect, s *schema.Schema) *IndexAdvisor {
+	ia := &IndexAdvisor{dialect: d, workload: make(map[string]*IndexRecommendation)}
+	if s != nil {
+		ia.resolver = schema.NewResolver(d, s)
+	}
+	return ia
+}
+
+// tableAccess is how a query reads one table
+type tableAccess struct {
+	ref      *parser.TableReference
+	table    *schema.Table // nil without a schema
+	equality []string
+	ranges   []string
+	sort     []string
+	reads    []string // every column read, for covering
+	readsAll bool     // * or columns that couldn't be told apart
+	reasons  []string
+}
+
+func (ta *tableAccess) name() string {
+	if ta.ref.Schema != "" {
+		return ta.ref.Schema + "." + ta.ref.Name
+	}
+	return ta.ref.Name
+}
+
+func (ta *tableAccess) qualifier() string {
+	if ta.ref.Alias != "" {
+		return ta.ref.Alias
+	}
+	return ta.ref.Name
+}
+
+// Advise returns the indexes that would serve one statement
+func (ia *IndexAdvisor) Advise(stmt parser.Statement) []IndexRecommendation {
+	accesses, conditions, joins := statementAccesses(stmt, ia.resolver)
+	if len(accesses) == 0 {
+		return nil
+	}
+
+	for _, cond := range conditions {
+		ia.addPredicate(cond, accesses, "WHERE")
+	}
+	for _, cond := range joins {
+		ia.addPredicate(cond, accesses, "JOIN")
+	}
+	if sel, ok := stmt.(*parser.SelectStatement); ok {
+		ia.addSortColumns(sel, accesses)
+		ia.addReads(sel, accesses)
+	}
+
+	var recs []IndexRecommendation
+	for _, ta := range accesses {
+		if rec, ok := ia.recommend(ta); ok {
+			recs = append(recs, rec)
+		}
+	}
+	return recs
+}
+
+// Add records a statement of a workload and returns its recommendations
+func (ia *IndexAdvisor) Add(stmt parser.Statement) []IndexRecommendation {
+	recs := ia.Advise(stmt)
+	for _, rec := range recs {
+		key := strings.ToLower(rec.Table + "(" + strings.Join(rec.Columns, ",") + ")")
+		if existing, ok := ia.workload[key]; ok {
+			existing.Frequency++
+			existing.Include = mergeColumns(existing.Include, rec.Include)
+			continue
+		}
+		r := rec
+		r.Frequency = 1
+		ia.workload[key] = &r
+	}
+	return recs
+}
+
+// Recommendations returns the indexes for the workload added so far, most
+// used first. An index whose columns lead another's is folded into it.
+func (ia *IndexAdvisor) Recommendations() []IndexRecommendation {
+	recs := make([]*IndexRecommendation, 0, len(ia.workload))
+	for _, rec := range ia.workload {
+		r := *rec
+		recs = append(recs, &r)
+	}
+	// Longest keys first, so shorter ones fold into them
+	sort.Slice(recs, func(i, j int) bool {
+		if len(recs[i].Columns) != len(recs[j].Columns) {
+			return len(recs[i].Columns) > len(recs[j].Columns)
+		}
+		return indexKey(recs[i]) < indexKey(recs[j])
+	})
+
+	var kept []*IndexRecommendation
+	for _, rec := range recs {
+		folded := false
+		for _, longer := range kept {
+			if strings.EqualFold(longer.Table, rec.Table) && hasPrefix(longer.Columns, rec.Columns) {
+				longer.Frequency += rec.Frequency
+				longer.Include = mergeColumns(longer.Include, rec.Include)
+				folded = true
+				break
+			}
+		}
+		if !folded {
+			kept = append(kept, rec)
+		}
+	}
+
+	result := make([]IndexRecommendation, len(kept))
+	for i, rec := range kept {
+		if len(rec.Include) > maxIncludeColumns {
+			rec.Include = rec.Include[:maxIncludeColumns]
+		}
+		rec.DDL = ia.indexDDL(rec.Table, rec.Columns, rec.Include)
+		result[i] = *rec
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Frequency != result[j].Frequency {
+			return result[i].Frequency > result[j].Frequency
+		}
+		return indexKey(&result[i]) < indexKey(&result[j])
+	})
+	return result
+}
+
+func indexKey(rec *IndexRecommendation) string {
+	return strings.ToLower(rec.Table + "(" + strings.Join(rec.Columns, ",") + ")")
+}
+
+// addPredicate records the columns a condition lets an index seek on
+func (ia *IndexAdvisor) addPredicate(expr parser.Expression, accesses []*tableAccess, clause string) {
+	switch e := expr.(type) {
+	case *parser.BinaryExpression:
+		op := e.Operator
+		left, leftIsCol := e.Left.(*parser.ColumnReference)
+		right, rightIsCol := e.Right.(*parser.ColumnReference)
+
+		if leftIsCol && rightIsCol {
+			// A join: the table joined later is looked up by the values of
+			// the one before it
+			l, r := columnOwner(left, accesses), columnOwner(right, accesses)
+			if op != "=" || l == nil || r == nil || l == r {
+				return
+			}
+			inner, col, outer := r, right, left
+			if accessIndex(accesses, l) > accessIndex(accesses, r) {
+				inner, col, outer = l, left, right
+			}
+			inner.addEquality(col.Column, fmt.Sprintf("%s %s = %s", clause, col.Column, outer.String()))
+			return
+		}
+		if !leftIsCol && rightIsCol && isConstantExpression(e.Left) {
+			left, e = right, &parser.BinaryExpression{Left: e.Right, Operator: flipComparison(op), Right: e.Left}
+			leftIsCol = true
+		}
+		if !leftIsCol || !isConstantExpression(e.Right) {
+			return
+		}
+		ta := columnOwner(left, accesses)
+		if ta == nil {
+			return
+		}
+		switch e.Operator {
+		case "=":
+			ta.addEquality(left.Column, fmt.Sprintf("%s %s = ...", clause, left.Column))
+		case "<", ">", "<=", ">=":
+			ta.addRange(left.Column, fmt.Sprintf("%s %s %s ...", clause, left.Column, e.Operator))
+		case "LIKE":
+			// Only a fixed prefix can seek
+			if lit, ok := e.Right.(*parser.Literal); ok {
+				if pattern, ok := lit.Value.(string); ok && pattern != "" && pattern[0] != '%' && pattern[0] != '_' {
+					ta.addRange(left.Column, fmt.Sprintf("%s %s LIKE '%s'", clause, left.Column, pattern))
+				}
+			}
+		case "IS":
+			if isNullLiteral(e.Right) {
+				ta.addEquality(left.Column, fmt.Sprintf("%s %s IS NULL", clause, left.Column))
+			}
+		}
+	case *parser.InExpression:
+		col, ok := e.Expression.(*parser.ColumnReference)
+		if !ok || e.Not {
+			return
+		}
+		for _, value := range e.Values {
+			if !isConstantExpression(value) {
+				return
+			}
+		}
+		if ta := columnOwner(col, accesses); ta != nil {
+			ta.addEquality(col.Column, fmt.Sprintf("%s %s IN (...)", clause, col.Column))
+		}
+	case *parser.BetweenExpression:
+		col, ok := e.Expression.(*parser.ColumnReference)
+		if !ok || e.Not || !isConstantExpression(e.Low) || !isConstantExpression(e.High) {
+			return
+		}
+		if ta := columnOwner(col, accesses); ta != nil {
+			ta.addRange(col.Column, fmt.Sprintf("%s %s BETWEEN ...", clause, col.Column))
+		}
+	}
+}
+
+// addSortColumns records ORDER BY, or else GROUP BY, columns when they
+// all belong to one table, so that an index can return rows in order
+func (ia *IndexAdvisor) addSortColumns(sel *parser.SelectStatement, accesses []*tableAccess) {
+	var exprs []parser.Expression
+	clause := "ORDER BY"
+	for _, item := range sel.OrderBy {
+		exprs = append(exprs, item.Expression)
+	}
+	if len(exprs) == 0 {
+		exprs, clause = sel.GroupBy, "GROUP BY"
+	}
+	if len(exprs) == 0 {
+		return
+	}
+
+	var owner *tableAccess
+	var columns []string
+	for _, expr := range exprs {
+		col, ok := expr.(*parser.ColumnReference)
+		if !ok {
+			return
+		}
+		ta := columnOwner(col, accesses)
+		if ta == nil || (owner != nil && ta != owner) {
+			return
+		}
+		owner = ta
+		columns = append(columns, col.Column)
+	}
+	owner.sort = columns
+	owner.reasons = append(owner.reasons, clause+" "+strings.Join(columns, ", "))
+}
+
+// addReads records the columns a query reads from each table
+func (ia *IndexAdvisor) addReads(sel *parser.SelectStatement, accesses []*tableAccess) {
+	exprs := append([]parser.Expression{sel.Where, sel.Having}, sel.Columns...)
+	exprs = append(exprs, sel.GroupBy...)
+	for _, item := range sel.OrderBy {
+		exprs = append(exprs, item.Expression)
+	}
+	for _, join := range sel.Joins {
+		exprs = append(exprs, join.Condition)
+	}
+
+	for _, expr := range exprs {
+		if star, ok := expr.(*parser.StarExpression); ok {
+			for _, ta := range accesses {
+				if star.Table == "" || strings.EqualFold(star.Table, ta.qualifier()) {
+					ta.readsAll = true
+				}
+			}
+			continue
+		}
+		cols, ok := expressionColumns(expr)
+		if !ok {
+			for _, ta := range accesses {
+				ta.readsAll = true
+			}
+			return
+		}
+		for _, col := range cols {
+			if ta := columnOwner(col, accesses); ta != nil {
+				ta.reads = mergeColumns(ta.reads, []string{col.Column})
+			} else {
+				// A column we can't place may come from any table
+				for _, ta := range accesses {
+					ta.readsAll = true
+				}
+			}
+		}
+	}
+}
+
+// statementAccesses returns the tables a SELECT, UPDATE or DELETE reads,
+// resolved when there is a resolver, with its WHERE conditions and join
+// conditions. Derived tables and table functions are left out.
+func statementAccesses(stmt parser.Statement, resolver *schema.Resolver) (accesses []*tableAccess, conditions, joins []parser.Expression) {
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		if s.From != nil {
+			for i := range s.From.Tables {
+				accesses = append(accesses, &tableAccess{ref: &s.From.Tables[i]})
+			}
+		}
+		for _, join := range s.Joins {
+			accesses = append(accesses, &tableAccess{ref: &join.Table})
+			joins = append(joins, splitConjuncts(join.Condition)...)
+		}
+		conditions = splitConjuncts(s.Where)
+	case *parser.UpdateStatement:
+		accesses = []*tableAccess{{ref: &s.Table}}
+		conditions = splitConjuncts(s.Where)
+	case *parser.DeleteStatement:
+		accesses = []*tableAccess{{ref: &s.From}}
+		conditions = splitConjuncts(s.Where)
+	default:
+		return nil, nil, nil
+	}
+
+	kept := accesses[:0]
+	for _, ta := range accesses {
+		if ta.ref.Subquery != nil || ta.ref.Name == "" {
+			continue
+		}
+		if resolver != nil {
+			ta.table, _ = resolver.ResolveTable(ta.ref)
+		}
+		kept = append(kept, ta)
+	}
+	return kept, conditions, joins
+}
+
+func accessIndex(accesses []*tableAccess, ta *tableAccess) int {
+	for i, a := range accesses {
+		if a == ta {
+			return i
+		}
+	}
+	return -1
+}
+
+// columnOwner returns the table a column belongs to, or nil when it can't
+// be told
+func columnOwner(col *parser.ColumnReference, accesses []*tableAccess) *tableAccess {
+	if col.Table != "" {
+		for _, ta := range accesses {
+			if strings.EqualFold(col.Table, ta.qualifier()) {
+				return ta
+			}
+		}
+		return nil
+	}
+	if len(accesses) == 1 {
+		return accesses[0]
+	}
+
+	// With a schema, the one table that has the column
+	var found *tableAccess
+	for _, ta := range accesses {
+		if ta.table == nil {
+			return nil
+		}
+		if ta.table.HasColumn(col.Column) {
+			if found != nil {
+				return nil
+			}
+			found = ta
+		}
+	}
+	return found
+}
+
+func (ta *tableAccess) addEquality(column, reason string) {
+	if !containsFold(ta.equality, column) {
+		ta.equality = append(ta.equality, column)
+		ta.reasons = append(ta.reasons, reason)
+	}
+}
+
+func (ta *tableAccess) addRange(column, reason string) {
+	if !containsFold(ta.ranges, column) {
+		ta.ranges = append(ta.ranges, column)
+		ta.reasons = append(ta.reasons, reason)
+	}
+}
+
+// recommend builds the index for a table: equality columns, then the
+// first range column, then sort columns, covering the other columns read
+// where the dialect can INCLUDE them
+func (ia *IndexAdvisor) recommend(ta *tableAccess) (IndexRecommendation, bool) {
+	key := append([]string(nil), ta.equality...)
+	for _, col := range ta.ranges {
+		if !containsFold(key, col) {
+			key = append(key, col)
+			break // an index seeks on one range at most
+		}
+	}
+	for _, col := range ta.sort {
+		if !containsFold(key, col) {
+			key = append(key, col)
+		}
+	}
+	if len(key) == 0 {
+		return IndexRecommendation{}, false
+	}
+
+	rec := IndexRecommendation{
+		Table:   ta.name(),
+		Columns: key,
+		Reason:  strings.Join(ta.reasons, "; "),
+	}
+
+	if ta.table != nil {
+		if ta.table.HasUniqueKey(ta.equality) || ia.coveredByUniqueKey(ta) {
+			return IndexRecommendation{}, false
+		}
+		for _, idx := range existingIndexes(ta.table) {
+			n := leadingMatch(idx.Columns, key, len(ta.equality))
+			if n == len(key) {
+				return IndexRecommendation{}, false // already indexed
+			}
+			// A unique index or the primary key enforces a constraint, so
+			// it stays even when the new index leads with its columns
+			if n == len(idx.Columns) && !idx.IsUnique && rec.Supersede == "" {
+				rec.Supersede = idx.Name
+			}
+		}
+	}
+
+	if !ta.readsAll && ia.dialect != nil && ia.dialect.SupportsFeature(dialect.FeatureIndexInclude) {
+		for _, col := range ta.reads {
+			if !containsFold(key, col) {
+				rec.Include = append(rec.Include, col)
+			}
+		}
+		if len(rec.Include) > maxIncludeColumns {
+			rec.Include = nil // too wide to be worth covering
+		}
+	}
+
+	rec.DDL = ia.indexDDL(rec.Table, rec.Columns, rec.Include)
+	return rec, true
+}
+
+// coveredByUniqueKey reports whether a unique key lies within the equality
+// columns, so that the query looks up a single row already
+func (ia *IndexAdvisor) coveredByUniqueKey(ta *tableAccess) bool {
+	for _, idx := range existingIndexes(ta.table) {
+		if !idx.IsUnique {
+			continue
+		}
+		within := true
+		for _, col := range idx.Columns {
+			within = within && containsFold(ta.equality, col)
+		}
+		if within {
+			return true
+		}
+	}
+	return false
+}
+
+// existingIndexes returns a table's indexes, its primary key included
+func existingIndexes(table *schema.Table) []*schema.Index {
+	indexes := table.OrderedIndexes()
+	var primaryKey []string
+	for _, col := range table.OrderedColumns() {
+		if col.IsPrimaryKey {
+			primaryKey = append(primaryKey, col.Name)
+		}
+	}
+	if len(primaryKey) > 0 {
+		indexes = append(indexes, &schema.Index{Name: "PRIMARY KEY", Table: table.Name, Columns: primaryKey, IsUnique: true})
+	}
+	return indexes
+}
+
+// leadingMatch returns how many leading columns of an existing index
+// serve a key whose first equality columns may come in any order
+func leadingMatch(indexColumns, key []string, equality int) int {
+	n := 0
+	for n < len(indexColumns) && n < len(key) {
+		if n < equality {
+			if !containsFold(key[:equality], indexColumns[n]) {
+				break
+			}
+		} else if !strings.EqualFold(indexColumns[n], key[n]) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// indexDDL returns the CREATE INDEX statement for an index
+func (ia *IndexAdvisor) indexDDL(table string, columns, include []string) string {
+	name := "idx_" + strings.ToLower(strings.ReplaceAll(table, ".", "_")+"_"+strings.Join(columns, "_"))
+	if len(name) > maxIndexNameLength {
+		name = name[:maxIndexNameLength]
+	}
+
+	create := "CREATE INDEX"
+	if ia.dialect != nil && ia.dialect.Name() == "SQL Server" {
+		create = "CREATE NONCLUSTERED INDEX"
+	}
+
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		parts[i] = quoteIdent(ia.dialect, part)
+	}
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(ia.dialect, col)
+	}
+
+	ddl := fmt.Sprintf("%s %s ON %s (%s)", create, quoteIdent(ia.dialect, name), strings.Join(parts, "."), strings.Join(quoted, ", "))
+	if len(include) > 0 {
+		quoted := make([]string, len(include))
+		for i, col := range include {
+			quoted[i] = quoteIdent(ia.dialect, col)
+		}
+		ddl += fmt.Sprintf(" INCLUDE (%s)", strings.Join(quoted, ", "))
+	}
+	return ddl + ";"
+}
+
+// splitConjuncts returns the operands of a chain of ANDs
+func splitConjuncts(expr parser.Expression) []parser.Expression {
+	if expr == nil {
+		return nil
+	}
+	if bin, ok := expr.(*parser.BinaryExpression); ok && bin.Operator == "AND" {
+		return append(splitConjuncts(bin.Left), splitConjuncts(bin.Right)...)
+	}
+	return []parser.Expression{expr}
+}
+
+// expressionColumns returns the columns an expression reads. It fails for
+// expressions it can't look into, such as subqueries.
+func expressionColumns(expr parser.Expression) ([]*parser.ColumnReference, bool) {
+	var cols []*parser.ColumnReference
+	var walk func(parser.Expression) bool
+	walk = func(expr parser.Expression) bool {
+		switch e := expr.(type) {
+		case nil, *parser.Literal, *parser.Parameter:
+			return true
+		case *parser.ColumnReference:
+			cols = append(cols, e)
+			return true
+		case *parser.AliasedExpression:
+			return walk(e.Expression)
+		case *parser.BinaryExpression:
+			return walk(e.Left) && walk(e.Right)
+		case *parser.UnaryExpression:
+			return walk(e.Operand)
+		case *parser.BetweenExpression:
+			return walk(e.Expression) && walk(e.Low) && walk(e.High)
+		case *parser.InExpression:
+			for _, value := range e.Values {
+				if !walk(value) {
+					return false
+				}
+			}
+			return walk(e.Expression)
+		case *parser.FunctionCall:
+			for i, arg := range e.Arguments {
+				if i == 0 && isDatePartArgument(e) {
+					continue
+				}
+				if _, isStar := arg.(*parser.StarExpression); !isStar && !walk(arg) {
+					return false
+				}
+			}
+			return walk(e.Filter)
+		case *parser.CaseExpression:
+			if !walk(e.Input) || !walk(e.ElseResult) {
+				return false
+			}
+			for _, when := range e.WhenClauses {
+				if !walk(when.Condition) || !walk(when.Result) {
+					return false
+				}
+			}
+			return true
+		case *parser.CastExpression:
+			return walk(e.Expression)
+		case *parser.ExtractExpression:
+			return walk(e.Source)
+		case *parser.TrimExpression:
+			return walk(e.Characters) && walk(e.Source)
+		}
+		return false
+	}
+	if !walk(expr) {
+		return nil, false
+	}
+	return cols, true
+}
+
+// isDatePartArgument reports whether a function's first argument names a
+// date part, such as year in SQL Server's DATEPART(year, col), rather than
+// a column
+func isDatePartArgument(fn *parser.FunctionCall) bool {
+	switch strings.ToUpper(fn.Name) {
+	case "DATEPART", "DATENAME", "DATEADD", "DATEDIFF", "DATEDIFF_BIG", "DATETRUNC":
+		if len(fn.Arguments) > 1 {
+			col, ok := fn.Arguments[0].(*parser.ColumnReference)
+			return ok && col.Table == ""
+		}
+	}
+	return false
+}
+
+// isConstantExpression reports whether an expression reads no column, so
+// that its value is fixed for the query
+func isConstantExpression(expr parser.Expression) bool {
+	cols, ok := expressionColumns(expr)
+	return ok && len(cols) == 0
+}
+
+// flipComparison returns the operator of a comparison with its operands
+// swapped
+func flipComparison(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case ">":
+		return "<"
+	case "<=":
+		return ">="
+	case ">=":
+		return "<="
+	}
+	return op
+}
+
+// hasPrefix reports whether columns starts with prefix, ignoring case
+func hasPrefix(columns, prefix []string) bool {
+	if len(prefix) > len(columns) {
+		return false
+	}
+	for i := range prefix {
+		if !strings.EqualFold(columns[i], prefix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeColumns appends the columns of b missing from a
+func mergeColumns(a, b []string) []string {
+	for _, col := range b {
+		if !containsFold(a, col) {
+			a = append(a, col)
+		}
+	}
+	return a
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// plainIdentifier matches identifiers that need no quoting
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// quoteIdent quotes an identifier when the dialect requires it
+func quoteIdent(d dialect.Dialect, name string) string {
+	if d == nil || (plainIdentifier.MatchString(name) && !d.IsReservedWord(name)) {
+		return name
+	}
+	return d.QuoteIdentifier(name)
+}
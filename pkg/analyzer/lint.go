@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+)
+
+// severityRanks orders suggestion severities
+var severityRanks = map[string]int{
+	"INFO":     1,
+	"WARNING":  2,
+	"ERROR":    3,
+	"CRITICAL": 4,
+}
+
+// SeverityRank returns the rank of a severity, higher being more severe,
+// or 0 for an unknown severity
+func SeverityRank(severity string) int {
+	return severityRanks[strings.ToUpper(severity)]
+}
+
+// RuleConfig enables, disables or re-grades a rule
+type RuleConfig struct {
+	Enabled  *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`   // nil keeps the default
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"` // empty keeps the rule's own
+}
+
+// Rules returns the engine's rules, in the order they run
+func (oe *OptimizationEngine) Rules() []OptimizationRule {
+	rules := make([]OptimizationRule, len(oe.rules))
+	copy(rules, oe.rules)
+	for i := range rules {
+		if severity, ok := oe.severities[rules[i].ID]; ok {
+			rules[i].Severity = severity
+		}
+	}
+	return rules
+}
+
+// ConfigureRules applies per-rule settings keyed by rule ID. Rules of
+// other dialects are accepted and ignored, so one configuration can serve
+// every dialect; IDs no dialect knows are an error.
+func (oe *OptimizationEngine) ConfigureRules(rules map[string]RuleConfig) error {
+	for id, rc := range rules {
+		if rc.Severity != "" && SeverityRank(rc.Severity) == 0 {
+			return fmt.Errorf("invalid severity %s for rule %s", rc.Severity, id)
+		}
+		if !IsRuleID(id) {
+			return fmt.Errorf("unknown rule %s", id)
+		}
+	}
+
+	for i := range oe.rules {
+		rc, ok := lookupRuleConfig(rules, oe.rules[i].ID)
+		if !ok {
+			continue
+		}
+		if rc.Enabled != nil {
+			oe.rules[i].Enabled = *rc.Enabled
+		}
+		if rc.Severity != "" {
+			if oe.severities == nil {
+				oe.severities = make(map[string]string)
+			}
+			oe.severities[oe.rules[i].ID] = strings.ToUpper(rc.Severity)
+		}
+	}
+	return nil
+}
+
+// lookupRuleConfig finds a rule's settings, ignoring the ID's case
+func lookupRuleConfig(rules map[string]RuleConfig, id string) (RuleConfig, bool) {
+	if rc, ok := rules[id]; ok {
+		return rc, true
+	}
+	for key, rc := range rules {
+		if strings.EqualFold(key, id) {
+			return rc, true
+		}
+	}
+	return RuleConfig{}, false
+}
+
+// RuleIDs returns the IDs of the rules of every registered dialect, sorted
+func RuleIDs() []string {
+	seen := make(map[string]bool)
+	for _, name := range dialect.Names() {
+		for _, rule := range NewOptimizationEngine(dialect.GetDialect(name)).rules {
+			seen[rule.ID] = true
+		}
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// IsRuleID reports whether a rule with the ID exists in some dialect
+func IsRuleID(id string) bool {
+	for _, known := range RuleIDs() {
+		if strings.EqualFold(known, id) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,12 +5,16 @@ import (
 
 	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
 )
 
 // OptimizationEngine provides comprehensive SQL optimization suggestions
 type OptimizationEngine struct {
-	dialect dialect.Dialect
-	rules   []OptimizationRule
+	dialect    dialect.Dialect
+	rules      []OptimizationRule
+	schema     *schema.Schema      // Optional, for migration rules
+	columnRefs map[string][]string // "table.column" -> known users, for migration rules
+	severities map[string]string   // rule ID -> configured severity
 }
 
 // OptimizationRule defines a rule for optimization analysis
@@ -18,7 +22,7 @@ type OptimizationRule struct {
 	ID          string
 	Name        string
 	Description string
-	Category    string // PERFORMANCE, SECURITY, MAINTAINABILITY, BEST_PRACTICE
+	Category    string // PERFORMANCE, SECURITY, MAINTAINABILITY, BEST_PRACTICE, MIGRATION
 	Severity    string // INFO, WARNING, ERROR, CRITICAL
 	Enabled     bool
 	CheckFunc   func(*OptimizationEngine, parser.Statement) []EnhancedOptimizationSuggestion
@@ -35,6 +39,7 @@ type EnhancedOptimizationSuggestion struct {
 	Column        int    `json:"column,omitempty"`
 	Table         string `json:"table,omitempty"`
 	ColumnName    string `json:"column_name,omitempty"`
+	Operator      string `json:"operator,omitempty"` // comparison operator of a predicate finding
 	Suggestion    string `json:"suggestion"`
 	Impact        string `json:"impact"` // HIGH, MEDIUM, LOW
 	Dialect       string `json:"dialect,omitempty"`
@@ -54,6 +59,7 @@ func NewOptimizationEngine(d dialect.Dialect) *OptimizationEngine {
 	engine.registerPerformanceRules()
 	engine.registerSecurityRules()
 	engine.registerDialectSpecificRules()
+	engine.registerMigrationRules()
 
 	return engine
 }
@@ -65,6 +71,11 @@ func (oe *OptimizationEngine) AnalyzeOptimizations(stmt parser.Statement) []Enha
 	for _, rule := range oe.rules {
 		if rule.Enabled {
 			ruleSuggestions := rule.CheckFunc(oe, stmt)
+			if severity, ok := oe.severities[rule.ID]; ok {
+				for i := range ruleSuggestions {
+					ruleSuggestions[i].Severity = severity
+				}
+			}
 			suggestions = append(suggestions, ruleSuggestions...)
 		}
 	}
@@ -111,7 +122,7 @@ func (oe *OptimizationEngine) registerCoreRules() {
 		{
 			ID:          "FUNCTION_IN_WHERE",
 			Name:        "Function in WHERE clause",
-			Description: "Functions on columns in WHERE prevent index usage",
+			Description: "Functions, casts and arithmetic on columns in WHERE and JOIN prevent index usage",
 			Category:    "PERFORMANCE",
 			Severity:    "WARNING",
 			Enabled:     true,
@@ -119,6 +130,39 @@ func (oe *OptimizationEngine) registerCoreRules() {
 				return engine.checkFunctionInWhere(stmt)
 			},
 		},
+		{
+			ID:          "NULL_COMPARISON",
+			Name:        "Comparison with NULL",
+			Description: "= NULL and <> NULL are never true; use IS [NOT] NULL",
+			Category:    "BEST_PRACTICE",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkNullComparison(stmt)
+			},
+		},
+		{
+			ID:          "NOT_IN_NULL",
+			Name:        "NOT IN with NULLs",
+			Description: "NOT IN is never true when its list or subquery has a NULL",
+			Category:    "BEST_PRACTICE",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkNotInNulls(stmt)
+			},
+		},
+		{
+			ID:          "UNBOUNDED_DELETE",
+			Name:        "Unbounded DELETE",
+			Description: "DELETE without WHERE or a row limit removes every row in one transaction",
+			Category:    "BEST_PRACTICE",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkUnboundedDelete(stmt)
+			},
+		},
 	}...)
 }
 
@@ -158,6 +202,50 @@ func (oe *OptimizationEngine) registerPerformanceRules() {
 				return engine.checkIndexOpportunities(stmt)
 			},
 		},
+		{
+			ID:          "LEADING_WILDCARD",
+			Name:        "Leading wildcard in LIKE",
+			Description: "LIKE patterns starting with % or _ can't use an index",
+			Category:    "PERFORMANCE",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkLeadingWildcard(stmt)
+			},
+		},
+		{
+			ID:          "OR_ACROSS_COLUMNS",
+			Name:        "OR across columns",
+			Description: "OR between conditions on different columns can't use a single index",
+			Category:    "PERFORMANCE",
+			Severity:    "INFO",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkOrAcrossColumns(stmt)
+			},
+		},
+		{
+			ID:          "IMPLICIT_CONVERSION",
+			Name:        "Implicit conversion",
+			Description: "Comparing a column with a value of another type converts the column on every row",
+			Category:    "PERFORMANCE",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkImplicitConversion(stmt)
+			},
+		},
+		{
+			ID:          "NOT_EQUAL_INDEXED",
+			Name:        "<> on an indexed column",
+			Description: "<> and != can't seek on an index",
+			Category:    "PERFORMANCE",
+			Severity:    "INFO",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkNotEqualIndexed(stmt)
+			},
+		},
 		{
 			ID:          "INEFFICIENT_JOIN_ORDER",
 			Name:        "Join Order Optimization",
@@ -178,7 +266,7 @@ func (oe *OptimizationEngine) registerSecurityRules() {
 		{
 			ID:          "SQL_INJECTION_RISK",
 			Name:        "SQL Injection Risk",
-			Description: "Dynamic SQL construction may be vulnerable to injection",
+			Description: "Dynamic SQL built by concatenation, or always-true OR conditions, suggest injection",
 			Category:    "SECURITY",
 			Severity:    "CRITICAL",
 			Enabled:     true,
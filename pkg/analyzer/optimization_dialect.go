@@ -1,8 +1,10 @@
 package analyzer
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
 )
 
@@ -121,7 +123,7 @@ func (oe *OptimizationEngine) checkSQLServerTop(stmt parser.Statement) []Enhance
 	var suggestions []EnhancedOptimizationSuggestion
 
 	if selectStmt, ok := stmt.(*parser.SelectStatement); ok {
-		if selectStmt.Limit != nil {
+		if selectStmt.Limit != nil && selectStmt.Limit.Syntax == dialect.LimitSyntaxStandard {
 			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
 				Type:          "SQLSERVER_TOP_VS_LIMIT",
 				Description:   "Use TOP instead of LIMIT for SQL Server compatibility",
@@ -143,26 +145,75 @@ func (oe *OptimizationEngine) checkSQLServerTop(stmt parser.Statement) []Enhance
 func (oe *OptimizationEngine) checkSQLServerNoLock(stmt parser.Statement) []EnhancedOptimizationSuggestion {
 	var suggestions []EnhancedOptimizationSuggestion
 
-	// Check for NOLOCK hints in the query
-	queryStr := strings.ToUpper(stmt.String())
-	if strings.Contains(queryStr, "NOLOCK") || strings.Contains(queryStr, "WITH (NOLOCK)") {
-		suggestions = append(suggestions, EnhancedOptimizationSuggestion{
-			Type:          "SQLSERVER_NOLOCK_WARNING",
-			Description:   "NOLOCK hint can cause dirty reads and data inconsistency",
-			Severity:      "WARNING",
-			Category:      "SECURITY",
-			Rule:          "SQLSERVER_NOLOCK_WARNING",
-			Dialect:       "sqlserver",
-			Suggestion:    "Consider alternatives to NOLOCK",
-			Impact:        "HIGH",
-			AutoFixable:   false,
-			FixSuggestion: "Use READ UNCOMMITTED isolation level or consider if dirty reads are acceptable",
-		})
+	for _, table := range hintedTables(stmt) {
+		for _, hint := range table.Hints {
+			if hint.Name != "NOLOCK" && hint.Name != "READUNCOMMITTED" {
+				continue
+			}
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "SQLSERVER_NOLOCK_WARNING",
+				Description:   fmt.Sprintf("%s hint on %s can cause dirty reads and data inconsistency", hint.Name, table.Name),
+				Severity:      "WARNING",
+				Category:      "SECURITY",
+				Rule:          "SQLSERVER_NOLOCK_WARNING",
+				Dialect:       "sqlserver",
+				Suggestion:    "Consider alternatives to NOLOCK",
+				Impact:        "HIGH",
+				AutoFixable:   true,
+				FixSuggestion: "Use READ COMMITTED SNAPSHOT isolation or confirm dirty reads are acceptable",
+			})
+		}
 	}
 
 	return suggestions
 }
 
+// oracleTableHints are Oracle hints whose first argument names a table
+var oracleTableHints = map[string]bool{
+	"INDEX":         true,
+	"NO_INDEX":      true,
+	"INDEX_ASC":     true,
+	"INDEX_DESC":    true,
+	"INDEX_FFS":     true,
+	"FULL":          true,
+	"LEADING":       true,
+	"USE_NL":        true,
+	"USE_HASH":      true,
+	"USE_MERGE":     true,
+	"PARALLEL":      true,
+	"NO_PARALLEL":   true,
+	"CACHE":         true,
+	"NOCACHE":       true,
+	"DRIVING_SITE":  true,
+	"PUSH_PRED":     true,
+	"NO_PUSH_PRED":  true,
+	"INDEX_COMBINE": true,
+}
+
+// hintedTables returns the table references of a statement that can carry
+// table hints: the FROM and join tables of a SELECT and DML targets
+func hintedTables(stmt parser.Statement) []*parser.TableReference {
+	var tables []*parser.TableReference
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		if s.From != nil {
+			for i := range s.From.Tables {
+				tables = append(tables, &s.From.Tables[i])
+			}
+		}
+		for _, join := range s.Joins {
+			tables = append(tables, &join.Table)
+		}
+	case *parser.InsertStatement:
+		tables = append(tables, &s.Table)
+	case *parser.UpdateStatement:
+		tables = append(tables, &s.Table)
+	case *parser.DeleteStatement:
+		tables = append(tables, &s.From)
+	}
+	return tables
+}
+
 // SQLite-specific optimizations
 func (oe *OptimizationEngine) checkSQLitePragma(stmt parser.Statement) []EnhancedOptimizationSuggestion {
 	var suggestions []EnhancedOptimizationSuggestion
@@ -192,28 +243,62 @@ func (oe *OptimizationEngine) checkSQLitePragma(stmt parser.Statement) []Enhance
 func (oe *OptimizationEngine) checkOracleHints(stmt parser.Statement) []EnhancedOptimizationSuggestion {
 	var suggestions []EnhancedOptimizationSuggestion
 
-	if selectStmt, ok := stmt.(*parser.SelectStatement); ok {
-		// For complex queries with multiple tables, suggest considering hints
-		tableCount := 0
-		if selectStmt.From != nil {
-			tableCount += len(selectStmt.From.Tables)
-		}
-		tableCount += len(selectStmt.Joins)
+	selectStmt, ok := stmt.(*parser.SelectStatement)
+	if !ok {
+		return suggestions
+	}
 
-		if tableCount > 3 {
+	// Oracle silently ignores hints naming a table that is not in the query.
+	// An aliased table can only be referenced by its alias.
+	if len(selectStmt.Hints) > 0 {
+		names := make(map[string]bool)
+		for _, table := range hintedTables(stmt) {
+			if table.Alias != "" {
+				names[strings.ToUpper(table.Alias)] = true
+			} else {
+				names[strings.ToUpper(table.Name)] = true
+			}
+		}
+		for _, hint := range selectStmt.Hints {
+			if !oracleTableHints[hint.Name] || len(hint.Args) == 0 || names[strings.ToUpper(hint.Args[0])] {
+				continue
+			}
 			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
-				Type:          "ORACLE_HINT_SUGGESTION",
-				Description:   "Complex multi-table query may benefit from Oracle optimizer hints",
-				Severity:      "INFO",
+				Type:          "ORACLE_HINT_UNKNOWN_TABLE",
+				Description:   fmt.Sprintf("Hint %s refers to %s, which is not a table or alias in the query", hint, hint.Args[0]),
+				Severity:      "WARNING",
 				Category:      "PERFORMANCE",
-				Rule:          "ORACLE_HINT_OPTIMIZATION",
+				Rule:          "ORACLE_HINT_UNKNOWN_TABLE",
 				Dialect:       "oracle",
-				Suggestion:    "Consider using Oracle optimizer hints",
+				Suggestion:    "Oracle ignores hints it cannot resolve",
 				Impact:        "MEDIUM",
 				AutoFixable:   false,
-				FixSuggestion: "Consider hints like /*+ USE_INDEX */ or /*+ LEADING */ for complex queries",
+				FixSuggestion: "Reference the table by its alias when it has one",
 			})
 		}
+		return suggestions
+	}
+
+	// For complex queries with multiple tables, suggest considering hints
+	tableCount := 0
+	if selectStmt.From != nil {
+		tableCount += len(selectStmt.From.Tables)
+	}
+	tableCount += len(selectStmt.Joins)
+
+	if tableCount > 3 {
+		suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+			Type:          "ORACLE_HINT_SUGGESTION",
+			Description:   "Complex multi-table query may benefit from Oracle optimizer hints",
+			Severity:      "INFO",
+			Category:      "PERFORMANCE",
+			Rule:          "ORACLE_HINT_OPTIMIZATION",
+			Dialect:       "oracle",
+			Suggestion:    "Consider using Oracle optimizer hints",
+			Impact:        "MEDIUM",
+			AutoFixable:   false,
+			FixSuggestion: "Consider hints like /*+ INDEX(t idx) */ or /*+ LEADING(t1 t2) */ for complex queries",
+		})
 	}
 
 	return suggestions
@@ -0,0 +1,519 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Migration safety rules flag DDL that locks or rewrites tables, which
+// breaks zero-downtime deploys
+
+// postgresVolatileFunctions are defaults PostgreSQL must evaluate per row,
+// forcing a table rewrite when used in ADD COLUMN ... DEFAULT
+var postgresVolatileFunctions = map[string]bool{
+	"RANDOM":              true,
+	"GEN_RANDOM_UUID":     true,
+	"UUID_GENERATE_V1":    true,
+	"UUID_GENERATE_V4":    true,
+	"CLOCK_TIMESTAMP":     true,
+	"TIMEOFDAY":           true,
+	"STATEMENT_TIMESTAMP": true,
+	"NEXTVAL":             true,
+	"TXID_CURRENT":        true,
+}
+
+// SetSchema sets the schema migration rules use to find foreign keys and
+// indexes that still reference a dropped or renamed column
+func (oe *OptimizationEngine) SetSchema(s *schema.Schema) {
+	oe.schema = s
+}
+
+// AddColumnReference records that table.column is still used, for example
+// by a view or by application queries. source describes the user.
+func (oe *OptimizationEngine) AddColumnReference(table, column, source string) {
+	if oe.columnRefs == nil {
+		oe.columnRefs = make(map[string][]string)
+	}
+	key := strings.ToLower(table + "." + column)
+	oe.columnRefs[key] = append(oe.columnRefs[key], source)
+}
+
+// Migration safety rules
+func (oe *OptimizationEngine) registerMigrationRules() {
+	oe.rules = append(oe.rules, []OptimizationRule{
+		{
+			ID:          "MIGRATION_BLOCKING_INDEX",
+			Name:        "Blocking Index Build",
+			Description: "Building an index without the online option blocks writes to the table",
+			Category:    "MIGRATION",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkMigrationBlockingIndex(stmt)
+			},
+		},
+		{
+			ID:          "MIGRATION_TABLE_REWRITE",
+			Name:        "Table Rewrite",
+			Description: "ALTER TABLE actions that rewrite or copy the whole table under a lock",
+			Category:    "MIGRATION",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkMigrationTableRewrite(stmt)
+			},
+		},
+		{
+			ID:          "MIGRATION_UNVALIDATED_CONSTRAINT",
+			Name:        "Constraint Validation Under Lock",
+			Description: "Adding NOT NULL, CHECK or FOREIGN KEY constraints scans the table while holding a lock",
+			Category:    "MIGRATION",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkMigrationConstraintValidation(stmt)
+			},
+		},
+		{
+			ID:          "MIGRATION_BREAKING_COLUMN_CHANGE",
+			Name:        "Breaking Column Change",
+			Description: "Dropping or renaming a column breaks code and objects that still reference it",
+			Category:    "MIGRATION",
+			Severity:    "WARNING",
+			Enabled:     true,
+			CheckFunc: func(engine *OptimizationEngine, stmt parser.Statement) []EnhancedOptimizationSuggestion {
+				return engine.checkMigrationBreakingColumnChange(stmt)
+			},
+		},
+	}...)
+}
+
+// dialectKey returns the lower-cased dialect name without spaces
+func (oe *OptimizationEngine) dialectKey() string {
+	return strings.ToLower(strings.ReplaceAll(oe.dialect.Name(), " ", ""))
+}
+
+func (oe *OptimizationEngine) checkMigrationBlockingIndex(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	indexStmt, ok := stmt.(*parser.CreateIndexStatement)
+	if !ok || indexStmt.Online() {
+		return suggestions
+	}
+
+	columns := strings.Join(indexStmt.Columns, ", ")
+	switch oe.dialectKey() {
+	case "postgresql":
+		suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+			Type:          "POSTGRESQL_INDEX_WITHOUT_CONCURRENTLY",
+			Description:   fmt.Sprintf("CREATE INDEX on %s takes a SHARE lock that blocks writes until the build finishes", indexStmt.Table.String()),
+			Severity:      "WARNING",
+			Category:      "MIGRATION",
+			Rule:          "MIGRATION_BLOCKING_INDEX",
+			Table:         indexStmt.Table.String(),
+			Dialect:       "postgresql",
+			Suggestion:    "Build the index with CONCURRENTLY",
+			Impact:        "HIGH",
+			AutoFixable:   false,
+			FixSuggestion: fmt.Sprintf("CREATE INDEX CONCURRENTLY %s ON %s (%s); -- outside a transaction block", indexStmt.IndexName, indexStmt.Table.String(), columns),
+		})
+	case "sqlserver":
+		suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+			Type:          "SQLSERVER_INDEX_WITHOUT_ONLINE",
+			Description:   fmt.Sprintf("CREATE INDEX on %s without ONLINE = ON blocks writes for the duration of the build", indexStmt.Table.String()),
+			Severity:      "WARNING",
+			Category:      "MIGRATION",
+			Rule:          "MIGRATION_BLOCKING_INDEX",
+			Table:         indexStmt.Table.String(),
+			Dialect:       "sqlserver",
+			Suggestion:    "Build the index with WITH (ONLINE = ON)",
+			Impact:        "HIGH",
+			AutoFixable:   false,
+			FixSuggestion: fmt.Sprintf("CREATE INDEX %s ON %s (%s) WITH (ONLINE = ON); -- Enterprise edition or Azure SQL", indexStmt.IndexName, indexStmt.Table.String(), columns),
+		})
+	case "mysql":
+		// InnoDB builds secondary indexes in place without blocking DML
+		// unless a copy or a lock is requested
+		if indexStmt.Options["ALGORITHM"] == "COPY" || indexStmt.Options["LOCK"] == "SHARED" || indexStmt.Options["LOCK"] == "EXCLUSIVE" {
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "MYSQL_INDEX_BLOCKING_ALGORITHM",
+				Description:   fmt.Sprintf("CREATE INDEX on %s requests ALGORITHM=%s LOCK=%s, which blocks writes", indexStmt.Table.String(), indexStmt.Options["ALGORITHM"], indexStmt.Options["LOCK"]),
+				Severity:      "WARNING",
+				Category:      "MIGRATION",
+				Rule:          "MIGRATION_BLOCKING_INDEX",
+				Table:         indexStmt.Table.String(),
+				Dialect:       "mysql",
+				Suggestion:    "Use ALGORITHM=INPLACE, LOCK=NONE",
+				Impact:        "HIGH",
+				AutoFixable:   false,
+				FixSuggestion: fmt.Sprintf("CREATE INDEX %s ON %s (%s) ALGORITHM=INPLACE LOCK=NONE", indexStmt.IndexName, indexStmt.Table.String(), columns),
+			})
+		}
+	}
+
+	return suggestions
+}
+
+func (oe *OptimizationEngine) checkMigrationTableRewrite(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	alterStmt, ok := stmt.(*parser.AlterTableStatement)
+	if !ok {
+		return suggestions
+	}
+	table := alterStmt.Table.String()
+
+	switch oe.dialectKey() {
+	case "postgresql":
+		for _, action := range alterStmt.Actions {
+			if action.ActionType == "ADD" {
+				for _, col := range action.Columns {
+					if fn := volatileDefault(col.Default); fn != "" {
+						suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+							Type:          "POSTGRESQL_VOLATILE_DEFAULT",
+							Description:   fmt.Sprintf("Adding column %s with volatile default %s() rewrites %s under an ACCESS EXCLUSIVE lock", col.Name, strings.ToLower(fn), table),
+							Severity:      "WARNING",
+							Category:      "MIGRATION",
+							Rule:          "MIGRATION_TABLE_REWRITE",
+							Table:         table,
+							ColumnName:    col.Name,
+							Dialect:       "postgresql",
+							Suggestion:    "Add the column without a default, set the default, then backfill existing rows in batches",
+							Impact:        "HIGH",
+							AutoFixable:   false,
+							FixSuggestion: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s; ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s(); -- then backfill in batches", table, col.Name, col.DataType, table, col.Name, strings.ToLower(fn)),
+						})
+					}
+				}
+			}
+
+			if action.ActionType == "ALTER_COLUMN" && action.ColumnChange == "TYPE" {
+				suggestions = append(suggestions, oe.columnTypeChange(table, action.ColumnName, "postgresql",
+					fmt.Sprintf("Changing the type of %s rewrites %s and its indexes under an ACCESS EXCLUSIVE lock", action.ColumnName, table)))
+			}
+		}
+
+	case "sqlserver":
+		for _, action := range alterStmt.Actions {
+			if action.ActionType == "ALTER_COLUMN" && action.ColumnChange == "TYPE" {
+				suggestions = append(suggestions, oe.columnTypeChange(table, action.ColumnName, "sqlserver",
+					fmt.Sprintf("ALTER COLUMN %s can update every row of %s while holding a schema modification lock", action.ColumnName, table)))
+			}
+		}
+
+	case "mysql":
+		if alterStmt.Algorithm == "COPY" || alterStmt.Lock == "SHARED" || alterStmt.Lock == "EXCLUSIVE" {
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "MYSQL_BLOCKING_ALGORITHM",
+				Description:   fmt.Sprintf("ALTER TABLE %s requests ALGORITHM=%s LOCK=%s, which blocks writes", table, alterStmt.Algorithm, alterStmt.Lock),
+				Severity:      "WARNING",
+				Category:      "MIGRATION",
+				Rule:          "MIGRATION_TABLE_REWRITE",
+				Table:         table,
+				Dialect:       "mysql",
+				Suggestion:    "Use ALGORITHM=INSTANT or ALGORITHM=INPLACE, LOCK=NONE",
+				Impact:        "HIGH",
+				AutoFixable:   false,
+				FixSuggestion: "Replace the ALGORITHM/LOCK clause with ALGORITHM=INPLACE, LOCK=NONE, or run the change with gh-ost or pt-online-schema-change",
+			})
+		}
+
+		for _, action := range alterStmt.Actions {
+			if reason := mysqlCopyReason(action); reason != "" {
+				suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+					Type:          "MYSQL_TABLE_COPY",
+					Description:   fmt.Sprintf("%s on %s can't use ALGORITHM=INSTANT or INPLACE and copies the table", reason, table),
+					Severity:      "WARNING",
+					Category:      "MIGRATION",
+					Rule:          "MIGRATION_TABLE_REWRITE",
+					Table:         table,
+					ColumnName:    action.ColumnName,
+					Dialect:       "mysql",
+					Suggestion:    "Run the change with an online schema change tool, or pin the algorithm so MySQL fails instead of copying",
+					Impact:        "HIGH",
+					AutoFixable:   false,
+					FixSuggestion: "Append ALGORITHM=INPLACE, LOCK=NONE to fail fast, and use gh-ost or pt-online-schema-change for changes that need a copy",
+				})
+			}
+		}
+
+		if alterStmt.Algorithm == "" && allInstantActions(alterStmt.Actions) {
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "MYSQL_PIN_INSTANT",
+				Description:   fmt.Sprintf("ALTER TABLE %s can run with ALGORITHM=INSTANT but doesn't request it", table),
+				Severity:      "INFO",
+				Category:      "MIGRATION",
+				Rule:          "MIGRATION_TABLE_REWRITE",
+				Table:         table,
+				Dialect:       "mysql",
+				Suggestion:    "Add ALGORITHM=INSTANT so MySQL errors instead of silently falling back to a copy",
+				Impact:        "LOW",
+				AutoFixable:   false,
+				FixSuggestion: "ALTER TABLE ... , ALGORITHM=INSTANT",
+			})
+		}
+	}
+
+	return suggestions
+}
+
+// columnTypeChange builds the suggestion for an in-place column type change
+func (oe *OptimizationEngine) columnTypeChange(table, column, dialectName, description string) EnhancedOptimizationSuggestion {
+	return EnhancedOptimizationSuggestion{
+		Type:          strings.ToUpper(dialectName) + "_COLUMN_TYPE_CHANGE",
+		Description:   description,
+		Severity:      "WARNING",
+		Category:      "MIGRATION",
+		Rule:          "MIGRATION_TABLE_REWRITE",
+		Table:         table,
+		ColumnName:    column,
+		Dialect:       dialectName,
+		Suggestion:    "Add a new column with the target type, backfill it in batches, then switch readers and drop the old column",
+		Impact:        "HIGH",
+		AutoFixable:   false,
+		FixSuggestion: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s_new <type>; -- backfill, dual-write, then swap names", table, column),
+	}
+}
+
+// volatileDefault returns the name of the volatile function used as a
+// column default, or "". Casts such as random()::int and arithmetic are
+// looked through; parentheses leave no node.
+func volatileDefault(expr parser.Expression) string {
+	switch e := expr.(type) {
+	case *parser.CastExpression:
+		return volatileDefault(e.Expression)
+	case *parser.UnaryExpression:
+		return volatileDefault(e.Operand)
+	case *parser.BinaryExpression:
+		if fn := volatileDefault(e.Left); fn != "" {
+			return fn
+		}
+		return volatileDefault(e.Right)
+	case *parser.FunctionCall:
+		if name := strings.ToUpper(e.Name); postgresVolatileFunctions[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// mysqlCopyReason returns why an ALTER action needs ALGORITHM=COPY, or ""
+func mysqlCopyReason(action *parser.AlterAction) string {
+	switch action.ActionType {
+	case "MODIFY":
+		return "Changing a column definition with MODIFY"
+	case "CHANGE":
+		return "Changing a column definition with CHANGE"
+	case "ALTER_COLUMN":
+		if action.ColumnChange == "TYPE" {
+			return "Changing a column type"
+		}
+	case "ADD":
+		if action.Constraint != nil && action.Constraint.ConstraintType == "PRIMARY_KEY" {
+			return "Adding a primary key"
+		}
+	}
+	return ""
+}
+
+// allInstantActions reports whether every action supports ALGORITHM=INSTANT
+// in MySQL 8.0
+func allInstantActions(actions []*parser.AlterAction) bool {
+	if len(actions) == 0 {
+		return false
+	}
+	for _, action := range actions {
+		switch action.ActionType {
+		case "ADD":
+			if action.Constraint != nil {
+				return false
+			}
+		case "RENAME_COLUMN", "RENAME":
+		case "ALTER_COLUMN":
+			if action.ColumnChange != "SET_DEFAULT" && action.ColumnChange != "DROP_DEFAULT" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (oe *OptimizationEngine) checkMigrationConstraintValidation(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	alterStmt, ok := stmt.(*parser.AlterTableStatement)
+	if !ok || oe.dialectKey() != "postgresql" {
+		return suggestions
+	}
+	table := alterStmt.Table.String()
+
+	for _, action := range alterStmt.Actions {
+		switch {
+		case action.ActionType == "ALTER_COLUMN" && action.ColumnChange == "SET_NOT_NULL":
+			check := fmt.Sprintf("%s_not_null", action.ColumnName)
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:        "POSTGRESQL_SET_NOT_NULL",
+				Description: fmt.Sprintf("SET NOT NULL on %s.%s scans the whole table under an ACCESS EXCLUSIVE lock", table, action.ColumnName),
+				Severity:    "WARNING",
+				Category:    "MIGRATION",
+				Rule:        "MIGRATION_UNVALIDATED_CONSTRAINT",
+				Table:       table,
+				ColumnName:  action.ColumnName,
+				Dialect:     "postgresql",
+				Suggestion:  "Add a NOT VALID check constraint, validate it separately, then set NOT NULL (PostgreSQL 12+ skips the scan)",
+				Impact:      "HIGH",
+				AutoFixable: false,
+				FixSuggestion: fmt.Sprintf("ALTER TABLE %[1]s ADD CONSTRAINT %[2]s CHECK (%[3]s IS NOT NULL) NOT VALID; "+
+					"ALTER TABLE %[1]s VALIDATE CONSTRAINT %[2]s; "+
+					"ALTER TABLE %[1]s ALTER COLUMN %[3]s SET NOT NULL; "+
+					"ALTER TABLE %[1]s DROP CONSTRAINT %[2]s;", table, check, action.ColumnName),
+			})
+
+		case action.ActionType == "ADD" && action.Constraint != nil && !action.NotValid &&
+			(action.Constraint.ConstraintType == "CHECK" || action.Constraint.ConstraintType == "FOREIGN_KEY"):
+			name := action.Constraint.Name
+			if name == "" {
+				name = "<constraint>"
+			}
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:        "POSTGRESQL_CONSTRAINT_WITHOUT_NOT_VALID",
+				Description: fmt.Sprintf("Adding %s constraint %s validates every row of %s while blocking writes", action.Constraint.ConstraintType, name, table),
+				Severity:    "WARNING",
+				Category:    "MIGRATION",
+				Rule:        "MIGRATION_UNVALIDATED_CONSTRAINT",
+				Table:       table,
+				Dialect:     "postgresql",
+				Suggestion:  "Add the constraint NOT VALID, then VALIDATE CONSTRAINT in a separate statement",
+				Impact:      "HIGH",
+				AutoFixable: false,
+				FixSuggestion: fmt.Sprintf("ALTER TABLE %[1]s ADD CONSTRAINT %[2]s ... NOT VALID; ALTER TABLE %[1]s VALIDATE CONSTRAINT %[2]s;",
+					table, name),
+			})
+
+		case action.ActionType == "ADD":
+			for _, col := range action.Columns {
+				if col.NotNull && col.Default == nil && !col.PrimaryKey {
+					suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+						Type:          "POSTGRESQL_ADD_NOT_NULL_COLUMN",
+						Description:   fmt.Sprintf("Adding NOT NULL column %s without a default fails if %s has rows", col.Name, table),
+						Severity:      "WARNING",
+						Category:      "MIGRATION",
+						Rule:          "MIGRATION_UNVALIDATED_CONSTRAINT",
+						Table:         table,
+						ColumnName:    col.Name,
+						Dialect:       "postgresql",
+						Suggestion:    "Add the column as nullable (or with a constant default), backfill, then enforce NOT NULL",
+						Impact:        "HIGH",
+						AutoFixable:   false,
+						FixSuggestion: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s; -- backfill, then add NOT NULL via a validated check constraint", table, col.Name, col.DataType),
+					})
+				}
+			}
+		}
+	}
+
+	return suggestions
+}
+
+func (oe *OptimizationEngine) checkMigrationBreakingColumnChange(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	alterStmt, ok := stmt.(*parser.AlterTableStatement)
+	if !ok {
+		return suggestions
+	}
+	table := alterStmt.Table.String()
+
+	for _, action := range alterStmt.Actions {
+		var verb string
+		switch {
+		case action.ActionType == "DROP" && action.ColumnName != "":
+			verb = "Dropping"
+		case action.ActionType == "RENAME_COLUMN":
+			verb = "Renaming"
+		case action.ActionType == "CHANGE" && action.NewColumn != nil && !strings.EqualFold(action.NewColumn.Name, action.ColumnName):
+			verb = "Renaming"
+		default:
+			continue
+		}
+
+		suggestion := EnhancedOptimizationSuggestion{
+			Type:          "BREAKING_COLUMN_CHANGE",
+			Description:   fmt.Sprintf("%s column %s.%s breaks application code still deployed against the old schema", verb, table, action.ColumnName),
+			Severity:      "WARNING",
+			Category:      "MIGRATION",
+			Rule:          "MIGRATION_BREAKING_COLUMN_CHANGE",
+			Table:         table,
+			ColumnName:    action.ColumnName,
+			Dialect:       oe.dialect.Name(),
+			Suggestion:    "Use expand/contract: add the new column, dual-write and backfill, move readers, then drop the old column in a later deploy",
+			Impact:        "HIGH",
+			AutoFixable:   false,
+			FixSuggestion: "Ship the schema change in a separate deploy after no running code references the column",
+		}
+
+		if refs := oe.columnReferences(&alterStmt.Table, action.ColumnName); len(refs) > 0 {
+			suggestion.Type = "REFERENCED_COLUMN_CHANGE"
+			suggestion.Severity = "ERROR"
+			suggestion.Description = fmt.Sprintf("%s column %s.%s, which is still referenced by %s", verb, table, action.ColumnName, strings.Join(refs, ", "))
+			suggestion.FixSuggestion = "Remove or migrate the references first: " + strings.Join(refs, ", ")
+		}
+
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions
+}
+
+// columnReferences lists the known users of a column: foreign keys and
+// indexes from the schema, and references recorded with AddColumnReference
+func (oe *OptimizationEngine) columnReferences(tableRef *parser.TableReference, column string) []string {
+	var refs []string
+
+	refs = append(refs, oe.columnRefs[strings.ToLower(tableRef.Name+"."+column)]...)
+	if tableRef.Schema != "" {
+		refs = append(refs, oe.columnRefs[strings.ToLower(tableRef.Schema+"."+tableRef.Name+"."+column)]...)
+	}
+
+	if oe.schema == nil {
+		return refs
+	}
+
+	var table *schema.Table
+	if tableRef.Schema != "" {
+		table, _ = oe.schema.GetQualifiedTable(tableRef.Schema, tableRef.Name)
+	} else {
+		table, _ = oe.schema.GetTable(tableRef.Name)
+	}
+	if table == nil {
+		return refs
+	}
+
+	for _, idx := range table.OrderedIndexes() {
+		for _, col := range idx.Columns {
+			if strings.EqualFold(col, column) {
+				refs = append(refs, "index "+idx.Name)
+				break
+			}
+		}
+	}
+
+	var foreignKeys []string
+	for _, other := range oe.schema.OrderedTables() {
+		for _, col := range other.OrderedColumns() {
+			fk := col.ForeignKey
+			if fk != nil && strings.EqualFold(fk.Table, table.Name) && strings.EqualFold(fk.Column, column) {
+				foreignKeys = append(foreignKeys, fmt.Sprintf("foreign key %s.%s", other.Name, col.Name))
+			}
+		}
+	}
+	sort.Strings(foreignKeys)
+
+	return append(refs, foreignKeys...)
+}
@@ -0,0 +1,915 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Predicate rules look at the comparisons of WHERE and JOIN conditions.
+// With a schema, columns resolve to their tables, so that rules about
+// indexes and types only report what the schema confirms.
+
+// predicateScope is the tables of a statement and the conditions on them
+type predicateScope struct {
+	accesses   []*tableAccess
+	conditions []scopedCondition
+}
+
+type scopedCondition struct {
+	expr   parser.Expression
+	clause string // WHERE or JOIN
+}
+
+// predicateScope returns the tables and conditions of a SELECT, UPDATE or
+// DELETE
+func (oe *OptimizationEngine) predicateScope(stmt parser.Statement) *predicateScope {
+	var resolver *schema.Resolver
+	if oe.schema != nil {
+		resolver = schema.NewResolver(oe.dialect, oe.schema)
+	}
+	accesses, conditions, joins := statementAccesses(stmt, resolver)
+
+	scope := &predicateScope{accesses: accesses}
+	for _, cond := range conditions {
+		scope.conditions = append(scope.conditions, scopedCondition{cond, "WHERE"})
+	}
+	for _, cond := range joins {
+		scope.conditions = append(scope.conditions, scopedCondition{cond, "JOIN"})
+	}
+	return scope
+}
+
+// column returns the schema table and column a reference reads, or nils
+// when there is no schema or the column can't be placed
+func (ps *predicateScope) column(col *parser.ColumnReference) (*schema.Table, *schema.Column) {
+	ta := columnOwner(col, ps.accesses)
+	if ta == nil || ta.table == nil {
+		return nil, nil
+	}
+	column, ok := ta.table.GetColumn(col.Column)
+	if !ok {
+		return ta.table, nil
+	}
+	return ta.table, column
+}
+
+// tableName returns the name of the table a column reads, or its qualifier
+func (ps *predicateScope) tableName(col *parser.ColumnReference) string {
+	if ta := columnOwner(col, ps.accesses); ta != nil {
+		return ta.name()
+	}
+	return col.Table
+}
+
+// mayBeIndexed reports whether a column leads an index, or may without a
+// schema to tell
+func (ps *predicateScope) mayBeIndexed(col *parser.ColumnReference) bool {
+	table, _ := ps.column(col)
+	return table == nil || leadsIndex(table, col.Column)
+}
+
+// leadsIndex reports whether a column is the first column of an index or
+// of the primary key
+func leadsIndex(table *schema.Table, column string) bool {
+	for _, idx := range existingIndexes(table) {
+		if len(idx.Columns) > 0 && strings.EqualFold(idx.Columns[0], column) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkPredicates calls visit for every node of a condition, outside of
+// subqueries; visit returns false to skip a node's operands
+func walkPredicates(expr parser.Expression, visit func(parser.Expression) bool) {
+	if expr == nil || !visit(expr) {
+		return
+	}
+	switch e := expr.(type) {
+	case *parser.BinaryExpression:
+		walkPredicates(e.Left, visit)
+		walkPredicates(e.Right, visit)
+	case *parser.UnaryExpression:
+		walkPredicates(e.Operand, visit)
+	case *parser.InExpression:
+		walkPredicates(e.Expression, visit)
+		for _, value := range e.Values {
+			walkPredicates(value, visit)
+		}
+	case *parser.BetweenExpression:
+		walkPredicates(e.Expression, visit)
+		walkPredicates(e.Low, visit)
+		walkPredicates(e.High, visit)
+	case *parser.FunctionCall:
+		for _, arg := range e.Arguments {
+			walkPredicates(arg, visit)
+		}
+	case *parser.CaseExpression:
+		walkPredicates(e.Input, visit)
+		for _, when := range e.WhenClauses {
+			walkPredicates(when.Condition, visit)
+			walkPredicates(when.Result, visit)
+		}
+		walkPredicates(e.ElseResult, visit)
+	}
+}
+
+// predicateComparisons are the binary operators that compare two values
+var predicateComparisons = map[string]bool{
+	"=": true, "<>": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true,
+	"LIKE": true, "NOT LIKE": true, "ILIKE": true, "NOT ILIKE": true, "<=>": true,
+}
+
+// comparison is a predicate between an operand and values: a binary
+// comparison, IN over a list or BETWEEN
+type comparison struct {
+	operator string
+	operand  parser.Expression
+	values   []parser.Expression
+	binary   bool // operand and value may be swapped
+}
+
+func asComparison(expr parser.Expression) (comparison, bool) {
+	switch e := expr.(type) {
+	case *parser.BinaryExpression:
+		if predicateComparisons[e.Operator] {
+			return comparison{e.Operator, e.Left, []parser.Expression{e.Right}, true}, true
+		}
+	case *parser.InExpression:
+		for _, value := range e.Values {
+			if _, isSubquery := value.(*parser.SubqueryExpression); isSubquery {
+				return comparison{}, false
+			}
+		}
+		operator := "IN"
+		if e.Not {
+			operator = "NOT IN"
+		}
+		return comparison{operator, e.Expression, e.Values, false}, true
+	case *parser.BetweenExpression:
+		operator := "BETWEEN"
+		if e.Not {
+			operator = "NOT BETWEEN"
+		}
+		return comparison{operator, e.Expression, []parser.Expression{e.Low, e.High}, false}, true
+	}
+	return comparison{}, false
+}
+
+// comparisonSide is one operand of a comparison and what it is compared with
+type comparisonSide struct {
+	column parser.Expression
+	others []parser.Expression
+}
+
+// sides returns the operand compared with the values and, for a binary
+// comparison, the value compared with the operand
+func (c comparison) sides() []comparisonSide {
+	sides := []comparisonSide{{c.operand, c.values}}
+	if c.binary {
+		sides = append(sides, comparisonSide{c.values[0], []parser.Expression{c.operand}})
+	}
+	return sides
+}
+
+// wrappedColumn returns the one column inside a function, cast or
+// arithmetic, and a description of what wraps it
+func wrappedColumn(expr parser.Expression) (*parser.ColumnReference, string, bool) {
+	var wrapper string
+	switch e := expr.(type) {
+	case *parser.FunctionCall:
+		wrapper = fmt.Sprintf("Function %s()", strings.ToUpper(e.Name))
+	case *parser.CastExpression:
+		wrapper = fmt.Sprintf("%s()", strings.ToUpper(e.Syntax))
+		if e.Syntax == "::" {
+			wrapper = "Cast ::"
+		}
+	case *parser.ExtractExpression:
+		wrapper = fmt.Sprintf("EXTRACT(%s)", strings.ToUpper(e.Field))
+	case *parser.TrimExpression:
+		wrapper = "Function TRIM()"
+	case *parser.BinaryExpression:
+		switch e.Operator {
+		case "+", "-", "*", "/", "%", "||":
+			wrapper = fmt.Sprintf("Operator %s", e.Operator)
+		default:
+			return nil, "", false
+		}
+	case *parser.UnaryExpression:
+		if e.Operator != "-" {
+			return nil, "", false
+		}
+		wrapper = "Negation"
+	default:
+		return nil, "", false
+	}
+
+	cols, ok := expressionColumns(expr)
+	if !ok || len(cols) == 0 {
+		return nil, "", false
+	}
+	for _, col := range cols[1:] {
+		if !strings.EqualFold(col.String(), cols[0].String()) {
+			return nil, "", false
+		}
+	}
+	return cols[0], wrapper, true
+}
+
+// checkFunctionInWhere detects functions, casts and arithmetic around a
+// column in a comparison, which keep an index on the column from being used
+func (oe *OptimizationEngine) checkFunctionInWhere(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	scope := oe.predicateScope(stmt)
+	for _, cond := range scope.conditions {
+		years := make(map[parser.Expression]YearRange)
+		for _, r := range YearComparisons(cond.expr) {
+			years[r.Comparison] = r
+		}
+
+		walkPredicates(cond.expr, func(expr parser.Expression) bool {
+			cmp, ok := asComparison(expr)
+			if !ok {
+				return true
+			}
+			for _, side := range cmp.sides() {
+				col, wrapper, ok := wrappedColumn(side.column)
+				if !ok || !comparedWithValue(side.others) || !scope.mayBeIndexed(col) {
+					continue
+				}
+
+				suggestion := EnhancedOptimizationSuggestion{
+					Type:          "FUNCTION_IN_WHERE",
+					Description:   fmt.Sprintf("%s on column %s in %s prevents index usage", wrapper, col.String(), cond.clause),
+					Severity:      "WARNING",
+					Category:      "PERFORMANCE",
+					Rule:          "FUNCTION_IN_WHERE",
+					Table:         scope.tableName(col),
+					ColumnName:    col.Column,
+					Operator:      cmp.operator,
+					Suggestion:    "Compare the bare column so that an index on it can be used",
+					Impact:        "MEDIUM",
+					AutoFixable:   false,
+					FixSuggestion: "Apply the function to the compared value instead, or index the expression",
+				}
+				if strings.HasPrefix(wrapper, "Operator") || wrapper == "Negation" {
+					suggestion.FixSuggestion = "Move the arithmetic to the other side of the comparison"
+				}
+				if r, ok := years[expr]; ok {
+					suggestion.Suggestion = fmt.Sprintf("Compare %s with a date range", col.String())
+					suggestion.AutoFixable = true
+					suggestion.FixSuggestion = r.Condition(oe.dialect, col.String())
+				}
+				suggestions = append(suggestions, suggestion)
+			}
+			return true
+		})
+	}
+
+	return suggestions
+}
+
+// comparedWithValue reports whether the other side of a comparison is a
+// constant or a bare column, so that an index on this side could serve it
+func comparedWithValue(others []parser.Expression) bool {
+	for _, other := range others {
+		if _, isCol := other.(*parser.ColumnReference); !isCol && !isConstantExpression(other) {
+			return false
+		}
+	}
+	return true
+}
+
+// YearRange is a comparison of a date column's year with a constant, such
+// as YEAR(created_at) = 2024, and the years of the column range that
+// selects the same rows
+type YearRange struct {
+	Comparison parser.Expression
+	Column     *parser.ColumnReference
+	Conjunct   bool // ANDed at the top of its condition, so it needs no parentheses
+	From       int  // first year of the range, 0 when unbounded
+	To         int  // first year after the range, 0 when unbounded
+}
+
+// Condition returns the sargable condition on a column equivalent to the
+// year comparison
+func (r YearRange) Condition(d dialect.Dialect, column string) string {
+	var parts []string
+	if r.From != 0 {
+		parts = append(parts, fmt.Sprintf("%s >= %s", column, yearStart(d, r.From)))
+	}
+	if r.To != 0 {
+		parts = append(parts, fmt.Sprintf("%s < %s", column, yearStart(d, r.To)))
+	}
+	return strings.Join(parts, " AND ")
+}
+
+// yearStart returns the literal of January 1st of a year; SQL Server reads
+// YYYYMMDD the same under every language setting
+func yearStart(d dialect.Dialect, year int) string {
+	switch d.Name() {
+	case "SQL Server":
+		return fmt.Sprintf("'%04d0101'", year)
+	case "Oracle":
+		return fmt.Sprintf("DATE '%04d-01-01'", year)
+	}
+	return fmt.Sprintf("'%04d-01-01'", year)
+}
+
+// YearComparisons returns the comparisons of a date column's year with an
+// integer in a condition, outside of subqueries
+func YearComparisons(expr parser.Expression) []YearRange {
+	var found []YearRange
+	var walk func(parser.Expression, bool)
+	walk = func(expr parser.Expression, conjunct bool) {
+		switch e := expr.(type) {
+		case *parser.BinaryExpression:
+			if e.Operator == "AND" || e.Operator == "OR" {
+				walk(e.Left, conjunct && e.Operator == "AND")
+				walk(e.Right, conjunct && e.Operator == "AND")
+				return
+			}
+			col, op, year := yearOf(e.Left), e.Operator, e.Right
+			if col == nil {
+				col, op, year = yearOf(e.Right), flipComparison(e.Operator), e.Left
+			}
+			n, isInt := integerLiteral(year)
+			if col == nil || !isInt {
+				return
+			}
+			r := YearRange{Comparison: e, Column: col, Conjunct: conjunct}
+			switch op {
+			case "=":
+				r.From, r.To = n, n+1
+			case ">":
+				r.From = n + 1
+			case ">=":
+				r.From = n
+			case "<":
+				r.To = n
+			case "<=":
+				r.To = n + 1
+			default:
+				return
+			}
+			found = append(found, r)
+		case *parser.BetweenExpression:
+			col := yearOf(e.Expression)
+			low, lowOK := integerLiteral(e.Low)
+			high, highOK := integerLiteral(e.High)
+			if col != nil && !e.Not && lowOK && highOK && low <= high {
+				found = append(found, YearRange{Comparison: e, Column: col, Conjunct: conjunct, From: low, To: high + 1})
+			}
+		case *parser.UnaryExpression:
+			walk(e.Operand, false)
+		}
+	}
+	walk(expr, true)
+	return found
+}
+
+// yearOf returns the column of YEAR(col), EXTRACT(YEAR FROM col),
+// DATEPART(year, col) or DATE_PART('year', col)
+func yearOf(expr parser.Expression) *parser.ColumnReference {
+	switch e := expr.(type) {
+	case *parser.FunctionCall:
+		switch strings.ToUpper(e.Name) {
+		case "YEAR":
+			if len(e.Arguments) == 1 {
+				col, _ := e.Arguments[0].(*parser.ColumnReference)
+				return col
+			}
+		case "DATEPART", "DATE_PART":
+			if len(e.Arguments) != 2 {
+				return nil
+			}
+			part := ""
+			switch arg := e.Arguments[0].(type) {
+			case *parser.ColumnReference:
+				part = arg.Column
+			case *parser.Literal:
+				part, _ = arg.Value.(string)
+			}
+			switch strings.ToLower(part) {
+			case "year", "yy", "yyyy":
+				col, _ := e.Arguments[1].(*parser.ColumnReference)
+				return col
+			}
+		}
+	case *parser.ExtractExpression:
+		if strings.EqualFold(e.Field, "YEAR") {
+			col, _ := e.Source.(*parser.ColumnReference)
+			return col
+		}
+	}
+	return nil
+}
+
+func integerLiteral(expr parser.Expression) (int, bool) {
+	lit, ok := expr.(*parser.Literal)
+	if !ok {
+		return 0, false
+	}
+	n, ok := lit.Value.(int64)
+	if !ok || n <= 0 || n > 9998 {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// checkLeadingWildcard detects LIKE patterns that start with a wildcard,
+// which no index can seek on
+func (oe *OptimizationEngine) checkLeadingWildcard(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	scope := oe.predicateScope(stmt)
+	for _, cond := range scope.conditions {
+		walkPredicates(cond.expr, func(expr parser.Expression) bool {
+			bin, ok := expr.(*parser.BinaryExpression)
+			if !ok || (bin.Operator != "LIKE" && bin.Operator != "ILIKE") {
+				return true
+			}
+			col, isCol := bin.Left.(*parser.ColumnReference)
+			lit, isLit := bin.Right.(*parser.Literal)
+			if !isCol || !isLit {
+				return true
+			}
+			pattern, _ := lit.Value.(string)
+			if pattern == "" || (pattern[0] != '%' && pattern[0] != '_') {
+				return true
+			}
+
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "LEADING_WILDCARD",
+				Description:   fmt.Sprintf("%s '%s' on column %s starts with a wildcard, so no index can seek on it", bin.Operator, pattern, col.String()),
+				Severity:      "WARNING",
+				Category:      "PERFORMANCE",
+				Rule:          "LEADING_WILDCARD",
+				Table:         scope.tableName(col),
+				ColumnName:    col.Column,
+				Operator:      bin.Operator,
+				Suggestion:    "Anchor the pattern at the start, or search the text another way",
+				Impact:        "HIGH",
+				AutoFixable:   false,
+				FixSuggestion: oe.textSearchSuggestion(col.Column),
+			})
+			return false
+		})
+	}
+
+	return suggestions
+}
+
+// textSearchSuggestion returns the dialect's way to search inside text
+func (oe *OptimizationEngine) textSearchSuggestion(column string) string {
+	switch oe.dialect.Name() {
+	case "PostgreSQL":
+		return fmt.Sprintf("Create a trigram index: CREATE INDEX ... USING gin (%s gin_trgm_ops)", column)
+	case "MySQL":
+		return fmt.Sprintf("Create a FULLTEXT index on %s and search with MATCH ... AGAINST", column)
+	case "SQL Server":
+		return fmt.Sprintf("Create a full-text index on %s and search with CONTAINS", column)
+	case "SQLite":
+		return "Store the text in an FTS5 virtual table and search with MATCH"
+	case "Oracle":
+		return fmt.Sprintf("Create an Oracle Text index on %s and search with CONTAINS", column)
+	}
+	return "Use full-text search, or store the reversed value to search suffixes"
+}
+
+// checkOrAcrossColumns detects OR between conditions on different
+// columns, which one index can't serve
+func (oe *OptimizationEngine) checkOrAcrossColumns(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	scope := oe.predicateScope(stmt)
+	for _, cond := range scope.conditions {
+		walkPredicates(cond.expr, func(expr parser.Expression) bool {
+			bin, ok := expr.(*parser.BinaryExpression)
+			if !ok || bin.Operator != "OR" {
+				return true
+			}
+
+			var names []string
+			seen := make(map[string]bool)
+			for _, disjunct := range splitDisjuncts(bin) {
+				cols, ok := expressionColumns(disjunct)
+				if !ok {
+					return false
+				}
+				for _, col := range cols {
+					key := strings.ToLower(scope.tableName(col) + "." + col.Column)
+					if !seen[key] {
+						seen[key] = true
+						names = append(names, col.String())
+					}
+				}
+			}
+			if len(names) > 1 {
+				suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+					Type:          "OR_ACROSS_COLUMNS",
+					Description:   fmt.Sprintf("OR across columns %s in %s keeps a single index from serving the condition", strings.Join(names, ", "), cond.clause),
+					Severity:      "INFO",
+					Category:      "PERFORMANCE",
+					Rule:          "OR_ACROSS_COLUMNS",
+					ColumnName:    strings.Join(names, ", "),
+					Operator:      "OR",
+					Suggestion:    "Split the condition so that each column's index can be used",
+					Impact:        "MEDIUM",
+					AutoFixable:   false,
+					FixSuggestion: "Rewrite as a UNION of one query per column, each served by its own index",
+				})
+			}
+			return false
+		})
+	}
+
+	return suggestions
+}
+
+// splitDisjuncts returns the operands of a chain of ORs
+func splitDisjuncts(expr parser.Expression) []parser.Expression {
+	if bin, ok := expr.(*parser.BinaryExpression); ok && bin.Operator == "OR" {
+		return append(splitDisjuncts(bin.Left), splitDisjuncts(bin.Right)...)
+	}
+	return []parser.Expression{expr}
+}
+
+// checkNotInNulls detects NOT IN over a list with NULL or a subquery that
+// may return NULL, which makes the condition unknown for every row
+func (oe *OptimizationEngine) checkNotInNulls(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	scope := oe.predicateScope(stmt)
+	for _, cond := range scope.conditions {
+		walkPredicates(cond.expr, func(expr parser.Expression) bool {
+			in, ok := expr.(*parser.InExpression)
+			if !ok || !in.Not {
+				return true
+			}
+			column := in.Expression.String()
+			if col, ok := in.Expression.(*parser.ColumnReference); ok {
+				column = col.Column
+			}
+
+			suggestion := EnhancedOptimizationSuggestion{
+				Type:          "NOT_IN_NULL",
+				Severity:      "WARNING",
+				Category:      "BEST_PRACTICE",
+				Rule:          "NOT_IN_NULL",
+				ColumnName:    column,
+				Operator:      "NOT IN",
+				Suggestion:    "Use NOT EXISTS, which is not affected by NULLs",
+				Impact:        "HIGH",
+				AutoFixable:   false,
+				FixSuggestion: "Replace NOT IN (SELECT x ...) with NOT EXISTS (SELECT 1 ... WHERE x = outer.column)",
+			}
+			if col, ok := in.Expression.(*parser.ColumnReference); ok {
+				suggestion.Table = scope.tableName(col)
+			}
+
+			if len(in.Values) == 1 {
+				if sub, ok := in.Values[0].(*parser.SubqueryExpression); ok {
+					selected, nullable := oe.subqueryNullable(sub.Query)
+					if !nullable {
+						return false
+					}
+					suggestion.Description = fmt.Sprintf("%s NOT IN (SELECT %s ...) is never true once the subquery returns a NULL", in.Expression.String(), selected)
+					suggestions = append(suggestions, suggestion)
+					return false
+				}
+			}
+			for _, value := range in.Values {
+				if isNullLiteral(value) {
+					suggestion.Description = fmt.Sprintf("%s NOT IN (...) lists NULL, so the condition is never true", in.Expression.String())
+					suggestion.Suggestion = "Remove NULL from the list"
+					suggestion.FixSuggestion = "Remove NULL from the NOT IN list and test the column with IS NOT NULL if needed"
+					suggestions = append(suggestions, suggestion)
+					break
+				}
+			}
+			return false
+		})
+	}
+
+	return suggestions
+}
+
+// subqueryNullable returns the column a subquery selects and whether it
+// may be NULL. Without a schema that says otherwise it may, unless the
+// subquery filters NULLs out.
+func (oe *OptimizationEngine) subqueryNullable(sub *parser.SelectStatement) (string, bool) {
+	if sub == nil || len(sub.Columns) != 1 {
+		return "...", true
+	}
+	expr := sub.Columns[0]
+	if aliased, ok := expr.(*parser.AliasedExpression); ok {
+		expr = aliased.Expression
+	}
+	col, ok := expr.(*parser.ColumnReference)
+	if !ok {
+		return expr.String(), true
+	}
+
+	for _, cond := range splitConjuncts(sub.Where) {
+		if bin, ok := cond.(*parser.BinaryExpression); ok && bin.Operator == "IS NOT" && isNullLiteral(bin.Right) {
+			if filtered, ok := bin.Left.(*parser.ColumnReference); ok && strings.EqualFold(filtered.Column, col.Column) {
+				return col.String(), false
+			}
+		}
+	}
+
+	if _, column := oe.predicateScope(sub).column(col); column != nil {
+		notNull := column.IsPrimaryKey || (column.DataType != nil && !column.DataType.Nullable)
+		return col.String(), !notNull
+	}
+	return col.String(), true
+}
+
+// Type families for implicit conversions
+const (
+	familyString   = "string"
+	familyNational = "national string"
+	familyNumber   = "number"
+)
+
+// typeFamily returns the family of a schema type, or "" for other types
+func typeFamily(dt *schema.DataType) string {
+	if dt == nil {
+		return ""
+	}
+	switch strings.ToUpper(dt.Name) {
+	case "NVARCHAR", "NCHAR", "NTEXT", "NVARCHAR2":
+		return familyNational
+	case "VARCHAR", "CHAR", "TEXT", "CHARACTER", "CHARACTER VARYING", "VARCHAR2", "CLOB",
+		"TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "CITEXT", "STRING":
+		return familyString
+	case "INT", "INTEGER", "BIGINT", "SMALLINT", "TINYINT", "MEDIUMINT", "DECIMAL", "NUMERIC",
+		"NUMBER", "FLOAT", "DOUBLE", "DOUBLE PRECISION", "REAL", "MONEY", "SMALLMONEY", "SERIAL", "BIGSERIAL":
+		return familyNumber
+	}
+	return ""
+}
+
+// valueSQL returns an expression for a message, with strings quoted
+func valueSQL(expr parser.Expression) string {
+	if lit, ok := expr.(*parser.Literal); ok {
+		if v, ok := lit.Value.(string); ok {
+			return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		}
+	}
+	return expr.String()
+}
+
+// literalFamily returns the family of a literal's value
+func literalFamily(lit *parser.Literal) string {
+	switch lit.Value.(type) {
+	case int64, float64:
+		return familyNumber
+	case string:
+		if lit.Kind == lexer.LiteralNational {
+			return familyNational
+		}
+		return familyString
+	}
+	return ""
+}
+
+// convertsColumn reports whether comparing a column of one family with a
+// value of another converts the column's value on every row. A number
+// column compared with a string converts the string instead.
+func (oe *OptimizationEngine) convertsColumn(column, value string) bool {
+	if column != familyString && column != familyNational {
+		return false
+	}
+	if value == familyNumber {
+		return true
+	}
+	// A Unicode value makes SQL Server convert a non-Unicode column
+	return column == familyString && value == familyNational && oe.dialect.Name() == "SQL Server"
+}
+
+// checkImplicitConversion detects comparisons whose types make the
+// database convert the column, which keeps its index from being used.
+// It needs a schema for the column types.
+func (oe *OptimizationEngine) checkImplicitConversion(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+	if oe.schema == nil {
+		return suggestions
+	}
+
+	scope := oe.predicateScope(stmt)
+	report := func(col *parser.ColumnReference, column *schema.Column, operator, other, clause string) {
+		suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+			Type:          "IMPLICIT_CONVERSION",
+			Description:   fmt.Sprintf("Comparing %s column %s with %s in %s converts the column on every row", column.DataType.Name, col.String(), other, clause),
+			Severity:      "WARNING",
+			Category:      "PERFORMANCE",
+			Rule:          "IMPLICIT_CONVERSION",
+			Table:         scope.tableName(col),
+			ColumnName:    col.Column,
+			Operator:      operator,
+			Suggestion:    "Compare values of the column's own type",
+			Impact:        "HIGH",
+			AutoFixable:   false,
+			FixSuggestion: fmt.Sprintf("Write the compared value as a %s, or convert it instead of the column", strings.ToUpper(column.DataType.Name)),
+		})
+	}
+
+	for _, cond := range scope.conditions {
+		walkPredicates(cond.expr, func(expr parser.Expression) bool {
+			cmp, ok := asComparison(expr)
+			if !ok {
+				return true
+			}
+			for _, side := range cmp.sides() {
+				col, ok := side.column.(*parser.ColumnReference)
+				if !ok {
+					continue
+				}
+				_, column := scope.column(col)
+				if column == nil {
+					continue
+				}
+				family := typeFamily(column.DataType)
+				for _, other := range side.others {
+					switch o := other.(type) {
+					case *parser.Literal:
+						if oe.convertsColumn(family, literalFamily(o)) {
+							report(col, column, cmp.operator, valueSQL(o), cond.clause)
+						}
+					case *parser.ColumnReference:
+						if _, otherColumn := scope.column(o); otherColumn != nil {
+							if oe.convertsColumn(family, typeFamily(otherColumn.DataType)) {
+								report(col, column, cmp.operator, fmt.Sprintf("%s column %s", otherColumn.DataType.Name, o.String()), cond.clause)
+							}
+						}
+					default:
+						continue
+					}
+					break // one finding per column and comparison
+				}
+			}
+			return true
+		})
+	}
+
+	return suggestions
+}
+
+// checkNotEqualIndexed detects <> on a column that leads an index, which
+// the index can't seek on. It needs a schema for the indexes.
+func (oe *OptimizationEngine) checkNotEqualIndexed(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+	if oe.schema == nil {
+		return suggestions
+	}
+
+	scope := oe.predicateScope(stmt)
+	for _, cond := range scope.conditions {
+		walkPredicates(cond.expr, func(expr parser.Expression) bool {
+			bin, ok := expr.(*parser.BinaryExpression)
+			if !ok || (bin.Operator != "<>" && bin.Operator != "!=") {
+				return true
+			}
+			col, isCol := bin.Left.(*parser.ColumnReference)
+			value := bin.Right
+			if !isCol {
+				col, isCol = bin.Right.(*parser.ColumnReference)
+				value = bin.Left
+			}
+			if !isCol || !isConstantExpression(value) || isNullLiteral(value) {
+				return true
+			}
+			table, _ := scope.column(col)
+			if table == nil || !leadsIndex(table, col.Column) {
+				return true
+			}
+
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "NOT_EQUAL_INDEXED",
+				Description:   fmt.Sprintf("%s %s on indexed column %s in %s can't seek on the index", bin.Operator, valueSQL(value), col.String(), cond.clause),
+				Severity:      "INFO",
+				Category:      "PERFORMANCE",
+				Rule:          "NOT_EQUAL_INDEXED",
+				Table:         table.Name,
+				ColumnName:    col.Column,
+				Operator:      bin.Operator,
+				Suggestion:    "Select the wanted values rather than excluding one",
+				Impact:        "LOW",
+				AutoFixable:   false,
+				FixSuggestion: fmt.Sprintf("If few values remain, list them: %s IN (...)", col.String()),
+			})
+			return true
+		})
+	}
+
+	return suggestions
+}
+
+// Security-related checks
+
+// checkSQLInjectionRisk detects dynamic SQL built by concatenating
+// variables, and always-true OR conditions typical of injected input
+func (oe *OptimizationEngine) checkSQLInjectionRisk(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	if exec, ok := stmt.(*parser.ExecStatement); ok && exec.SQL != nil {
+		if operator, variable := concatenatedVariable(exec.SQL); variable != "" {
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "SQL_INJECTION_RISK",
+				Description:   fmt.Sprintf("Dynamic SQL is built by concatenating %s into the statement text", variable),
+				Severity:      "CRITICAL",
+				Category:      "SECURITY",
+				Rule:          "SQL_INJECTION_RISK",
+				ColumnName:    variable,
+				Operator:      operator,
+				Suggestion:    "Use parameterized queries",
+				Impact:        "HIGH",
+				AutoFixable:   false,
+				FixSuggestion: "Pass values as parameters of sp_executesql instead of concatenating them into the SQL text",
+			})
+		}
+	}
+
+	scope := oe.predicateScope(stmt)
+	for _, cond := range scope.conditions {
+		walkPredicates(cond.expr, func(expr parser.Expression) bool {
+			bin, ok := expr.(*parser.BinaryExpression)
+			if !ok || bin.Operator != "OR" {
+				return true
+			}
+			for _, disjunct := range splitDisjuncts(bin) {
+				if tautology, ok := disjunct.(*parser.BinaryExpression); ok && isTautology(tautology) {
+					suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+						Type:          "SQL_INJECTION_RISK",
+						Description:   fmt.Sprintf("Always-true condition OR %s = %s in %s is typical of injected input", valueSQL(tautology.Left), valueSQL(tautology.Right), cond.clause),
+						Severity:      "CRITICAL",
+						Category:      "SECURITY",
+						Rule:          "SQL_INJECTION_RISK",
+						Operator:      "OR",
+						Suggestion:    "Use parameterized queries",
+						Impact:        "HIGH",
+						AutoFixable:   false,
+						FixSuggestion: "Pass input as query parameters so that it can't add conditions",
+					})
+				}
+			}
+			return false
+		})
+	}
+
+	return suggestions
+}
+
+// concatenatedVariable returns the operator and the first variable or
+// column concatenated into a string expression
+func concatenatedVariable(expr parser.Expression) (string, string) {
+	switch e := expr.(type) {
+	case *parser.BinaryExpression:
+		if e.Operator != "+" && e.Operator != "||" {
+			return "", ""
+		}
+		for _, operand := range []parser.Expression{e.Left, e.Right} {
+			switch o := operand.(type) {
+			case *parser.Parameter:
+				return e.Operator, o.Name
+			case *parser.ColumnReference:
+				return e.Operator, o.String()
+			}
+			if operator, variable := concatenatedVariable(operand); variable != "" {
+				return operator, variable
+			}
+		}
+	case *parser.FunctionCall:
+		if !strings.EqualFold(e.Name, "CONCAT") {
+			return "", ""
+		}
+		for _, arg := range e.Arguments {
+			switch a := arg.(type) {
+			case *parser.Parameter:
+				return "CONCAT", a.Name
+			case *parser.ColumnReference:
+				return "CONCAT", a.String()
+			}
+		}
+	}
+	return "", ""
+}
+
+// isTautology reports whether a comparison is between two equal
+// constants, such as 1 = 1 or 'a' = 'a'
+func isTautology(bin *parser.BinaryExpression) bool {
+	if bin.Operator != "=" {
+		return false
+	}
+	left, ok := bin.Left.(*parser.Literal)
+	right, ok2 := bin.Right.(*parser.Literal)
+	return ok && ok2 && left.Value != nil && fmt.Sprint(left.Value) == fmt.Sprint(right.Value)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
 )
 
@@ -24,7 +25,7 @@ func (oe *OptimizationEngine) checkSelectStar(stmt parser.Statement) []EnhancedO
 					Rule:          "SELECT_STAR",
 					Suggestion:    "Replace SELECT * with specific column names",
 					Impact:        "MEDIUM",
-					AutoFixable:   false,
+					AutoFixable:   oe.schema != nil, // the schema lists the columns
 					FixSuggestion: "List only the columns you actually need: SELECT col1, col2, col3 FROM ...",
 				})
 				break
@@ -70,8 +71,9 @@ func (oe *OptimizationEngine) checkCartesianProduct(stmt parser.Statement) []Enh
 
 		joinCount := len(selectStmt.Joins)
 
-		// If we have multiple tables but no proper joins, potential Cartesian product
-		if tableCount > 1 && joinCount == 0 {
+		// If we have multiple tables but no proper joins or WHERE conditions
+		// relating them, potential Cartesian product
+		if tableCount > 1 && joinCount == 0 && !joinedByWhere(selectStmt.From.Tables, selectStmt.Where) {
 			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
 				Type:          "CARTESIAN_PRODUCT",
 				Description:   "Potential Cartesian product detected - missing JOIN conditions",
@@ -80,14 +82,30 @@ func (oe *OptimizationEngine) checkCartesianProduct(stmt parser.Statement) []Enh
 				Rule:          "CARTESIAN_PRODUCT",
 				Suggestion:    "Add proper JOIN conditions between tables",
 				Impact:        "HIGH",
-				AutoFixable:   false,
+				AutoFixable:   true,
 				FixSuggestion: "Use explicit JOIN syntax with ON conditions instead of comma-separated tables",
 			})
 		}
 
-		// Check for JOINs without conditions
+		// Check for JOINs without conditions. USING, NATURAL, LATERAL and
+		// APPLY joins match or correlate rows without an ON clause.
 		for _, join := range selectStmt.Joins {
-			if join.Condition == nil {
+			if join.JoinType == "CROSS" && !join.Lateral {
+				suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+					Type:          "CARTESIAN_PRODUCT",
+					Description:   fmt.Sprintf("CROSS JOIN with table '%s' produces a Cartesian product", join.Table.Name),
+					Severity:      "WARNING",
+					Category:      "PERFORMANCE",
+					Rule:          "CARTESIAN_PRODUCT",
+					Table:         join.Table.Name,
+					Suggestion:    "Confirm that every combination of rows is intended",
+					Impact:        "HIGH",
+					AutoFixable:   false,
+					FixSuggestion: "Replace the CROSS JOIN with an INNER JOIN ... ON if the tables are related",
+				})
+				continue
+			}
+			if !join.HasJoinCondition() {
 				suggestions = append(suggestions, EnhancedOptimizationSuggestion{
 					Type:          "CARTESIAN_PRODUCT",
 					Description:   fmt.Sprintf("JOIN without condition detected for table '%s'", join.Table.Name),
@@ -107,35 +125,62 @@ func (oe *OptimizationEngine) checkCartesianProduct(stmt parser.Statement) []Enh
 	return suggestions
 }
 
-// checkFunctionInWhere detects functions applied to columns in WHERE clauses
-func (oe *OptimizationEngine) checkFunctionInWhere(stmt parser.Statement) []EnhancedOptimizationSuggestion {
-	var suggestions []EnhancedOptimizationSuggestion
-
-	if selectStmt, ok := stmt.(*parser.SelectStatement); ok && selectStmt.Where != nil {
-		// This is a simplified check - in a real implementation, you'd parse the WHERE expression
-		whereStr := selectStmt.Where.String()
-
-		// Common functions that prevent index usage
-		functionPatterns := []string{"UPPER(", "LOWER(", "TRIM(", "SUBSTRING(", "DATEPART(", "YEAR(", "MONTH(", "DAY("}
-
-		for _, pattern := range functionPatterns {
-			if strings.Contains(strings.ToUpper(whereStr), pattern) {
-				suggestions = append(suggestions, EnhancedOptimizationSuggestion{
-					Type:          "FUNCTION_IN_WHERE",
-					Description:   fmt.Sprintf("Function %s in WHERE clause prevents index usage", strings.TrimSuffix(pattern, "(")),
-					Severity:      "WARNING",
-					Category:      "PERFORMANCE",
-					Rule:          "FUNCTION_IN_WHERE",
-					Suggestion:    "Avoid functions on columns in WHERE clause",
-					Impact:        "MEDIUM",
-					AutoFixable:   false,
-					FixSuggestion: "Consider using computed columns or restructuring the query to avoid functions on indexed columns",
-				})
+// joinedByWhere reports whether WHERE conditions join every one of the
+// comma-separated tables to the others, as in FROM a, b WHERE a.id = b.a_id
+func joinedByWhere(tables []parser.TableReference, where parser.Expression) bool {
+	// group[i] is the lowest table that table i is joined with
+	group := make([]int, len(tables))
+	for i := range group {
+		group[i] = i
+	}
+	for _, cond := range splitConjuncts(where) {
+		cols, ok := expressionColumns(cond)
+		if !ok {
+			continue
+		}
+		first := -1
+		for _, col := range cols {
+			i := qualifiedTable(tables, col.Table)
+			if i < 0 {
+				continue
+			}
+			if first < 0 {
+				first = i
+				continue
+			}
+			from, to := max(group[i], group[first]), min(group[i], group[first])
+			for k := range group {
+				if group[k] == from {
+					group[k] = to
+				}
 			}
 		}
 	}
 
-	return suggestions
+	for _, g := range group {
+		if g != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// qualifiedTable returns the index of the table a column qualifier names,
+// its alias or else its name, or -1
+func qualifiedTable(tables []parser.TableReference, qualifier string) int {
+	if qualifier == "" {
+		return -1
+	}
+	for i, t := range tables {
+		name := t.Alias
+		if name == "" {
+			name = t.Name
+		}
+		if strings.EqualFold(name, qualifier) {
+			return i
+		}
+	}
+	return -1
 }
 
 // checkInefficientSubquery detects subqueries that could be optimized
@@ -143,25 +188,20 @@ func (oe *OptimizationEngine) checkInefficientSubquery(stmt parser.Statement) []
 	var suggestions []EnhancedOptimizationSuggestion
 
 	if selectStmt, ok := stmt.(*parser.SelectStatement); ok && selectStmt.Where != nil {
-		// Check for InExpression with subqueries
-		if inExpr, ok := selectStmt.Where.(*parser.InExpression); ok {
-			// Check if any of the values in the IN expression are subqueries
-			for _, value := range inExpr.Values {
-				if _, isSubquery := value.(*parser.SubqueryExpression); isSubquery {
-					suggestions = append(suggestions, EnhancedOptimizationSuggestion{
-						Type:          "INEFFICIENT_SUBQUERY",
-						Description:   "Subquery in WHERE clause may be optimized as a JOIN",
-						Severity:      "INFO",
-						Category:      "PERFORMANCE",
-						Rule:          "INEFFICIENT_SUBQUERY",
-						Suggestion:    "Consider converting subquery to JOIN",
-						Impact:        "MEDIUM",
-						AutoFixable:   false,
-						FixSuggestion: "Replace correlated subqueries with JOINs when possible for better performance",
-					})
-					break // Only need to suggest once per statement
-				}
-			}
+		// Check for IN (SELECT ...) among the WHERE conditions. Only need
+		// to suggest once per statement.
+		if inExpr := firstInSubquery(selectStmt.Where); inExpr != nil {
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "INEFFICIENT_SUBQUERY",
+				Description:   "Subquery in WHERE clause may be optimized as a JOIN",
+				Severity:      "INFO",
+				Category:      "PERFORMANCE",
+				Rule:          "INEFFICIENT_SUBQUERY",
+				Suggestion:    "Consider converting subquery to JOIN",
+				Impact:        "MEDIUM",
+				AutoFixable:   !inExpr.Not, // IN rewrites to EXISTS, NOT IN differs on NULLs
+				FixSuggestion: "Replace correlated subqueries with JOINs when possible for better performance",
+			})
 		}
 
 		// Also check for string patterns as fallback (for cases we might have missed)
@@ -184,6 +224,28 @@ func (oe *OptimizationEngine) checkInefficientSubquery(stmt parser.Statement) []
 	return suggestions
 }
 
+// firstInSubquery returns the first IN (SELECT ...) condition of a WHERE
+// clause, looking through AND and OR
+func firstInSubquery(expr parser.Expression) *parser.InExpression {
+	switch e := expr.(type) {
+	case *parser.BinaryExpression:
+		if e.Operator != "AND" && e.Operator != "OR" {
+			return nil
+		}
+		if inExpr := firstInSubquery(e.Left); inExpr != nil {
+			return inExpr
+		}
+		return firstInSubquery(e.Right)
+	case *parser.InExpression:
+		for _, value := range e.Values {
+			if _, isSubquery := value.(*parser.SubqueryExpression); isSubquery {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
 // checkUnnecessaryDistinct detects potentially unnecessary DISTINCT usage
 func (oe *OptimizationEngine) checkUnnecessaryDistinct(stmt parser.Statement) []EnhancedOptimizationSuggestion {
 	var suggestions []EnhancedOptimizationSuggestion
@@ -210,48 +272,28 @@ func (oe *OptimizationEngine) checkUnnecessaryDistinct(stmt parser.Statement) []
 	return suggestions
 }
 
-// checkIndexOpportunities suggests potential index opportunities
+// checkIndexOpportunities reports the indexes the advisor proposes
 func (oe *OptimizationEngine) checkIndexOpportunities(stmt parser.Statement) []EnhancedOptimizationSuggestion {
 	var suggestions []EnhancedOptimizationSuggestion
 
-	if selectStmt, ok := stmt.(*parser.SelectStatement); ok {
-		// Analyze WHERE clause for potential index candidates
-		if selectStmt.Where != nil {
-			whereStr := selectStmt.Where.String()
-
-			// This is simplified - in practice, you'd parse the expression tree
-			if strings.Contains(whereStr, "=") || strings.Contains(whereStr, ">") || strings.Contains(whereStr, "<") {
-				suggestions = append(suggestions, EnhancedOptimizationSuggestion{
-					Type:          "INDEX_SUGGESTION",
-					Description:   "Columns in WHERE clause may benefit from indexes",
-					Severity:      "INFO",
-					Category:      "PERFORMANCE",
-					Rule:          "INDEX_SUGGESTION",
-					Suggestion:    "Consider adding indexes on frequently queried columns",
-					Impact:        "HIGH",
-					AutoFixable:   false,
-					FixSuggestion: "Analyze query execution plan and consider creating indexes on columns used in WHERE, JOIN, and ORDER BY clauses",
-				})
-			}
-		}
-
-		// Check JOIN conditions
-		for _, join := range selectStmt.Joins {
-			if join.Condition != nil {
-				suggestions = append(suggestions, EnhancedOptimizationSuggestion{
-					Type:          "INDEX_SUGGESTION",
-					Description:   fmt.Sprintf("JOIN condition on table '%s' may benefit from index", join.Table.Name),
-					Severity:      "INFO",
-					Category:      "PERFORMANCE",
-					Rule:          "INDEX_SUGGESTION",
-					Table:         join.Table.Name,
-					Suggestion:    "Consider adding index on JOIN columns",
-					Impact:        "HIGH",
-					AutoFixable:   false,
-					FixSuggestion: "Create indexes on columns used in JOIN conditions for better performance",
-				})
-			}
+	for _, rec := range NewIndexAdvisor(oe.dialect, oe.schema).Advise(stmt) {
+		description := fmt.Sprintf("An index on %s (%s) would serve %s", rec.Table, strings.Join(rec.Columns, ", "), rec.Reason)
+		if rec.Supersede != "" {
+			description += fmt.Sprintf(", extending %s", rec.Supersede)
 		}
+		suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+			Type:          "INDEX_SUGGESTION",
+			Description:   description,
+			Severity:      "INFO",
+			Category:      "PERFORMANCE",
+			Rule:          "INDEX_SUGGESTION",
+			Table:         rec.Table,
+			ColumnName:    strings.Join(rec.Columns, ", "),
+			Suggestion:    "Create the index if the query runs often",
+			Impact:        "HIGH",
+			AutoFixable:   false,
+			FixSuggestion: rec.DDL,
+		})
 	}
 
 	return suggestions
@@ -280,41 +322,6 @@ func (oe *OptimizationEngine) checkJoinOrder(stmt parser.Statement) []EnhancedOp
 	return suggestions
 }
 
-// Security-related checks
-
-// checkSQLInjectionRisk detects potential SQL injection vulnerabilities
-func (oe *OptimizationEngine) checkSQLInjectionRisk(stmt parser.Statement) []EnhancedOptimizationSuggestion {
-	var suggestions []EnhancedOptimizationSuggestion
-
-	// This is a basic check - in practice, you'd need more sophisticated analysis
-	// For now, we'll check for common patterns that might indicate dynamic SQL
-
-	if selectStmt, ok := stmt.(*parser.SelectStatement); ok && selectStmt.Where != nil {
-		whereStr := selectStmt.Where.String()
-
-		// Check for potential string concatenation patterns
-		suspiciousPatterns := []string{"'+'", "CONCAT(", "||"}
-
-		for _, pattern := range suspiciousPatterns {
-			if strings.Contains(strings.ToUpper(whereStr), pattern) {
-				suggestions = append(suggestions, EnhancedOptimizationSuggestion{
-					Type:          "SQL_INJECTION_RISK",
-					Description:   "Potential SQL injection risk detected in WHERE clause",
-					Severity:      "CRITICAL",
-					Category:      "SECURITY",
-					Rule:          "SQL_INJECTION_RISK",
-					Suggestion:    "Use parameterized queries",
-					Impact:        "HIGH",
-					AutoFixable:   false,
-					FixSuggestion: "Replace string concatenation with parameterized queries or prepared statements",
-				})
-			}
-		}
-	}
-
-	return suggestions
-}
-
 // checkOverprivilegedSelect detects queries that may access too much data
 func (oe *OptimizationEngine) checkOverprivilegedSelect(stmt parser.Statement) []EnhancedOptimizationSuggestion {
 	var suggestions []EnhancedOptimizationSuggestion
@@ -346,3 +353,117 @@ func (oe *OptimizationEngine) checkOverprivilegedSelect(stmt parser.Statement) [
 
 	return suggestions
 }
+
+// checkNullComparison detects = NULL and <> NULL, which are unknown for
+// every row
+func (oe *OptimizationEngine) checkNullComparison(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	var conditions []parser.Expression
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		conditions = append(conditions, s.Where, s.Having)
+		for _, join := range s.Joins {
+			conditions = append(conditions, join.Condition)
+		}
+	case *parser.UpdateStatement:
+		conditions = append(conditions, s.Where)
+	case *parser.DeleteStatement:
+		conditions = append(conditions, s.Where)
+	}
+
+	for _, cond := range conditions {
+		for _, cmp := range NullComparisons(cond) {
+			check := "IS NULL"
+			if cmp.Operator != "=" {
+				check = "IS NOT NULL"
+			}
+			suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+				Type:          "NULL_COMPARISON",
+				Description:   fmt.Sprintf("Comparison %s NULL is never true", cmp.Operator),
+				Severity:      "WARNING",
+				Category:      "BEST_PRACTICE",
+				Rule:          "NULL_COMPARISON",
+				Suggestion:    fmt.Sprintf("Use %s", check),
+				Impact:        "HIGH",
+				AutoFixable:   true,
+				FixSuggestion: fmt.Sprintf("Replace %s NULL with %s", cmp.Operator, check),
+			})
+		}
+	}
+
+	return suggestions
+}
+
+// NullComparisons returns the =, <> and != comparisons with a NULL literal
+// in an expression, outside of subqueries
+func NullComparisons(expr parser.Expression) []*parser.BinaryExpression {
+	var found []*parser.BinaryExpression
+	var walk func(parser.Expression)
+	walk = func(expr parser.Expression) {
+		switch e := expr.(type) {
+		case *parser.BinaryExpression:
+			switch e.Operator {
+			case "=", "<>", "!=":
+				if isNullLiteral(e.Left) || isNullLiteral(e.Right) {
+					found = append(found, e)
+					return
+				}
+			}
+			walk(e.Left)
+			walk(e.Right)
+		case *parser.UnaryExpression:
+			walk(e.Operand)
+		case *parser.FunctionCall:
+			for _, arg := range e.Arguments {
+				walk(arg)
+			}
+		case *parser.CaseExpression:
+			walk(e.Input)
+			for _, when := range e.WhenClauses {
+				walk(when.Condition)
+				walk(when.Result)
+			}
+			walk(e.ElseResult)
+		}
+	}
+	walk(expr)
+	return found
+}
+
+func isNullLiteral(expr parser.Expression) bool {
+	lit, ok := expr.(*parser.Literal)
+	return ok && lit.Value == nil
+}
+
+// checkUnboundedDelete detects DELETE statements that remove every row
+func (oe *OptimizationEngine) checkUnboundedDelete(stmt parser.Statement) []EnhancedOptimizationSuggestion {
+	var suggestions []EnhancedOptimizationSuggestion
+
+	if deleteStmt, ok := stmt.(*parser.DeleteStatement); ok && deleteStmt.Where == nil && deleteStmt.Limit == nil {
+		suggestions = append(suggestions, EnhancedOptimizationSuggestion{
+			Type:          "UNBOUNDED_DELETE",
+			Description:   fmt.Sprintf("DELETE without WHERE removes every row of %s in one transaction", deleteStmt.From.Name),
+			Severity:      "WARNING",
+			Category:      "BEST_PRACTICE",
+			Rule:          "UNBOUNDED_DELETE",
+			Table:         deleteStmt.From.Name,
+			Suggestion:    "Add a WHERE clause, delete in batches or use TRUNCATE",
+			Impact:        "HIGH",
+			AutoFixable:   oe.dialect != nil && SupportsDeleteLimit(oe.dialect),
+			FixSuggestion: "Delete in batches with a row limit, repeating until no rows are affected",
+		})
+	}
+
+	return suggestions
+}
+
+// SupportsDeleteLimit reports whether a dialect can bound a single-table
+// DELETE: MySQL with LIMIT n, SQL Server with TOP (n)
+func SupportsDeleteLimit(d dialect.Dialect) bool {
+	switch d.Name() {
+	case "MySQL", "SQL Server":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,397 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// PipelineOptions configures the stages AnalyzeStream runs on each job
+type PipelineOptions struct {
+	Workers int           // jobs processed at once; 0 for the analyzer's worker count
+	Timeout time.Duration // per job; 0 for none
+	Ordered bool          // emit results in the order of the jobs
+
+	// Dialect of jobs that don't set one; nil detects it from the query
+	Dialect dialect.Dialect
+
+	// Weights and Threshold are the complexity model; zero Weights keeps
+	// the defaults
+	Weights   ComplexityWeights
+	Threshold int
+
+	// Optimize runs the optimization rules, with the settings Rules returns
+	// for a job's ID, e.g. LintConfig.RulesFor, and the sqlens: directives
+	// of its query
+	Optimize bool
+	Rules    func(id string) map[string]RuleConfig
+
+	// Schema feeds row estimates and migration rules, and checks the names
+	// and types statements use
+	Schema *schema.Schema
+}
+
+// AnalyzeStream parses, analyzes, optimizes and validates the queries of
+// jobs as they arrive, with at most opts.Workers jobs processed and twice
+// as many results held at once. The results channel is closed once jobs
+// is closed and drained, or ctx is done; results not yet sent are dropped.
+func (ca *ConcurrentAnalyzer) AnalyzeStream(ctx context.Context, jobs <-chan AnalysisJob, opts PipelineOptions) <-chan AnalysisResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = ca.workerCount
+	}
+
+	type indexedJob struct {
+		index int
+		job   AnalysisJob
+	}
+	work := make(chan indexedJob)
+	done := make(chan AnalysisResult, workers)
+	results := make(chan AnalysisResult, workers)
+	// A slot is held from dispatch to emit, bounding the results an
+	// ordered stream waits on
+	slots := make(chan struct{}, 2*workers)
+
+	// Dispatch
+	go func() {
+		defer close(work)
+		for index := 0; ; index++ {
+			var job AnalysisJob
+			var ok bool
+			select {
+			case job, ok = <-jobs:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case work <- indexedJob{index, job}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Run the stages
+	ca.mu.RLock()
+	cache := ca.cache
+	ca.mu.RUnlock()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := newPipelineWorker(opts, cache)
+			for ij := range work {
+				result := w.run(ctx, ij.job)
+				result.Index = ij.index
+				done <- result
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Emit
+	go func() {
+		defer close(results)
+		emit := func(result AnalysisResult) {
+			select {
+			case results <- result:
+			case <-ctx.Done():
+			}
+			<-slots
+		}
+
+		pending := make(map[int]AnalysisResult)
+		next := 0
+		for result := range done {
+			if !opts.Ordered {
+				emit(result)
+				continue
+			}
+			pending[result.Index] = result
+			for r, ok := pending[next]; ok; r, ok = pending[next] {
+				delete(pending, next)
+				emit(r)
+				next++
+			}
+		}
+	}()
+
+	return results
+}
+
+// AnalyzeSeq runs AnalyzeStream over a sequence of jobs. Stopping the
+// iteration stops the pipeline.
+func (ca *ConcurrentAnalyzer) AnalyzeSeq(ctx context.Context, jobs iter.Seq[AnalysisJob], opts PipelineOptions) iter.Seq[AnalysisResult] {
+	return func(yield func(AnalysisResult) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		in := make(chan AnalysisJob)
+		go func() {
+			defer close(in)
+			for job := range jobs {
+				select {
+				case in <- job:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		for result := range ca.AnalyzeStream(ctx, in, opts) {
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
+// pipelineWorker holds the analyzers and engines of one worker, which are
+// not safe for concurrent use
+type pipelineWorker struct {
+	opts      PipelineOptions
+	cache     *AnalysisCache
+	analyzers map[string]*Analyzer           // by dialect, version and engine
+	engines   map[string]*OptimizationEngine // by dialect, version and rule settings
+}
+
+func newPipelineWorker(opts PipelineOptions, cache *AnalysisCache) *pipelineWorker {
+	return &pipelineWorker{
+		opts:      opts,
+		cache:     cache,
+		analyzers: make(map[string]*Analyzer),
+		engines:   make(map[string]*OptimizationEngine),
+	}
+}
+
+// run processes a job: the statements of Query, or Stmt when set
+func (w *pipelineWorker) run(ctx context.Context, job AnalysisJob) (result AnalysisResult) {
+	start := time.Now()
+	result.ID = job.ID
+	defer func() { result.Duration = time.Since(start) }()
+
+	if w.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.opts.Timeout)
+		defer cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		result.Error = err
+		return result
+	}
+
+	d := job.Dialect
+	if d == nil {
+		d = w.opts.Dialect
+	}
+	var guess *dialect.Guess
+	if d == nil && job.Stmt == nil {
+		var g dialect.Guess
+		d, g = dialect.DetectDialect(job.Query)
+		guess = &g
+	}
+	if d != nil {
+		result.Dialect = d.Name()
+	}
+
+	// Parse
+	stmts := []parser.Statement{job.Stmt}
+	lines := [][2]int{{1, strings.Count(job.Query, "\n") + 1}}
+	fingerprints := []string{""}
+	if job.Query != "" {
+		fingerprints[0] = QueryFingerprint(job.Query, d) // Query is the text of Stmt
+	}
+	var p *parser.Parser
+	if job.Stmt == nil {
+		p = parser.NewWithDialect(ctx, job.Query, d)
+		p.EnableSpans()
+		var err error
+		if stmts, err = p.ParseStatements(); err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			result.Error = fmt.Errorf("failed to parse query: %w", err)
+			return result
+		}
+		lines = lines[:0]
+		fingerprints = fingerprints[:0]
+		for _, stmt := range stmts {
+			span, ok := p.Span(stmt)
+			if !ok {
+				span = parser.Span{End: len(job.Query)}
+			}
+			lines = append(lines, [2]int{
+				strings.Count(job.Query[:span.Start], "\n") + 1,
+				strings.Count(job.Query[:span.End], "\n") + 1,
+			})
+			fingerprints = append(fingerprints, QueryFingerprint(job.Query[span.Start:span.End], d))
+		}
+	}
+
+	var engine *OptimizationEngine
+	var suppressions *Suppressions
+	if w.opts.Optimize {
+		var err error
+		if engine, err = w.engine(d, job.ID); err != nil {
+			result.Error = err
+			return result
+		}
+		if p != nil {
+			suppressions = ParseSuppressions(p.Comments())
+		}
+	}
+	a := w.analyzer(d, engine)
+
+	// The validator tracks temp tables, table variables and USE of the
+	// job's script, so each job starts a fresh one
+	var validator *schema.Validator
+	var checker *schema.TypeChecker
+	if w.opts.Schema != nil {
+		validator = schema.NewValidator(w.opts.Schema)
+		checker = schema.NewTypeChecker(w.opts.Schema)
+	}
+
+	result.Fingerprints = fingerprints
+	for i, stmt := range stmts {
+		if err := ctx.Err(); err != nil {
+			result.Error = err
+			return result
+		}
+
+		// Analyze and optimize, cached by the statement's fingerprint
+		key := fingerprints[i]
+		if key == "" {
+			key = job.ID
+		}
+		var analysis QueryAnalysis
+		if engine != nil {
+			// Analyze and optimize, less the suppressed suggestions
+			analysis = a.OptimizeWithCache(stmt, key)
+			if suppressions != nil {
+				analysis.EnhancedSuggestions = suppressions.Filter(analysis.EnhancedSuggestions, lines[i][0], lines[i][1])
+			}
+		} else {
+			// The analyzer reuses its buffers for the next statement
+			analysis = cloneAnalysis(a.AnalyzeWithCache(stmt, key))
+		}
+		analysis.DetectedDialect = guess
+		if p != nil {
+			analysis.Warnings = p.Warnings()
+		}
+
+		// Validate
+		if validator != nil {
+			result.Validation = append(result.Validation, validator.ValidateStatement(stmt)...)
+			result.Validation = append(result.Validation, checker.CheckStatement(stmt)...)
+		}
+
+		result.Analyses = append(result.Analyses, analysis)
+	}
+	if len(result.Analyses) > 0 {
+		result.Analysis = result.Analyses[0]
+	}
+	return result
+}
+
+// analyzer returns the worker's analyzer for a dialect and optimization
+// engine; a nil dialect is the dialect-neutral analyzer of pre-parsed
+// statements
+func (w *pipelineWorker) analyzer(d dialect.Dialect, engine *OptimizationEngine) *Analyzer {
+	key := dialectKey(d)
+	if engine != nil {
+		key += fmt.Sprintf("|%p", engine)
+	}
+	if a, ok := w.analyzers[key]; ok {
+		return a
+	}
+
+	a := New()
+	if d != nil {
+		a = NewWithDialect(d)
+	}
+	if w.opts.Weights != (ComplexityWeights{}) {
+		a.SetComplexityModel(w.opts.Weights, w.opts.Threshold)
+	}
+	if w.opts.Schema != nil {
+		a.SetSchema(w.opts.Schema)
+	}
+	if engine != nil {
+		a.SetOptimizationEngine(engine)
+	}
+	a.SetCache(w.cache)
+	w.analyzers[key] = a
+	return a
+}
+
+// engine returns the worker's optimization engine for a dialect and the
+// rule settings of a job
+func (w *pipelineWorker) engine(d dialect.Dialect, id string) (*OptimizationEngine, error) {
+	if d == nil {
+		d = dialect.GetDialect("sqlserver")
+	}
+	var rules map[string]RuleConfig
+	if w.opts.Rules != nil {
+		rules = w.opts.Rules(id)
+	}
+	key := dialectKey(d) + "|" + rulesKey(rules)
+	if engine, ok := w.engines[key]; ok {
+		return engine, nil
+	}
+
+	engine := NewOptimizationEngine(d)
+	if err := engine.ConfigureRules(rules); err != nil {
+		return nil, err
+	}
+	if w.opts.Schema != nil {
+		engine.SetSchema(w.opts.Schema)
+	}
+	w.engines[key] = engine
+	return engine, nil
+}
+
+func dialectKey(d dialect.Dialect) string {
+	if d == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %s", d.Name(), d.GetVersion())
+}
+
+// rulesKey encodes rule settings in a stable order
+func rulesKey(rules map[string]RuleConfig) string {
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var b strings.Builder
+	for _, id := range ids {
+		rc := rules[id]
+		enabled := "-"
+		if rc.Enabled != nil {
+			enabled = fmt.Sprint(*rc.Enabled)
+		}
+		fmt.Fprintf(&b, "%s %s %s;", id, enabled, rc.Severity)
+	}
+	return b.String()
+}
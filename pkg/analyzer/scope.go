@@ -0,0 +1,297 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+// Nested queries are analyzed in scopes. The scope path of a table or
+// column names the queries it sits in, outermost first and separated by
+// "/"; the statement itself has the empty path:
+//
+//	cte:name             the body of a CTE
+//	set:N                the Nth query of a UNION, INTERSECT or EXCEPT
+//	from:alias, from:N   a derived table
+//	where:N, select:N …  the Nth subquery of a clause
+//	source               the query INSERT, CREATE TABLE AS or MERGE reads
+//	view:name            the query of a view
+//	procedure:name, function:name, trigger:name
+//	                     a routine's body
+//
+// e.g. "cte:recent/where:1" is the first subquery in the WHERE clause of
+// the CTE recent.
+
+// CTEInfo is a CTE defined by a statement
+type CTEInfo struct {
+	Name      string   `json:"name"`
+	Columns   []string `json:"columns,omitempty"`
+	Recursive bool     `json:"recursive,omitempty"`
+	Scope     string   `json:"scope,omitempty"` // where it is defined
+}
+
+func (a *Analyzer) resetScopes() {
+	a.scope = a.scope[:0]
+	a.cteNames = a.cteNames[:0]
+	a.scopeCounts = nil
+	a.taggedTables = 0
+	a.taggedColumns = 0
+}
+
+func (a *Analyzer) scopePath() string {
+	return strings.Join(a.scope, "/")
+}
+
+// tagScope sets the scope of the tables and columns collected since the
+// last change of scope, and marks reads of CTEs
+func (a *Analyzer) tagScope() {
+	path := a.scopePath()
+	for i := a.taggedTables; i < len(a.analysis.Tables); i++ {
+		table := &a.analysis.Tables[i]
+		table.Scope = path
+		table.CTE = table.Usage == "SELECT" && table.Schema == "" && a.isCTE(table.Name)
+	}
+	for i := a.taggedColumns; i < len(a.analysis.Columns); i++ {
+		a.analysis.Columns[i].Scope = path
+	}
+	a.taggedTables = len(a.analysis.Tables)
+	a.taggedColumns = len(a.analysis.Columns)
+}
+
+func (a *Analyzer) enterScope(segment string) {
+	a.tagScope()
+	a.scope = append(a.scope, segment)
+}
+
+func (a *Analyzer) leaveScope() {
+	a.tagScope()
+	a.scope = a.scope[:len(a.scope)-1]
+}
+
+// nextScope numbers the subqueries of a clause within the current scope
+func (a *Analyzer) nextScope(clause string) string {
+	if a.scopeCounts == nil {
+		a.scopeCounts = make(map[string]int)
+	}
+	key := a.scopePath() + "|" + clause
+	a.scopeCounts[key]++
+	return fmt.Sprintf("%s:%d", clause, a.scopeCounts[key])
+}
+
+func (a *Analyzer) isCTE(name string) bool {
+	for i := len(a.cteNames) - 1; i >= 0; i-- {
+		if a.cteNames[i][strings.ToLower(name)] {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeNested analyzes a query or statement in a scope
+func (a *Analyzer) analyzeNested(segment string, stmt parser.Statement) {
+	if stmt == nil {
+		return
+	}
+	a.enterScope(segment)
+	a.analyzeStatement(stmt)
+	a.leaveScope()
+}
+
+// analyzeSubquery analyzes a subquery of a clause; usage is the clause's
+// column usage, e.g. WHERE
+func (a *Analyzer) analyzeSubquery(stmt parser.Statement, usage string) {
+	if stmt == nil {
+		return
+	}
+	if sel, ok := stmt.(*parser.SelectStatement); ok && sel == nil {
+		return
+	}
+	a.analyzeNested(a.nextScope(strings.ToLower(usage)), stmt)
+}
+
+// analyzeDerivedTable analyzes a subquery in FROM or JOIN
+func (a *Analyzer) analyzeDerivedTable(ref *parser.TableReference) {
+	segment := "from:" + ref.Alias
+	if ref.Alias == "" {
+		segment = a.nextScope("from")
+	}
+	a.analyzeNested(segment, ref.Subquery)
+}
+
+// analyzeWithStatement records the CTEs of a WITH statement, analyzes each
+// in its own scope and the main query in the current one
+func (a *Analyzer) analyzeWithStatement(stmt *parser.WithStatement) string {
+	// Without RECURSIVE, a CTE is visible to the ones after it
+	names := make(map[string]bool, len(stmt.CTEs))
+	if stmt.Recursive {
+		for _, cte := range stmt.CTEs {
+			names[strings.ToLower(cte.Name)] = true
+		}
+	}
+	a.tagScope()
+	a.cteNames = append(a.cteNames, names)
+
+	for _, cte := range stmt.CTEs {
+		a.analysis.CTEs = append(a.analysis.CTEs, CTEInfo{
+			Name:      cte.Name,
+			Columns:   cte.Columns,
+			Recursive: stmt.Recursive && cteIsRecursive(cte),
+			Scope:     a.scopePath(),
+		})
+		a.analyzeNested("cte:"+cte.Name, cte.Query)
+		names[strings.ToLower(cte.Name)] = true
+	}
+	queryType := a.analyzeStatement(stmt.Query)
+
+	a.tagScope()
+	a.cteNames = a.cteNames[:len(a.cteNames)-1]
+	return queryType
+}
+
+// analyzeSetOperation analyzes each query of a chain of set operations in
+// its own scope
+func (a *Analyzer) analyzeSetOperation(stmt *parser.SetOperation) {
+	var queries []parser.Statement
+	var flatten func(parser.Statement)
+	flatten = func(s parser.Statement) {
+		if op, ok := s.(*parser.SetOperation); ok {
+			flatten(op.Left)
+			flatten(op.Right)
+			return
+		}
+		queries = append(queries, s)
+	}
+	flatten(stmt)
+
+	for i, query := range queries {
+		a.analyzeNested(fmt.Sprintf("set:%d", i+1), query)
+	}
+}
+
+// analyzeMergeStatement records the target as merged into and the source
+// as read in the source scope
+func (a *Analyzer) analyzeMergeStatement(stmt *parser.MergeStatement) {
+	a.analysis.Tables = append(a.analysis.Tables, tableInfo(&stmt.TargetTable, "MERGE"))
+
+	a.enterScope("source")
+	switch source := stmt.SourceTable.(type) {
+	case *parser.TableReference:
+		a.addTableReference(source, "SELECT")
+	case parser.TableReference:
+		a.addTableReference(&source, "SELECT")
+	case *parser.SelectStatement:
+		a.analyzeStatement(source)
+	}
+	a.leaveScope()
+
+	if stmt.OnCondition != nil {
+		a.analyzeExpression(stmt.OnCondition, "JOIN")
+	}
+
+	target := stmt.TargetTable.Alias
+	if target == "" {
+		target = stmt.TargetTable.Name
+	}
+	for _, clauses := range [][]*parser.MergeWhenClause{stmt.WhenMatched, stmt.WhenNotMatched, stmt.WhenNotMatchedBy} {
+		for _, when := range clauses {
+			if when.Condition != nil {
+				a.analyzeExpression(when.Condition, "WHERE")
+			}
+			if when.Action == nil {
+				continue
+			}
+			for _, col := range when.Action.Columns {
+				a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
+					Table: target,
+					Name:  col,
+					Usage: when.Action.ActionType,
+				})
+			}
+			for _, value := range when.Action.Values {
+				a.analyzeExpression(value, when.Action.ActionType)
+			}
+		}
+	}
+}
+
+// analyzeCreateViewStatement records the view and the objects its query
+// reads
+func (a *Analyzer) analyzeCreateViewStatement(stmt *parser.CreateViewStatement) {
+	a.analysis.Tables = append(a.analysis.Tables, tableInfo(&stmt.ViewName, "CREATE"))
+	for _, col := range stmt.Columns {
+		a.analysis.Columns = append(a.analysis.Columns, ColumnInfo{
+			Table: stmt.ViewName.Name,
+			Name:  col,
+			Usage: "CREATE",
+		})
+	}
+	if stmt.SelectStmt != nil {
+		a.analyzeNested("view:"+stmt.ViewName.Name, stmt.SelectStmt)
+	}
+}
+
+// analyzeRoutineBody analyzes the statements of a procedure, function or
+// trigger body in the routine's scope
+func (a *Analyzer) analyzeRoutineBody(segment string, body *parser.ProcedureBody) {
+	if body == nil {
+		return
+	}
+	a.enterScope(segment)
+	for _, cursor := range body.Cursors {
+		if cursor.Query != nil {
+			a.analyzeSelectStatement(cursor.Query)
+		}
+	}
+	a.analyzeBlock(body.Statements)
+	if body.ExceptionBlock != nil {
+		for _, when := range body.ExceptionBlock.WhenClauses {
+			a.analyzeBlock(when.Body)
+		}
+	}
+	a.leaveScope()
+}
+
+// analyzeBlock analyzes the statements of a block, descending into
+// control flow
+func (a *Analyzer) analyzeBlock(statements []parser.Statement) {
+	for _, stmt := range statements {
+		switch s := stmt.(type) {
+		case *parser.IfStatement:
+			a.analyzeExpression(s.Condition, "CONDITION")
+			a.analyzeBlock(s.ThenBlock)
+			for _, elseIf := range s.ElseIfList {
+				a.analyzeExpression(elseIf.Condition, "CONDITION")
+				a.analyzeBlock(elseIf.Block)
+			}
+			a.analyzeBlock(s.ElseBlock)
+		case *parser.WhileStatement:
+			a.analyzeExpression(s.Condition, "CONDITION")
+			a.analyzeBlock(s.Block)
+		case *parser.LoopStatement:
+			a.analyzeBlock(s.Block)
+		case *parser.RepeatStatement:
+			a.analyzeBlock(s.Body)
+			a.analyzeExpression(s.Condition, "CONDITION")
+		case *parser.ForStatement:
+			a.analyzeBlock(s.Block)
+		case *parser.CaseStatement:
+			for _, when := range s.WhenList {
+				a.analyzeExpression(when.Condition, "CONDITION")
+				a.analyzeBlock(when.Block)
+			}
+			a.analyzeBlock(s.ElseBlock)
+		case *parser.TryStatement:
+			a.analyzeBlock(s.TryBlock)
+			if s.CatchBlock != nil {
+				a.analyzeBlock(s.CatchBlock.Body)
+			}
+		case *parser.ReturnStatement:
+			a.analyzeExpression(s.Value, "RETURN")
+		case *parser.AssignmentStatement:
+			a.analyzeExpression(s.Value, "SET")
+		default:
+			a.analyzeStatement(stmt)
+		}
+	}
+}
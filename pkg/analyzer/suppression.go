@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+)
+
+// suppressionPrefix starts an inline lint directive in a comment
+const suppressionPrefix = "sqlens:"
+
+// Suppression is a sqlens: directive read from a SQL comment
+type Suppression struct {
+	Directive string   // disable, enable or disable-next-line
+	Rules     []string // rule IDs; empty means every rule
+	Line      int      // line the directive takes effect on
+}
+
+// covers reports whether the suppression names a rule
+func (s Suppression) covers(rule string) bool {
+	if len(s.Rules) == 0 {
+		return true
+	}
+	for _, r := range s.Rules {
+		if strings.EqualFold(r, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// Suppressions are the sqlens: directives of a script:
+//
+//	-- sqlens:disable-next-line SELECT_STAR
+//	/* sqlens:disable CARTESIAN_PRODUCT, MISSING_WHERE */
+//	-- sqlens:enable CARTESIAN_PRODUCT
+//
+// disable and enable hold until the end of the script or the next
+// directive for the rule; disable-next-line covers the statement on the
+// following line. Suggestions carry no position of their own, so a
+// directive applies to whole statements.
+type Suppressions struct {
+	directives []Suppression
+}
+
+// ParseSuppressions reads the sqlens: directives of a script's comments
+func ParseSuppressions(comments []lexer.Comment) *Suppressions {
+	s := &Suppressions{}
+	for _, c := range comments {
+		text := strings.TrimSpace(stripCommentDelimiters(c.Text))
+		if !strings.HasPrefix(strings.ToLower(text), suppressionPrefix) {
+			continue
+		}
+		fields := strings.FieldsFunc(text[len(suppressionPrefix):], func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == ','
+		})
+		if len(fields) == 0 {
+			continue
+		}
+
+		sup := Suppression{Directive: strings.ToLower(fields[0]), Line: c.Line}
+		for _, rule := range fields[1:] {
+			if rule == "--" {
+				break // the rest is a reason
+			}
+			sup.Rules = append(sup.Rules, strings.ToUpper(rule))
+		}
+		switch sup.Directive {
+		case "disable-next-line":
+			// The line after the comment's last line
+			sup.Line = c.Line + strings.Count(c.Text, "\n") + 1
+		case "disable", "enable":
+		default:
+			continue
+		}
+		s.directives = append(s.directives, sup)
+	}
+	return s
+}
+
+// stripCommentDelimiters removes --, #, /* and */ from a comment's text
+func stripCommentDelimiters(text string) string {
+	switch {
+	case strings.HasPrefix(text, "--"):
+		return text[2:]
+	case strings.HasPrefix(text, "#"):
+		return text[1:]
+	case strings.HasPrefix(text, "/*"):
+		return strings.TrimSuffix(text[2:], "*/")
+	}
+	return text
+}
+
+// Directives returns the directives in source order
+func (s *Suppressions) Directives() []Suppression {
+	return s.directives
+}
+
+// Suppressed reports whether a rule is disabled for the statement on
+// lines start to end. A disable anywhere before the statement's end line
+// counts, so a trailing /* sqlens:disable RULE */ covers its statement.
+func (s *Suppressions) Suppressed(rule string, start, end int) bool {
+	disabled := false
+	for _, d := range s.directives {
+		if !d.covers(rule) {
+			continue
+		}
+		switch d.Directive {
+		case "disable-next-line":
+			if d.Line >= start && d.Line <= end {
+				return true
+			}
+		case "disable":
+			if d.Line <= end {
+				disabled = true
+			}
+		case "enable":
+			if d.Line <= start {
+				disabled = false
+			}
+		}
+	}
+	return disabled
+}
+
+// Filter drops the suggestions suppressed for the statement on lines
+// start to end
+func (s *Suppressions) Filter(suggestions []EnhancedOptimizationSuggestion, start, end int) []EnhancedOptimizationSuggestion {
+	if len(s.directives) == 0 {
+		return suggestions
+	}
+	kept := suggestions[:0:0]
+	for _, suggestion := range suggestions {
+		if !s.Suppressed(suggestion.Rule, start, end) {
+			kept = append(kept, suggestion)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,140 @@
+package dialect
+
+import "strings"
+
+// Spec is the data-driven description of a dialect: the release each
+// feature, operator and built-in function first appeared in. Entries
+// missing from a table are not supported by any release.
+type Spec struct {
+	Latest    Version // release assumed when no version is pinned
+	Features  map[Feature]Version
+	Operators map[string]Version
+	Functions map[string]FunctionSignature
+}
+
+// FunctionKind classifies built-in functions
+type FunctionKind int
+
+const (
+	FunctionScalar FunctionKind = iota
+	FunctionAggregate
+	FunctionWindow // ranking and offset functions that need OVER (...)
+)
+
+func (k FunctionKind) String() string {
+	switch k {
+	case FunctionAggregate:
+		return "aggregate"
+	case FunctionWindow:
+		return "window"
+	default:
+		return "scalar"
+	}
+}
+
+// Variadic is the MaxArgs of functions taking any number of arguments
+const Variadic = -1
+
+// FunctionSignature describes a built-in function
+type FunctionSignature struct {
+	Name       string
+	Kind       FunctionKind
+	MinArgs    int
+	MaxArgs    int     // Variadic for no upper bound
+	ReturnType string  // "" when it follows the argument type
+	Since      Version // first release with the function
+}
+
+// AcceptsArgs reports whether the function can be called with n arguments
+func (fs FunctionSignature) AcceptsArgs(n int) bool {
+	return n >= fs.MinArgs && (fs.MaxArgs == Variadic || n <= fs.MaxArgs)
+}
+
+// coreOperators are the operators every dialect supports
+var coreOperators = map[string]bool{
+	"=": true, "<>": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true,
+	"+": true, "-": true, "*": true, "/": true, "%": true,
+	"AND": true, "OR": true, "NOT": true, "LIKE": true, "IN": true, "IS": true, "BETWEEN": true,
+}
+
+// SupportsOperator reports whether operator is available in version v
+func (s *Spec) SupportsOperator(operator string, v Version) bool {
+	operator = strings.ToUpper(operator)
+	return coreOperators[operator] || supportedIn(s.Operators, operator, v)
+}
+
+// SupportsFeature reports whether feature is available in version v
+func (s *Spec) SupportsFeature(feature Feature, v Version) bool {
+	return supportedIn(s.Features, feature, v)
+}
+
+// LookupFunction returns the signature of a built-in function by name,
+// whichever release introduced it
+func (s *Spec) LookupFunction(name string) (FunctionSignature, bool) {
+	fs, ok := s.Functions[strings.ToUpper(name)]
+	return fs, ok
+}
+
+// catalog builds a function table from signature lists; later lists
+// override earlier ones
+func catalog(lists ...[]FunctionSignature) map[string]FunctionSignature {
+	functions := make(map[string]FunctionSignature)
+	for _, list := range lists {
+		for _, fs := range list {
+			functions[fs.Name] = fs
+		}
+	}
+	return functions
+}
+
+func scalar(name string, minArgs, maxArgs int, returns string) FunctionSignature {
+	return FunctionSignature{Name: name, Kind: FunctionScalar, MinArgs: minArgs, MaxArgs: maxArgs, ReturnType: returns}
+}
+
+func aggregate(name string, minArgs, maxArgs int, returns string) FunctionSignature {
+	return FunctionSignature{Name: name, Kind: FunctionAggregate, MinArgs: minArgs, MaxArgs: maxArgs, ReturnType: returns}
+}
+
+// since returns fs as introduced in release v
+func since(v Version, fs FunctionSignature) FunctionSignature {
+	fs.Since = v
+	return fs
+}
+
+// commonFunctions are built-ins every dialect shares
+var commonFunctions = []FunctionSignature{
+	aggregate("COUNT", 1, 1, "INT"),
+	aggregate("SUM", 1, 1, ""),
+	aggregate("AVG", 1, 1, ""),
+	aggregate("MIN", 1, 1, ""),
+	aggregate("MAX", 1, 1, ""),
+	scalar("COALESCE", 1, Variadic, ""),
+	scalar("NULLIF", 2, 2, ""),
+	scalar("ABS", 1, 1, ""),
+	scalar("ROUND", 1, 2, ""),
+	scalar("UPPER", 1, 1, "VARCHAR"),
+	scalar("LOWER", 1, 1, "VARCHAR"),
+	scalar("REPLACE", 3, 3, "VARCHAR"),
+}
+
+// windowFunctions returns the ranking and offset functions, introduced in
+// release v
+func windowFunctions(v Version) []FunctionSignature {
+	fns := []FunctionSignature{
+		{Name: "ROW_NUMBER", MinArgs: 0, MaxArgs: 0, ReturnType: "INT"},
+		{Name: "RANK", MinArgs: 0, MaxArgs: 0, ReturnType: "INT"},
+		{Name: "DENSE_RANK", MinArgs: 0, MaxArgs: 0, ReturnType: "INT"},
+		{Name: "NTILE", MinArgs: 1, MaxArgs: 1, ReturnType: "INT"},
+		{Name: "PERCENT_RANK", MinArgs: 0, MaxArgs: 0, ReturnType: "FLOAT"},
+		{Name: "CUME_DIST", MinArgs: 0, MaxArgs: 0, ReturnType: "FLOAT"},
+		{Name: "LAG", MinArgs: 1, MaxArgs: 3},
+		{Name: "LEAD", MinArgs: 1, MaxArgs: 3},
+		{Name: "FIRST_VALUE", MinArgs: 1, MaxArgs: 1},
+		{Name: "LAST_VALUE", MinArgs: 1, MaxArgs: 1},
+	}
+	for i := range fns {
+		fns[i].Kind = FunctionWindow
+		fns[i].Since = v
+	}
+	return fns
+}
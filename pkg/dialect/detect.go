@@ -0,0 +1,366 @@
+package dialect
+
+import (
+	"sort"
+	"strings"
+)
+
+// Auto is the dialect name that asks for detection from the SQL text
+const Auto = "auto"
+
+// Guess is a candidate dialect for a piece of SQL
+type Guess struct {
+	Dialect    string   `json:"dialect"`    // registry name, as accepted by Lookup
+	Confidence float64  `json:"confidence"` // share of all evidence found, from 0 to 1
+	Evidence   []string `json:"evidence"`   // the clues pointing to this dialect
+}
+
+// vote adds weight to a dialect when a clue is seen
+type vote struct {
+	dialect string
+	weight  int
+}
+
+// wordClues are keywords, types and functions specific to some dialects
+var wordClues = map[string][]vote{
+	// SQL Server
+	"NVARCHAR":         {{"sqlserver", 3}},
+	"DATETIME2":        {{"sqlserver", 3}},
+	"UNIQUEIDENTIFIER": {{"sqlserver", 3}},
+	"NOLOCK":           {{"sqlserver", 3}},
+	"GETDATE":          {{"sqlserver", 3}},
+	"NEWID":            {{"sqlserver", 3}},
+	"DATEADD":          {{"sqlserver", 2}},
+	"LEN":              {{"sqlserver", 2}},
+	"APPLY":            {{"sqlserver", 2}},
+	"IDENTITY":         {{"sqlserver", 1}},
+	"ISNULL":           {{"sqlserver", 1}},
+	// MySQL
+	"AUTO_INCREMENT": {{"mysql", 3}},
+	"ENGINE":         {{"mysql", 3}},
+	"STRAIGHT_JOIN":  {{"mysql", 3}},
+	"DUPLICATE":      {{"mysql", 3}},
+	"UNSIGNED":       {{"mysql", 2}},
+	"IFNULL":         {{"mysql", 2}, {"sqlite", 2}},
+	"GROUP_CONCAT":   {{"mysql", 2}, {"sqlite", 2}},
+	// PostgreSQL
+	"SERIAL":          {{"postgresql", 3}},
+	"BIGSERIAL":       {{"postgresql", 3}},
+	"ILIKE":           {{"postgresql", 3}},
+	"JSONB":           {{"postgresql", 3}},
+	"BYTEA":           {{"postgresql", 3}},
+	"GENERATE_SERIES": {{"postgresql", 2}},
+	"RETURNING":       {{"postgresql", 2}, {"sqlite", 1}, {"oracle", 1}},
+	// Oracle
+	"VARCHAR2":  {{"oracle", 3}},
+	"NVARCHAR2": {{"oracle", 3}},
+	"ROWNUM":    {{"oracle", 3}},
+	"NVL":       {{"oracle", 3}},
+	"NUMBER":    {{"oracle", 2}},
+	"DECODE":    {{"oracle", 2}},
+	"SYSDATE":   {{"oracle", 2}},
+	"DUAL":      {{"oracle", 2}},
+	"MINUS":     {{"oracle", 2}},
+	"CONNECT":   {{"oracle", 2}},
+	// SQLite
+	"AUTOINCREMENT": {{"sqlite", 3}},
+	"PRAGMA":        {{"sqlite", 3}},
+	"SQLITE_MASTER": {{"sqlite", 3}},
+	"GLOB":          {{"sqlite", 2}},
+	// Row limits
+	"LIMIT": {{"mysql", 2}, {"postgresql", 2}, {"sqlite", 2}},
+}
+
+// syntaxClues are the votes for punctuation and layout clues, by evidence
+var syntaxClues = map[string][]vote{
+	"[bracketed] identifiers": {{"sqlserver", 3}},
+	"`backtick` identifiers":  {{"mysql", 3}, {"sqlite", 1}},
+	`"quoted" identifiers`:    {{"postgresql", 1}, {"oracle", 1}, {"sqlite", 1}},
+	"TOP":                     {{"sqlserver", 3}},
+	":: casts":                {{"postgresql", 3}},
+	"$$ bodies":               {{"postgresql", 3}},
+	"$n parameters":           {{"postgresql", 2}},
+	"@variables":              {{"sqlserver", 2}, {"mysql", 1}},
+	"#temp tables":            {{"sqlserver", 2}},
+	"# comments":              {{"mysql", 2}},
+	"N'...' strings":          {{"sqlserver", 1}},
+	"q'[...]' strings":        {{"oracle", 3}},
+	":bind variables":         {{"oracle", 1}},
+	"GO batch separator":      {{"sqlserver", 3}},
+	"DELIMITER command":       {{"mysql", 3}},
+}
+
+// Detect scores the built-in dialects by the syntax clues in sql and
+// returns the ones with any evidence, most likely first. It returns nil
+// when nothing tells the dialects apart. Strings and comments are skipped
+// with a small scanner, as the lexer itself depends on a dialect.
+func Detect(sql string) []Guess {
+	d := detector{scores: make(map[string]int), evidence: make(map[string][]string), seen: make(map[string]bool)}
+	d.scanLines(sql)
+	d.scan(sql)
+	return d.ranking()
+}
+
+// DetectDialect returns the most likely dialect for sql with the guess
+// behind it, falling back to SQL Server when there is no evidence
+func DetectDialect(sql string) (Dialect, Guess) {
+	if guesses := Detect(sql); len(guesses) > 0 {
+		if d, err := Lookup(guesses[0].Dialect); err == nil {
+			return d, guesses[0]
+		}
+	}
+	return &SQLServerDialect{}, Guess{Dialect: "sqlserver"}
+}
+
+type detector struct {
+	scores   map[string]int
+	evidence map[string][]string
+	seen     map[string]bool // clues counted once each
+}
+
+func (d *detector) add(clue string, votes []vote) {
+	if d.seen[clue] {
+		return
+	}
+	d.seen[clue] = true
+	for _, v := range votes {
+		d.scores[v.dialect] += v.weight
+		d.evidence[v.dialect] = append(d.evidence[v.dialect], clue)
+	}
+}
+
+// scanLines looks for client commands, which only make sense on a line of
+// their own
+func (d *detector) scanLines(sql string) {
+	for _, line := range strings.Split(sql, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "GO":
+			if len(fields) == 1 || len(fields) == 2 && isNumber(fields[1]) {
+				d.add("GO batch separator", syntaxClues["GO batch separator"])
+			}
+		case "DELIMITER":
+			if len(fields) == 2 {
+				d.add("DELIMITER command", syntaxClues["DELIMITER command"])
+			}
+		}
+	}
+}
+
+// tableWords are the keywords a #temp table name follows at the start of
+// a line; elsewhere a # opening a line starts a MySQL comment
+var tableWords = map[string]bool{"FROM": true, "JOIN": true, "INTO": true, "TABLE": true, "UPDATE": true}
+
+// scan walks sql outside strings and comments, recording word and
+// punctuation clues. It does not use the lexer: the lexer imports this
+// package, and reads #, [, backslashes and $ differently in each dialect,
+// which is what is being guessed. Where dialects disagree scan accepts
+// every spelling, e.g. 'it\'s' escapes and # comments.
+func (d *detector) scan(sql string) {
+	prevWord := ""
+	for i := 0; i < len(sql); {
+		ch := sql[i]
+		switch {
+		case ch == '-' && at(sql, i+1) == '-':
+			i = skipTo(sql, i, "\n")
+		case ch == '/' && at(sql, i+1) == '*':
+			i = skipTo(sql, i+2, "*/")
+		case ch == '\'':
+			i = skipQuoted(sql, i, '\'')
+		case ch == '"':
+			d.add(`"quoted" identifiers`, syntaxClues[`"quoted" identifiers`])
+			i = skipQuoted(sql, i, '"')
+		case ch == '`':
+			d.add("`backtick` identifiers", syntaxClues["`backtick` identifiers"])
+			i = skipQuoted(sql, i, '`')
+		case ch == '[' && isIdentStart(at(sql, i+1)) && !isIdentChar(at(sql, i-1)) && at(sql, i-1) != ']' && at(sql, i-1) != ')':
+			d.add("[bracketed] identifiers", syntaxClues["[bracketed] identifiers"])
+			i = skipTo(sql, i, "]")
+		case ch == ':' && at(sql, i+1) == ':':
+			d.add(":: casts", syntaxClues[":: casts"])
+			i += 2
+		case ch == ':' && isIdentStart(at(sql, i+1)) && !isIdentChar(at(sql, i-1)):
+			d.add(":bind variables", syntaxClues[":bind variables"])
+			i++
+		case ch == '$' && (at(sql, i+1) == '$' || isIdentStart(at(sql, i+1))):
+			tag := dollarTag(sql, i)
+			if tag == "" {
+				i++
+				continue
+			}
+			d.add("$$ bodies", syntaxClues["$$ bodies"])
+			i = skipTo(sql, i+len(tag), tag)
+		case ch == '$' && isDigit(at(sql, i+1)):
+			d.add("$n parameters", syntaxClues["$n parameters"])
+			i++
+		case ch == '@' && (isIdentStart(at(sql, i+1)) || at(sql, i+1) == '@'):
+			d.add("@variables", syntaxClues["@variables"])
+			i++
+		case ch == '#' && (!tempName(sql, i) || lineStart(sql, i) && !tableWords[prevWord]) && !isIdentChar(at(sql, i-1)):
+			d.add("# comments", syntaxClues["# comments"])
+			i = skipTo(sql, i, "\n")
+		case ch == '#' && tempName(sql, i) && !isIdentChar(at(sql, i-1)):
+			d.add("#temp tables", syntaxClues["#temp tables"])
+			i++
+		case isIdentStart(ch):
+			start := i
+			for i < len(sql) && isIdentChar(sql[i]) {
+				i++
+			}
+			word := strings.ToUpper(sql[start:i])
+			switch {
+			case (word == "N" || word == "Q" || word == "NQ") && at(sql, i) == '\'':
+				if word == "N" {
+					d.add("N'...' strings", syntaxClues["N'...' strings"])
+				} else {
+					d.add("q'[...]' strings", syntaxClues["q'[...]' strings"])
+					i = skipTo(sql, i+2, string(closingDelimiter(at(sql, i+1)))+"'")
+				}
+				continue
+			case word == "TOP" && (prevWord == "SELECT" || prevWord == "DISTINCT"):
+				d.add("TOP", syntaxClues["TOP"])
+			default:
+				if votes, ok := wordClues[word]; ok {
+					d.add(word, votes)
+				}
+			}
+			prevWord = word
+		default:
+			i++
+		}
+	}
+}
+
+// ranking turns the scores into guesses, highest first
+func (d *detector) ranking() []Guess {
+	total := 0
+	for _, score := range d.scores {
+		total += score
+	}
+	if total == 0 {
+		return nil
+	}
+
+	guesses := make([]Guess, 0, len(d.scores))
+	for name, score := range d.scores {
+		guesses = append(guesses, Guess{
+			Dialect:    name,
+			Confidence: float64(score) / float64(total),
+			Evidence:   d.evidence[name],
+		})
+	}
+	sort.Slice(guesses, func(i, j int) bool {
+		if guesses[i].Confidence != guesses[j].Confidence {
+			return guesses[i].Confidence > guesses[j].Confidence
+		}
+		return guesses[i].Dialect < guesses[j].Dialect
+	})
+	return guesses
+}
+
+// dollarTag returns the $$ or $tag$ opening a PostgreSQL dollar-quoted
+// string at i, or "" when there is none
+func dollarTag(sql string, i int) string {
+	j := i + 1
+	for j < len(sql) && isIdentChar(sql[j]) {
+		j++
+	}
+	if at(sql, j) != '$' {
+		return ""
+	}
+	return sql[i : j+1]
+}
+
+// closingDelimiter returns the character closing an Oracle q'...' string
+func closingDelimiter(open byte) byte {
+	switch open {
+	case '[':
+		return ']'
+	case '{':
+		return '}'
+	case '(':
+		return ')'
+	case '<':
+		return '>'
+	}
+	return open
+}
+
+// tempName reports whether a #temp or ##global temp table name starts at i
+func tempName(sql string, i int) bool {
+	if at(sql, i+1) == '#' {
+		i++
+	}
+	return isIdentStart(at(sql, i+1))
+}
+
+// lineStart reports whether only spaces precede i on its line
+func lineStart(sql string, i int) bool {
+	for i--; i >= 0 && sql[i] != '\n'; i-- {
+		if sql[i] != ' ' && sql[i] != '\t' && sql[i] != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// at returns the character at i, or 0 outside sql
+func at(sql string, i int) byte {
+	if i < 0 || i >= len(sql) {
+		return 0
+	}
+	return sql[i]
+}
+
+// skipTo returns the position after the next end at or after from, or
+// the end of sql
+func skipTo(sql string, from int, end string) int {
+	if n := strings.Index(sql[from:], end); n >= 0 {
+		return from + n + len(end)
+	}
+	return len(sql)
+}
+
+// skipQuoted returns the position after the quoted text starting at i; a
+// doubled quote does not end it, nor does one escaped with a backslash, as
+// MySQL writes 'it\'s'
+func skipQuoted(sql string, i int, quote byte) int {
+	for i++; i < len(sql); i++ {
+		if sql[i] == '\\' && quote == '\'' {
+			i++
+			continue
+		}
+		if sql[i] != quote {
+			continue
+		}
+		if at(sql, i+1) != quote {
+			return i + 1
+		}
+		i++
+	}
+	return len(sql)
+}
+
+func isIdentStart(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+}
+
+func isIdentChar(ch byte) bool {
+	return isIdentStart(ch) || isDigit(ch)
+}
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+func isNumber(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -1,7 +1,5 @@
 package dialect
 
-import "strings"
-
 // Dialect represents a SQL dialect with its specific features and syntax
 type Dialect interface {
 	// Name returns the dialect name
@@ -24,6 +22,26 @@ type Dialect interface {
 
 	// GetLimitSyntax returns the LIMIT clause syntax for this dialect
 	GetLimitSyntax() LimitSyntax
+
+	// GetUpsertSyntax returns the INSERT-or-update syntax for this dialect
+	GetUpsertSyntax() UpsertSyntax
+
+	// DefaultSchema returns the schema unqualified names resolve to, or ""
+	// when it depends on the session (current database or user)
+	DefaultSchema() string
+
+	// GetVersion returns the server version the dialect describes
+	GetVersion() Version
+
+	// GetSpec returns the feature, operator and function tables
+	GetSpec() *Spec
+
+	// SupportsOperator checks if an operator such as ||, ILIKE or ->> is
+	// available in this dialect and version
+	SupportsOperator(operator string) bool
+
+	// LookupFunction returns the signature of a built-in function
+	LookupFunction(name string) (FunctionSignature, bool)
 }
 
 // Feature represents SQL features that may vary between dialects
@@ -40,6 +58,14 @@ const (
 	FeatureXMLSupport
 	FeatureUpsert
 	FeatureReturningClause
+	FeatureOutputClause
+	FeatureDistinctOn
+	FeatureNamedWindows // WINDOW w AS (...)
+	FeatureGroupingSets // ROLLUP (...), CUBE (...), GROUPING SETS (...)
+	FeatureFilterClause // aggregate FILTER (WHERE ...)
+	FeatureLateral      // LATERAL derived tables
+	FeatureIndexInclude // CREATE INDEX ... INCLUDE (...) covering columns
+	FeatureOffsetFetch  // OFFSET n ROWS, FETCH {FIRST | NEXT} n ROWS ONLY
 )
 
 // LimitSyntax represents different ways to limit results
@@ -49,24 +75,40 @@ const (
 	LimitSyntaxStandard  LimitSyntax = iota // LIMIT n OFFSET m
 	LimitSyntaxSQLServer                    // TOP n
 	LimitSyntaxOracle                       // ROWNUM
+	LimitSyntaxFetch                        // OFFSET m ROWS FETCH NEXT n ROWS ONLY
+)
+
+func (s LimitSyntax) String() string {
+	switch s {
+	case LimitSyntaxSQLServer:
+		return "TOP"
+	case LimitSyntaxOracle:
+		return "ROWNUM"
+	case LimitSyntaxFetch:
+		return "FETCH"
+	default:
+		return "LIMIT"
+	}
+}
+
+// UpsertSyntax represents different ways to insert or update a row
+type UpsertSyntax int
+
+const (
+	UpsertSyntaxNone           UpsertSyntax = iota
+	UpsertSyntaxOnConflict                  // INSERT ... ON CONFLICT
+	UpsertSyntaxOnDuplicateKey              // INSERT ... ON DUPLICATE KEY UPDATE
+	UpsertSyntaxMerge                       // MERGE
 )
 
-// GetDialect returns the appropriate dialect implementation
+// GetDialect returns the dialect registered under name, falling back to
+// SQL Server for unknown names. Use Lookup to reject them instead.
 func GetDialect(name string) Dialect {
-	switch strings.ToLower(name) {
-	case "mysql":
-		return &MySQLDialect{}
-	case "postgresql", "postgres":
-		return &PostgreSQLDialect{}
-	case "sqlserver", "mssql":
+	d, err := Lookup(name)
+	if err != nil {
 		return &SQLServerDialect{}
-	case "sqlite":
-		return &SQLiteDialect{}
-	case "oracle":
-		return &OracleDialect{}
-	default:
-		return &SQLServerDialect{} // Default fallback
 	}
+	return d
 }
 
 // Common keywords shared across most SQL dialects
@@ -3,7 +3,9 @@ package dialect
 import "strings"
 
 // MySQLDialect implements MySQL-specific features
-type MySQLDialect struct{}
+type MySQLDialect struct {
+	Version Version // zero for the latest release
+}
 
 func (d *MySQLDialect) Name() string {
 	return "MySQL"
@@ -14,22 +16,23 @@ func (d *MySQLDialect) QuoteIdentifier(identifier string) string {
 }
 
 func (d *MySQLDialect) SupportsFeature(feature Feature) bool {
-	switch feature {
-	case FeatureCTE:
-		return true // MySQL 8.0+
-	case FeatureWindowFunctions:
-		return true // MySQL 8.0+
-	case FeatureJSONSupport:
-		return true // MySQL 5.7+
-	case FeatureRecursiveCTE:
-		return true // MySQL 8.0+
-	case FeatureFullTextSearch:
-		return true
-	case FeatureUpsert:
-		return true // INSERT ... ON DUPLICATE KEY UPDATE
-	default:
-		return false
-	}
+	return mysqlSpec.SupportsFeature(feature, d.GetVersion())
+}
+
+func (d *MySQLDialect) SupportsOperator(operator string) bool {
+	return mysqlSpec.SupportsOperator(operator, d.GetVersion())
+}
+
+func (d *MySQLDialect) LookupFunction(name string) (FunctionSignature, bool) {
+	return mysqlSpec.LookupFunction(name)
+}
+
+func (d *MySQLDialect) GetSpec() *Spec {
+	return mysqlSpec
+}
+
+func (d *MySQLDialect) GetVersion() Version {
+	return orLatest(d.Version, mysqlSpec.Latest)
 }
 
 func (d *MySQLDialect) GetKeywords() []string {
@@ -107,3 +110,61 @@ func (d *MySQLDialect) IsReservedWord(word string) bool {
 func (d *MySQLDialect) GetLimitSyntax() LimitSyntax {
 	return LimitSyntaxStandard
 }
+
+func (d *MySQLDialect) GetUpsertSyntax() UpsertSyntax {
+	return UpsertSyntaxOnDuplicateKey
+}
+
+// DefaultSchema returns the schema used for unqualified names.
+// Schemas are databases; unqualified names use the current database.
+func (d *MySQLDialect) DefaultSchema() string {
+	return ""
+}
+
+// mysqlSpec describes MySQL from 5.6 on
+var mysqlSpec = &Spec{
+	Latest: Version{Major: 8, Minor: 4},
+	Features: map[Feature]Version{
+		FeatureCTE:             {Major: 8},
+		FeatureWindowFunctions: {Major: 8},
+		FeatureJSONSupport:     {Major: 5, Minor: 7},
+		FeatureRecursiveCTE:    {Major: 8},
+		FeatureFullTextSearch:  always,
+		FeatureUpsert:          always, // INSERT ... ON DUPLICATE KEY UPDATE
+		FeatureNamedWindows:    {Major: 8},
+		FeatureLateral:         {Major: 8, Patch: 14},
+	},
+	Operators: map[string]Version{
+		"<=>":    always,
+		"->":     {Major: 5, Minor: 7, Patch: 9},
+		"->>":    {Major: 5, Minor: 7, Patch: 13},
+		"REGEXP": always,
+		"RLIKE":  always,
+	},
+	Functions: catalog(commonFunctions, windowFunctions(Version{Major: 8}), []FunctionSignature{
+		scalar("CONCAT", 1, Variadic, "VARCHAR"),
+		scalar("CONCAT_WS", 2, Variadic, "VARCHAR"),
+		scalar("IFNULL", 2, 2, ""),
+		scalar("IF", 3, 3, ""),
+		scalar("LENGTH", 1, 1, "INT"),
+		scalar("CHAR_LENGTH", 1, 1, "INT"),
+		scalar("SUBSTR", 2, 3, "VARCHAR"),
+		scalar("SUBSTRING", 2, 3, "VARCHAR"),
+		scalar("CEIL", 1, 1, ""),
+		scalar("CEILING", 1, 1, ""),
+		scalar("FLOOR", 1, 1, ""),
+		scalar("GREATEST", 2, Variadic, ""),
+		scalar("LEAST", 2, Variadic, ""),
+		scalar("NOW", 0, 1, "DATETIME"),
+		scalar("DATE_FORMAT", 2, 2, "VARCHAR"),
+		scalar("DATEDIFF", 2, 2, "INT"),
+		scalar("DATE_ADD", 2, 2, "DATETIME"),
+		aggregate("GROUP_CONCAT", 1, Variadic, "VARCHAR"),
+		since(Version{Major: 5, Minor: 7}, scalar("ANY_VALUE", 1, 1, "")),
+		since(Version{Major: 5, Minor: 7}, scalar("JSON_EXTRACT", 2, Variadic, "JSON")),
+		since(Version{Major: 5, Minor: 7}, scalar("JSON_OBJECT", 0, Variadic, "JSON")),
+		since(Version{Major: 5, Minor: 7, Patch: 22}, aggregate("JSON_ARRAYAGG", 1, 1, "JSON")),
+		since(Version{Major: 8}, scalar("REGEXP_REPLACE", 3, 6, "VARCHAR")),
+		since(Version{Major: 8}, scalar("GROUPING", 1, Variadic, "INT")),
+	}),
+}
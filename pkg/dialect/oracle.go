@@ -3,7 +3,9 @@ package dialect
 import "strings"
 
 // OracleDialect implements Oracle-specific features
-type OracleDialect struct{}
+type OracleDialect struct {
+	Version Version // zero for the latest release
+}
 
 func (d *OracleDialect) Name() string {
 	return "Oracle"
@@ -14,22 +16,23 @@ func (d *OracleDialect) QuoteIdentifier(identifier string) string {
 }
 
 func (d *OracleDialect) SupportsFeature(feature Feature) bool {
-	switch feature {
-	case FeatureCTE:
-		return true
-	case FeatureWindowFunctions:
-		return true
-	case FeatureXMLSupport:
-		return true
-	case FeaturePartitioning:
-		return true
-	case FeatureFullTextSearch:
-		return true // Oracle Text
-	case FeatureRecursiveCTE:
-		return true
-	default:
-		return false
-	}
+	return oracleSpec.SupportsFeature(feature, d.GetVersion())
+}
+
+func (d *OracleDialect) SupportsOperator(operator string) bool {
+	return oracleSpec.SupportsOperator(operator, d.GetVersion())
+}
+
+func (d *OracleDialect) LookupFunction(name string) (FunctionSignature, bool) {
+	return oracleSpec.LookupFunction(name)
+}
+
+func (d *OracleDialect) GetSpec() *Spec {
+	return oracleSpec
+}
+
+func (d *OracleDialect) GetVersion() Version {
+	return orLatest(d.Version, oracleSpec.Latest)
 }
 
 func (d *OracleDialect) GetKeywords() []string {
@@ -71,3 +74,56 @@ func (d *OracleDialect) IsReservedWord(word string) bool {
 func (d *OracleDialect) GetLimitSyntax() LimitSyntax {
 	return LimitSyntaxOracle
 }
+
+func (d *OracleDialect) GetUpsertSyntax() UpsertSyntax {
+	return UpsertSyntaxMerge
+}
+
+// DefaultSchema returns the schema used for unqualified names.
+// Unqualified names resolve to the connected user's schema.
+func (d *OracleDialect) DefaultSchema() string {
+	return ""
+}
+
+// oracleSpec describes Oracle from 11g on
+var oracleSpec = &Spec{
+	Latest: Version{Major: 23},
+	Features: map[Feature]Version{
+		FeatureCTE:             always,
+		FeatureWindowFunctions: always,
+		FeatureJSONSupport:     {Major: 12},
+		FeatureXMLSupport:      always,
+		FeaturePartitioning:    always,
+		FeatureFullTextSearch:  always, // Oracle Text
+		FeatureRecursiveCTE:    {Major: 11, Minor: 2},
+		FeatureUpsert:          always, // MERGE statement
+		FeatureReturningClause: always, // RETURNING ... INTO
+		FeatureNamedWindows:    {Major: 21},
+		FeatureGroupingSets:    always,
+		FeatureLateral:         {Major: 12},
+		FeatureOffsetFetch:     {Major: 12},
+	},
+	Operators: map[string]Version{
+		"||": always,
+	},
+	Functions: catalog(commonFunctions, windowFunctions(always), []FunctionSignature{
+		scalar("NVL", 2, 2, ""),
+		scalar("NVL2", 3, 3, ""),
+		scalar("DECODE", 3, Variadic, ""),
+		scalar("CONCAT", 2, 2, "VARCHAR2"),
+		scalar("LENGTH", 1, 1, "NUMBER"),
+		scalar("SUBSTR", 2, 3, "VARCHAR2"),
+		scalar("INSTR", 2, 4, "NUMBER"),
+		scalar("CEIL", 1, 1, ""),
+		scalar("FLOOR", 1, 1, ""),
+		scalar("GREATEST", 1, Variadic, ""),
+		scalar("LEAST", 1, Variadic, ""),
+		scalar("TO_CHAR", 1, 3, "VARCHAR2"),
+		scalar("TO_DATE", 1, 3, "DATE"),
+		scalar("TO_NUMBER", 1, 3, "NUMBER"),
+		scalar("REGEXP_REPLACE", 2, 6, "VARCHAR2"),
+		scalar("GROUPING", 1, 1, "NUMBER"),
+		since(Version{Major: 11, Minor: 2}, aggregate("LISTAGG", 1, 2, "VARCHAR2")),
+		since(Version{Major: 12}, scalar("JSON_VALUE", 2, 2, "VARCHAR2")),
+	}),
+}
@@ -3,7 +3,9 @@ package dialect
 import "strings"
 
 // PostgreSQLDialect implements PostgreSQL-specific features
-type PostgreSQLDialect struct{}
+type PostgreSQLDialect struct {
+	Version Version // zero for the latest release
+}
 
 func (d *PostgreSQLDialect) Name() string {
 	return "PostgreSQL"
@@ -14,30 +16,23 @@ func (d *PostgreSQLDialect) QuoteIdentifier(identifier string) string {
 }
 
 func (d *PostgreSQLDialect) SupportsFeature(feature Feature) bool {
-	switch feature {
-	case FeatureCTE:
-		return true
-	case FeatureWindowFunctions:
-		return true
-	case FeatureJSONSupport:
-		return true
-	case FeatureArraySupport:
-		return true
-	case FeatureRecursiveCTE:
-		return true
-	case FeaturePartitioning:
-		return true
-	case FeatureFullTextSearch:
-		return true
-	case FeatureXMLSupport:
-		return true
-	case FeatureUpsert:
-		return true // INSERT ... ON CONFLICT
-	case FeatureReturningClause:
-		return true
-	default:
-		return false
-	}
+	return postgresqlSpec.SupportsFeature(feature, d.GetVersion())
+}
+
+func (d *PostgreSQLDialect) SupportsOperator(operator string) bool {
+	return postgresqlSpec.SupportsOperator(operator, d.GetVersion())
+}
+
+func (d *PostgreSQLDialect) LookupFunction(name string) (FunctionSignature, bool) {
+	return postgresqlSpec.LookupFunction(name)
+}
+
+func (d *PostgreSQLDialect) GetSpec() *Spec {
+	return postgresqlSpec
+}
+
+func (d *PostgreSQLDialect) GetVersion() Version {
+	return orLatest(d.Version, postgresqlSpec.Latest)
 }
 
 func (d *PostgreSQLDialect) GetKeywords() []string {
@@ -101,3 +96,73 @@ func (d *PostgreSQLDialect) IsReservedWord(word string) bool {
 func (d *PostgreSQLDialect) GetLimitSyntax() LimitSyntax {
 	return LimitSyntaxStandard
 }
+
+func (d *PostgreSQLDialect) GetUpsertSyntax() UpsertSyntax {
+	return UpsertSyntaxOnConflict
+}
+
+// DefaultSchema returns the schema used for unqualified names.
+// The default search_path is "$user", public.
+func (d *PostgreSQLDialect) DefaultSchema() string {
+	return "public"
+}
+
+// postgresqlSpec describes PostgreSQL from 9.0 on
+var postgresqlSpec = &Spec{
+	Latest: Version{Major: 17},
+	Features: map[Feature]Version{
+		FeatureCTE:             always,
+		FeatureWindowFunctions: always,
+		FeatureJSONSupport:     {Major: 9, Minor: 2},
+		FeatureArraySupport:    always,
+		FeatureRecursiveCTE:    always,
+		FeaturePartitioning:    {Major: 10},
+		FeatureFullTextSearch:  always,
+		FeatureXMLSupport:      always,
+		FeatureUpsert:          {Major: 9, Minor: 5}, // INSERT ... ON CONFLICT
+		FeatureReturningClause: always,
+		FeatureDistinctOn:      always, // SELECT DISTINCT ON (...)
+		FeatureNamedWindows:    always,
+		FeatureGroupingSets:    {Major: 9, Minor: 5},
+		FeatureFilterClause:    {Major: 9, Minor: 4},
+		FeatureLateral:         {Major: 9, Minor: 3},
+		FeatureIndexInclude:    {Major: 11},
+		FeatureOffsetFetch:     always,
+	},
+	Operators: map[string]Version{
+		"||":    always,
+		"::":    always,
+		"ILIKE": always,
+		"~":     always,
+		"@>":    always,
+		"<@":    always,
+		"->":    {Major: 9, Minor: 3},
+		"->>":   {Major: 9, Minor: 3},
+	},
+	Functions: catalog(commonFunctions, windowFunctions(always), []FunctionSignature{
+		since(Version{Major: 9, Minor: 1}, scalar("CONCAT", 1, Variadic, "VARCHAR")),
+		since(Version{Major: 9, Minor: 1}, scalar("CONCAT_WS", 2, Variadic, "VARCHAR")),
+		scalar("LENGTH", 1, 2, "INT"),
+		scalar("SUBSTR", 2, 3, "VARCHAR"),
+		scalar("SUBSTRING", 2, 3, "VARCHAR"),
+		scalar("CEIL", 1, 1, ""),
+		scalar("CEILING", 1, 1, ""),
+		scalar("FLOOR", 1, 1, ""),
+		scalar("GREATEST", 1, Variadic, ""),
+		scalar("LEAST", 1, Variadic, ""),
+		scalar("NOW", 0, 0, "TIMESTAMP"),
+		scalar("DATE_TRUNC", 2, 3, "TIMESTAMP"),
+		scalar("TO_CHAR", 2, 2, "VARCHAR"),
+		scalar("REGEXP_REPLACE", 3, 6, "VARCHAR"),
+		scalar("GENERATE_SERIES", 2, 3, ""),
+		aggregate("ARRAY_AGG", 1, 1, "ARRAY"),
+		aggregate("STRING_AGG", 2, 2, "VARCHAR"),
+		aggregate("BOOL_AND", 1, 1, "BOOLEAN"),
+		aggregate("BOOL_OR", 1, 1, "BOOLEAN"),
+		since(Version{Major: 9, Minor: 3}, aggregate("JSON_AGG", 1, 1, "JSON")),
+		since(Version{Major: 9, Minor: 4}, aggregate("PERCENTILE_CONT", 1, 1, "FLOAT")),
+		since(Version{Major: 9, Minor: 4}, aggregate("MODE", 0, 0, "")),
+		since(Version{Major: 9, Minor: 5}, scalar("JSONB_BUILD_OBJECT", 0, Variadic, "JSONB")),
+		since(Version{Major: 9, Minor: 5}, scalar("GROUPING", 1, Variadic, "INT")),
+	}),
+}
@@ -0,0 +1,76 @@
+package dialect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Factory creates a fresh dialect instance
+type Factory func() Dialect
+
+// registry maps lower-case names and aliases to dialect factories
+var registry = struct {
+	sync.RWMutex
+	factories map[string]Factory
+	names     []string // canonical names, in registration order
+}{factories: make(map[string]Factory)}
+
+func init() {
+	Register("sqlserver", []string{"mssql", "tsql"}, func() Dialect { return &SQLServerDialect{} })
+	Register("mysql", nil, func() Dialect { return &MySQLDialect{} })
+	Register("postgresql", []string{"postgres", "pg"}, func() Dialect { return &PostgreSQLDialect{} })
+	Register("sqlite", []string{"sqlite3"}, func() Dialect { return &SQLiteDialect{} })
+	Register("oracle", nil, func() Dialect { return &OracleDialect{} })
+}
+
+// Register makes a dialect available under name and its aliases, so that
+// other packages can add dialects such as MariaDB or Snowflake. Names are
+// case-insensitive. It panics if a name is empty or already registered.
+func Register(name string, aliases []string, factory Factory) {
+	if factory == nil {
+		panic("dialect: Register factory is nil for " + name)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	keys := append([]string{name}, aliases...)
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			panic("dialect: Register called with an empty name")
+		}
+		if _, dup := registry.factories[key]; dup {
+			panic("dialect: Register called twice for " + key)
+		}
+	}
+	for _, key := range keys {
+		registry.factories[strings.ToLower(strings.TrimSpace(key))] = factory
+	}
+	registry.names = append(registry.names, strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Lookup returns a new instance of the dialect registered under name or
+// one of its aliases
+func Lookup(name string) (Dialect, error) {
+	registry.RLock()
+	factory, ok := registry.factories[strings.ToLower(strings.TrimSpace(name))]
+	registry.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown SQL dialect %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return factory(), nil
+}
+
+// Names returns the canonical names of the registered dialects, sorted
+func Names() []string {
+	registry.RLock()
+	names := append([]string(nil), registry.names...)
+	registry.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
@@ -3,7 +3,9 @@ package dialect
 import "strings"
 
 // SQLiteDialect implements SQLite-specific features
-type SQLiteDialect struct{}
+type SQLiteDialect struct {
+	Version Version // zero for the latest release
+}
 
 func (d *SQLiteDialect) Name() string {
 	return "SQLite"
@@ -14,22 +16,23 @@ func (d *SQLiteDialect) QuoteIdentifier(identifier string) string {
 }
 
 func (d *SQLiteDialect) SupportsFeature(feature Feature) bool {
-	switch feature {
-	case FeatureCTE:
-		return true
-	case FeatureWindowFunctions:
-		return true // SQLite 3.25+
-	case FeatureJSONSupport:
-		return true // SQLite 3.38+
-	case FeatureRecursiveCTE:
-		return true
-	case FeatureFullTextSearch:
-		return true // FTS extension
-	case FeatureUpsert:
-		return true // INSERT ... ON CONFLICT
-	default:
-		return false
-	}
+	return sqliteSpec.SupportsFeature(feature, d.GetVersion())
+}
+
+func (d *SQLiteDialect) SupportsOperator(operator string) bool {
+	return sqliteSpec.SupportsOperator(operator, d.GetVersion())
+}
+
+func (d *SQLiteDialect) LookupFunction(name string) (FunctionSignature, bool) {
+	return sqliteSpec.LookupFunction(name)
+}
+
+func (d *SQLiteDialect) GetSpec() *Spec {
+	return sqliteSpec
+}
+
+func (d *SQLiteDialect) GetVersion() Version {
+	return orLatest(d.Version, sqliteSpec.Latest)
 }
 
 func (d *SQLiteDialect) GetKeywords() []string {
@@ -64,3 +67,54 @@ func (d *SQLiteDialect) IsReservedWord(word string) bool {
 func (d *SQLiteDialect) GetLimitSyntax() LimitSyntax {
 	return LimitSyntaxStandard
 }
+
+func (d *SQLiteDialect) GetUpsertSyntax() UpsertSyntax {
+	return UpsertSyntaxOnConflict
+}
+
+// DefaultSchema returns the schema used for unqualified names.
+// The primary database file is attached as main.
+func (d *SQLiteDialect) DefaultSchema() string {
+	return "main"
+}
+
+// sqliteSpec describes SQLite 3
+var sqliteSpec = &Spec{
+	Latest: Version{Major: 3, Minor: 46},
+	Features: map[Feature]Version{
+		FeatureCTE:             {Major: 3, Minor: 8, Patch: 3},
+		FeatureWindowFunctions: {Major: 3, Minor: 25},
+		FeatureJSONSupport:     {Major: 3, Minor: 38},
+		FeatureRecursiveCTE:    {Major: 3, Minor: 8, Patch: 3},
+		FeatureFullTextSearch:  always,                // FTS extension
+		FeatureUpsert:          {Major: 3, Minor: 24}, // INSERT ... ON CONFLICT
+		FeatureReturningClause: {Major: 3, Minor: 35},
+		FeatureNamedWindows:    {Major: 3, Minor: 25},
+		FeatureFilterClause:    {Major: 3, Minor: 30},
+	},
+	Operators: map[string]Version{
+		"||":     always,
+		"GLOB":   always,
+		"REGEXP": always,
+		"->":     {Major: 3, Minor: 38},
+		"->>":    {Major: 3, Minor: 38},
+	},
+	Functions: catalog(commonFunctions, windowFunctions(Version{Major: 3, Minor: 25}), []FunctionSignature{
+		scalar("LENGTH", 1, 1, "INT"),
+		scalar("SUBSTR", 2, 3, "TEXT"),
+		scalar("INSTR", 2, 2, "INT"),
+		scalar("IFNULL", 2, 2, ""),
+		scalar("PRINTF", 1, Variadic, "TEXT"),
+		scalar("DATE", 0, Variadic, "TEXT"),
+		scalar("DATETIME", 0, Variadic, "TEXT"),
+		scalar("STRFTIME", 1, Variadic, "TEXT"),
+		aggregate("GROUP_CONCAT", 1, 2, "TEXT"),
+		since(Version{Major: 3, Minor: 32}, scalar("IIF", 3, 3, "")),
+		since(Version{Major: 3, Minor: 34}, scalar("SUBSTRING", 2, 3, "TEXT")),
+		since(Version{Major: 3, Minor: 35}, scalar("CEIL", 1, 1, "")),
+		since(Version{Major: 3, Minor: 35}, scalar("FLOOR", 1, 1, "")),
+		since(Version{Major: 3, Minor: 38}, scalar("JSON_EXTRACT", 2, Variadic, "")),
+		since(Version{Major: 3, Minor: 44}, aggregate("STRING_AGG", 2, 2, "TEXT")),
+		since(Version{Major: 3, Minor: 44}, scalar("CONCAT", 1, Variadic, "TEXT")),
+	}),
+}
@@ -3,7 +3,9 @@ package dialect
 import "strings"
 
 // SQLServerDialect implements SQL Server-specific features
-type SQLServerDialect struct{}
+type SQLServerDialect struct {
+	Version Version // zero for the latest release
+}
 
 func (d *SQLServerDialect) Name() string {
 	return "SQL Server"
@@ -14,20 +16,23 @@ func (d *SQLServerDialect) QuoteIdentifier(identifier string) string {
 }
 
 func (d *SQLServerDialect) SupportsFeature(feature Feature) bool {
-	switch feature {
-	case FeatureCTE:
-		return true
-	case FeatureWindowFunctions:
-		return true
-	case FeatureJSONSupport:
-		return true // SQL Server 2016+
-	case FeatureXMLSupport:
-		return true
-	case FeatureUpsert:
-		return true // MERGE statement
-	default:
-		return false
-	}
+	return sqlServerSpec.SupportsFeature(feature, d.GetVersion())
+}
+
+func (d *SQLServerDialect) SupportsOperator(operator string) bool {
+	return sqlServerSpec.SupportsOperator(operator, d.GetVersion())
+}
+
+func (d *SQLServerDialect) LookupFunction(name string) (FunctionSignature, bool) {
+	return sqlServerSpec.LookupFunction(name)
+}
+
+func (d *SQLServerDialect) GetSpec() *Spec {
+	return sqlServerSpec
+}
+
+func (d *SQLServerDialect) GetVersion() Version {
+	return orLatest(d.Version, sqlServerSpec.Latest)
 }
 
 func (d *SQLServerDialect) GetKeywords() []string {
@@ -66,3 +71,75 @@ func (d *SQLServerDialect) IsReservedWord(word string) bool {
 func (d *SQLServerDialect) GetLimitSyntax() LimitSyntax {
 	return LimitSyntaxSQLServer
 }
+
+func (d *SQLServerDialect) GetUpsertSyntax() UpsertSyntax {
+	return UpsertSyntaxMerge
+}
+
+// DefaultSchema returns the schema used for unqualified names.
+// dbo is the default schema for new users.
+func (d *SQLServerDialect) DefaultSchema() string {
+	return "dbo"
+}
+
+// sqlServerSpec describes SQL Server from 2008 on. Releases are numbered
+// by year; see sqlServerRelease.
+var sqlServerSpec = &Spec{
+	Latest: Version{Major: 2022},
+	Features: map[Feature]Version{
+		FeatureCTE:             always,
+		FeatureWindowFunctions: always,
+		FeatureJSONSupport:     {Major: 2016},
+		FeatureRecursiveCTE:    always,
+		FeaturePartitioning:    always,
+		FeatureFullTextSearch:  always,
+		FeatureXMLSupport:      always,
+		FeatureUpsert:          always, // MERGE statement
+		FeatureOutputClause:    always, // OUTPUT inserted.* / deleted.*
+		FeatureNamedWindows:    {Major: 2022},
+		FeatureGroupingSets:    always,
+		FeatureIndexInclude:    always,
+		FeatureOffsetFetch:     {Major: 2012},
+	},
+	Functions: catalog(commonFunctions, windowFunctions(always), []FunctionSignature{
+		since(Version{Major: 2012}, FunctionSignature{Name: "LAG", Kind: FunctionWindow, MinArgs: 1, MaxArgs: 3}),
+		since(Version{Major: 2012}, FunctionSignature{Name: "LEAD", Kind: FunctionWindow, MinArgs: 1, MaxArgs: 3}),
+		since(Version{Major: 2012}, FunctionSignature{Name: "FIRST_VALUE", Kind: FunctionWindow, MinArgs: 1, MaxArgs: 1}),
+		since(Version{Major: 2012}, FunctionSignature{Name: "LAST_VALUE", Kind: FunctionWindow, MinArgs: 1, MaxArgs: 1}),
+		scalar("LEN", 1, 1, "INT"),
+		scalar("ISNULL", 2, 2, ""),
+		scalar("SUBSTRING", 3, 3, "VARCHAR"),
+		scalar("CHARINDEX", 2, 3, "INT"),
+		scalar("CEILING", 1, 1, ""),
+		scalar("FLOOR", 1, 1, ""),
+		scalar("GETDATE", 0, 0, "DATETIME"),
+		scalar("DATEADD", 3, 3, "DATETIME"),
+		scalar("DATEDIFF", 3, 3, "INT"),
+		scalar("DATEPART", 2, 2, "INT"),
+		scalar("NEWID", 0, 0, "UNIQUEIDENTIFIER"),
+		scalar("GROUPING", 1, 1, "INT"),
+		since(Version{Major: 2012}, scalar("IIF", 3, 3, "")),
+		since(Version{Major: 2012}, scalar("CONCAT", 2, Variadic, "NVARCHAR")),
+		since(Version{Major: 2012}, scalar("FORMAT", 2, 3, "NVARCHAR")),
+		since(Version{Major: 2012}, aggregate("PERCENTILE_CONT", 1, 1, "FLOAT")),
+		since(Version{Major: 2016}, scalar("JSON_VALUE", 2, 2, "NVARCHAR")),
+		since(Version{Major: 2016}, scalar("STRING_SPLIT", 2, 3, "")),
+		since(Version{Major: 2017}, scalar("CONCAT_WS", 3, Variadic, "NVARCHAR")),
+		since(Version{Major: 2017}, aggregate("STRING_AGG", 2, 2, "NVARCHAR")),
+		since(Version{Major: 2022}, scalar("GREATEST", 1, Variadic, "")),
+		since(Version{Major: 2022}, scalar("LEAST", 1, Variadic, "")),
+		since(Version{Major: 2022}, scalar("DATE_BUCKET", 3, 4, "DATETIME")),
+		since(Version{Major: 2022}, scalar("GENERATE_SERIES", 2, 3, "")),
+	}),
+}
+
+// sqlServerReleases maps internal major versions to release years
+var sqlServerReleases = map[int]int{10: 2008, 11: 2012, 12: 2014, 13: 2016, 14: 2017, 15: 2019, 16: 2022}
+
+// sqlServerRelease accepts both 2016 and its internal version 13
+func sqlServerRelease(v Version) Version {
+	if year, ok := sqlServerReleases[v.Major]; ok {
+		return Version{Major: year}
+	}
+	return v
+}
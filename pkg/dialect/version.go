@@ -0,0 +1,108 @@
+package dialect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a server version such as MySQL 5.7, PostgreSQL 16 or SQL
+// Server 2022. The zero Version means "the latest release we know".
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses "8", "5.7" or "8.0.14"
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	parts := strings.Split(strings.TrimSpace(s), ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+
+	fields := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		*fields[i] = n
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	if v.Patch > 0 {
+		return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	}
+	if v.Minor > 0 {
+		return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	}
+	return strconv.Itoa(v.Major)
+}
+
+// IsZero reports whether v is the zero Version
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// AtLeast reports whether v is the same as or newer than other
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// always marks a feature, operator or function every version supports
+var always = Version{}
+
+// supportedIn reports whether the entry for key exists in table and its
+// minimum version is satisfied by v
+func supportedIn[K comparable](table map[K]Version, key K, v Version) bool {
+	since, ok := table[key]
+	return ok && v.AtLeast(since)
+}
+
+// orLatest returns v, or latest when v is the zero Version
+func orLatest(v, latest Version) Version {
+	if v.IsZero() {
+		return latest
+	}
+	return v
+}
+
+// GetDialectVersion looks up the dialect for name, pinned to a server
+// version. An empty version selects the latest release.
+func GetDialectVersion(name, version string) (Dialect, error) {
+	d, err := Lookup(name)
+	if err != nil || version == "" {
+		return d, err
+	}
+
+	v, err := ParseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	switch d := d.(type) {
+	case *MySQLDialect:
+		d.Version = v
+	case *PostgreSQLDialect:
+		d.Version = v
+	case *SQLServerDialect:
+		d.Version = sqlServerRelease(v)
+	case *SQLiteDialect:
+		d.Version = v
+	case *OracleDialect:
+		d.Version = v
+	default:
+		return nil, fmt.Errorf("dialect %s cannot be pinned to a version", d.Name())
+	}
+	return d, nil
+}
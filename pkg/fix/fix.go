@@ -0,0 +1,203 @@
+package fix
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// maxFixes bounds the rewrites applied to one script
+const maxFixes = 100
+
+// Edit replaces the source text from Start to End
+type Edit struct {
+	Start int
+	End   int // offset just past the replaced text
+	Text  string
+}
+
+// Fix is a rewrite of the source text that resolves a suggestion
+type Fix struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Safe        bool   `json:"safe"` // false when the rewrite can change what the statement does
+	Edits       []Edit `json:"-"`
+}
+
+// key identifies a fix by what it rewrites
+func (f Fix) key() string {
+	var b strings.Builder
+	b.WriteString(f.Rule)
+	for _, e := range f.Edits {
+		fmt.Fprintf(&b, "|%d:%d:%s", e.Start, e.End, e.Text)
+	}
+	return b.String()
+}
+
+// Result is a fixed script and the fixes applied to it
+type Result struct {
+	SQL     string
+	Applied []Fix
+	Skipped []Fix // unsafe fixes left out
+}
+
+// Changed reports whether any fix was applied
+func (r *Result) Changed() bool {
+	return len(r.Applied) > 0
+}
+
+// Fixer rewrites SQL to resolve the auto-fixable optimization suggestions
+type Fixer struct {
+	dialect  dialect.Dialect
+	resolver *schema.Resolver
+	unsafe   bool
+	disabled map[string]bool
+}
+
+// NewFixer creates a fixer. The schema lists the columns SELECT * expands
+// to and may be nil.
+func NewFixer(d dialect.Dialect, s *schema.Schema) *Fixer {
+	f := &Fixer{dialect: d, disabled: make(map[string]bool)}
+	if s != nil {
+		f.resolver = schema.NewResolver(d, s)
+	}
+	return f
+}
+
+// AllowUnsafe also applies the fixes that change what a statement does,
+// such as = NULL becoming IS NULL
+func (f *Fixer) AllowUnsafe(unsafe bool) {
+	f.unsafe = unsafe
+}
+
+// DisableRule stops fixing a rule's suggestions
+func (f *Fixer) DisableRule(id string) {
+	f.disabled[strings.ToUpper(id)] = true
+}
+
+// Fix applies fixes to a script one at a time until none is left. Each
+// fixed script must parse into as many statements as the original, or the
+// fix is dropped.
+func (f *Fixer) Fix(ctx context.Context, sql string) (*Result, error) {
+	count, err := f.parse(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{SQL: sql}
+	rejected := make(map[string]bool)
+	for len(result.Applied) < maxFixes {
+		fixes, err := f.findFixes(ctx, result.SQL)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Skipped = nil
+		applied := false
+		for _, fx := range fixes {
+			if !fx.Safe && !f.unsafe {
+				result.Skipped = append(result.Skipped, fx)
+				continue
+			}
+			if rejected[fx.key()] {
+				continue
+			}
+
+			fixed := Apply(result.SQL, fx.Edits)
+			if n, err := f.parse(ctx, fixed); err != nil || n != count {
+				rejected[fx.key()] = true
+				continue
+			}
+			result.SQL = fixed
+			result.Applied = append(result.Applied, fx)
+			applied = true
+			break
+		}
+		if !applied {
+			break
+		}
+	}
+	return result, nil
+}
+
+// parse parses a script and returns its number of statements
+func (f *Fixer) parse(ctx context.Context, sql string) (int, error) {
+	p := parser.NewWithDialect(ctx, sql, f.dialect)
+	stmts, err := p.ParseStatements()
+	if err != nil {
+		return 0, err
+	}
+	return len(stmts), nil
+}
+
+// findFixes parses a script with spans and collects the fixes of each
+// statement
+func (f *Fixer) findFixes(ctx context.Context, sql string) ([]Fix, error) {
+	p := parser.NewWithDialect(ctx, sql, f.dialect)
+	p.EnableSpans()
+	stmts, err := p.ParseStatements()
+	if err != nil {
+		return nil, err
+	}
+
+	src := &source{sql: sql, parser: p, dialect: f.dialect}
+	var fixes []Fix
+	for _, stmt := range stmts {
+		for _, r := range rewriters {
+			if f.disabled[r.rule] {
+				continue
+			}
+			for _, fx := range r.fixes(f, src, stmt) {
+				fx.Rule = r.rule
+				fixes = append(fixes, fx)
+			}
+		}
+	}
+	return fixes, nil
+}
+
+// Apply applies non-overlapping edits to a text
+func Apply(text string, edits []Edit) string {
+	sorted := make([]Edit, len(edits))
+	copy(sorted, edits)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var b strings.Builder
+	pos := 0
+	for _, e := range sorted {
+		b.WriteString(text[pos:e.Start])
+		b.WriteString(e.Text)
+		pos = e.End
+	}
+	b.WriteString(text[pos:])
+	return b.String()
+}
+
+// source is a parsed script and the spans of its nodes
+type source struct {
+	sql     string
+	parser  *parser.Parser
+	dialect dialect.Dialect
+}
+
+// span returns the source span of a node
+func (s *source) span(node parser.Node) (parser.Span, bool) {
+	if node == nil {
+		return parser.Span{}, false
+	}
+	return s.parser.Span(node)
+}
+
+// text returns the source text of a node
+func (s *source) text(node parser.Node) (string, bool) {
+	span, ok := s.span(node)
+	if !ok {
+		return "", false
+	}
+	return s.sql[span.Start:span.End], true
+}
@@ -0,0 +1,741 @@
+package fix
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// deleteBatchSize is the row limit added to unbounded deletes
+const deleteBatchSize = 1000
+
+// rewriter finds the fixes for one rule's suggestions in a statement
+type rewriter struct {
+	rule  string
+	fixes func(*Fixer, *source, parser.Statement) []Fix
+}
+
+// rewriters run in this order; the first fix that re-parses is applied
+var rewriters = []rewriter{
+	{"SELECT_STAR", (*Fixer).expandStar},
+	{"INEFFICIENT_SUBQUERY", (*Fixer).inToExists},
+	{"SQLSERVER_NOLOCK_WARNING", (*Fixer).removeNoLock},
+	{"CARTESIAN_PRODUCT", (*Fixer).explicitJoins},
+	{"FUNCTION_IN_WHERE", (*Fixer).yearRanges},
+	{"NULL_COMPARISON", (*Fixer).nullChecks},
+	{"UNBOUNDED_DELETE", (*Fixer).boundDelete},
+}
+
+// RuleIDs returns the IDs of the rules with automatic fixes
+func RuleIDs() []string {
+	ids := make([]string, len(rewriters))
+	for i, r := range rewriters {
+		ids[i] = r.rule
+	}
+	return ids
+}
+
+// expandStar replaces * and t.* with the columns the schema lists, once
+// every table of the query resolves
+func (f *Fixer) expandStar(src *source, stmt parser.Statement) []Fix {
+	sel, ok := stmt.(*parser.SelectStatement)
+	if !ok || f.resolver == nil || sel.From == nil {
+		return nil
+	}
+
+	tables := queryTables(sel)
+	for _, join := range sel.Joins {
+		// USING and NATURAL merge the join columns, APPLY and LATERAL
+		// join subqueries
+		if join.Natural || len(join.Using) > 0 || join.Lateral || strings.HasSuffix(join.JoinType, "APPLY") {
+			return nil
+		}
+	}
+	resolved := make([]*schema.Table, len(tables))
+	for i, t := range tables {
+		table, ok := f.resolver.ResolveTable(t)
+		if !ok || t.Subquery != nil {
+			return nil
+		}
+		resolved[i] = table
+	}
+
+	var fixes []Fix
+	for _, col := range sel.Columns {
+		star, ok := col.(*parser.StarExpression)
+		if !ok || star.Schema != "" {
+			continue
+		}
+		text, ok := src.text(star)
+		if !ok {
+			continue
+		}
+
+		var columns []string
+		var names []string
+		if star.Table == "" {
+			if len(tables) > 1 && !distinctQualifiers(tables) {
+				continue
+			}
+			for i, t := range tables {
+				qualifier := ""
+				if len(tables) > 1 {
+					qualifier = f.quoteIdent(tableQualifier(t))
+				}
+				columns = append(columns, f.columnList(qualifier, resolved[i])...)
+				names = append(names, resolved[i].Name)
+			}
+		} else {
+			i := findTable(tables, star.Table)
+			if i < 0 {
+				continue
+			}
+			// Keep the qualifier as written
+			qualifier := strings.TrimSpace(text[:strings.LastIndex(text, ".")])
+			columns = f.columnList(qualifier, resolved[i])
+			names = append(names, resolved[i].Name)
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		span, _ := src.span(star)
+		fixes = append(fixes, Fix{
+			Description: fmt.Sprintf("Expand %s to the columns of %s", text, strings.Join(names, ", ")),
+			Safe:        true,
+			Edits:       []Edit{{Start: span.Start, End: span.End, Text: strings.Join(columns, ", ")}},
+		})
+	}
+	return fixes
+}
+
+// columnList returns a table's columns, optionally qualified
+func (f *Fixer) columnList(qualifier string, table *schema.Table) []string {
+	var columns []string
+	for _, col := range table.OrderedColumns() {
+		name := f.quoteIdent(col.Name)
+		if qualifier != "" {
+			name = qualifier + "." + name
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// inToExists rewrites x IN (SELECT y FROM ...) to
+// EXISTS (SELECT 1 FROM ... WHERE y = x). The two differ only when the
+// IN is unknown rather than false, which WHERE treats alike as long as
+// the IN isn't negated.
+func (f *Fixer) inToExists(src *source, stmt parser.Statement) []Fix {
+	sel, ok := stmt.(*parser.SelectStatement)
+	if !ok || sel.Where == nil || sel.From == nil {
+		return nil
+	}
+
+	var fixes []Fix
+	for _, in := range inSubqueries(sel.Where) {
+		if fx, ok := f.existsFix(src, sel, in); ok {
+			fixes = append(fixes, fx)
+		}
+	}
+	return fixes
+}
+
+// existsFix builds the EXISTS rewrite of an IN subquery
+func (f *Fixer) existsFix(src *source, sel *parser.SelectStatement, in *parser.InExpression) (Fix, bool) {
+	sub := in.Values[0].(*parser.SubqueryExpression)
+	query := sub.Query
+	if query == nil || query.From == nil || len(query.Columns) != 1 ||
+		len(query.DistinctOn) > 0 || len(query.GroupBy) > 0 || query.Having != nil ||
+		len(query.Windows) > 0 || query.Qualify != nil || len(query.OrderBy) > 0 ||
+		query.Limit != nil || len(query.Hints) > 0 || query.Into != nil {
+		return Fix{}, false
+	}
+	inner, ok := query.Columns[0].(*parser.ColumnReference)
+	if !ok {
+		return Fix{}, false
+	}
+	outer, ok := in.Expression.(*parser.ColumnReference)
+	if !ok {
+		return Fix{}, false
+	}
+
+	innerText, ok := src.text(inner)
+	if !ok {
+		return Fix{}, false
+	}
+	outerText, ok := src.text(outer)
+	if !ok {
+		return Fix{}, false
+	}
+
+	// Moved into the subquery, the outer column must still name the outer
+	// table, so it needs a qualifier no inner table shadows
+	qualifier := outer.Table
+	if qualifier == "" {
+		if len(sel.From.Tables) != 1 || len(sel.Joins) > 0 {
+			return Fix{}, false
+		}
+		qualifier = tableQualifier(&sel.From.Tables[0])
+		outerText = f.quoteIdent(qualifier) + "." + outerText
+	}
+	for _, t := range queryTables(query) {
+		if strings.EqualFold(tableQualifier(t), qualifier) || strings.EqualFold(t.Name, qualifier) {
+			return Fix{}, false
+		}
+	}
+
+	inSpan, ok1 := src.span(in)
+	subSpan, ok2 := src.span(sub)
+	querySpan, ok3 := src.span(query)
+	colSpan, ok4 := src.span(inner)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return Fix{}, false
+	}
+
+	cond := innerText + " = " + outerText
+	var b strings.Builder
+	b.WriteString("EXISTS (")
+	b.WriteString(src.sql[querySpan.Start:colSpan.Start]) // SELECT [DISTINCT]
+	b.WriteString("1")
+	if query.Where != nil {
+		whereSpan, ok := src.span(query.Where)
+		if !ok {
+			return Fix{}, false
+		}
+		b.WriteString(src.sql[colSpan.End:whereSpan.Start]) // FROM ... WHERE
+		b.WriteString(src.operand(query.Where))
+		b.WriteString(" AND ")
+		b.WriteString(cond)
+		b.WriteString(src.sql[whereSpan.End:querySpan.End])
+	} else {
+		b.WriteString(src.sql[colSpan.End:querySpan.End])
+		b.WriteString(" WHERE ")
+		b.WriteString(cond)
+	}
+	b.WriteString(src.sql[querySpan.End:subSpan.End]) // )
+
+	return Fix{
+		Description: fmt.Sprintf("Rewrite %s IN (SELECT %s ...) as EXISTS", outerText, innerText),
+		Safe:        true,
+		Edits:       []Edit{{Start: inSpan.Start, End: inSpan.End, Text: b.String()}},
+	}, true
+}
+
+// inSubqueries returns the IN (SELECT ...) conditions of a WHERE clause
+// that aren't negated, looking through AND and OR
+func inSubqueries(expr parser.Expression) []*parser.InExpression {
+	switch e := expr.(type) {
+	case *parser.BinaryExpression:
+		if e.Operator == "AND" || e.Operator == "OR" {
+			return append(inSubqueries(e.Left), inSubqueries(e.Right)...)
+		}
+	case *parser.InExpression:
+		if len(e.Values) == 1 && !e.Not {
+			if _, ok := e.Values[0].(*parser.SubqueryExpression); ok {
+				return []*parser.InExpression{e}
+			}
+		}
+	}
+	return nil
+}
+
+// removeNoLock drops NOLOCK and READUNCOMMITTED table hints, so the
+// table is read at the session's isolation level
+func (f *Fixer) removeNoLock(src *source, stmt parser.Statement) []Fix {
+	if f.dialect == nil || f.dialect.Name() != "SQL Server" {
+		return nil
+	}
+
+	var fixes []Fix
+	for _, table := range hintedTables(stmt) {
+		var removed []string
+		for _, hint := range table.Hints {
+			if hint.Name == "NOLOCK" || hint.Name == "READUNCOMMITTED" {
+				removed = append(removed, hint.Name)
+			}
+		}
+		if len(removed) == 0 {
+			continue
+		}
+		span, ok := src.span(table)
+		if !ok {
+			continue
+		}
+		if edit, ok := f.noLockEdit(src.sql, span); ok {
+			fixes = append(fixes, Fix{
+				Description: fmt.Sprintf("Remove %s from %s", strings.Join(removed, ", "), table.Name),
+				Safe:        true,
+				Edits:       []Edit{edit},
+			})
+		}
+	}
+	return fixes
+}
+
+// noLockEdit rewrites the hint list, the last parenthesized group of a
+// table reference, without its NOLOCK and READUNCOMMITTED hints
+func (f *Fixer) noLockEdit(sql string, span parser.Span) (Edit, bool) {
+	var tokens []lexer.Token
+	l := lexer.NewWithDialect(sql[span.Start:span.End], f.dialect)
+	for tok := l.NextToken(); tok.Type != lexer.EOF; tok = l.NextToken() {
+		tok.Position += span.Start
+		tok.End += span.Start
+		tokens = append(tokens, tok)
+	}
+	if len(tokens) == 0 || tokens[len(tokens)-1].Type != lexer.RPAREN {
+		return Edit{}, false
+	}
+
+	// Find the opening parenthesis and split the hints at commas
+	closing := len(tokens) - 1
+	opening := -1
+	depth := 0
+	var items [][]lexer.Token
+	var item []lexer.Token
+	for i := closing - 1; i >= 0 && opening < 0; i-- {
+		tok := tokens[i]
+		switch {
+		case tok.Type == lexer.RPAREN:
+			depth++
+		case tok.Type == lexer.LPAREN && depth > 0:
+			depth--
+		case tok.Type == lexer.LPAREN:
+			opening = i
+			items = append(items, item)
+			continue
+		case tok.Type == lexer.COMMA && depth == 0:
+			items = append(items, item)
+			item = nil
+			continue
+		}
+		item = append([]lexer.Token{tok}, item...)
+	}
+	if opening < 0 {
+		return Edit{}, false
+	}
+
+	var kept []string
+	for i := len(items) - 1; i >= 0; i-- {
+		it := items[i]
+		if len(it) == 0 {
+			continue
+		}
+		name := strings.ToUpper(it[0].Literal)
+		if len(it) == 1 && (name == "NOLOCK" || name == "READUNCOMMITTED") {
+			continue
+		}
+		kept = append(kept, sql[it[0].Position:it[len(it)-1].End])
+	}
+
+	if len(kept) > 0 {
+		return Edit{Start: tokens[opening].End, End: tokens[closing].Position, Text: strings.Join(kept, ", ")}, true
+	}
+
+	// Drop the whole WITH (...), and the space before it
+	first := opening
+	if first > 0 && tokens[first-1].Type == lexer.WITH {
+		first--
+	}
+	if first == 0 {
+		return Edit{}, false
+	}
+	return Edit{Start: tokens[first-1].End, End: tokens[closing].End}, true
+}
+
+// explicitJoins turns FROM a, b WHERE a.id = b.a_id into
+// FROM a JOIN b ON a.id = b.a_id. Each table joins on the WHERE
+// conditions linking it to the tables before it, or is CROSS JOINed.
+func (f *Fixer) explicitJoins(src *source, stmt parser.Statement) []Fix {
+	sel, ok := stmt.(*parser.SelectStatement)
+	if !ok || sel.From == nil || len(sel.From.Tables) < 2 || len(sel.Joins) > 0 {
+		return nil
+	}
+	tables := queryTables(sel)
+	if !distinctQualifiers(tables) {
+		return nil
+	}
+
+	texts := make([]string, len(tables))
+	for i, t := range tables {
+		text, ok := src.text(t)
+		if !ok {
+			return nil
+		}
+		texts[i] = text
+	}
+
+	conjuncts := splitAnd(sel.Where)
+	used := make([]bool, len(conjuncts))
+	var b strings.Builder
+	b.WriteString(texts[0])
+	for i := 1; i < len(tables); i++ {
+		var on []string
+		for k, cond := range conjuncts {
+			if used[k] {
+				continue
+			}
+			refs, ok := conditionTables(cond, tables)
+			if !ok || len(refs) < 2 || !refs[i] {
+				continue
+			}
+			later := false
+			for j := range refs {
+				later = later || j > i
+			}
+			if later {
+				continue
+			}
+			on = append(on, src.operand(cond))
+			used[k] = true
+		}
+
+		if len(on) == 0 {
+			b.WriteString(" CROSS JOIN ")
+			b.WriteString(texts[i])
+			continue
+		}
+		b.WriteString(" JOIN ")
+		b.WriteString(texts[i])
+		b.WriteString(" ON ")
+		b.WriteString(strings.Join(on, " AND "))
+	}
+
+	first, _ := src.span(tables[0])
+	last, _ := src.span(tables[len(tables)-1])
+	edits := []Edit{{Start: first.Start, End: last.End, Text: b.String()}}
+
+	var remaining []string
+	for k, cond := range conjuncts {
+		if !used[k] {
+			remaining = append(remaining, src.operand(cond))
+		}
+	}
+	if len(remaining) < len(conjuncts) {
+		whereSpan, ok := src.span(sel.Where)
+		if !ok {
+			return nil
+		}
+		if len(remaining) == 0 {
+			// Drop the WHERE keyword along with its conditions
+			edits = append(edits, Edit{Start: last.End, End: whereSpan.End})
+		} else {
+			edits = append(edits, Edit{Start: whereSpan.Start, End: whereSpan.End, Text: strings.Join(remaining, " AND ")})
+		}
+	}
+
+	return []Fix{{
+		Description: "Replace the comma-separated tables with explicit JOINs",
+		Safe:        true,
+		Edits:       edits,
+	}}
+}
+
+// splitAnd returns the operands of a chain of ANDs
+func splitAnd(expr parser.Expression) []parser.Expression {
+	if expr == nil {
+		return nil
+	}
+	if bin, ok := expr.(*parser.BinaryExpression); ok && bin.Operator == "AND" {
+		return append(splitAnd(bin.Left), splitAnd(bin.Right)...)
+	}
+	return []parser.Expression{expr}
+}
+
+// conditionTables returns the tables a condition's columns qualify. It
+// fails for unqualified columns and expressions it can't look into, such
+// as subqueries.
+func conditionTables(expr parser.Expression, tables []*parser.TableReference) (map[int]bool, bool) {
+	refs := make(map[int]bool)
+	var walk func(parser.Expression) bool
+	walk = func(expr parser.Expression) bool {
+		switch e := expr.(type) {
+		case nil, *parser.Literal, *parser.Parameter:
+			return true
+		case *parser.ColumnReference:
+			i := findTable(tables, e.Table)
+			if e.Table == "" || i < 0 {
+				return false
+			}
+			refs[i] = true
+			return true
+		case *parser.BinaryExpression:
+			return walk(e.Left) && walk(e.Right)
+		case *parser.UnaryExpression:
+			return walk(e.Operand)
+		case *parser.BetweenExpression:
+			return walk(e.Expression) && walk(e.Low) && walk(e.High)
+		case *parser.InExpression:
+			for _, value := range e.Values {
+				if !walk(value) {
+					return false
+				}
+			}
+			return walk(e.Expression)
+		case *parser.FunctionCall:
+			for _, arg := range e.Arguments {
+				if !walk(arg) {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	}
+	if !walk(expr) {
+		return nil, false
+	}
+	return refs, true
+}
+
+// nullChecks rewrites x = NULL to x IS NULL and x <> NULL to
+// x IS NOT NULL. The comparisons are never true, so the rewrite changes
+// which rows match: it is what the query meant, but not what it did.
+func (f *Fixer) nullChecks(src *source, stmt parser.Statement) []Fix {
+	var conditions []parser.Expression
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		conditions = append(conditions, s.Where, s.Having)
+		for _, join := range s.Joins {
+			conditions = append(conditions, join.Condition)
+		}
+	case *parser.UpdateStatement:
+		conditions = append(conditions, s.Where)
+	case *parser.DeleteStatement:
+		conditions = append(conditions, s.Where)
+	}
+
+	var fixes []Fix
+	for _, cond := range conditions {
+		for _, cmp := range analyzer.NullComparisons(cond) {
+			operand := cmp.Left
+			if lit, ok := operand.(*parser.Literal); ok && lit.Value == nil {
+				operand = cmp.Right
+			}
+			if lit, ok := operand.(*parser.Literal); ok && lit.Value == nil {
+				continue // NULL = NULL
+			}
+			text, ok := src.text(cmp)
+			span, _ := src.span(cmp)
+			if !ok {
+				continue
+			}
+			if _, ok := src.span(operand); !ok {
+				continue
+			}
+
+			check := " IS NULL"
+			if cmp.Operator != "=" {
+				check = " IS NOT NULL"
+			}
+			operandText, _ := src.text(operand)
+			switch operand.(type) {
+			case *parser.BinaryExpression, *parser.UnaryExpression, *parser.BetweenExpression, *parser.InExpression:
+				if !src.parenthesized(operand) {
+					operandText = "(" + operandText + ")"
+				}
+			}
+			replacement := operandText + check
+			fixes = append(fixes, Fix{
+				Description: fmt.Sprintf("Replace %s with %s", text, replacement),
+				Safe:        false,
+				Edits:       []Edit{{Start: span.Start, End: span.End, Text: replacement}},
+			})
+		}
+	}
+	return fixes
+}
+
+// yearRanges replaces comparisons of a column's year, such as
+// YEAR(created_at) = 2024, with the date range they select, which an index
+// on the column can serve
+func (f *Fixer) yearRanges(src *source, stmt parser.Statement) []Fix {
+	if f.dialect == nil {
+		return nil
+	}
+	var conditions []parser.Expression
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		conditions = append(conditions, s.Where)
+		for _, join := range s.Joins {
+			conditions = append(conditions, join.Condition)
+		}
+	case *parser.UpdateStatement:
+		conditions = append(conditions, s.Where)
+	case *parser.DeleteStatement:
+		conditions = append(conditions, s.Where)
+	}
+
+	var fixes []Fix
+	for _, cond := range conditions {
+		for _, r := range analyzer.YearComparisons(cond) {
+			text, ok := src.text(r.Comparison)
+			span, _ := src.span(r.Comparison)
+			column, columnOK := src.text(r.Column)
+			if !ok || !columnOK {
+				continue
+			}
+			replacement := r.Condition(f.dialect, column)
+			if r.From != 0 && r.To != 0 && !r.Conjunct && !src.parenthesized(r.Comparison) {
+				replacement = "(" + replacement + ")"
+			}
+			fixes = append(fixes, Fix{
+				Description: fmt.Sprintf("Replace %s with %s", text, replacement),
+				Safe:        true,
+				Edits:       []Edit{{Start: span.Start, End: span.End, Text: replacement}},
+			})
+		}
+	}
+	return fixes
+}
+
+// boundDelete limits a DELETE without WHERE to a batch of rows, to be
+// run until it affects no rows. Each run deletes one batch rather than
+// the whole table.
+func (f *Fixer) boundDelete(src *source, stmt parser.Statement) []Fix {
+	del, ok := stmt.(*parser.DeleteStatement)
+	if !ok || del.Where != nil || del.Limit != nil || f.dialect == nil || !analyzer.SupportsDeleteLimit(f.dialect) {
+		return nil
+	}
+	span, ok := src.span(del)
+	if !ok {
+		return nil
+	}
+
+	var edit Edit
+	switch f.dialect.Name() {
+	case "SQL Server":
+		// DELETE TOP (n) FROM t
+		const keyword = "DELETE"
+		if !strings.EqualFold(src.sql[span.Start:min(span.Start+len(keyword), span.End)], keyword) {
+			return nil
+		}
+		at := span.Start + len(keyword)
+		edit = Edit{Start: at, End: at, Text: fmt.Sprintf(" TOP (%d)", deleteBatchSize)}
+	default:
+		edit = Edit{Start: span.End, End: span.End, Text: fmt.Sprintf(" LIMIT %d", deleteBatchSize)}
+	}
+
+	return []Fix{{
+		Description: fmt.Sprintf("Delete from %s in batches of %d rows; repeat until no rows are affected", del.From.Name, deleteBatchSize),
+		Safe:        false,
+		Edits:       []Edit{edit},
+	}}
+}
+
+// queryTables returns the FROM and join tables of a query
+func queryTables(sel *parser.SelectStatement) []*parser.TableReference {
+	var tables []*parser.TableReference
+	if sel.From != nil {
+		for i := range sel.From.Tables {
+			tables = append(tables, &sel.From.Tables[i])
+		}
+	}
+	for _, join := range sel.Joins {
+		tables = append(tables, &join.Table)
+	}
+	return tables
+}
+
+// hintedTables returns the table references of a statement that can carry
+// table hints
+func hintedTables(stmt parser.Statement) []*parser.TableReference {
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		return queryTables(s)
+	case *parser.InsertStatement:
+		return []*parser.TableReference{&s.Table}
+	case *parser.UpdateStatement:
+		return []*parser.TableReference{&s.Table}
+	case *parser.DeleteStatement:
+		return []*parser.TableReference{&s.From}
+	}
+	return nil
+}
+
+// tableQualifier returns the name columns qualify a table by
+func tableQualifier(t *parser.TableReference) string {
+	if t.Alias != "" {
+		return t.Alias
+	}
+	return t.Name
+}
+
+// findTable returns the index of the table a qualifier names, or -1
+func findTable(tables []*parser.TableReference, qualifier string) int {
+	for i, t := range tables {
+		if qualifier != "" && strings.EqualFold(tableQualifier(t), qualifier) {
+			return i
+		}
+	}
+	return -1
+}
+
+// distinctQualifiers reports whether every table has its own qualifier
+func distinctQualifiers(tables []*parser.TableReference) bool {
+	seen := make(map[string]bool)
+	for _, t := range tables {
+		q := strings.ToLower(tableQualifier(t))
+		if q == "" || seen[q] {
+			return false
+		}
+		seen[q] = true
+	}
+	return true
+}
+
+// plainIdent matches identifiers that need no quoting
+var plainIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// quoteIdent quotes an identifier when the dialect requires it
+func (f *Fixer) quoteIdent(name string) string {
+	if plainIdent.MatchString(name) && (f.dialect == nil || !f.dialect.IsReservedWord(name)) {
+		return name
+	}
+	if f.dialect == nil {
+		return `"` + name + `"`
+	}
+	return f.dialect.QuoteIdentifier(name)
+}
+
+// operand returns an expression's text, parenthesized when it is an OR
+// that isn't already, so that it can be ANDed with other conditions
+func (s *source) operand(expr parser.Expression) string {
+	text, _ := s.text(expr)
+	if bin, ok := expr.(*parser.BinaryExpression); ok && bin.Operator == "OR" && !s.parenthesized(expr) {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+// parenthesized reports whether an expression's text is wrapped in one
+// pair of parentheses
+func (s *source) parenthesized(expr parser.Expression) bool {
+	text, _ := s.text(expr)
+	l := lexer.NewWithDialect(text, s.dialect)
+	depth := 0
+	for tok := l.NextToken(); tok.Type != lexer.EOF; tok = l.NextToken() {
+		switch tok.Type {
+		case lexer.LPAREN:
+			if tok.Position > 0 && depth == 0 {
+				return false
+			}
+			depth++
+		case lexer.RPAREN:
+			depth--
+			if depth == 0 {
+				return tok.End == len(text)
+			}
+		default:
+			if depth == 0 {
+				return false
+			}
+		}
+	}
+	return false
+}
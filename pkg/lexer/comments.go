@@ -0,0 +1,103 @@
+package lexer
+
+import "strings"
+
+// Comment is a comment skipped by the lexer, kept as trivia. Text includes
+// the delimiters (-- ..., # ..., /* ... */).
+type Comment struct {
+	Text     string
+	Position int
+	Line     int
+	Column   int
+}
+
+// hintableTokens are the keywords an optimizer hint may directly follow
+var hintableTokens = map[TokenType]bool{
+	SELECT:  true,
+	INSERT:  true,
+	UPDATE:  true,
+	DELETE:  true,
+	REPLACE: true,
+	MERGE:   true,
+}
+
+// Comments returns the comments skipped so far, in source order
+func (l *Lexer) Comments() []Comment {
+	return l.comments
+}
+
+// supportsHints reports whether /*+ ... */ is an optimizer hint in the dialect
+func (l *Lexer) supportsHints() bool {
+	name := l.dialect.Name()
+	return name == "Oracle" || name == "MySQL"
+}
+
+// nestsBlockComments reports whether /* /* */ */ nests in the dialect
+func (l *Lexer) nestsBlockComments() bool {
+	name := l.dialect.Name()
+	return name == "PostgreSQL" || name == "SQL Server"
+}
+
+func (l *Lexer) skipLineComment() {
+	start, line, column := l.position, l.line, l.column
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+	l.comments = append(l.comments, Comment{Text: l.input[start:l.position], Position: start, Line: line, Column: column})
+}
+
+// readBlockComment consumes a /* ... */ comment. It returns a token when
+// the comment is an optimizer hint, or ILLEGAL when it is unterminated.
+// MySQL /*! ... */ sections are opened here and their content is lexed as
+// ordinary SQL.
+func (l *Lexer) readBlockComment() (Token, bool) {
+	start, line, column := l.position, l.line, l.column
+	marker := byte(0)
+	if l.readPosition+1 < len(l.input) {
+		marker = l.input[l.readPosition+1]
+	}
+
+	// MySQL: /*!50100 ... */ is executed, optionally gated on a version
+	if marker == '!' && l.dialect.Name() == "MySQL" {
+		if l.conditionalDepth == 0 {
+			l.conditionalStart = Token{Position: start, Line: line, Column: column}
+		}
+		l.readChar() // /
+		l.readChar() // *
+		l.readChar() // !
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+		l.conditionalDepth++
+		return Token{}, false
+	}
+
+	isHint := marker == '+' && l.supportsHints() && hintableTokens[l.prevType]
+	nests := l.nestsBlockComments() && !isHint
+
+	l.readChar() // /
+	l.readChar() // *
+	depth := 1
+	for depth > 0 {
+		switch {
+		case l.ch == 0:
+			return Token{Type: ILLEGAL, Literal: l.input[start:l.position], Position: start, Line: line, Column: column}, true
+		case l.ch == '*' && l.peekChar() == '/':
+			depth--
+			l.readChar()
+		case nests && l.ch == '/' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+		}
+		l.readChar()
+	}
+
+	text := l.input[start:l.position]
+	if isHint {
+		body := strings.TrimSpace(text[len("/*+") : len(text)-len("*/")])
+		return Token{Type: HINT, Literal: body, Position: start, Line: line, Column: column}, true
+	}
+
+	l.comments = append(l.comments, Comment{Text: text, Position: start, Line: line, Column: column})
+	return Token{}, false
+}
@@ -14,6 +14,12 @@ type Lexer struct {
 	line         int
 	column       int
 	dialect      dialect.Dialect
+
+	prevType         TokenType // type of the last token returned
+	start            Token     // position of the token being read
+	conditionalDepth int       // open MySQL /*! ... */ sections
+	conditionalStart Token     // position of the outermost open section
+	comments         []Comment
 }
 
 func New(input string) *Lexer {
@@ -73,14 +79,35 @@ func (l *Lexer) peekChar() byte {
 }
 
 func (l *Lexer) NextToken() Token {
+	tok := l.nextToken()
+	tok.Position, tok.Line, tok.Column = l.start.Position, l.start.Line, l.start.Column
+	tok.End = min(l.position, len(l.input))
+	l.prevType = tok.Type
+	return tok
+}
+
+func (l *Lexer) nextToken() Token {
 	var tok Token
 
 	l.skipWhitespace()
+	l.start = Token{Position: min(l.position, len(l.input)), Line: l.line, Column: l.column}
 
-	// Handle line comments
-	if l.ch == '-' && l.peekChar() == '-' {
+	// Handle comments
+	switch {
+	case l.ch == '-' && l.peekChar() == '-', l.ch == '#' && l.dialect.Name() == "MySQL":
 		l.skipLineComment()
-		return l.NextToken()
+		return l.nextToken()
+	case l.ch == '/' && l.peekChar() == '*':
+		if hint, ok := l.readBlockComment(); ok {
+			return hint
+		}
+		return l.nextToken()
+	case l.ch == '*' && l.peekChar() == '/' && l.conditionalDepth > 0:
+		// End of a MySQL /*! ... */ section
+		l.readChar()
+		l.readChar()
+		l.conditionalDepth--
+		return l.nextToken()
 	}
 
 	switch l.ch {
@@ -103,11 +130,22 @@ func (l *Lexer) NextToken() Token {
 			tok = newToken(ILLEGAL, l.ch, l.position, l.line, l.column)
 		}
 	case '<':
-		if l.peekChar() == '=' {
+		if l.peekChar() == '=' && l.charAt(2) == '>' {
+			// MySQL null-safe equality
+			return l.readOperator(3)
+		}
+		if l.peekChar() == '@' && l.dialect.Name() == "PostgreSQL" {
+			// PostgreSQL "is contained by"; elsewhere <@x compares with a variable
+			return l.readOperator(2)
+		}
+		if l.peekChar() == '=' || l.peekChar() == '>' {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
 			tok = Token{Type: LTE, Literal: literal, Position: l.position, Line: l.line, Column: l.column}
+			if literal == "<>" {
+				tok.Type = NOT_EQ
+			}
 		} else {
 			tok = newToken(LT, l.ch, l.position, l.line, l.column)
 		}
@@ -129,23 +167,33 @@ func (l *Lexer) NextToken() Token {
 	case ')':
 		tok = newToken(RPAREN, l.ch, l.position, l.line, l.column)
 	case '.':
+		// .5 is a number unless the dot qualifies a name or is part of a
+		// PL/SQL 1..10 range
+		if isDigit(l.peekChar()) && l.prevType != IDENT && l.prevType != RPAREN && l.prevType != DOT && l.prevType != NUMBER {
+			tok = Token{Type: NUMBER, Position: l.position, Line: l.line, Column: l.column}
+			tok.Literal, tok.Kind = l.readNumber()
+			return tok
+		}
 		tok = newToken(DOT, l.ch, l.position, l.line, l.column)
 	case '*':
 		tok = newToken(ASTERISK, l.ch, l.position, l.line, l.column)
 	case '+':
 		tok = newToken(PLUS, l.ch, l.position, l.line, l.column)
 	case '-':
+		if l.peekChar() == '>' {
+			// JSON extraction: -> and ->>
+			if l.charAt(2) == '>' {
+				return l.readOperator(3)
+			}
+			return l.readOperator(2)
+		}
 		tok = newToken(MINUS, l.ch, l.position, l.line, l.column)
 	case '/':
 		tok = newToken(SLASH, l.ch, l.position, l.line, l.column)
 	case '%':
 		tok = newToken(PERCENT, l.ch, l.position, l.line, l.column)
 	case '\'':
-		tok.Type = STRING
-		tok.Literal = l.readString()
-		tok.Position = l.position
-		tok.Line = l.line
-		tok.Column = l.column
+		return l.readStringLiteral(LiteralPlain, l.position, l.line, l.column, l.backslashEscapes())
 	case '"':
 		// Double quotes can be string literals or identifiers depending on dialect
 		if l.dialect.Name() == "PostgreSQL" || l.dialect.Name() == "SQLite" || l.dialect.Name() == "Oracle" {
@@ -175,21 +223,59 @@ func (l *Lexer) NextToken() Token {
 			tok.Type = IDENT
 			tok.Literal = l.readBracketedIdentifier()
 		} else {
-			tok = newToken(ILLEGAL, l.ch, l.position, l.line, l.column)
+			tok = newToken(LBRACKET, l.ch, l.position, l.line, l.column)
 		}
 		tok.Position = l.position
 		tok.Line = l.line
 		tok.Column = l.column
+	case ']':
+		tok = newToken(RBRACKET, l.ch, l.position, l.line, l.column)
+	case '|':
+		if l.peekChar() == '|' {
+			return l.readOperator(2)
+		}
+		tok = newToken(ILLEGAL, l.ch, l.position, l.line, l.column)
+	case '~':
+		tok = Token{Type: OPERATOR, Literal: "~", Position: l.position, Line: l.line, Column: l.column}
+	case '#':
+		// SQL Server temporary tables: #local and ##global
+		if l.dialect.Name() == "SQL Server" && (isLetter(l.peekChar()) || l.peekChar() == '#' && isLetter(l.charAt(2))) {
+			return l.readTempTableName()
+		}
+		tok = newToken(ILLEGAL, l.ch, l.position, l.line, l.column)
+	case '?':
+		tok = newToken(PARAM, l.ch, l.position, l.line, l.column)
+	case '@', ':':
+		if l.ch == ':' && l.peekChar() == ':' {
+			l.readChar()
+			tok = Token{Type: DOUBLE_COLON, Literal: "::", Position: l.position, Line: l.line, Column: l.column}
+			break
+		}
+		if l.ch == '@' && l.peekChar() == '>' {
+			// PostgreSQL "contains"
+			return l.readOperator(2)
+		}
+		// Named parameters and variables: @name, @@name (SQL Server, MySQL), :name (Oracle)
+		if isLetter(l.peekChar()) || (l.ch == '@' && l.peekChar() == '@') {
+			return l.readParameter()
+		}
+		tok = newToken(ILLEGAL, l.ch, l.position, l.line, l.column)
 	case '$':
+		// Positional parameters: $1, $2, ...
+		if isDigit(l.peekChar()) {
+			return l.readParameter()
+		}
 		// PostgreSQL dollar-quoted strings: $tag$...$tag$ or $$...$$
 		if l.dialect.Name() == "PostgreSQL" {
+			start, line, column := l.position, l.line, l.column
 			_, str := l.readDollarQuotedString()
 			if str != "" {
 				tok.Type = STRING
+				tok.Kind = LiteralDollar
 				tok.Literal = str
-				tok.Position = l.position
-				tok.Line = l.line
-				tok.Column = l.column
+				tok.Position = start
+				tok.Line = line
+				tok.Column = column
 			} else {
 				// Not a dollar-quoted string, treat as illegal
 				tok = newToken(ILLEGAL, l.ch, l.position, l.line, l.column)
@@ -198,6 +284,14 @@ func (l *Lexer) NextToken() Token {
 			tok = newToken(ILLEGAL, l.ch, l.position, l.line, l.column)
 		}
 	case 0:
+		if l.conditionalDepth > 0 {
+			// An unterminated MySQL /*! ... section
+			tok = l.conditionalStart
+			tok.Type = ILLEGAL
+			tok.Literal = l.input[tok.Position:]
+			l.conditionalDepth = 0
+			break
+		}
 		tok.Literal = ""
 		tok.Type = EOF
 		tok.Position = l.position
@@ -205,6 +299,9 @@ func (l *Lexer) NextToken() Token {
 		tok.Column = l.column
 	default:
 		if isLetter(l.ch) {
+			if prefixed, ok := l.readPrefixedString(); ok {
+				return prefixed
+			}
 			tok.Position = l.position
 			tok.Line = l.line
 			tok.Column = l.column
@@ -213,10 +310,10 @@ func (l *Lexer) NextToken() Token {
 			return tok
 		} else if isDigit(l.ch) {
 			tok.Type = NUMBER
-			tok.Literal = l.readNumber()
 			tok.Position = l.position
 			tok.Line = l.line
 			tok.Column = l.column
+			tok.Literal, tok.Kind = l.readNumber()
 			return tok
 		} else {
 			tok = newToken(ILLEGAL, l.ch, l.position, l.line, l.column)
@@ -233,12 +330,6 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
-func (l *Lexer) skipLineComment() {
-	for l.ch != '\n' && l.ch != 0 {
-		l.readChar()
-	}
-}
-
 func (l *Lexer) readIdentifier() string {
 	position := l.position
 	for isLetter(l.ch) || isDigit(l.ch) || l.ch == '_' {
@@ -247,45 +338,54 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:l.position]
 }
 
-func (l *Lexer) readBracketedIdentifier() string {
-	l.readChar()
+// readParameter reads a bind parameter or variable such as $1, :name or @name
+func (l *Lexer) readParameter() Token {
+	tok := Token{Type: PARAM, Position: l.position, Line: l.line, Column: l.column}
 	position := l.position
-	for l.ch != ']' && l.ch != 0 {
-		l.readChar()
+	l.readChar() // skip the sigil
+	if l.ch == '@' {
+		l.readChar() // @@global
 	}
-	result := l.input[position:l.position]
-	if l.ch == ']' {
+	for isLetter(l.ch) || isDigit(l.ch) {
 		l.readChar()
 	}
-	return result
+	tok.Literal = l.input[position:l.position]
+	return tok
 }
 
-func (l *Lexer) readNumber() string {
+// readTempTableName reads a SQL Server #temp or ##global table name
+func (l *Lexer) readTempTableName() Token {
+	tok := Token{Type: IDENT, Position: l.position, Line: l.line, Column: l.column}
 	position := l.position
-	for isDigit(l.ch) {
+	for l.ch == '#' {
 		l.readChar()
 	}
-
-	if l.ch == '.' && isDigit(l.peekChar()) {
+	for isLetter(l.ch) || isDigit(l.ch) {
 		l.readChar()
-		for isDigit(l.ch) {
-			l.readChar()
-		}
 	}
+	tok.Literal = l.input[position:l.position]
+	return tok
+}
 
-	return l.input[position:l.position]
+// readOperator reads an operator of n characters
+func (l *Lexer) readOperator(n int) Token {
+	tok := Token{Type: OPERATOR, Literal: l.input[l.position : l.position+n], Position: l.position, Line: l.line, Column: l.column}
+	for i := 0; i < n; i++ {
+		l.readChar()
+	}
+	return tok
 }
 
-func (l *Lexer) readString() string {
-	l.readChar() // skip the opening quote
+func (l *Lexer) readBracketedIdentifier() string {
+	l.readChar()
 	position := l.position
-	for l.ch != '\'' && l.ch != 0 {
-		if l.ch == '\\' {
-			l.readChar() // skip escape character
-		}
+	for l.ch != ']' && l.ch != 0 {
 		l.readChar()
 	}
 	result := l.input[position:l.position]
+	if l.ch == ']' {
+		l.readChar()
+	}
 	return result
 }
 
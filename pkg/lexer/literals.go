@@ -0,0 +1,338 @@
+package lexer
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LiteralKind records the form a STRING or NUMBER token was written in
+type LiteralKind int
+
+const (
+	LiteralPlain    LiteralKind = iota // 'text', 42, 1.5e3
+	LiteralNational                    // N'text'
+	LiteralEscape                      // E'tab\there' (PostgreSQL)
+	LiteralUnicode                     // U&'d\0061t\+000061' (PostgreSQL)
+	LiteralHex                         // X'ABCD', 0xABCD
+	LiteralBit                         // B'101', 0b101
+	LiteralQuoted                      // q'[it's]' (Oracle)
+	LiteralDollar                      // $$text$$, $tag$text$tag$ (PostgreSQL)
+)
+
+var literalKindNames = map[LiteralKind]string{
+	LiteralPlain:    "PLAIN",
+	LiteralNational: "NATIONAL",
+	LiteralEscape:   "ESCAPE",
+	LiteralUnicode:  "UNICODE",
+	LiteralHex:      "HEX",
+	LiteralBit:      "BIT",
+	LiteralQuoted:   "QUOTED",
+	LiteralDollar:   "DOLLAR",
+}
+
+func (k LiteralKind) String() string {
+	if name, ok := literalKindNames[k]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// backslashEscapes reports whether backslash escapes apply in plain strings
+func (l *Lexer) backslashEscapes() bool {
+	return l.dialect.Name() == "MySQL"
+}
+
+// readStringLiteral reads a quoted string starting at the current quote
+// and consumes the closing quote. A doubled quote stands for itself. When
+// escapes is set, backslash sequences are decoded as well.
+func (l *Lexer) readStringLiteral(kind LiteralKind, start, line, column int, escapes bool) Token {
+	quote := l.ch
+	l.readChar()
+
+	var sb strings.Builder
+	for {
+		switch {
+		case l.ch == 0:
+			return Token{Type: ILLEGAL, Literal: l.input[start:l.position], Position: start, Line: line, Column: column}
+		case l.ch == quote && l.peekChar() == quote:
+			sb.WriteByte(quote)
+			l.readChar()
+		case l.ch == quote:
+			l.readChar()
+			return Token{Type: STRING, Literal: sb.String(), Kind: kind, Position: start, Line: line, Column: column}
+		case l.ch == '\\' && escapes:
+			l.readChar()
+			if l.ch == 0 {
+				continue
+			}
+			sb.WriteString(l.readEscape(kind == LiteralEscape))
+			continue
+		default:
+			sb.WriteByte(l.ch)
+		}
+		l.readChar()
+	}
+}
+
+// readEscape decodes the backslash sequence at the current character and
+// moves past it. PostgreSQL E'...' strings add octal, \x, \u and \U escapes;
+// MySQL keeps \% and \_ so LIKE patterns still see them.
+func (l *Lexer) readEscape(postgres bool) string {
+	ch := l.ch
+	l.readChar()
+
+	switch ch {
+	case 'n':
+		return "\n"
+	case 't':
+		return "\t"
+	case 'r':
+		return "\r"
+	case 'b':
+		return "\b"
+	}
+
+	if !postgres {
+		switch ch {
+		case '0':
+			return "\x00"
+		case 'Z':
+			return "\x1a"
+		case '%', '_':
+			return "\\" + string(ch)
+		}
+		return string(ch)
+	}
+
+	switch {
+	case ch == 'f':
+		return "\f"
+	case ch >= '0' && ch <= '7':
+		return l.readCodePoint(string(ch), 3, 8)
+	case ch == 'x' && isHexDigit(l.ch):
+		return l.readCodePoint("", 2, 16)
+	case ch == 'u':
+		return l.readCodePoint("", 4, 16)
+	case ch == 'U':
+		return l.readCodePoint("", 8, 16)
+	}
+	return string(ch)
+}
+
+// readCodePoint reads up to max digits in base and returns the character
+// they encode
+func (l *Lexer) readCodePoint(digits string, max, base int) string {
+	for len(digits) < max && (base == 16 && isHexDigit(l.ch) || base == 8 && l.ch >= '0' && l.ch <= '7') {
+		digits += string(l.ch)
+		l.readChar()
+	}
+	value, err := strconv.ParseUint(digits, base, 32)
+	if err != nil {
+		return digits
+	}
+	if base == 8 || max == 2 {
+		return string([]byte{byte(value)})
+	}
+	return string(rune(value))
+}
+
+// readPrefixedString reads a string with a letter prefix: N'...',
+// E'...', U&'...', X'...', B'...' and Oracle q'[...]'. It reports false
+// when the identifier at the current position does not start one.
+func (l *Lexer) readPrefixedString() (Token, bool) {
+	start, line, column := l.position, l.line, l.column
+	name := l.dialect.Name()
+	prefix := l.ch | 0x20 // lower case
+	next := l.peekChar()
+
+	switch {
+	case prefix == 'n' && next == '\'':
+		l.readChar()
+		return l.readStringLiteral(LiteralNational, start, line, column, l.backslashEscapes()), true
+	case prefix == 'n' && (next == 'q' || next == 'Q') && name == "Oracle" && l.charAt(2) == '\'':
+		l.readChar()
+		tok := l.readQuotedString(start, line, column)
+		if tok.Type == STRING {
+			tok.Kind = LiteralNational
+		}
+		return tok, true
+	case prefix == 'e' && next == '\'' && name == "PostgreSQL":
+		l.readChar()
+		return l.readStringLiteral(LiteralEscape, start, line, column, true), true
+	case prefix == 'u' && next == '&' && l.charAt(2) == '\'' && name == "PostgreSQL":
+		l.readChar()
+		l.readChar()
+		tok := l.readStringLiteral(LiteralUnicode, start, line, column, false)
+		if tok.Type == STRING {
+			decoded, ok := decodeUnicodeEscapes(tok.Literal)
+			if !ok {
+				return Token{Type: ILLEGAL, Literal: l.input[start:l.position], Position: start, Line: line, Column: column}, true
+			}
+			tok.Literal = decoded
+		}
+		return tok, true
+	case prefix == 'x' && next == '\'':
+		l.readChar()
+		return l.readDigitString(LiteralHex, isHexDigit, start, line, column), true
+	case prefix == 'b' && next == '\'' && (name == "PostgreSQL" || name == "MySQL"):
+		l.readChar()
+		return l.readDigitString(LiteralBit, isBitDigit, start, line, column), true
+	case prefix == 'q' && next == '\'' && name == "Oracle":
+		return l.readQuotedString(start, line, column), true
+	}
+
+	return Token{}, false
+}
+
+// readDigitString reads X'ABCD' or B'0101', rejecting other characters
+func (l *Lexer) readDigitString(kind LiteralKind, valid func(byte) bool, start, line, column int) Token {
+	tok := l.readStringLiteral(kind, start, line, column, false)
+	if tok.Type != STRING {
+		return tok
+	}
+	for i := 0; i < len(tok.Literal); i++ {
+		if !valid(tok.Literal[i]) {
+			return Token{Type: ILLEGAL, Literal: l.input[start:l.position], Position: start, Line: line, Column: column}
+		}
+	}
+	return tok
+}
+
+// readQuotedString reads Oracle's q'<delim>...<delim>' alternative quoting.
+// Brackets close with their pair; any other delimiter closes with itself.
+func (l *Lexer) readQuotedString(start, line, column int) Token {
+	l.readChar() // q
+	l.readChar() // '
+
+	closing := l.ch
+	switch l.ch {
+	case '[':
+		closing = ']'
+	case '{':
+		closing = '}'
+	case '(':
+		closing = ')'
+	case '<':
+		closing = '>'
+	}
+	if l.ch == 0 || l.ch == ' ' || l.ch == '\t' || l.ch == '\n' {
+		return Token{Type: ILLEGAL, Literal: l.input[start:l.position], Position: start, Line: line, Column: column}
+	}
+	l.readChar()
+
+	contentStart := l.position
+	for {
+		if l.ch == 0 {
+			return Token{Type: ILLEGAL, Literal: l.input[start:l.position], Position: start, Line: line, Column: column}
+		}
+		if l.ch == closing && l.peekChar() == '\'' {
+			content := l.input[contentStart:l.position]
+			l.readChar()
+			l.readChar()
+			return Token{Type: STRING, Literal: content, Kind: LiteralQuoted, Position: start, Line: line, Column: column}
+		}
+		l.readChar()
+	}
+}
+
+// decodeUnicodeEscapes decodes the \XXXX and \+XXXXXX escapes of a
+// PostgreSQL U&'...' string. A doubled backslash stands for itself.
+func decodeUnicodeEscapes(s string) (string, bool) {
+	if !strings.Contains(s, "\\") {
+		return s, true
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		digits := 4
+		switch {
+		case i+1 < len(s) && s[i+1] == '\\':
+			sb.WriteByte('\\')
+			i++
+			continue
+		case i+1 < len(s) && s[i+1] == '+':
+			digits = 6
+			i++
+		}
+		if i+digits >= len(s) {
+			return "", false
+		}
+		value, err := strconv.ParseUint(s[i+1:i+1+digits], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(value)) {
+			return "", false
+		}
+		sb.WriteRune(rune(value))
+		i += digits
+	}
+	return sb.String(), true
+}
+
+// readNumber reads a decimal number with optional fraction and exponent
+// (42, 1.5, .5, 1e10, 2.5E-3), a 0x hex number or a MySQL 0b bit number
+func (l *Lexer) readNumber() (string, LiteralKind) {
+	position := l.position
+
+	if l.ch == '0' && (l.peekChar() == 'x' || l.peekChar() == 'X') && isHexDigit(l.charAt(2)) {
+		l.readChar()
+		l.readChar()
+		for isHexDigit(l.ch) {
+			l.readChar()
+		}
+		return l.input[position:l.position], LiteralHex
+	}
+	if l.ch == '0' && l.peekChar() == 'b' && isBitDigit(l.charAt(2)) && l.dialect.Name() == "MySQL" {
+		l.readChar()
+		l.readChar()
+		for isBitDigit(l.ch) {
+			l.readChar()
+		}
+		return l.input[position:l.position], LiteralBit
+	}
+
+	for isDigit(l.ch) {
+		l.readChar()
+	}
+
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		l.readChar()
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+	}
+
+	if l.ch == 'e' || l.ch == 'E' {
+		next := l.peekChar()
+		if isDigit(next) || ((next == '+' || next == '-') && isDigit(l.charAt(2))) {
+			l.readChar()
+			if l.ch == '+' || l.ch == '-' {
+				l.readChar()
+			}
+			for isDigit(l.ch) {
+				l.readChar()
+			}
+		}
+	}
+
+	return l.input[position:l.position], LiteralPlain
+}
+
+// charAt returns the character offset positions ahead of the current one
+func (l *Lexer) charAt(offset int) byte {
+	if l.position+offset >= len(l.input) {
+		return 0
+	}
+	return l.input[l.position+offset]
+}
+
+func isHexDigit(ch byte) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
+func isBitDigit(ch byte) bool {
+	return ch == '0' || ch == '1'
+}
@@ -12,6 +12,8 @@ const (
 	IDENT  // table_name, column_name
 	STRING // 'hello'
 	NUMBER // 123, 123.45
+	PARAM  // ?, $1, :name, @name
+	HINT   // /*+ optimizer hints */ (Oracle, MySQL)
 
 	// SQL Keywords
 	SELECT
@@ -197,7 +199,7 @@ const (
 	// Operators
 	ASSIGN  // =
 	EQ      // ==
-	NOT_EQ  // !=
+	NOT_EQ  // != or <>
 	LT      // <
 	GT      // >
 	LTE     // <=
@@ -218,6 +220,11 @@ const (
 	MINUS     // -
 	SLASH     // /
 	PERCENT   // %
+
+	DOUBLE_COLON // :: (PostgreSQL cast)
+	OPERATOR     // ||, <=>, ->, ->>, @>, <@, ~ (dialect-specific operators)
+	LBRACKET     // [ outside SQL Server, e.g. the PostgreSQL array type int[]
+	RBRACKET     // ]
 )
 
 var keywords = map[string]TokenType{
@@ -397,6 +404,8 @@ var tokenNames = map[TokenType]string{
 	IDENT:          "IDENT",
 	STRING:         "STRING",
 	NUMBER:         "NUMBER",
+	PARAM:          "PARAM",
+	HINT:           "HINT",
 	SELECT:         "SELECT",
 	FROM:           "FROM",
 	WHERE:          "WHERE",
@@ -475,6 +484,10 @@ var tokenNames = map[TokenType]string{
 	MINUS:          "MINUS",
 	SLASH:          "SLASH",
 	PERCENT:        "PERCENT",
+	DOUBLE_COLON:   "DOUBLE_COLON",
+	OPERATOR:       "OPERATOR",
+	LBRACKET:       "LBRACKET",
+	RBRACKET:       "RBRACKET",
 	BEGIN:          "BEGIN",
 	START:          "START",
 	COMMIT:         "COMMIT",
@@ -586,7 +599,9 @@ var tokenNames = map[TokenType]string{
 type Token struct {
 	Type     TokenType
 	Literal  string
-	Position int
+	Kind     LiteralKind // form of a STRING or NUMBER literal
+	Position int         // byte offset of the token's first character
+	End      int         // byte offset just past the token
 	Line     int
 	Column   int
 }
@@ -0,0 +1,477 @@
+package lineage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Transformation is how an output column is derived from its sources
+type Transformation string
+
+const (
+	TransformDirect     Transformation = "DIRECT"     // copied unchanged
+	TransformExpression Transformation = "EXPRESSION" // computed by functions or operators
+	TransformCase       Transformation = "CASE"       // chosen by a CASE expression
+	TransformAggregate  Transformation = "AGGREGATE"  // aggregated over groups of rows
+	TransformWindow     Transformation = "WINDOW"     // computed by a window function
+)
+
+// transformOrder is the order transformations are reported in
+var transformOrder = []Transformation{TransformDirect, TransformExpression, TransformCase, TransformAggregate, TransformWindow}
+
+// Column is a column of a source table
+type Column struct {
+	Table  string `json:"table"` // qualified table name, empty when it can't be told
+	Column string `json:"column"`
+}
+
+func (c Column) String() string {
+	if c.Table == "" {
+		return c.Column
+	}
+	return c.Table + "." + c.Column
+}
+
+// ColumnLineage is where one output column comes from
+type ColumnLineage struct {
+	Name            string           `json:"name"`
+	Sources         []Column         `json:"sources"`
+	Transformations []Transformation `json:"transformations"`
+}
+
+// Dependency is a source column that decides which rows are produced,
+// or how they are grouped, rather than flowing into an output column
+type Dependency struct {
+	Column
+	Clause string `json:"clause"` // JOIN, WHERE, GROUP BY, HAVING, QUALIFY, WINDOW or WHEN
+}
+
+// Lineage is the column lineage of one statement
+type Lineage struct {
+	Target   string           `json:"target,omitempty"` // table or view written, empty for queries
+	Inputs   []string         `json:"inputs"`           // tables read
+	Columns  []*ColumnLineage `json:"columns"`
+	Filters  []Dependency     `json:"filters,omitempty"`
+	Warnings []string         `json:"warnings,omitempty"`
+}
+
+// JSON returns the lineage as indented JSON
+func (l *Lineage) JSON() ([]byte, error) {
+	return json.MarshalIndent(l, "", "  ")
+}
+
+// Extractor computes column lineage
+type Extractor struct {
+	dialect  dialect.Dialect
+	resolver *schema.Resolver
+}
+
+// NewExtractor creates a lineage extractor. The dialect tells aggregates
+// from other functions and the schema expands * and places unqualified
+// columns; either may be nil.
+func NewExtractor(d dialect.Dialect, s *schema.Schema) *Extractor {
+	e := &Extractor{dialect: d}
+	if s != nil {
+		e.resolver = schema.NewResolver(d, s)
+	}
+	return e
+}
+
+// NewExtractorWithResolver creates a lineage extractor that resolves
+// tables across all of the resolver's schemas
+func NewExtractorWithResolver(d dialect.Dialect, resolver *schema.Resolver) *Extractor {
+	return &Extractor{dialect: d, resolver: resolver}
+}
+
+// Extract returns the column lineage of a SELECT, INSERT, UPDATE, MERGE,
+// CREATE VIEW or CREATE TABLE ... AS SELECT statement
+func (e *Extractor) Extract(stmt parser.Statement) (*Lineage, error) {
+	x := &extraction{
+		Extractor: e,
+		lineage:   &Lineage{Inputs: []string{}, Columns: []*ColumnLineage{}},
+		ctes:      make(map[string]*relation),
+		seen:      make(map[string]bool),
+	}
+	if err := x.statement(stmt); err != nil {
+		return nil, err
+	}
+	for _, col := range x.lineage.Columns {
+		col.finish()
+	}
+	return x.lineage, nil
+}
+
+// extraction is the state of one Extract call
+type extraction struct {
+	*Extractor
+	lineage *Lineage
+	ctes    map[string]*relation // CTEs in scope, by lower-cased name
+	seen    map[string]bool      // inputs, filters and warnings already recorded
+}
+
+func (x *extraction) statement(stmt parser.Statement) error {
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		if s.Into != nil {
+			x.lineage.Target = s.Into.String()
+		}
+		x.lineage.Columns = x.selectColumns(s, nil)
+	case *parser.SetOperation:
+		x.lineage.Columns = x.queryColumns(s, nil)
+	case *parser.WithStatement:
+		defer x.withCTEs(s)()
+		return x.statement(s.Query)
+	case *parser.InsertStatement:
+		x.insertColumns(s)
+	case *parser.UpdateStatement:
+		x.updateColumns(s)
+	case *parser.MergeStatement:
+		x.mergeColumns(s)
+	case *parser.CreateViewStatement:
+		x.lineage.Target = s.ViewName.String()
+		x.lineage.Columns = rename(x.selectColumns(s.SelectStmt, nil), s.Columns)
+	case *parser.CreateTableStatement:
+		if s.Query == nil {
+			return fmt.Errorf("column lineage needs CREATE TABLE ... AS SELECT, got CREATE TABLE %s", s.Table.String())
+		}
+		names := make([]string, len(s.Columns))
+		for i, col := range s.Columns {
+			names[i] = col.Name
+		}
+		x.lineage.Target = s.Table.String()
+		x.lineage.Columns = rename(x.queryColumns(s.Query, nil), names)
+	default:
+		return fmt.Errorf("column lineage is not supported for %s", stmt.Type())
+	}
+	return nil
+}
+
+// withCTEs brings a WITH statement's CTEs into scope and returns the
+// function restoring the previous ones
+func (x *extraction) withCTEs(ws *parser.WithStatement) func() {
+	previous := x.ctes
+	x.ctes = make(map[string]*relation, len(previous)+len(ws.CTEs))
+	for name, rel := range previous {
+		x.ctes[name] = rel
+	}
+
+	for _, cte := range ws.CTEs {
+		key := strings.ToLower(cte.Name)
+		// A recursive CTE refers to itself; its anchor member gives the
+		// columns the recursive member sees
+		if setOp, ok := cte.Query.(*parser.SetOperation); ok && ws.Recursive {
+			anchor := x.queryColumns(leftmost(setOp), nil)
+			x.ctes[key] = &relation{name: cte.Name, columns: rename(anchor, cte.Columns)}
+		}
+		x.ctes[key] = &relation{name: cte.Name, columns: rename(x.queryColumns(cte.Query, nil), cte.Columns)}
+	}
+
+	return func() { x.ctes = previous }
+}
+
+// queryColumns returns the output columns of a query
+func (x *extraction) queryColumns(stmt parser.Statement, parent *scope) []*ColumnLineage {
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		return x.selectColumns(s, parent)
+	case *parser.SetOperation:
+		// Columns line up by position and take the names of the left side
+		left := x.queryColumns(s.Left, parent)
+		right := x.queryColumns(s.Right, parent)
+		for i, col := range left {
+			if i < len(right) {
+				col.merge(right[i], true)
+			}
+		}
+		return left
+	case *parser.WithStatement:
+		defer x.withCTEs(s)()
+		return x.queryColumns(s.Query, parent)
+	}
+	x.warn(fmt.Sprintf("lineage of %s is not traced", stmt.Type()))
+	return nil
+}
+
+// selectColumns returns the output columns of a SELECT and records its
+// filter dependencies
+func (x *extraction) selectColumns(stmt *parser.SelectStatement, parent *scope) []*ColumnLineage {
+	sc := &scope{parent: parent}
+	if stmt.From != nil {
+		for i := range stmt.From.Tables {
+			sc.relations = append(sc.relations, x.relation(&stmt.From.Tables[i], sc))
+		}
+	}
+	for _, join := range stmt.Joins {
+		previous := &scope{relations: sc.relations, parent: parent}
+		rel := x.relation(&join.Table, sc)
+		sc.relations = append(sc.relations, rel)
+
+		x.depend(join.Condition, sc, "JOIN")
+		for _, name := range join.Using {
+			ref := &parser.ColumnReference{Column: name}
+			x.dependOn(x.resolve(ref, previous), "JOIN")
+			x.dependOn(rel.column(x, name), "JOIN")
+		}
+	}
+
+	x.depend(stmt.Where, sc, "WHERE")
+	for _, expr := range stmt.GroupBy {
+		x.depend(expr, sc, "GROUP BY")
+	}
+	x.depend(stmt.Having, sc, "HAVING")
+	x.depend(stmt.Qualify, sc, "QUALIFY")
+
+	columns := make([]*ColumnLineage, 0, len(stmt.Columns))
+	for _, expr := range stmt.Columns {
+		if star, ok := expr.(*parser.StarExpression); ok {
+			columns = append(columns, x.expandStar(star, sc)...)
+			continue
+		}
+		col := x.derive(expr, sc)
+		col.Name = outputName(expr)
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// insertColumns traces INSERT ... SELECT and INSERT ... VALUES
+func (x *extraction) insertColumns(stmt *parser.InsertStatement) {
+	x.lineage.Target = stmt.Table.String()
+
+	var values []*ColumnLineage
+	if stmt.Select != nil {
+		values = x.queryColumns(stmt.Select, nil)
+	} else {
+		for _, row := range stmt.Values {
+			for i, expr := range row {
+				col := x.derive(expr, &scope{})
+				if i < len(values) {
+					values[i].merge(col, true)
+				} else {
+					col.Name = outputName(expr)
+					values = append(values, col)
+				}
+			}
+		}
+	}
+
+	names := stmt.Columns
+	if len(names) == 0 {
+		names = x.tableColumns(&stmt.Table)
+	}
+	if len(names) > 0 && len(names) != len(values) {
+		x.warn(fmt.Sprintf("INSERT into %s lists %d columns but %d values", x.lineage.Target, len(names), len(values)))
+	}
+	x.lineage.Columns = rename(values, names)
+}
+
+// updateColumns traces the SET assignments of an UPDATE
+func (x *extraction) updateColumns(stmt *parser.UpdateStatement) {
+	x.lineage.Target = stmt.Table.String()
+
+	sc := &scope{relations: []*relation{x.target(&stmt.Table)}}
+	x.depend(stmt.Where, sc, "WHERE")
+	for _, assignment := range stmt.Set {
+		col := x.derive(assignment.Value, sc)
+		col.Name = unqualified(assignment.Column)
+		x.lineage.Columns = append(x.lineage.Columns, col)
+	}
+}
+
+// mergeColumns traces the UPDATE and INSERT actions of a MERGE
+func (x *extraction) mergeColumns(stmt *parser.MergeStatement) {
+	x.lineage.Target = stmt.TargetTable.String()
+
+	sc := &scope{relations: []*relation{x.target(&stmt.TargetTable)}}
+	switch source := stmt.SourceTable.(type) {
+	case parser.TableReference:
+		if stmt.SourceAlias != "" {
+			source.Alias = stmt.SourceAlias
+		}
+		sc.relations = append(sc.relations, x.relation(&source, sc))
+	case *parser.SelectStatement:
+		sc.relations = append(sc.relations, &relation{alias: stmt.SourceAlias, columns: x.selectColumns(source, nil)})
+	}
+	x.depend(stmt.OnCondition, sc, "JOIN")
+
+	byName := make(map[string]*ColumnLineage)
+	clauses := append(append(append([]*parser.MergeWhenClause{}, stmt.WhenMatched...), stmt.WhenNotMatched...), stmt.WhenNotMatchedBy...)
+	for _, clause := range clauses {
+		x.depend(clause.Condition, sc, "WHEN")
+		if clause.Action == nil {
+			continue
+		}
+
+		names := clause.Action.Columns
+		if len(names) == 0 && clause.Action.ActionType == "INSERT" {
+			names = x.tableColumns(&stmt.TargetTable)
+		}
+		for i, value := range clause.Action.Values {
+			if i >= len(names) {
+				x.warn(fmt.Sprintf("MERGE INSERT into %s has more values than columns", x.lineage.Target))
+				break
+			}
+			name := unqualified(names[i])
+			col := x.derive(value, sc)
+			if existing, ok := byName[strings.ToLower(name)]; ok {
+				existing.merge(col, true)
+				continue
+			}
+			col.Name = name
+			byName[strings.ToLower(name)] = col
+			x.lineage.Columns = append(x.lineage.Columns, col)
+		}
+	}
+}
+
+// tableColumns returns the column names of a table in the schema, in order
+func (x *extraction) tableColumns(ref *parser.TableReference) []string {
+	if x.resolver == nil {
+		return nil
+	}
+	table, ok := x.resolver.ResolveTable(ref)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, col := range table.OrderedColumns() {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+func (x *extraction) addInput(name string) {
+	if key := "input:" + strings.ToLower(name); !x.seen[key] {
+		x.seen[key] = true
+		x.lineage.Inputs = append(x.lineage.Inputs, name)
+	}
+}
+
+func (x *extraction) warn(message string) {
+	if key := "warning:" + message; !x.seen[key] {
+		x.seen[key] = true
+		x.lineage.Warnings = append(x.lineage.Warnings, message)
+	}
+}
+
+// depend records the columns expr reads as dependencies of clause
+func (x *extraction) depend(expr parser.Expression, sc *scope, clause string) {
+	if expr != nil {
+		x.dependOn(x.derive(expr, sc), clause)
+	}
+}
+
+func (x *extraction) dependOn(col *ColumnLineage, clause string) {
+	for _, source := range col.Sources {
+		key := "filter:" + clause + ":" + strings.ToLower(source.String())
+		if !x.seen[key] {
+			x.seen[key] = true
+			x.lineage.Filters = append(x.lineage.Filters, Dependency{Column: source, Clause: clause})
+		}
+	}
+}
+
+// outputName returns the name of a select list or VALUES expression
+func outputName(expr parser.Expression) string {
+	switch e := expr.(type) {
+	case *parser.AliasedExpression:
+		return e.Alias
+	case *parser.ColumnReference:
+		return e.Column
+	case *parser.VariableAssignment:
+		return e.Variable
+	}
+	return expr.String()
+}
+
+// rename gives columns the names of an explicit column list, by position
+func rename(columns []*ColumnLineage, names []string) []*ColumnLineage {
+	for i, col := range columns {
+		if i < len(names) {
+			col.Name = names[i]
+		}
+	}
+	return columns
+}
+
+// leftmost returns the first query of a chain of set operations
+func leftmost(stmt parser.Statement) parser.Statement {
+	for {
+		setOp, ok := stmt.(*parser.SetOperation)
+		if !ok {
+			return stmt
+		}
+		stmt = setOp.Left
+	}
+}
+
+// unqualified strips the table from a t.column name
+func unqualified(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// merge adds other's sources and transformations to c. A column taken
+// as-is into an expression is not itself a direct copy, so DIRECT is only
+// kept when keepDirect is set (set operations, repeated assignments).
+func (c *ColumnLineage) merge(other *ColumnLineage, keepDirect bool) {
+	for _, source := range other.Sources {
+		c.addSource(source)
+	}
+	for _, t := range other.Transformations {
+		if t != TransformDirect || keepDirect {
+			c.addTransformation(t)
+		}
+	}
+}
+
+func (c *ColumnLineage) addSource(source Column) {
+	for _, existing := range c.Sources {
+		if strings.EqualFold(existing.Table, source.Table) && strings.EqualFold(existing.Column, source.Column) {
+			return
+		}
+	}
+	c.Sources = append(c.Sources, source)
+}
+
+func (c *ColumnLineage) addTransformation(t Transformation) {
+	for _, existing := range c.Transformations {
+		if existing == t {
+			return
+		}
+	}
+	c.Transformations = append(c.Transformations, t)
+}
+
+// finish puts the transformations in their reporting order. Constants
+// have no sources and count as expressions.
+func (c *ColumnLineage) finish() {
+	if len(c.Transformations) == 0 {
+		c.Transformations = []Transformation{TransformExpression}
+	}
+	ordered := make([]Transformation, 0, len(c.Transformations))
+	for _, t := range transformOrder {
+		for _, existing := range c.Transformations {
+			if existing == t {
+				ordered = append(ordered, t)
+			}
+		}
+	}
+	c.Transformations = ordered
+	if c.Sources == nil {
+		c.Sources = []Column{}
+	}
+}
+
+// clone returns a copy of c that can be merged into without changing c
+func (c *ColumnLineage) clone() *ColumnLineage {
+	return &ColumnLineage{
+		Name:            c.Name,
+		Sources:         append([]Column(nil), c.Sources...),
+		Transformations: append([]Transformation(nil), c.Transformations...),
+	}
+}
@@ -0,0 +1,122 @@
+package lineage
+
+const (
+	openLineageProducer = "https://github.com/Chahine-tech/sql-parser-go"
+	columnLineageSchema = "https://openlineage.io/spec/facets/1-2-0/ColumnLineageDatasetFacet.json#/$defs/ColumnLineageDatasetFacet"
+	openLineageDirect   = "DIRECT"
+	openLineageIndirect = "INDIRECT"
+)
+
+// ColumnLineageFacet is an OpenLineage column lineage dataset facet
+type ColumnLineageFacet struct {
+	Producer  string                  `json:"_producer"`
+	SchemaURL string                  `json:"_schemaURL"`
+	Fields    map[string]FieldLineage `json:"fields"`
+	Dataset   []InputField            `json:"dataset,omitempty"` // dependencies of every field
+}
+
+// FieldLineage lists the input fields of one output field
+type FieldLineage struct {
+	InputFields []InputField `json:"inputFields"`
+}
+
+// InputField is a source column with how it is used
+type InputField struct {
+	Namespace       string                `json:"namespace"`
+	Name            string                `json:"name"` // dataset (table) name
+	Field           string                `json:"field"`
+	Transformations []FacetTransformation `json:"transformations,omitempty"`
+}
+
+// FacetTransformation is an OpenLineage transformation type and subtype
+type FacetTransformation struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype,omitempty"`
+	Description string `json:"description,omitempty"`
+	Masking     bool   `json:"masking"`
+}
+
+// facetTransformations maps transformations to OpenLineage's
+var facetTransformations = map[Transformation]FacetTransformation{
+	TransformDirect:     {Type: openLineageDirect, Subtype: "IDENTITY"},
+	TransformExpression: {Type: openLineageDirect, Subtype: "TRANSFORMATION"},
+	TransformCase:       {Type: openLineageDirect, Subtype: "TRANSFORMATION", Description: "CASE"},
+	TransformAggregate:  {Type: openLineageDirect, Subtype: "AGGREGATION"},
+	TransformWindow:     {Type: openLineageIndirect, Subtype: "WINDOW"},
+}
+
+// clauseSubtypes maps dependency clauses to OpenLineage indirect subtypes
+var clauseSubtypes = map[string]string{
+	"JOIN":     "JOIN",
+	"WHERE":    "FILTER",
+	"HAVING":   "FILTER",
+	"QUALIFY":  "FILTER",
+	"GROUP BY": "GROUP_BY",
+	"WINDOW":   "WINDOW",
+	"WHEN":     "CONDITIONAL",
+}
+
+// OpenLineage returns the lineage as an OpenLineage column lineage facet.
+// namespace names the data source the tables belong to. Sources that
+// could not be traced to a table are left out.
+func (l *Lineage) OpenLineage(namespace string) *ColumnLineageFacet {
+	facet := &ColumnLineageFacet{
+		Producer:  openLineageProducer,
+		SchemaURL: columnLineageSchema,
+		Fields:    make(map[string]FieldLineage, len(l.Columns)),
+	}
+
+	for _, col := range l.Columns {
+		var transformations []FacetTransformation
+		for _, t := range col.Transformations {
+			transformations = append(transformations, facetTransformations[t])
+		}
+
+		field := FieldLineage{InputFields: []InputField{}}
+		for _, source := range col.Sources {
+			if source.Table == "" {
+				continue
+			}
+			field.InputFields = append(field.InputFields, InputField{
+				Namespace:       namespace,
+				Name:            source.Table,
+				Field:           source.Column,
+				Transformations: transformations,
+			})
+		}
+		facet.Fields[col.Name] = field
+	}
+
+	// One dataset entry per column, listing every clause it is used in
+	index := make(map[Column]int)
+	for _, dep := range l.Filters {
+		if dep.Table == "" {
+			continue
+		}
+		transformation := FacetTransformation{Type: openLineageIndirect, Subtype: clauseSubtypes[dep.Clause]}
+		if i, ok := index[dep.Column]; ok {
+			if !hasTransformation(facet.Dataset[i].Transformations, transformation) {
+				facet.Dataset[i].Transformations = append(facet.Dataset[i].Transformations, transformation)
+			}
+			continue
+		}
+		index[dep.Column] = len(facet.Dataset)
+		facet.Dataset = append(facet.Dataset, InputField{
+			Namespace:       namespace,
+			Name:            dep.Table,
+			Field:           dep.Column.Column,
+			Transformations: []FacetTransformation{transformation},
+		})
+	}
+
+	return facet
+}
+
+func hasTransformation(list []FacetTransformation, t FacetTransformation) bool {
+	for _, existing := range list {
+		if existing == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+package lineage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// relation is a table, CTE, derived table or table-valued function in a
+// FROM clause
+type relation struct {
+	name    string // table or CTE name as written
+	alias   string
+	columns []*ColumnLineage // output columns of a CTE or derived table; nil for tables
+	table   *schema.Table    // schema definition of a table, when known
+	call    *ColumnLineage   // lineage of a table-valued function's arguments
+}
+
+// derived reports whether the relation is a CTE or derived table
+func (r *relation) derived() bool {
+	return r.columns != nil
+}
+
+// matches reports whether a column qualifier refers to the relation
+func (r *relation) matches(qualifier string) bool {
+	if r.alias != "" {
+		return strings.EqualFold(r.alias, qualifier)
+	}
+	return strings.EqualFold(unqualified(r.name), qualifier) || strings.EqualFold(r.name, qualifier)
+}
+
+// has reports whether the relation is known to have a column
+func (r *relation) has(name string) bool {
+	if r.derived() {
+		for _, col := range r.columns {
+			if strings.EqualFold(col.Name, name) {
+				return true
+			}
+		}
+		return false
+	}
+	return r.table != nil && r.table.HasColumn(name)
+}
+
+// column returns the lineage of one of the relation's columns
+func (r *relation) column(x *extraction, name string) *ColumnLineage {
+	if r.call != nil {
+		// Every column of a table-valued function derives from its arguments
+		return r.call.clone()
+	}
+	if !r.derived() {
+		if r.table != nil {
+			if col, ok := r.table.GetColumn(name); ok {
+				name = col.Name
+			} else {
+				x.warn(fmt.Sprintf("column %s not found in %s", name, r.name))
+			}
+		}
+		x.addInput(r.name)
+		return &ColumnLineage{Sources: []Column{{Table: r.name, Column: name}}, Transformations: []Transformation{TransformDirect}}
+	}
+
+	for _, col := range r.columns {
+		if strings.EqualFold(col.Name, name) {
+			return col.clone()
+		}
+	}
+	// An unexpanded * of the relation's own source passes the column through
+	for _, col := range r.columns {
+		if col.Name == "*" && len(col.Sources) == 1 {
+			return &ColumnLineage{Sources: []Column{{Table: col.Sources[0].Table, Column: name}}, Transformations: col.Transformations}
+		}
+	}
+	x.warn(fmt.Sprintf("column %s not found in %s", name, r.label()))
+	return &ColumnLineage{}
+}
+
+// label names the relation in warnings
+func (r *relation) label() string {
+	if r.alias != "" {
+		return r.alias
+	}
+	return r.name
+}
+
+// scope is the relations visible to a query's expressions
+type scope struct {
+	relations []*relation
+	parent    *scope // enclosing query, for correlated subqueries
+}
+
+// relation returns the relation a FROM or JOIN table reference reads
+func (x *extraction) relation(ref *parser.TableReference, sc *scope) *relation {
+	if ref.Subquery != nil {
+		// Derived tables see the enclosing query's scope, not their siblings
+		return &relation{alias: ref.Alias, columns: x.selectColumns(ref.Subquery, sc.parent)}
+	}
+	if ref.Function != nil {
+		return &relation{name: ref.String(), alias: ref.Alias, call: x.derive(ref.Function, sc)}
+	}
+	if ref.Schema == "" && ref.Catalog == "" {
+		if cte, ok := x.ctes[strings.ToLower(ref.Name)]; ok {
+			return &relation{name: cte.name, alias: ref.Alias, columns: cte.columns}
+		}
+	}
+
+	rel := x.target(ref)
+	x.addInput(rel.name)
+	return rel
+}
+
+// target returns the relation of a table, such as a DML statement's target
+func (x *extraction) target(ref *parser.TableReference) *relation {
+	rel := &relation{name: ref.String(), alias: ref.Alias}
+	if x.resolver != nil {
+		if table, ok := x.resolver.ResolveTable(ref); ok {
+			rel.table = table
+		}
+	}
+	return rel
+}
+
+// resolve returns the lineage of a column reference
+func (x *extraction) resolve(ref *parser.ColumnReference, sc *scope) *ColumnLineage {
+	for s := sc; s != nil; s = s.parent {
+		if col := x.lookup(ref, s); col != nil {
+			return col
+		}
+	}
+
+	if ref.Table != "" {
+		// A qualifier that is not in scope names the table itself
+		name := strings.TrimSuffix(ref.String(), "."+ref.Column)
+		x.addInput(name)
+		return &ColumnLineage{Sources: []Column{{Table: name, Column: ref.Column}}, Transformations: []Transformation{TransformDirect}}
+	}
+	x.warn(fmt.Sprintf("column %s could not be traced to a table", ref.Column))
+	return &ColumnLineage{Sources: []Column{{Column: ref.Column}}, Transformations: []Transformation{TransformDirect}}
+}
+
+// lookup finds a column reference among one scope's relations, or
+// returns nil
+func (x *extraction) lookup(ref *parser.ColumnReference, sc *scope) *ColumnLineage {
+	if ref.Table != "" {
+		for _, rel := range sc.relations {
+			if rel.matches(ref.Table) {
+				return rel.column(x, ref.Column)
+			}
+		}
+		return nil
+	}
+
+	var unknown []*relation
+	for _, rel := range sc.relations {
+		if rel.has(ref.Column) {
+			return rel.column(x, ref.Column)
+		}
+		if !rel.derived() && rel.table == nil {
+			unknown = append(unknown, rel)
+		}
+	}
+	switch len(unknown) {
+	case 0:
+		return nil
+	case 1:
+		return unknown[0].column(x, ref.Column)
+	}
+
+	x.warn(fmt.Sprintf("column %s is ambiguous without a schema", ref.Column))
+	col := &ColumnLineage{Transformations: []Transformation{TransformDirect}}
+	for _, rel := range unknown {
+		col.merge(rel.column(x, ref.Column), true)
+	}
+	return col
+}
+
+// expandStar returns the columns * or t.* stands for. Tables missing from
+// the schema can't be expanded and give a single * column.
+func (x *extraction) expandStar(star *parser.StarExpression, sc *scope) []*ColumnLineage {
+	var columns []*ColumnLineage
+	for _, rel := range sc.relations {
+		if star.Table != "" && !rel.matches(star.Table) {
+			continue
+		}
+		switch {
+		case rel.derived():
+			for _, col := range rel.columns {
+				columns = append(columns, col.clone())
+			}
+		case rel.table != nil:
+			for _, col := range rel.table.OrderedColumns() {
+				columns = append(columns, &ColumnLineage{
+					Name:            col.Name,
+					Sources:         []Column{{Table: rel.name, Column: col.Name}},
+					Transformations: []Transformation{TransformDirect},
+				})
+			}
+		default:
+			x.warn(fmt.Sprintf("%s.* can't be expanded without a schema", rel.label()))
+			columns = append(columns, &ColumnLineage{
+				Name:            "*",
+				Sources:         []Column{{Table: rel.name, Column: "*"}},
+				Transformations: []Transformation{TransformDirect},
+			})
+		}
+	}
+	return columns
+}
+
+// aggregateFunctions are recognized when the dialect's catalog doesn't
+// list them
+var aggregateFunctions = map[string]bool{
+	"COUNT": true, "SUM": true, "AVG": true, "MIN": true, "MAX": true,
+	"STRING_AGG": true, "GROUP_CONCAT": true, "ARRAY_AGG": true, "LISTAGG": true,
+	"JSON_AGG": true, "JSONB_AGG": true, "BOOL_AND": true, "BOOL_OR": true,
+	"STDDEV": true, "STDEV": true, "VARIANCE": true, "VAR": true,
+	"PERCENTILE_CONT": true, "PERCENTILE_DISC": true,
+}
+
+// isAggregate reports whether a function call aggregates rows
+func (x *extraction) isAggregate(fn *parser.FunctionCall) bool {
+	if x.dialect != nil {
+		if sig, ok := x.dialect.LookupFunction(fn.Name); ok && sig.Kind == dialect.FunctionAggregate {
+			return true
+		}
+	}
+	return aggregateFunctions[strings.ToUpper(fn.Name)] || fn.WithinGroup != nil
+}
+
+// derive returns the sources and transformations of an expression
+func (x *extraction) derive(expr parser.Expression, sc *scope) *ColumnLineage {
+	switch e := expr.(type) {
+	case *parser.ColumnReference:
+		return x.resolve(e, sc)
+
+	case *parser.AliasedExpression:
+		return x.derive(e.Expression, sc)
+
+	case *parser.Literal, *parser.Parameter, *parser.StarExpression:
+		return &ColumnLineage{}
+
+	case *parser.VariableAssignment:
+		return x.derive(e.Value, sc)
+
+	case *parser.FunctionCall:
+		kind := TransformExpression
+		if x.isAggregate(e) {
+			kind = TransformAggregate
+		}
+		col := &ColumnLineage{Transformations: []Transformation{kind}}
+		for _, arg := range e.Arguments {
+			col.merge(x.derive(arg, sc), false)
+		}
+		for _, ob := range append(append([]*parser.OrderByClause{}, e.OrderBy...), e.WithinGroup...) {
+			col.merge(x.derive(ob.Expression, sc), false)
+		}
+		x.depend(e.Filter, sc, "WHERE")
+		return col
+
+	case *parser.WindowFunction:
+		col := &ColumnLineage{Transformations: []Transformation{TransformWindow}}
+		for _, arg := range e.Function.Arguments {
+			col.merge(x.derive(arg, sc), false)
+		}
+		if e.OverClause != nil {
+			for _, expr := range e.OverClause.PartitionBy {
+				x.depend(expr, sc, "WINDOW")
+			}
+			for _, ob := range e.OverClause.OrderBy {
+				x.depend(ob.Expression, sc, "WINDOW")
+			}
+		}
+		return col
+
+	case *parser.CaseExpression:
+		// Conditions only add their sources; results add how they are computed
+		col := &ColumnLineage{Transformations: []Transformation{TransformCase}}
+		conditions := []parser.Expression{e.Input}
+		results := []parser.Expression{e.ElseResult}
+		for _, when := range e.WhenClauses {
+			conditions = append(conditions, when.Condition)
+			results = append(results, when.Result)
+		}
+		for _, condition := range conditions {
+			if condition != nil {
+				for _, source := range x.derive(condition, sc).Sources {
+					col.addSource(source)
+				}
+			}
+		}
+		for _, result := range results {
+			if result != nil {
+				col.merge(x.derive(result, sc), false)
+			}
+		}
+		return col
+
+	case *parser.SubqueryExpression:
+		// A scalar subquery passes on its single column
+		col := &ColumnLineage{}
+		if columns := x.selectColumns(e.Query, sc); len(columns) > 0 {
+			col.merge(columns[0], true)
+		}
+		return col
+
+	case *parser.ExistsExpression:
+		col := &ColumnLineage{Transformations: []Transformation{TransformExpression}}
+		for _, c := range x.queryColumns(e.Subquery, sc) {
+			col.merge(c, false)
+		}
+		return col
+	}
+
+	// Operators, casts and other scalar expressions
+	col := &ColumnLineage{Transformations: []Transformation{TransformExpression}}
+	for _, child := range parser.Operands(expr) {
+		if child != nil {
+			col.merge(x.derive(child, sc), false)
+		}
+	}
+	return col
+}
@@ -113,6 +113,11 @@ func (p *SQLServerLogParser) parseProfilerLine(line string) *LogEntry {
 		}
 	}
 
+	// The counters follow the query text
+	if loc := p.patterns["perf_counter"].FindStringIndex(content); loc != nil {
+		content = content[:loc[0]]
+	}
+
 	// Extract SQL query
 	if sqlIndex := strings.Index(content, "SELECT"); sqlIndex != -1 {
 		entry.Query = strings.TrimSpace(content[sqlIndex:])
@@ -19,7 +19,7 @@ func (p *LogProcessor) extractQueryFromLine(line string) string {
 		// SQL Server
 		`^.*exec\s+(.+)$`,
 		// Generic SQL (if line starts with SELECT, INSERT, UPDATE, DELETE, etc.)
-		`^(SELECT|INSERT|UPDATE|DELETE|CREATE|DROP|ALTER|MERGE|WITH)\s+.+$`,
+		`^((?:SELECT|INSERT|UPDATE|DELETE|CREATE|DROP|ALTER|MERGE|WITH)\s+.+)$`,
 	}
 
 	for _, pattern := range patterns {
@@ -15,8 +15,9 @@ import (
 // LogProcessor processes log lines in real-time
 type LogProcessor struct {
 	dialectName  string
-	dialect      dialect.Dialect
+	dialect      dialect.Dialect // nil to detect the dialect of each query
 	queryHandler func(*ProcessedQuery)
+	cache        *analyzer.AnalysisCache // nil analyzes every query
 	stats        *Statistics
 	mu           sync.RWMutex
 }
@@ -37,15 +38,20 @@ type ProcessedQuery struct {
 	// Log metadata
 	LogFormat string
 	Severity  string
+	Dialect   string // dialect the query was parsed with
 }
 
-// NewLogProcessor creates a new log processor
+// NewLogProcessor creates a new log processor. With an empty dialect name
+// or "auto", the dialect of each query is detected from its text.
 func NewLogProcessor(dialectName string) *LogProcessor {
-	return &LogProcessor{
+	p := &LogProcessor{
 		dialectName: dialectName,
-		dialect:     dialect.GetDialect(dialectName),
 		stats:       NewStatistics(),
 	}
+	if dialectName != "" && dialectName != dialect.Auto {
+		p.dialect = dialect.GetDialect(dialectName)
+	}
+	return p
 }
 
 // SetQueryHandler sets the callback for processed queries
@@ -55,6 +61,14 @@ func (p *LogProcessor) SetQueryHandler(handler func(*ProcessedQuery)) {
 	p.queryHandler = handler
 }
 
+// SetCache sets the cache of analyses, which repeated queries hit; it may
+// be shared with a ConcurrentAnalyzer
+func (p *LogProcessor) SetCache(cache *analyzer.AnalysisCache) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache = cache
+}
+
 // Start begins processing log lines from the channel
 func (p *LogProcessor) Start(ctx context.Context, lines <-chan string) {
 	for {
@@ -97,9 +111,15 @@ func (p *LogProcessor) processLine(line string) {
 		Severity:     "INFO",
 	}
 
+	d := p.dialect
+	if d == nil {
+		d, _ = dialect.DetectDialect(query)
+	}
+	pq.Dialect = d.Name()
+
 	// Parse the SQL query
 	ctx := context.Background()
-	sqlParser := parser.NewWithDialect(ctx, query, p.dialect)
+	sqlParser := parser.NewWithDialect(ctx, query, d)
 	stmt, err := sqlParser.ParseStatement()
 	if err != nil {
 		// Failed to parse, but still record it
@@ -112,8 +132,18 @@ func (p *LogProcessor) processLine(line string) {
 
 	// Analyze the query if parsing succeeded
 	if stmt != nil && err == nil {
-		a := analyzer.NewWithDialect(p.dialect)
-		analysis := a.Analyze(stmt)
+		a := analyzer.NewWithDialect(d)
+		p.mu.RLock()
+		cache := p.cache
+		p.mu.RUnlock()
+
+		var analysis analyzer.QueryAnalysis
+		if cache != nil {
+			a.SetCache(cache)
+			analysis = a.AnalyzeWithCache(stmt, analyzer.QueryFingerprint(query, d))
+		} else {
+			analysis = a.Analyze(stmt)
+		}
 		pq.Analysis = &analysis
 	}
 
@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
 	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
 )
 
@@ -14,10 +15,16 @@ func (p *Parser) parseWithStatement() (*WithStatement, error) {
 	if !p.curTokenIs(lexer.WITH) {
 		return nil, fmt.Errorf("expected WITH, got %s", p.curToken.Literal)
 	}
+	if err := p.requireFeature(dialect.FeatureCTE, "WITH"); err != nil {
+		return nil, err
+	}
 	p.nextToken()
 
 	// Check for RECURSIVE keyword
 	if p.curTokenIs(lexer.RECURSIVE) {
+		if err := p.requireFeature(dialect.FeatureRecursiveCTE, "WITH RECURSIVE"); err != nil {
+			return nil, err
+		}
 		stmt.Recursive = true
 		p.nextToken()
 	}
@@ -190,18 +197,76 @@ func (p *Parser) parseWindowFunction(funcCall *FunctionCall) (*WindowFunction, e
 }
 
 // parseOverClause parses the OVER clause of a window function
-// Syntax: OVER (PARTITION BY ... ORDER BY ... frame_clause)
+// Syntax: OVER (PARTITION BY ... ORDER BY ... frame_clause) or OVER window_name
 func (p *Parser) parseOverClause() (*OverClause, error) {
-	oc := &OverClause{}
-
+	if err := p.requireFeature(dialect.FeatureWindowFunctions, "OVER"); err != nil {
+		return nil, err
+	}
 	p.nextToken() // move past OVER
 
+	// Reference to a window from the WINDOW clause
+	if p.curTokenIs(lexer.IDENT) {
+		oc := &OverClause{WindowName: p.curToken.Literal}
+		p.nextToken()
+		return oc, nil
+	}
+
+	return p.parseWindowSpec()
+}
+
+// parseWindowClause parses WINDOW name AS (spec) [, name AS (spec) ...]
+func (p *Parser) parseWindowClause() ([]*WindowDefinition, error) {
+	if err := p.requireFeature(dialect.FeatureNamedWindows, "WINDOW"); err != nil {
+		return nil, err
+	}
+	p.nextToken() // move past WINDOW
+
+	var windows []*WindowDefinition
+	for {
+		if !p.curTokenIs(lexer.IDENT) {
+			return nil, fmt.Errorf("expected window name, got %s", p.curToken.Literal)
+		}
+		def := &WindowDefinition{Name: p.curToken.Literal}
+		p.nextToken()
+
+		if !p.curTokenIs(lexer.AS) {
+			return nil, fmt.Errorf("expected AS after window name, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+
+		spec, err := p.parseWindowSpec()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse window %s: %w", def.Name, err)
+		}
+		def.Spec = spec
+		windows = append(windows, def)
+
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	return windows, nil
+}
+
+// parseWindowSpec parses a parenthesized window specification
+// Syntax: ([existing_window] [PARTITION BY ...] [ORDER BY ...] [frame_clause])
+func (p *Parser) parseWindowSpec() (*OverClause, error) {
+	oc := &OverClause{}
+
 	// Expect opening parenthesis
 	if !p.curTokenIs(lexer.LPAREN) {
 		return nil, fmt.Errorf("expected '(' after OVER, got %s", p.curToken.Literal)
 	}
 	p.nextToken()
 
+	// Optional: name of a window being refined
+	if p.curTokenIs(lexer.IDENT) {
+		oc.WindowName = p.curToken.Literal
+		p.nextToken()
+	}
+
 	// Optional: PARTITION BY clause
 	if p.curTokenIs(lexer.PARTITION) {
 		p.nextToken()
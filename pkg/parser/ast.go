@@ -3,6 +3,9 @@ package parser
 import (
 	"fmt"
 	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
 )
 
 type Node interface {
@@ -34,16 +37,22 @@ func (bn *BaseNode) Type() string {
 // SELECT Statement
 type SelectStatement struct {
 	BaseNode
-	Distinct bool
-	Top      *TopClause
-	Columns  []Expression
-	From     *FromClause
-	Joins    []*JoinClause
-	Where    Expression
-	GroupBy  []Expression
-	Having   Expression
-	OrderBy  []*OrderByClause
-	Limit    *LimitClause
+	Distinct   bool
+	DistinctOn []Expression // PostgreSQL DISTINCT ON (...)
+	Modifiers  []string     // MySQL SQL_NO_CACHE, STRAIGHT_JOIN, HIGH_PRIORITY, ...
+	Columns    []Expression
+	From       *FromClause
+	Joins      []*JoinClause
+	Where      Expression
+	GroupBy    []Expression // may contain *GroupingSetsExpression
+	WithRollup bool         // MySQL GROUP BY ... WITH ROLLUP
+	Having     Expression
+	Windows    []*WindowDefinition // WINDOW w AS (...)
+	Qualify    Expression
+	OrderBy    []*OrderByClause
+	Limit      *RowLimit       // LIMIT, TOP, OFFSET/FETCH or ROWNUM
+	Hints      []*Hint         // /*+ ... */ optimizer hints and SQL Server OPTION (...)
+	Into       *TableReference // SELECT ... INTO new_table (SQL Server) or a variable
 }
 
 func (ss *SelectStatement) statementNode() {}
@@ -64,57 +73,148 @@ func (fc *FromClause) String() string { return "FROM Clause" }
 // Table Reference
 type TableReference struct {
 	BaseNode
+	Server   string // Linked server (SQL Server four-part names)
+	Catalog  string // Database/catalog (db.schema.table, project.dataset.table)
 	Schema   string
 	Name     string
 	Alias    string
 	Subquery *SelectStatement // For derived tables: (SELECT ...) AS alias
+	Function *FunctionCall    // For table-valued functions: STRING_SPLIT(s, ',') AS alias
+	Hints    []*Hint          // WITH (NOLOCK), USE INDEX (idx)
 }
 
 func (tr *TableReference) expressionNode() {}
 func (tr *TableReference) Type() string    { return "TableReference" }
 func (tr *TableReference) String() string {
+	if tr.Function != nil {
+		return tr.Function.String()
+	}
+	if tr.Server != "" {
+		return fmt.Sprintf("%s.%s.%s.%s", tr.Server, tr.Catalog, tr.Schema, tr.Name)
+	}
+	if tr.Catalog != "" {
+		return fmt.Sprintf("%s.%s.%s", tr.Catalog, tr.Schema, tr.Name)
+	}
 	if tr.Schema != "" {
 		return fmt.Sprintf("%s.%s", tr.Schema, tr.Name)
 	}
 	return tr.Name
 }
 
+// SetQualifiedName assigns the parts of a dotted name, right to left:
+// name, schema, catalog and server
+func (tr *TableReference) SetQualifiedName(parts []string) {
+	fields := []*string{&tr.Name, &tr.Schema, &tr.Catalog, &tr.Server}
+	for i := range fields {
+		*fields[i] = ""
+	}
+	for i := 0; i < len(parts) && i < len(fields); i++ {
+		*fields[i] = parts[len(parts)-1-i]
+	}
+}
+
+// Hint is an optimizer, query or table hint such as INDEX(o idx_status),
+// NOLOCK or MAXDOP 4
+type Hint struct {
+	BaseNode
+	Name string   // upper-cased, multi-word names are space separated (HASH JOIN)
+	Args []string // INDEX(o idx_status) -> [o idx_status], MAXDOP 4 -> [4]
+}
+
+func (h *Hint) Type() string { return "Hint" }
+func (h *Hint) String() string {
+	if len(h.Args) == 0 {
+		return h.Name
+	}
+	return fmt.Sprintf("%s(%s)", h.Name, strings.Join(h.Args, " "))
+}
+
 // JOIN Clause
 type JoinClause struct {
 	BaseNode
-	JoinType  string // INNER, LEFT, RIGHT, FULL
+	JoinType  string // INNER, LEFT, RIGHT, FULL, CROSS, CROSS APPLY, OUTER APPLY
 	Table     TableReference
-	Condition Expression
+	Condition Expression // ON condition
+	Using     []string   // JOIN ... USING (cols)
+	Natural   bool       // NATURAL JOIN
+	Lateral   bool       // JOIN LATERAL (subquery)
+	Straight  bool       // MySQL STRAIGHT_JOIN: join in the written order
+}
+
+func (jc *JoinClause) Type() string { return "JoinClause" }
+func (jc *JoinClause) String() string {
+	if jc.IsApply() {
+		return jc.JoinType
+	}
+	s := fmt.Sprintf("%s JOIN", jc.JoinType)
+	if jc.Straight {
+		s = "STRAIGHT_JOIN"
+	}
+	if jc.Natural {
+		s = "NATURAL " + s
+	}
+	if jc.Lateral {
+		s += " LATERAL"
+	}
+	return s
 }
 
-func (jc *JoinClause) Type() string   { return "JoinClause" }
-func (jc *JoinClause) String() string { return fmt.Sprintf("%s JOIN", jc.JoinType) }
+// IsApply reports whether this is a SQL Server CROSS APPLY or OUTER APPLY
+func (jc *JoinClause) IsApply() bool {
+	return strings.HasSuffix(jc.JoinType, "APPLY")
+}
+
+// HasJoinCondition reports whether the join matches rows on columns, through
+// ON, USING or NATURAL, or is correlated to the tables before it (LATERAL,
+// APPLY). Joins without one produce a Cartesian product.
+func (jc *JoinClause) HasJoinCondition() bool {
+	return jc.Condition != nil || len(jc.Using) > 0 || jc.Natural || jc.Lateral || jc.IsApply()
+}
 
 // Column Reference
 type ColumnReference struct {
 	BaseNode
-	Table  string
-	Column string
+	Catalog string // Database qualifier (db.schema.table.column)
+	Schema  string // Schema qualifier (schema.table.column)
+	Table   string
+	Column  string
 }
 
 func (cr *ColumnReference) expressionNode() {}
 func (cr *ColumnReference) Type() string    { return "ColumnReference" }
 func (cr *ColumnReference) String() string {
+	if cr.Catalog != "" {
+		return fmt.Sprintf("%s.%s.%s.%s", cr.Catalog, cr.Schema, cr.Table, cr.Column)
+	}
+	if cr.Schema != "" {
+		return fmt.Sprintf("%s.%s.%s", cr.Schema, cr.Table, cr.Column)
+	}
 	if cr.Table != "" {
 		return fmt.Sprintf("%s.%s", cr.Table, cr.Column)
 	}
 	return cr.Column
 }
 
-// Literal Expression
+// Literal Expression. Value is int64, float64, string (decoded, or the
+// digits of a bit string), []byte (hex strings), bool or nil.
 type Literal struct {
 	BaseNode
-	Value interface{}
+	Value   interface{}
+	Kind    lexer.LiteralKind // N'', E'', X'', B'', q'[]' ...
+	Charset string            // MySQL _utf8mb4'...' introducer
 }
 
 func (l *Literal) expressionNode() {}
 func (l *Literal) Type() string    { return "Literal" }
-func (l *Literal) String() string  { return fmt.Sprintf("%v", l.Value) }
+func (l *Literal) String() string {
+	if b, ok := l.Value.([]byte); ok {
+		return fmt.Sprintf("X'%X'", b)
+	}
+	if l.Value == nil {
+		return "NULL"
+	}
+	return fmt.Sprintf("%v", l.Value)
+}
 
 // Binary Expression (for WHERE conditions, etc.)
 type BinaryExpression struct {
@@ -133,23 +233,125 @@ func (be *BinaryExpression) String() string {
 // Function Call
 type FunctionCall struct {
 	BaseNode
-	Name      string
-	Arguments []Expression
+	Name        string
+	Arguments   []Expression
+	Distinct    bool             // COUNT(DISTINCT x)
+	OrderBy     []*OrderByClause // STRING_AGG(x, ',' ORDER BY y)
+	WithinGroup []*OrderByClause // PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY x)
+	Filter      Expression       // COUNT(*) FILTER (WHERE ...)
 }
 
 func (fc *FunctionCall) expressionNode() {}
 func (fc *FunctionCall) Type() string    { return "FunctionCall" }
 func (fc *FunctionCall) String() string  { return fmt.Sprintf("%s(...)", fc.Name) }
 
+// CastExpression is CAST(expr AS type), TRY_CAST, PostgreSQL expr::type and
+// CONVERT in its SQL Server and MySQL forms
+type CastExpression struct {
+	BaseNode
+	Expression Expression
+	DataType   *DataTypeDefinition // nil for MySQL CONVERT(expr USING charset)
+	Style      Expression          // SQL Server CONVERT(type, expr, style)
+	Charset    string              // MySQL CONVERT(expr USING charset)
+	Safe       bool                // TRY_CAST, TRY_CONVERT
+	TypeFirst  bool                // SQL Server CONVERT(type, expr)
+	Syntax     string              // CAST, ::, CONVERT
+}
+
+func (ce *CastExpression) expressionNode() {}
+func (ce *CastExpression) Type() string    { return "CastExpression" }
+func (ce *CastExpression) String() string {
+	switch {
+	case ce.Syntax == "::":
+		return fmt.Sprintf("%s::%s", ce.Expression.String(), ce.DataType.String())
+	case ce.Charset != "":
+		return fmt.Sprintf("CONVERT(%s USING %s)", ce.Expression.String(), ce.Charset)
+	}
+
+	name := ce.Syntax
+	if ce.Safe {
+		name = "TRY_" + name
+	}
+	switch {
+	case ce.TypeFirst && ce.Style != nil:
+		return fmt.Sprintf("%s(%s, %s, %s)", name, ce.DataType.String(), ce.Expression.String(), ce.Style.String())
+	case ce.TypeFirst:
+		return fmt.Sprintf("%s(%s, %s)", name, ce.DataType.String(), ce.Expression.String())
+	case ce.Syntax == "CONVERT":
+		return fmt.Sprintf("%s(%s, %s)", name, ce.Expression.String(), ce.DataType.String())
+	}
+	return fmt.Sprintf("%s(%s AS %s)", name, ce.Expression.String(), ce.DataType.String())
+}
+
+// ExtractExpression is EXTRACT(field FROM source)
+type ExtractExpression struct {
+	BaseNode
+	Field  string // YEAR, MONTH, EPOCH, ...
+	Source Expression
+}
+
+func (ee *ExtractExpression) expressionNode() {}
+func (ee *ExtractExpression) Type() string    { return "ExtractExpression" }
+func (ee *ExtractExpression) String() string {
+	return fmt.Sprintf("EXTRACT(%s FROM %s)", ee.Field, ee.Source.String())
+}
+
+// TrimExpression is TRIM([LEADING | TRAILING | BOTH] [chars] FROM source)
+// or TRIM(source)
+type TrimExpression struct {
+	BaseNode
+	Position   string // LEADING, TRAILING, BOTH or empty
+	Characters Expression
+	Source     Expression
+}
+
+func (te *TrimExpression) expressionNode() {}
+func (te *TrimExpression) Type() string    { return "TrimExpression" }
+func (te *TrimExpression) String() string {
+	var parts []string
+	if te.Position != "" {
+		parts = append(parts, te.Position)
+	}
+	if te.Characters != nil {
+		parts = append(parts, te.Characters.String())
+	}
+	if len(parts) > 0 {
+		parts = append(parts, "FROM")
+	}
+	parts = append(parts, te.Source.String())
+	return fmt.Sprintf("TRIM(%s)", strings.Join(parts, " "))
+}
+
+// IntervalExpression is INTERVAL '1 day', INTERVAL '1' DAY or MySQL's
+// INTERVAL 1 DAY
+type IntervalExpression struct {
+	BaseNode
+	Value Expression
+	Unit  string // empty when the unit is part of the string
+}
+
+func (ie *IntervalExpression) expressionNode() {}
+func (ie *IntervalExpression) Type() string    { return "IntervalExpression" }
+func (ie *IntervalExpression) String() string {
+	if ie.Unit != "" {
+		return fmt.Sprintf("INTERVAL %s %s", ie.Value.String(), ie.Unit)
+	}
+	return fmt.Sprintf("INTERVAL %s", ie.Value.String())
+}
+
 // SELECT * Expression
 type StarExpression struct {
 	BaseNode
-	Table string // optional table qualifier
+	Schema string // optional schema qualifier (schema.table.*)
+	Table  string // optional table qualifier
 }
 
 func (se *StarExpression) expressionNode() {}
 func (se *StarExpression) Type() string    { return "StarExpression" }
 func (se *StarExpression) String() string {
+	if se.Schema != "" {
+		return fmt.Sprintf("%s.%s.*", se.Schema, se.Table)
+	}
 	if se.Table != "" {
 		return fmt.Sprintf("%s.*", se.Table)
 	}
@@ -172,6 +374,34 @@ func (ae *AliasedExpression) String() string {
 	return ae.Expression.String()
 }
 
+// GroupingSetsExpression is a GROUP BY element that produces several
+// grouping sets: ROLLUP(a, b), CUBE(a, b) or GROUPING SETS ((a), (b), ()).
+// Each entry of Sets is one parenthesized element; for GROUPING SETS an
+// entry may itself hold a nested ROLLUP or CUBE.
+type GroupingSetsExpression struct {
+	BaseNode
+	Kind string // ROLLUP, CUBE, GROUPING SETS
+	Sets [][]Expression
+}
+
+func (gse *GroupingSetsExpression) expressionNode() {}
+func (gse *GroupingSetsExpression) Type() string    { return "GroupingSetsExpression" }
+func (gse *GroupingSetsExpression) String() string {
+	sets := make([]string, len(gse.Sets))
+	for i, set := range gse.Sets {
+		exprs := make([]string, len(set))
+		for j, expr := range set {
+			exprs[j] = expr.String()
+		}
+		if len(set) == 1 && gse.Kind != "GROUPING SETS" {
+			sets[i] = exprs[0]
+		} else {
+			sets[i] = "(" + strings.Join(exprs, ", ") + ")"
+		}
+	}
+	return fmt.Sprintf("%s(%s)", gse.Kind, strings.Join(sets, ", "))
+}
+
 // ORDER BY Clause
 type OrderByClause struct {
 	BaseNode
@@ -184,52 +414,188 @@ func (obc *OrderByClause) String() string {
 	return fmt.Sprintf("ORDER BY %s %s", obc.Expression.String(), obc.Direction)
 }
 
-// TOP Clause (SQL Server specific)
-type TopClause struct {
+// RowLimit is a row-limiting clause in any of the dialect syntaxes: LIMIT
+// n [OFFSET m], MySQL LIMIT m, n, TOP n, OFFSET m ROWS FETCH NEXT n ROWS
+// ONLY and Oracle ROWNUM <= n. Count and Offset are expressions, so bind
+// parameters and variables are allowed. For ROWNUM the predicate stays in
+// the WHERE clause; the RowLimit mirrors it.
+type RowLimit struct {
 	BaseNode
-	Count   int
-	Percent bool
+	Syntax   dialect.LimitSyntax
+	Count    Expression // nil for LIMIT ALL or an OFFSET on its own
+	Offset   Expression
+	Percent  bool // TOP n PERCENT, FETCH FIRST n PERCENT ROWS ONLY
+	WithTies bool // TOP n WITH TIES, FETCH FIRST n ROWS WITH TIES
+}
+
+func (rl *RowLimit) Type() string { return "RowLimit" }
+func (rl *RowLimit) String() string {
+	count := "ALL"
+	if rl.Count != nil {
+		count = rl.Count.String()
+	}
+
+	var parts []string
+	switch rl.Syntax {
+	case dialect.LimitSyntaxSQLServer:
+		parts = append(parts, "TOP "+count)
+		if rl.Percent {
+			parts = append(parts, "PERCENT")
+		}
+		if rl.WithTies {
+			parts = append(parts, "WITH TIES")
+		}
+	case dialect.LimitSyntaxOracle:
+		parts = append(parts, "ROWNUM <= "+count)
+	case dialect.LimitSyntaxFetch:
+		if rl.Offset != nil {
+			parts = append(parts, "OFFSET "+rl.Offset.String()+" ROWS")
+		}
+		if rl.Count != nil {
+			fetch := "FETCH NEXT " + count
+			if rl.Percent {
+				fetch += " PERCENT"
+			}
+			if rl.WithTies {
+				fetch += " ROWS WITH TIES"
+			} else {
+				fetch += " ROWS ONLY"
+			}
+			parts = append(parts, fetch)
+		}
+	default:
+		parts = append(parts, "LIMIT "+count)
+		if rl.Offset != nil {
+			parts = append(parts, "OFFSET "+rl.Offset.String())
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CountValue returns the row count when it is an integer literal
+func (rl *RowLimit) CountValue() (int64, bool) {
+	return intLiteralValue(rl.Count)
 }
 
-func (tc *TopClause) Type() string   { return "TopClause" }
-func (tc *TopClause) String() string { return fmt.Sprintf("TOP %d", tc.Count) }
+// OffsetValue returns the offset when it is an integer literal
+func (rl *RowLimit) OffsetValue() (int64, bool) {
+	return intLiteralValue(rl.Offset)
+}
 
-// LIMIT Clause
-type LimitClause struct {
+func intLiteralValue(expr Expression) (int64, bool) {
+	if lit, ok := expr.(*Literal); ok {
+		if v, ok := lit.Value.(int64); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// Parameter is a bind parameter or variable: ?, $1, :name, @name
+type Parameter struct {
 	BaseNode
-	Count  int
-	Offset int
+	Name string
 }
 
-func (lc *LimitClause) Type() string   { return "LimitClause" }
-func (lc *LimitClause) String() string { return fmt.Sprintf("LIMIT %d", lc.Count) }
+func (pm *Parameter) expressionNode() {}
+func (pm *Parameter) Type() string    { return "Parameter" }
+func (pm *Parameter) String() string  { return pm.Name }
 
 // INSERT Statement
 type InsertStatement struct {
 	BaseNode
-	Table   TableReference
-	Columns []string         // Optional column list
-	Values  [][]Expression   // For INSERT ... VALUES
-	Select  *SelectStatement // For INSERT ... SELECT
+	Table                TableReference
+	Columns              []string          // Optional column list
+	Values               [][]Expression    // For INSERT ... VALUES
+	Select               *SelectStatement  // For INSERT ... SELECT
+	Ignore               bool              // MySQL INSERT IGNORE
+	Replace              bool              // MySQL REPLACE INTO
+	OnConflict           *OnConflictClause // PostgreSQL/SQLite ON CONFLICT
+	OnDuplicateKeyUpdate []*Assignment     // MySQL ON DUPLICATE KEY UPDATE
+	Returning            *ReturningClause  // PostgreSQL/SQLite/Oracle RETURNING
+	Output               *OutputClause     // SQL Server OUTPUT
+	Hints                []*Hint           // /*+ ... */ optimizer hints and SQL Server OPTION (...)
 }
 
 func (is *InsertStatement) statementNode() {}
 func (is *InsertStatement) Type() string   { return "InsertStatement" }
 func (is *InsertStatement) String() string {
+	verb := "INSERT INTO"
+	if is.Replace {
+		verb = "REPLACE INTO"
+	} else if is.Ignore {
+		verb = "INSERT IGNORE INTO"
+	}
 	if is.Select != nil {
-		return fmt.Sprintf("INSERT INTO %s SELECT", is.Table.Name)
+		return fmt.Sprintf("%s %s SELECT", verb, is.Table.Name)
+	}
+	return fmt.Sprintf("%s %s (%d rows)", verb, is.Table.Name, len(is.Values))
+}
+
+// OnConflictClause represents INSERT ... ON CONFLICT (PostgreSQL, SQLite)
+type OnConflictClause struct {
+	BaseNode
+	Columns     []string      // Conflict target columns
+	Constraint  string        // ON CONFLICT ON CONSTRAINT name
+	TargetWhere Expression    // Partial index predicate of the target
+	DoNothing   bool          // DO NOTHING
+	Set         []*Assignment // DO UPDATE SET
+	Where       Expression    // DO UPDATE ... WHERE
+}
+
+func (oc *OnConflictClause) Type() string { return "OnConflictClause" }
+func (oc *OnConflictClause) String() string {
+	target := ""
+	if oc.Constraint != "" {
+		target = " ON CONSTRAINT " + oc.Constraint
+	} else if len(oc.Columns) > 0 {
+		target = " (" + strings.Join(oc.Columns, ", ") + ")"
+	}
+	if oc.DoNothing {
+		return "ON CONFLICT" + target + " DO NOTHING"
 	}
-	return fmt.Sprintf("INSERT INTO %s (%d rows)", is.Table.Name, len(is.Values))
+	return fmt.Sprintf("ON CONFLICT%s DO UPDATE SET %d columns", target, len(oc.Set))
+}
+
+// ReturningClause represents RETURNING (PostgreSQL, SQLite, Oracle)
+type ReturningClause struct {
+	BaseNode
+	Columns []Expression
+	Into    []string // Oracle RETURNING ... INTO variables
+}
+
+func (rc *ReturningClause) Type() string { return "ReturningClause" }
+func (rc *ReturningClause) String() string {
+	return fmt.Sprintf("RETURNING %d columns", len(rc.Columns))
+}
+
+// OutputClause represents SQL Server's OUTPUT inserted.* / deleted.*
+type OutputClause struct {
+	BaseNode
+	Columns     []Expression
+	Into        *TableReference // OUTPUT ... INTO table
+	IntoColumns []string
+}
+
+func (oc *OutputClause) Type() string { return "OutputClause" }
+func (oc *OutputClause) String() string {
+	if oc.Into != nil {
+		return fmt.Sprintf("OUTPUT %d columns INTO %s", len(oc.Columns), oc.Into.String())
+	}
+	return fmt.Sprintf("OUTPUT %d columns", len(oc.Columns))
 }
 
 // UPDATE Statement
 type UpdateStatement struct {
 	BaseNode
-	Table   TableReference
-	Set     []*Assignment
-	Where   Expression
-	OrderBy []*OrderByClause // MySQL/SQLite support ORDER BY in UPDATE
-	Limit   *LimitClause     // MySQL/SQLite support LIMIT in UPDATE
+	Table     TableReference
+	Set       []*Assignment
+	Where     Expression
+	OrderBy   []*OrderByClause // MySQL/SQLite support ORDER BY in UPDATE
+	Limit     *RowLimit        // MySQL/SQLite LIMIT, SQL Server TOP
+	Returning *ReturningClause // PostgreSQL/SQLite/Oracle RETURNING
+	Output    *OutputClause    // SQL Server OUTPUT
+	Hints     []*Hint          // /*+ ... */ optimizer hints and SQL Server OPTION (...)
 }
 
 func (us *UpdateStatement) statementNode() {}
@@ -251,10 +617,13 @@ func (a *Assignment) String() string { return fmt.Sprintf("%s = %s", a.Column, a
 // DELETE Statement
 type DeleteStatement struct {
 	BaseNode
-	From    TableReference
-	Where   Expression
-	OrderBy []*OrderByClause // MySQL/SQLite support ORDER BY in DELETE
-	Limit   *LimitClause     // MySQL/SQLite support LIMIT in DELETE
+	From      TableReference
+	Where     Expression
+	OrderBy   []*OrderByClause // MySQL/SQLite support ORDER BY in DELETE
+	Limit     *RowLimit        // MySQL/SQLite LIMIT, SQL Server TOP
+	Returning *ReturningClause // PostgreSQL/SQLite/Oracle RETURNING
+	Output    *OutputClause    // SQL Server OUTPUT
+	Hints     []*Hint          // /*+ ... */ optimizer hints and SQL Server OPTION (...)
 }
 
 func (ds *DeleteStatement) statementNode() {}
@@ -319,6 +688,9 @@ type UnaryExpression struct {
 func (ue *UnaryExpression) expressionNode() {}
 func (ue *UnaryExpression) Type() string    { return "UnaryExpression" }
 func (ue *UnaryExpression) String() string {
+	if ue.Operator == "-" || ue.Operator == "+" {
+		return ue.Operator + ue.Operand.String()
+	}
 	return fmt.Sprintf("%s %s", ue.Operator, ue.Operand.String())
 }
 
@@ -339,6 +711,25 @@ func (ie *InExpression) String() string {
 	return fmt.Sprintf("%s IN (...)", ie.Expression.String())
 }
 
+// BETWEEN Expression
+type BetweenExpression struct {
+	BaseNode
+	Expression Expression
+	Low        Expression
+	High       Expression
+	Not        bool
+}
+
+func (be *BetweenExpression) expressionNode() {}
+func (be *BetweenExpression) Type() string    { return "BetweenExpression" }
+func (be *BetweenExpression) String() string {
+	operator := "BETWEEN"
+	if be.Not {
+		operator = "NOT BETWEEN"
+	}
+	return fmt.Sprintf("%s %s %s AND %s", be.Expression.String(), operator, be.Low.String(), be.High.String())
+}
+
 // EXISTS Expression
 type ExistsExpression struct {
 	BaseNode
@@ -400,12 +791,17 @@ type WindowFunction struct {
 func (wf *WindowFunction) expressionNode() {}
 func (wf *WindowFunction) Type() string    { return "WindowFunction" }
 func (wf *WindowFunction) String() string {
+	oc := wf.OverClause
+	if oc != nil && oc.WindowName != "" && len(oc.PartitionBy) == 0 && len(oc.OrderBy) == 0 && oc.Frame == nil {
+		return fmt.Sprintf("%s OVER %s", wf.Function.Name, oc.WindowName)
+	}
 	return fmt.Sprintf("%s OVER (...)", wf.Function.Name)
 }
 
 // OVER Clause for Window Functions
 type OverClause struct {
 	BaseNode
+	WindowName  string // OVER w, or OVER (w ORDER BY ...) refining a named window
 	PartitionBy []Expression
 	OrderBy     []*OrderByClause
 	Frame       *WindowFrame
@@ -414,6 +810,16 @@ type OverClause struct {
 func (oc *OverClause) Type() string   { return "OverClause" }
 func (oc *OverClause) String() string { return "OVER clause" }
 
+// WindowDefinition is a named window from the WINDOW clause
+type WindowDefinition struct {
+	BaseNode
+	Name string
+	Spec *OverClause
+}
+
+func (wd *WindowDefinition) Type() string   { return "WindowDefinition" }
+func (wd *WindowDefinition) String() string { return fmt.Sprintf("WINDOW %s AS (...)", wd.Name) }
+
 // Window Frame (ROWS/RANGE BETWEEN ... AND ...)
 type WindowFrame struct {
 	BaseNode
@@ -488,6 +894,7 @@ type CreateTableStatement struct {
 	Columns     []*ColumnDefinition
 	Constraints []*TableConstraint
 	IfNotExists bool
+	Query       Statement // CREATE TABLE ... AS SELECT
 }
 
 func (cts *CreateTableStatement) statementNode() {}
@@ -558,8 +965,12 @@ func (ds *DropStatement) String() string {
 // ALTER TABLE Statement
 type AlterTableStatement struct {
 	BaseNode
-	Table  TableReference
-	Action *AlterAction
+	Table     TableReference
+	IfExists  bool
+	Action    *AlterAction   // First action
+	Actions   []*AlterAction // All comma-separated actions, in order
+	Algorithm string         // ALGORITHM = INSTANT/INPLACE/COPY (MySQL)
+	Lock      string         // LOCK = NONE/SHARED/EXCLUSIVE (MySQL)
 }
 
 func (ats *AlterTableStatement) statementNode() {}
@@ -571,11 +982,20 @@ func (ats *AlterTableStatement) String() string {
 // ALTER Action
 type AlterAction struct {
 	BaseNode
-	ActionType string            // ADD, DROP, MODIFY, CHANGE, RENAME
-	Column     *ColumnDefinition // For ADD/MODIFY
-	ColumnName string            // For DROP/CHANGE
-	NewColumn  *ColumnDefinition // For CHANGE
-	Constraint *TableConstraint  // For ADD constraint
+	ActionType     string              // ADD, DROP, MODIFY, CHANGE, RENAME, RENAME_COLUMN, RENAME_CONSTRAINT, ALTER_COLUMN, DROP_CONSTRAINT, VALIDATE_CONSTRAINT, NOCHECK_CONSTRAINT
+	Column         *ColumnDefinition   // For ADD/MODIFY and SQL Server ALTER COLUMN; first of Columns
+	Columns        []*ColumnDefinition // For ADD (...) / MODIFY (...) lists (Oracle)
+	ColumnName     string              // For DROP/CHANGE/RENAME_COLUMN/ALTER_COLUMN
+	NewName        string              // For RENAME/RENAME_COLUMN/RENAME_CONSTRAINT
+	NewColumn      *ColumnDefinition   // For CHANGE
+	Constraint     *TableConstraint    // For ADD constraint
+	ConstraintName string              // For DROP/VALIDATE/NOCHECK/RENAME constraint
+	ColumnChange   string              // For ALTER_COLUMN: SET_DEFAULT, DROP_DEFAULT, SET_NOT_NULL, DROP_NOT_NULL, TYPE
+	Default        Expression          // For ALTER COLUMN ... SET DEFAULT
+	Using          Expression          // For ALTER COLUMN ... TYPE ... USING (PostgreSQL)
+	IfExists       bool                // DROP COLUMN/CONSTRAINT IF EXISTS
+	NotValid       bool                // ADD CONSTRAINT ... NOT VALID (PostgreSQL)
+	NoCheck        bool                // WITH NOCHECK ADD CONSTRAINT (SQL Server)
 }
 
 func (aa *AlterAction) Type() string   { return "AlterAction" }
@@ -584,11 +1004,22 @@ func (aa *AlterAction) String() string { return fmt.Sprintf("ALTER %s", aa.Actio
 // CREATE INDEX Statement
 type CreateIndexStatement struct {
 	BaseNode
-	IndexName   string
-	Table       TableReference
-	Columns     []string
-	Unique      bool
-	IfNotExists bool
+	IndexName    string
+	Table        TableReference
+	Columns      []string
+	Include      []string // INCLUDE (...) covering columns
+	Unique       bool
+	IfNotExists  bool
+	Concurrently bool              // CREATE INDEX CONCURRENTLY (PostgreSQL)
+	Clustered    string            // CLUSTERED or NONCLUSTERED (SQL Server)
+	Where        Expression        // Partial index predicate
+	Options      map[string]string // WITH (ONLINE = ON, ...) and ALGORITHM/LOCK, keys upper-cased
+}
+
+// Online reports whether the index is built without blocking writes
+func (cis *CreateIndexStatement) Online() bool {
+	return cis.Concurrently || strings.EqualFold(cis.Options["ONLINE"], "ON") ||
+		strings.EqualFold(cis.Options["LOCK"], "NONE")
 }
 
 func (cis *CreateIndexStatement) statementNode() {}
@@ -672,6 +1103,18 @@ func (rss *ReleaseSavepointStatement) String() string {
 	return fmt.Sprintf("RELEASE SAVEPOINT %s", rss.Name)
 }
 
+// SET TRANSACTION Statement
+type SetTransactionStatement struct {
+	BaseNode
+	IsolationLevel string // READ UNCOMMITTED, READ COMMITTED, REPEATABLE READ, SNAPSHOT or SERIALIZABLE
+}
+
+func (sts *SetTransactionStatement) statementNode() {}
+func (sts *SetTransactionStatement) Type() string   { return "SetTransactionStatement" }
+func (sts *SetTransactionStatement) String() string {
+	return "SET TRANSACTION ISOLATION LEVEL " + sts.IsolationLevel
+}
+
 // EXPLAIN Statement
 type ExplainStatement struct {
 	BaseNode
@@ -756,18 +1199,24 @@ type DataTypeDefinition struct {
 	IsArray   bool   // For array types (PostgreSQL)
 }
 
+// MaxLength is the Length of SQL Server's NVARCHAR(MAX) and similar types
+const MaxLength = -1
+
 func (dtd *DataTypeDefinition) Type() string { return "DataTypeDefinition" }
 func (dtd *DataTypeDefinition) String() string {
-	if dtd.Length > 0 {
-		return fmt.Sprintf("%s(%d)", dtd.Name, dtd.Length)
-	}
-	if dtd.Precision > 0 {
-		return fmt.Sprintf("%s(%d,%d)", dtd.Name, dtd.Precision, dtd.Scale)
+	name := dtd.Name
+	switch {
+	case dtd.Length == MaxLength:
+		name += "(MAX)"
+	case dtd.Length > 0:
+		name = fmt.Sprintf("%s(%d)", dtd.Name, dtd.Length)
+	case dtd.Precision > 0:
+		name = fmt.Sprintf("%s(%d,%d)", dtd.Name, dtd.Precision, dtd.Scale)
 	}
 	if dtd.IsArray {
-		return fmt.Sprintf("%s[]", dtd.Name)
+		name += "[]"
 	}
-	return dtd.Name
+	return name
 }
 
 // CreateProcedureStatement represents CREATE PROCEDURE
@@ -845,6 +1294,7 @@ type VariableDecl struct {
 	Name     string
 	DataType *DataTypeDefinition
 	Default  Expression
+	Table    []*ColumnDefinition // DECLARE @t TABLE (...) (SQL Server)
 }
 
 func (vd *VariableDecl) statementNode() {}
@@ -1022,12 +1472,15 @@ func (rs *ReturnStatement) String() string { return "RETURN" }
 type AssignmentStatement struct {
 	BaseNode
 	Variable string
+	Operator string // + for SET @x += 1 (SQL Server); empty for =
 	Value    Expression
 }
 
 func (as *AssignmentStatement) statementNode() {}
 func (as *AssignmentStatement) Type() string   { return "AssignmentStatement" }
-func (as *AssignmentStatement) String() string { return fmt.Sprintf("SET %s = ...", as.Variable) }
+func (as *AssignmentStatement) String() string {
+	return fmt.Sprintf("SET %s %s= ...", as.Variable, as.Operator)
+}
 
 // OpenCursorStatement represents OPEN cursor
 type OpenCursorStatement struct {
@@ -1298,3 +1751,130 @@ func (ss *SignalStatement) String() string {
 	}
 	return result
 }
+
+// SQL Server batch statements
+
+// DeclareStatement represents a top-level DECLARE of variables, a table
+// variable or a cursor (SQL Server)
+type DeclareStatement struct {
+	BaseNode
+	Variables []*VariableDecl
+	Cursor    *CursorDecl
+}
+
+func (ds *DeclareStatement) statementNode() {}
+func (ds *DeclareStatement) Type() string   { return "DeclareStatement" }
+func (ds *DeclareStatement) String() string {
+	if ds.Cursor != nil {
+		return ds.Cursor.String()
+	}
+	names := make([]string, len(ds.Variables))
+	for i, v := range ds.Variables {
+		names[i] = v.Name
+	}
+	return "DECLARE " + strings.Join(names, ", ")
+}
+
+// SetOptionStatement represents SET NOCOUNT ON and similar session options
+type SetOptionStatement struct {
+	BaseNode
+	Options []string
+	Value   string // ON or OFF
+}
+
+func (sos *SetOptionStatement) statementNode() {}
+func (sos *SetOptionStatement) Type() string   { return "SetOptionStatement" }
+func (sos *SetOptionStatement) String() string {
+	return "SET " + strings.Join(sos.Options, ", ") + " " + sos.Value
+}
+
+// VariableAssignment is a SELECT @var = expr item (SQL Server)
+type VariableAssignment struct {
+	BaseNode
+	Variable string
+	Value    Expression
+}
+
+func (va *VariableAssignment) expressionNode() {}
+func (va *VariableAssignment) Type() string    { return "VariableAssignment" }
+func (va *VariableAssignment) String() string {
+	return fmt.Sprintf("%s = %s", va.Variable, va.Value.String())
+}
+
+// ExecStatement represents EXEC procedure [args] and EXEC (sql)
+type ExecStatement struct {
+	BaseNode
+	ReturnVariable string // EXEC @rc = procedure
+	Procedure      string // qualified name; empty for EXEC (sql)
+	Arguments      []*ExecArgument
+	SQL            Expression // EXEC ('...') or the statement passed to sp_executesql
+}
+
+func (es *ExecStatement) statementNode() {}
+func (es *ExecStatement) Type() string   { return "ExecStatement" }
+func (es *ExecStatement) String() string {
+	if es.Procedure == "" {
+		return "EXEC (" + es.SQL.String() + ")"
+	}
+	return fmt.Sprintf("EXEC %s (%d arguments)", es.Procedure, len(es.Arguments))
+}
+
+// IsDynamic reports whether the statement runs dynamic SQL
+func (es *ExecStatement) IsDynamic() bool {
+	return es.SQL != nil
+}
+
+// ExecArgument is a positional or named (@name = value) procedure argument
+type ExecArgument struct {
+	BaseNode
+	Name   string
+	Value  Expression
+	Output bool // @var OUTPUT
+}
+
+func (ea *ExecArgument) Type() string { return "ExecArgument" }
+func (ea *ExecArgument) String() string {
+	result := ea.Value.String()
+	if ea.Name != "" {
+		result = ea.Name + " = " + result
+	}
+	if ea.Output {
+		result += " OUTPUT"
+	}
+	return result
+}
+
+// UseStatement represents USE database
+type UseStatement struct {
+	BaseNode
+	Database string
+}
+
+func (us *UseStatement) statementNode() {}
+func (us *UseStatement) Type() string   { return "UseStatement" }
+func (us *UseStatement) String() string { return "USE " + us.Database }
+
+// PrintStatement represents PRINT (SQL Server)
+type PrintStatement struct {
+	BaseNode
+	Value Expression
+}
+
+func (ps *PrintStatement) statementNode() {}
+func (ps *PrintStatement) Type() string   { return "PrintStatement" }
+func (ps *PrintStatement) String() string { return "PRINT " + ps.Value.String() }
+
+// GoStatement represents the GO batch separator (SQL Server tools)
+type GoStatement struct {
+	BaseNode
+	Count int // GO 5 runs the batch five times; 0 when omitted
+}
+
+func (gs *GoStatement) statementNode() {}
+func (gs *GoStatement) Type() string   { return "GoStatement" }
+func (gs *GoStatement) String() string {
+	if gs.Count > 0 {
+		return fmt.Sprintf("GO %d", gs.Count)
+	}
+	return "GO"
+}
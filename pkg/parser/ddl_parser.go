@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
 )
@@ -61,6 +62,11 @@ func (p *Parser) parseCreateStatement() (Statement, error) {
 		return p.parseCreateTableStatement()
 	case lexer.INDEX, lexer.UNIQUE:
 		return p.parseCreateIndexStatement()
+	case lexer.IDENT:
+		if p.curIdentIs("CLUSTERED") || p.curIdentIs("NONCLUSTERED") {
+			return p.parseCreateIndexStatement()
+		}
+		return nil, fmt.Errorf("unsupported CREATE statement: CREATE %s", p.curToken.Literal)
 	case lexer.VIEW, lexer.MATERIALIZED:
 		return p.parseCreateViewStatement()
 	case lexer.PROCEDURE:
@@ -98,16 +104,59 @@ func (p *Parser) parseCreateTableStatement() (*CreateTableStatement, error) {
 		p.nextToken()
 	}
 
-	// Parse table name
-	table, err := p.parseTableReference()
+	// Parse table name; not a table reference, as AS may follow
+	parts, err := p.parseQualifiedName("table")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse table name: %w", err)
 	}
-	stmt.Table = *table
+	stmt.Table.SetQualifiedName(parts)
+
+	if p.curTokenIs(lexer.LPAREN) {
+		columns, constraints, err := p.parseTableElements("CREATE TABLE")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Columns = columns
+		stmt.Constraints = constraints
+	}
+
+	// CREATE TABLE ... AS SELECT, or MySQL's CREATE TABLE ... SELECT
+	asSelect := p.curTokenIs(lexer.SELECT) && p.dialect != nil && p.dialect.Name() == "MySQL"
+	if p.curTokenIs(lexer.AS) {
+		p.nextToken()
+		if !p.curTokenIs(lexer.SELECT) {
+			return nil, fmt.Errorf("expected SELECT after AS, got %s", p.curToken.Literal)
+		}
+		asSelect = true
+	}
+	if asSelect {
+		query, err := p.parseSelectStatement()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CREATE TABLE query: %w", err)
+		}
+		stmt.Query, err = p.parseSetOperation(query)
+		if err != nil {
+			return nil, err
+		}
+		return stmt, nil
+	}
+
+	if stmt.Columns == nil {
+		return nil, fmt.Errorf("expected '(' after CREATE TABLE, got %s", p.curToken.Literal)
+	}
+
+	return stmt, nil
+}
+
+// parseTableElements parses the parenthesized column definitions and table
+// constraints of CREATE TABLE or a SQL Server table variable
+func (p *Parser) parseTableElements(what string) ([]*ColumnDefinition, []*TableConstraint, error) {
+	var columns []*ColumnDefinition
+	var constraints []*TableConstraint
 
 	// Expect opening parenthesis
 	if !p.curTokenIs(lexer.LPAREN) {
-		return nil, fmt.Errorf("expected '(' after table name, got %s", p.curToken.Literal)
+		return nil, nil, fmt.Errorf("expected '(' after table name, got %s", p.curToken.Literal)
 	}
 	p.nextToken()
 
@@ -118,33 +167,33 @@ func (p *Parser) parseCreateTableStatement() (*CreateTableStatement, error) {
 			p.curTokenIs(lexer.UNIQUE) || p.curTokenIs(lexer.CONSTRAINT) {
 			constraint, err := p.parseTableConstraint()
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			stmt.Constraints = append(stmt.Constraints, constraint)
+			constraints = append(constraints, constraint)
 		} else {
 			// Parse column definition
 			column, err := p.parseColumnDefinition()
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			stmt.Columns = append(stmt.Columns, column)
+			columns = append(columns, column)
 		}
 
 		// Check for comma
 		if p.curTokenIs(lexer.COMMA) {
 			p.nextToken()
 		} else if !p.curTokenIs(lexer.RPAREN) {
-			return nil, fmt.Errorf("expected ',' or ')', got %s", p.curToken.Literal)
+			return nil, nil, fmt.Errorf("expected ',' or ')', got %s", p.curToken.Literal)
 		}
 	}
 
 	// Expect closing parenthesis
 	if !p.curTokenIs(lexer.RPAREN) {
-		return nil, fmt.Errorf("expected ')' to close CREATE TABLE, got %s", p.curToken.Literal)
+		return nil, nil, fmt.Errorf("expected ')' to close %s, got %s", what, p.curToken.Literal)
 	}
 	p.nextToken()
 
-	return stmt, nil
+	return columns, constraints, nil
 }
 
 // parseColumnDefinition parses a column definition
@@ -158,45 +207,8 @@ func (p *Parser) parseColumnDefinition() (*ColumnDefinition, error) {
 	col.Name = p.curToken.Literal
 	p.nextToken()
 
-	// Data type
-	if !p.curTokenIs(lexer.IDENT) {
-		return nil, fmt.Errorf("expected data type, got %s", p.curToken.Literal)
-	}
-	col.DataType = p.curToken.Literal
-	p.nextToken()
-
-	// Check for length/precision: VARCHAR(255), DECIMAL(10,2)
-	if p.curTokenIs(lexer.LPAREN) {
-		p.nextToken()
-		if !p.curTokenIs(lexer.NUMBER) {
-			return nil, fmt.Errorf("expected number for type length, got %s", p.curToken.Literal)
-		}
-		length, err := strconv.Atoi(p.curToken.Literal)
-		if err != nil {
-			return nil, fmt.Errorf("invalid length: %w", err)
-		}
-		col.Length = length
-		p.nextToken()
-
-		// Check for scale (DECIMAL(10,2))
-		if p.curTokenIs(lexer.COMMA) {
-			p.nextToken()
-			if !p.curTokenIs(lexer.NUMBER) {
-				return nil, fmt.Errorf("expected number for type scale, got %s", p.curToken.Literal)
-			}
-			scale, err := strconv.Atoi(p.curToken.Literal)
-			if err != nil {
-				return nil, fmt.Errorf("invalid scale: %w", err)
-			}
-			col.Precision = col.Length
-			col.Scale = scale
-			p.nextToken()
-		}
-
-		if !p.curTokenIs(lexer.RPAREN) {
-			return nil, fmt.Errorf("expected ')' after type parameters, got %s", p.curToken.Literal)
-		}
-		p.nextToken()
+	if err := p.parseColumnType(col); err != nil {
+		return nil, err
 	}
 
 	// Parse column constraints
@@ -254,6 +266,53 @@ func (p *Parser) parseColumnDefinition() (*ColumnDefinition, error) {
 	}
 }
 
+// parseColumnType parses a data type with optional length or precision and
+// scale into col
+func (p *Parser) parseColumnType(col *ColumnDefinition) error {
+	// Data type
+	if !p.curTokenIs(lexer.IDENT) {
+		return fmt.Errorf("expected data type, got %s", p.curToken.Literal)
+	}
+	col.DataType = p.curToken.Literal
+	p.nextToken()
+
+	// Check for length/precision: VARCHAR(255), DECIMAL(10,2)
+	if p.curTokenIs(lexer.LPAREN) {
+		p.nextToken()
+		if !p.curTokenIs(lexer.NUMBER) {
+			return fmt.Errorf("expected number for type length, got %s", p.curToken.Literal)
+		}
+		length, err := strconv.Atoi(p.curToken.Literal)
+		if err != nil {
+			return fmt.Errorf("invalid length: %w", err)
+		}
+		col.Length = length
+		p.nextToken()
+
+		// Check for scale (DECIMAL(10,2))
+		if p.curTokenIs(lexer.COMMA) {
+			p.nextToken()
+			if !p.curTokenIs(lexer.NUMBER) {
+				return fmt.Errorf("expected number for type scale, got %s", p.curToken.Literal)
+			}
+			scale, err := strconv.Atoi(p.curToken.Literal)
+			if err != nil {
+				return fmt.Errorf("invalid scale: %w", err)
+			}
+			col.Precision = col.Length
+			col.Scale = scale
+			p.nextToken()
+		}
+
+		if !p.curTokenIs(lexer.RPAREN) {
+			return fmt.Errorf("expected ')' after type parameters, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+	}
+
+	return nil
+}
+
 // parseTableConstraint parses table-level constraints
 func (p *Parser) parseTableConstraint() (*TableConstraint, error) {
 	constraint := &TableConstraint{}
@@ -357,6 +416,26 @@ func (p *Parser) parseTableConstraint() (*TableConstraint, error) {
 		}
 		p.nextToken() // consume )
 
+	case lexer.CHECK:
+		constraint.ConstraintType = "CHECK"
+		p.nextToken()
+
+		if !p.curTokenIs(lexer.LPAREN) {
+			return nil, fmt.Errorf("expected '(' after CHECK, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+
+		check, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CHECK expression: %w", err)
+		}
+		constraint.Check = check
+
+		if !p.curTokenIs(lexer.RPAREN) {
+			return nil, fmt.Errorf("expected ')' after CHECK expression, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+
 	default:
 		return nil, fmt.Errorf("unexpected constraint type: %s", p.curToken.Literal)
 	}
@@ -526,72 +605,115 @@ func (p *Parser) parseAlterStatement() (*AlterTableStatement, error) {
 	}
 	p.nextToken()
 
-	// Parse table name
-	table, err := p.parseTableReference()
+	// Parse table name (no alias, so RENAME/VALIDATE aren't taken as one)
+	stmt.IfExists = p.parseIfExists()
+	parts, err := p.parseQualifiedName("table")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse table name: %w", err)
 	}
-	stmt.Table = *table
+	stmt.Table.SetQualifiedName(parts)
 
-	// Parse ALTER action
-	action, err := p.parseAlterAction()
-	if err != nil {
-		return nil, err
+	// Parse comma-separated ALTER actions and MySQL ALGORITHM/LOCK options
+	for {
+		switch {
+		case p.curIdentIs("ALGORITHM"):
+			stmt.Algorithm = p.parseOptionValue()
+		case p.curIdentIs("LOCK"):
+			stmt.Lock = p.parseOptionValue()
+		default:
+			action, err := p.parseAlterAction()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Actions = append(stmt.Actions, action)
+		}
+
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+	if len(stmt.Actions) == 0 {
+		return nil, fmt.Errorf("expected ALTER TABLE action, got %s", p.curToken.Literal)
 	}
-	stmt.Action = action
+	stmt.Action = stmt.Actions[0]
 
 	return stmt, nil
 }
 
-// parseAlterAction parses ADD/DROP/MODIFY/CHANGE
+// parseAlterAction parses a single ALTER TABLE action: ADD, DROP, MODIFY,
+// CHANGE, RENAME, ALTER COLUMN, VALIDATE CONSTRAINT and SQL Server's
+// WITH [NO]CHECK / [NO]CHECK CONSTRAINT
 func (p *Parser) parseAlterAction() (*AlterAction, error) {
 	action := &AlterAction{}
 
-	switch p.curToken.Type {
-	case lexer.ADD:
+	// SQL Server: WITH NOCHECK ADD CONSTRAINT ... / WITH CHECK CHECK CONSTRAINT ...
+	if p.curTokenIs(lexer.WITH) {
+		p.nextToken()
+		if p.curIdentIs("NOCHECK") {
+			action.NoCheck = true
+		} else if !p.curTokenIs(lexer.CHECK) {
+			return nil, fmt.Errorf("expected CHECK or NOCHECK after WITH, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+	}
+
+	switch {
+	case p.curTokenIs(lexer.ADD):
 		action.ActionType = "ADD"
 		p.nextToken()
 
 		// Check if adding a constraint or column
 		if p.curTokenIs(lexer.CONSTRAINT) || p.curTokenIs(lexer.PRIMARY) ||
-			p.curTokenIs(lexer.FOREIGN) || p.curTokenIs(lexer.UNIQUE) {
+			p.curTokenIs(lexer.FOREIGN) || p.curTokenIs(lexer.UNIQUE) || p.curTokenIs(lexer.CHECK) {
 			constraint, err := p.parseTableConstraint()
 			if err != nil {
 				return nil, err
 			}
 			action.Constraint = constraint
+
+			// PostgreSQL: NOT VALID skips checking existing rows
+			if p.curTokenIs(lexer.NOT) && p.peekIdentIs("VALID") {
+				action.NotValid = true
+				p.nextToken()
+				p.nextToken()
+			}
 		} else {
 			// Optional COLUMN keyword
 			if p.curTokenIs(lexer.COLUMN) {
 				p.nextToken()
 			}
 
-			// Parse column definition
-			col, err := p.parseColumnDefinition()
+			cols, err := p.parseAlterColumnDefinitions()
 			if err != nil {
 				return nil, err
 			}
-			action.Column = col
+			action.Columns = cols
+			action.Column = cols[0]
 		}
 
-	case lexer.DROP:
+	case p.curTokenIs(lexer.DROP):
 		action.ActionType = "DROP"
 		p.nextToken()
 
 		// Check if dropping a CONSTRAINT or COLUMN
 		if p.curTokenIs(lexer.CONSTRAINT) {
+			action.ActionType = "DROP_CONSTRAINT"
 			p.nextToken()
+			action.IfExists = p.parseIfExists()
+
 			// Constraint name
 			if !p.curTokenIs(lexer.IDENT) {
 				return nil, fmt.Errorf("expected constraint name, got %s", p.curToken.Literal)
 			}
-			action.ColumnName = p.curToken.Literal // Reuse ColumnName for constraint name
+			action.ConstraintName = p.curToken.Literal
 			p.nextToken()
 		} else {
 			// Optional COLUMN keyword
 			if p.curTokenIs(lexer.COLUMN) {
 				p.nextToken()
 			}
+			action.IfExists = p.parseIfExists()
 
 			// Column name
 			if !p.curTokenIs(lexer.IDENT) {
@@ -601,7 +723,12 @@ func (p *Parser) parseAlterAction() (*AlterAction, error) {
 			p.nextToken()
 		}
 
-	case lexer.MODIFY:
+		// Optional CASCADE / RESTRICT
+		if p.curIdentIs("CASCADE") || p.curIdentIs("RESTRICT") {
+			p.nextToken()
+		}
+
+	case p.curTokenIs(lexer.MODIFY):
 		action.ActionType = "MODIFY"
 		p.nextToken()
 
@@ -610,14 +737,15 @@ func (p *Parser) parseAlterAction() (*AlterAction, error) {
 			p.nextToken()
 		}
 
-		// Parse new column definition
-		col, err := p.parseColumnDefinition()
+		// Parse new column definition(s)
+		cols, err := p.parseAlterColumnDefinitions()
 		if err != nil {
 			return nil, err
 		}
-		action.Column = col
+		action.Columns = cols
+		action.Column = cols[0]
 
-	case lexer.CHANGE:
+	case p.curTokenIs(lexer.CHANGE):
 		action.ActionType = "CHANGE"
 		p.nextToken()
 
@@ -640,13 +768,253 @@ func (p *Parser) parseAlterAction() (*AlterAction, error) {
 		}
 		action.NewColumn = col
 
+	case p.curIdentIs("RENAME"):
+		if err := p.parseAlterRename(action); err != nil {
+			return nil, err
+		}
+
+	case p.curTokenIs(lexer.ALTER):
+		if err := p.parseAlterColumn(action); err != nil {
+			return nil, err
+		}
+
+	case p.curIdentIs("VALIDATE"), p.curTokenIs(lexer.CHECK), p.curIdentIs("NOCHECK"):
+		// VALIDATE CONSTRAINT (PostgreSQL), [NO]CHECK CONSTRAINT (SQL Server)
+		switch {
+		case p.curIdentIs("NOCHECK"):
+			action.ActionType = "NOCHECK_CONSTRAINT"
+		default:
+			action.ActionType = "VALIDATE_CONSTRAINT"
+		}
+		p.nextToken()
+		if !p.curTokenIs(lexer.CONSTRAINT) {
+			return nil, fmt.Errorf("expected CONSTRAINT, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		if !p.curTokenIs(lexer.IDENT) && !p.curTokenIs(lexer.ALL) {
+			return nil, fmt.Errorf("expected constraint name, got %s", p.curToken.Literal)
+		}
+		action.ConstraintName = p.curToken.Literal
+		p.nextToken()
+
 	default:
-		return nil, fmt.Errorf("expected ADD, DROP, MODIFY, or CHANGE, got %s", p.curToken.Literal)
+		return nil, fmt.Errorf("expected ADD, DROP, MODIFY, CHANGE, RENAME, or ALTER, got %s", p.curToken.Literal)
 	}
 
 	return action, nil
 }
 
+// parseAlterColumnDefinitions parses one column definition or a
+// parenthesized list of them (Oracle's ADD (...) and MODIFY (...))
+func (p *Parser) parseAlterColumnDefinitions() ([]*ColumnDefinition, error) {
+	if !p.curTokenIs(lexer.LPAREN) {
+		col, err := p.parseColumnDefinition()
+		if err != nil {
+			return nil, err
+		}
+		return []*ColumnDefinition{col}, nil
+	}
+	p.nextToken()
+
+	var cols []*ColumnDefinition
+	for {
+		col, err := p.parseColumnDefinition()
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' after column list, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return cols, nil
+}
+
+// parseAlterRename parses RENAME [TO|AS] name, RENAME [COLUMN] a TO b and
+// RENAME CONSTRAINT a TO b
+func (p *Parser) parseAlterRename(action *AlterAction) error {
+	p.nextToken() // consume RENAME
+
+	// RENAME TO new_name / RENAME AS new_name
+	if p.curIdentIs("TO") || p.curTokenIs(lexer.AS) {
+		p.nextToken()
+		parts, err := p.parseQualifiedName("table")
+		if err != nil {
+			return err
+		}
+		action.ActionType = "RENAME"
+		action.NewName = parts[len(parts)-1]
+		return nil
+	}
+
+	target := &action.ColumnName
+	action.ActionType = "RENAME_COLUMN"
+	if p.curTokenIs(lexer.CONSTRAINT) {
+		action.ActionType = "RENAME_CONSTRAINT"
+		target = &action.ConstraintName
+		p.nextToken()
+	} else if p.curTokenIs(lexer.COLUMN) {
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.IDENT) {
+		return fmt.Errorf("expected name after RENAME, got %s", p.curToken.Literal)
+	}
+	*target = p.curToken.Literal
+	p.nextToken()
+
+	if !p.curIdentIs("TO") {
+		return fmt.Errorf("expected TO after RENAME %s, got %s", *target, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	if !p.curTokenIs(lexer.IDENT) {
+		return fmt.Errorf("expected new name after TO, got %s", p.curToken.Literal)
+	}
+	action.NewName = p.curToken.Literal
+	p.nextToken()
+
+	return nil
+}
+
+// parseAlterColumn parses ALTER [COLUMN] name followed by SET DEFAULT,
+// DROP DEFAULT, SET/DROP NOT NULL, [SET DATA] TYPE ... [USING ...]
+// (PostgreSQL) or a full column definition (SQL Server)
+func (p *Parser) parseAlterColumn(action *AlterAction) error {
+	action.ActionType = "ALTER_COLUMN"
+	p.nextToken() // consume ALTER
+
+	if p.curTokenIs(lexer.COLUMN) {
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.IDENT) {
+		return fmt.Errorf("expected column name, got %s", p.curToken.Literal)
+	}
+	action.ColumnName = p.curToken.Literal
+
+	// SQL Server: ALTER COLUMN name type [NOT NULL]
+	if p.peekTokenIs(lexer.IDENT) && !p.peekIdentIs("TYPE") {
+		col, err := p.parseColumnDefinition()
+		if err != nil {
+			return err
+		}
+		action.ColumnChange = "TYPE"
+		action.Column = col
+		action.Columns = []*ColumnDefinition{col}
+		return nil
+	}
+	p.nextToken()
+
+	switch {
+	case p.curTokenIs(lexer.SET):
+		p.nextToken()
+		switch {
+		case p.curTokenIs(lexer.DEFAULT):
+			p.nextToken()
+			expr, err := p.parseExpression()
+			if err != nil {
+				return fmt.Errorf("failed to parse DEFAULT value: %w", err)
+			}
+			action.ColumnChange = "SET_DEFAULT"
+			action.Default = expr
+		case p.curTokenIs(lexer.NOT):
+			p.nextToken()
+			if !p.curTokenIs(lexer.NULL) {
+				return fmt.Errorf("expected NULL after SET NOT, got %s", p.curToken.Literal)
+			}
+			action.ColumnChange = "SET_NOT_NULL"
+			p.nextToken()
+		case p.curIdentIs("DATA"):
+			p.nextToken()
+			if !p.curIdentIs("TYPE") {
+				return fmt.Errorf("expected TYPE after SET DATA, got %s", p.curToken.Literal)
+			}
+			return p.parseAlterColumnType(action)
+		default:
+			return fmt.Errorf("expected DEFAULT, NOT NULL, or DATA TYPE after SET, got %s", p.curToken.Literal)
+		}
+
+	case p.curTokenIs(lexer.DROP):
+		p.nextToken()
+		switch {
+		case p.curTokenIs(lexer.DEFAULT):
+			action.ColumnChange = "DROP_DEFAULT"
+			p.nextToken()
+		case p.curTokenIs(lexer.NOT):
+			p.nextToken()
+			if !p.curTokenIs(lexer.NULL) {
+				return fmt.Errorf("expected NULL after DROP NOT, got %s", p.curToken.Literal)
+			}
+			action.ColumnChange = "DROP_NOT_NULL"
+			p.nextToken()
+		default:
+			return fmt.Errorf("expected DEFAULT or NOT NULL after DROP, got %s", p.curToken.Literal)
+		}
+
+	case p.curIdentIs("TYPE"):
+		return p.parseAlterColumnType(action)
+
+	default:
+		return fmt.Errorf("expected SET, DROP, or TYPE after ALTER COLUMN %s, got %s", action.ColumnName, p.curToken.Literal)
+	}
+
+	return nil
+}
+
+// parseAlterColumnType parses TYPE data_type [USING expression]
+func (p *Parser) parseAlterColumnType(action *AlterAction) error {
+	p.nextToken() // consume TYPE
+
+	col := &ColumnDefinition{Name: action.ColumnName}
+	if err := p.parseColumnType(col); err != nil {
+		return err
+	}
+	action.ColumnChange = "TYPE"
+	action.Column = col
+	action.Columns = []*ColumnDefinition{col}
+
+	if p.curTokenIs(lexer.USING) {
+		p.nextToken()
+		expr, err := p.parseExpression()
+		if err != nil {
+			return fmt.Errorf("failed to parse USING expression: %w", err)
+		}
+		action.Using = expr
+	}
+
+	return nil
+}
+
+// parseOptionValue parses NAME [=] value and returns the upper-cased value
+func (p *Parser) parseOptionValue() string {
+	p.nextToken() // consume option name
+	if p.curTokenIs(lexer.ASSIGN) {
+		p.nextToken()
+	}
+	value := strings.ToUpper(p.curToken.Literal)
+	p.nextToken()
+	return value
+}
+
+// parseIfExists consumes an optional IF EXISTS
+func (p *Parser) parseIfExists() bool {
+	if p.curTokenIs(lexer.IF) && p.peekTokenIs(lexer.EXISTS) {
+		p.nextToken()
+		p.nextToken()
+		return true
+	}
+	return false
+}
+
 // parseCreateIndexStatement parses CREATE INDEX
 func (p *Parser) parseCreateIndexStatement() (*CreateIndexStatement, error) {
 	stmt := &CreateIndexStatement{}
@@ -657,12 +1025,24 @@ func (p *Parser) parseCreateIndexStatement() (*CreateIndexStatement, error) {
 		p.nextToken()
 	}
 
+	// SQL Server: [CLUSTERED | NONCLUSTERED]
+	if p.curIdentIs("CLUSTERED") || p.curIdentIs("NONCLUSTERED") {
+		stmt.Clustered = strings.ToUpper(p.curToken.Literal)
+		p.nextToken()
+	}
+
 	// Expect INDEX
 	if !p.curTokenIs(lexer.INDEX) {
 		return nil, fmt.Errorf("expected INDEX, got %s", p.curToken.Literal)
 	}
 	p.nextToken()
 
+	// PostgreSQL: CONCURRENTLY
+	if p.curIdentIs("CONCURRENTLY") {
+		stmt.Concurrently = true
+		p.nextToken()
+	}
+
 	// Check for IF NOT EXISTS
 	if p.curTokenIs(lexer.IF) {
 		p.nextToken()
@@ -710,13 +1090,103 @@ func (p *Parser) parseCreateIndexStatement() (*CreateIndexStatement, error) {
 		stmt.Columns = append(stmt.Columns, p.curToken.Literal)
 		p.nextToken()
 
+		// Optional sort order
+		if p.curIdentIs("ASC") || p.curIdentIs("DESC") {
+			p.nextToken()
+		}
+
 		if p.curTokenIs(lexer.COMMA) {
 			p.nextToken()
 		}
 	}
 	p.nextToken() // consume )
 
-	return stmt, nil
+	return stmt, p.parseIndexOptions(stmt)
+}
+
+// parseIndexOptions parses the clauses after an index column list:
+// INCLUDE (...), WITH (name = value, ...), WHERE predicate and MySQL's
+// ALGORITHM/LOCK options
+func (p *Parser) parseIndexOptions(stmt *CreateIndexStatement) error {
+	for {
+		switch {
+		case p.curIdentIs("INCLUDE"):
+			p.nextToken()
+			if !p.curTokenIs(lexer.LPAREN) {
+				return fmt.Errorf("expected '(' after INCLUDE, got %s", p.curToken.Literal)
+			}
+			p.nextToken()
+			for !p.curTokenIs(lexer.RPAREN) {
+				if !p.curTokenIs(lexer.IDENT) {
+					return fmt.Errorf("expected column name, got %s", p.curToken.Literal)
+				}
+				stmt.Include = append(stmt.Include, p.curToken.Literal)
+				p.nextToken()
+				if p.curTokenIs(lexer.COMMA) {
+					p.nextToken()
+				}
+			}
+			p.nextToken() // consume )
+
+		case p.curTokenIs(lexer.WITH) && p.peekTokenIs(lexer.LPAREN):
+			p.nextToken()
+			p.nextToken()
+			for !p.curTokenIs(lexer.RPAREN) {
+				if p.curTokenIs(lexer.EOF) {
+					return fmt.Errorf("expected ')' to close index options")
+				}
+				name := strings.ToUpper(p.curToken.Literal)
+				p.setIndexOption(stmt, name, p.parseOptionValue())
+
+				// Skip nested arguments such as ONLINE = ON (WAIT_AT_LOW_PRIORITY (...))
+				if p.curTokenIs(lexer.LPAREN) {
+					depth := 0
+					for {
+						if p.curTokenIs(lexer.EOF) {
+							return fmt.Errorf("expected ')' to close index options")
+						}
+						if p.curTokenIs(lexer.LPAREN) {
+							depth++
+						} else if p.curTokenIs(lexer.RPAREN) {
+							depth--
+						}
+						p.nextToken()
+						if depth == 0 {
+							break
+						}
+					}
+				}
+
+				if p.curTokenIs(lexer.COMMA) {
+					p.nextToken()
+				}
+			}
+			p.nextToken() // consume )
+
+		case p.curTokenIs(lexer.WHERE):
+			p.nextToken()
+			where, err := p.parseExpression()
+			if err != nil {
+				return fmt.Errorf("failed to parse index predicate: %w", err)
+			}
+			stmt.Where = where
+
+		case p.curIdentIs("ALGORITHM"), p.curIdentIs("LOCK"):
+			name := strings.ToUpper(p.curToken.Literal)
+			p.setIndexOption(stmt, name, p.parseOptionValue())
+
+		default:
+			return nil
+		}
+	}
+}
+
+// setIndexOption records an index option
+func (p *Parser) setIndexOption(stmt *CreateIndexStatement, name, value string) {
+	if stmt.Options == nil {
+		stmt.Options = make(map[string]string)
+	}
+	stmt.Options[name] = value
 }
 
 // parseCreateViewStatement parses CREATE VIEW and CREATE MATERIALIZED VIEW statements
@@ -755,27 +1225,11 @@ func (p *Parser) parseCreateViewStatement() (*CreateViewStatement, error) {
 	// We need a simpler approach than parseTableReference because AS is coming
 	viewName := TableReference{}
 
-	// Check for schema.table format
-	if !p.curTokenIs(lexer.IDENT) {
-		return nil, fmt.Errorf("expected view name, got %s", p.curToken.Literal)
-	}
-
-	firstPart := p.curToken.Literal
-	p.nextToken()
-
-	if p.curTokenIs(lexer.DOT) {
-		// schema.view_name
-		viewName.Schema = firstPart
-		p.nextToken()
-		if !p.curTokenIs(lexer.IDENT) {
-			return nil, fmt.Errorf("expected view name after schema, got %s", p.curToken.Literal)
-		}
-		viewName.Name = p.curToken.Literal
-		p.nextToken()
-	} else {
-		// Just view_name
-		viewName.Name = firstPart
+	parts, err := p.parseQualifiedName("view")
+	if err != nil {
+		return nil, err
 	}
+	viewName.SetQualifiedName(parts)
 
 	stmt.ViewName = viewName
 
@@ -990,23 +1444,12 @@ func (p *Parser) parseCreateTriggerStatement() (*CreateTriggerStatement, error)
 		p.nextToken()
 	}
 
-	// Parse trigger body
-	// For now, we'll handle BEGIN...END blocks simply
+	// Parse trigger body: a BEGIN...END block like a procedure's
 	if p.curTokenIs(lexer.BEGIN) {
-		// Skip to END for now - full body parsing would be more complex
-		body := &ProcedureBody{}
-		p.nextToken()
-
-		// Consume tokens until we find END
-		for !p.curTokenIs(lexer.END) && !p.curTokenIs(lexer.EOF) {
-			p.nextToken()
-		}
-
-		if !p.curTokenIs(lexer.END) {
-			return nil, fmt.Errorf("expected END for trigger body, got %s", p.curToken.Literal)
+		body, err := p.parseProcedureBody()
+		if err != nil {
+			return nil, err
 		}
-		p.nextToken()
-
 		stmt.Body = body
 	}
 
@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+)
+
+// wordOperators are infix operators the lexer leaves as identifiers
+var wordOperators = map[string]bool{
+	"ILIKE":  true,
+	"REGEXP": true,
+	"RLIKE":  true,
+	"GLOB":   true,
+}
+
+// Warnings returns problems that don't stop parsing, such as a built-in
+// function that needs a newer release of the dialect
+func (p *Parser) Warnings() []string {
+	return p.warnings
+}
+
+func (p *Parser) warn(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+// isDialectOperator reports whether the current token is an operator
+// outside the core set: ||, ->>, ILIKE ...
+func (p *Parser) isDialectOperator() bool {
+	return p.curTokenIs(lexer.OPERATOR) ||
+		(p.curTokenIs(lexer.IDENT) && wordOperators[strings.ToUpper(p.curToken.Literal)])
+}
+
+// requireOperator returns an error if the parser's dialect, at its
+// version, lacks an operator
+func (p *Parser) requireOperator(operator string) error {
+	if p.dialect == nil || p.dialect.SupportsOperator(operator) {
+		return nil
+	}
+	if since, ok := p.dialect.GetSpec().Operators[strings.ToUpper(operator)]; ok {
+		return fmt.Errorf("operator %s requires %s %s or later, got %s", operator, p.dialect.Name(), since, p.dialect.GetVersion())
+	}
+	return fmt.Errorf("operator %s is not supported by %s", operator, p.dialect.Name())
+}
+
+// checkFunctionCall warns when a call to a built-in function doesn't match
+// the dialect's catalog. Unknown names may be user-defined functions and
+// are not reported.
+func (p *Parser) checkFunctionCall(fn *FunctionCall, windowed bool) {
+	if p.dialect == nil {
+		return
+	}
+	sig, ok := p.dialect.LookupFunction(fn.Name)
+	if !ok {
+		return
+	}
+
+	if version := p.dialect.GetVersion(); !version.AtLeast(sig.Since) {
+		p.warn("%s requires %s %s or later, got %s", sig.Name, p.dialect.Name(), sig.Since, version)
+	}
+	if !sig.AcceptsArgs(len(fn.Arguments)) {
+		p.warn("%s expects %s, got %d", sig.Name, argumentRange(sig.MinArgs, sig.MaxArgs), len(fn.Arguments))
+	}
+	if sig.Kind == dialect.FunctionWindow && !windowed {
+		p.warn("%s requires an OVER clause", sig.Name)
+	}
+}
+
+// argumentRange describes an accepted argument count: "2 arguments",
+// "1 to 3 arguments" or "at least 1 argument"
+func argumentRange(minArgs, maxArgs int) string {
+	plural := func(n int) string {
+		if n == 1 {
+			return "1 argument"
+		}
+		return fmt.Sprintf("%d arguments", n)
+	}
+	switch {
+	case maxArgs < 0:
+		return "at least " + plural(minArgs)
+	case minArgs == maxArgs:
+		return plural(minArgs)
+	default:
+		return fmt.Sprintf("%d to %s", minArgs, plural(maxArgs))
+	}
+}
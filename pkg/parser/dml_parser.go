@@ -0,0 +1,322 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+)
+
+// clauseIdents are clause and join keywords the lexer leaves as
+// identifiers. They end a table reference instead of being taken as an
+// implicit alias.
+var clauseIdents = map[string]bool{
+	"RETURNING":     true,
+	"OUTPUT":        true,
+	"CROSS":         true,
+	"NATURAL":       true,
+	"OUTER":         true,
+	"STRAIGHT_JOIN": true,
+	"WINDOW":        true,
+	"QUALIFY":       true,
+	// Statements starting the next line of a script without semicolons
+	"GO":      true,
+	"PRINT":   true,
+	"EXEC":    true,
+	"EXECUTE": true,
+	"USE":     true,
+}
+
+// isClauseIdent reports whether the current token starts a clause or join
+func (p *Parser) isClauseIdent() bool {
+	return p.curTokenIs(lexer.IDENT) && clauseIdents[strings.ToUpper(p.curToken.Literal)]
+}
+
+// requireFeature returns an error if the parser's dialect, at its version,
+// lacks a feature
+func (p *Parser) requireFeature(feature dialect.Feature, clause string) error {
+	if p.dialect == nil || p.dialect.SupportsFeature(feature) {
+		return nil
+	}
+	if since, ok := p.dialect.GetSpec().Features[feature]; ok {
+		return fmt.Errorf("%s requires %s %s or later, got %s", clause, p.dialect.Name(), since, p.dialect.GetVersion())
+	}
+	return fmt.Errorf("%s is not supported by %s", clause, p.dialect.Name())
+}
+
+// requireUpsertSyntax returns an error unless the dialect upserts with the
+// given syntax
+func (p *Parser) requireUpsertSyntax(syntax dialect.UpsertSyntax, clause string) error {
+	if err := p.requireFeature(dialect.FeatureUpsert, clause); err != nil {
+		return err
+	}
+	if p.dialect != nil && p.dialect.GetUpsertSyntax() != syntax {
+		return fmt.Errorf("%s is not supported by %s", clause, p.dialect.Name())
+	}
+	return nil
+}
+
+// parseAssignments parses col1 = val1, col2 = val2, ...
+func (p *Parser) parseAssignments() ([]*Assignment, error) {
+	var assignments []*Assignment
+
+	for {
+		if !p.curTokenIs(lexer.IDENT) {
+			return nil, fmt.Errorf("expected column name in SET clause, got %s", p.curToken.Literal)
+		}
+
+		assignment := &Assignment{
+			Column: p.curToken.Literal,
+		}
+		p.nextToken()
+
+		// Expect = operator
+		if !p.curTokenIs(lexer.ASSIGN) {
+			return nil, fmt.Errorf("expected '=' after column name, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+
+		// Parse value expression
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value in SET clause: %w", err)
+		}
+		assignment.Value = value
+
+		assignments = append(assignments, assignment)
+
+		// Check for more assignments
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken() // consume comma
+	}
+
+	return assignments, nil
+}
+
+// parseIdentifierList parses (name1, name2, ...)
+func (p *Parser) parseIdentifierList(what string) ([]string, error) {
+	if !p.curTokenIs(lexer.LPAREN) {
+		return nil, fmt.Errorf("expected '(' before %s list, got %s", what, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	var names []string
+	for {
+		if !p.curTokenIs(lexer.IDENT) {
+			return nil, fmt.Errorf("expected %s name, got %s", what, p.curToken.Literal)
+		}
+		names = append(names, p.curToken.Literal)
+		p.nextToken()
+
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' after %s list, got %s", what, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return names, nil
+}
+
+// parseOnConflictClause parses ON CONFLICT [target] DO NOTHING | DO UPDATE SET ...
+func (p *Parser) parseOnConflictClause() (*OnConflictClause, error) {
+	if err := p.requireUpsertSyntax(dialect.UpsertSyntaxOnConflict, "ON CONFLICT"); err != nil {
+		return nil, err
+	}
+	p.nextToken() // consume ON
+	p.nextToken() // consume CONFLICT
+
+	clause := &OnConflictClause{}
+
+	// Optional conflict target: (cols) [WHERE ...] or ON CONSTRAINT name
+	if p.curTokenIs(lexer.LPAREN) {
+		columns, err := p.parseIdentifierList("conflict column")
+		if err != nil {
+			return nil, err
+		}
+		clause.Columns = columns
+
+		if p.curTokenIs(lexer.WHERE) {
+			p.nextToken()
+			where, err := p.parseExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse conflict target WHERE: %w", err)
+			}
+			clause.TargetWhere = where
+		}
+	} else if p.curTokenIs(lexer.ON) {
+		p.nextToken()
+		if !p.curTokenIs(lexer.CONSTRAINT) {
+			return nil, fmt.Errorf("expected CONSTRAINT after ON CONFLICT ON, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		if !p.curTokenIs(lexer.IDENT) {
+			return nil, fmt.Errorf("expected constraint name, got %s", p.curToken.Literal)
+		}
+		clause.Constraint = p.curToken.Literal
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.DO) {
+		return nil, fmt.Errorf("expected DO after ON CONFLICT, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	if p.curIdentIs("NOTHING") {
+		clause.DoNothing = true
+		p.nextToken()
+		return clause, nil
+	}
+
+	if !p.curTokenIs(lexer.UPDATE) {
+		return nil, fmt.Errorf("expected NOTHING or UPDATE after DO, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	if len(clause.Columns) == 0 && clause.Constraint == "" {
+		return nil, fmt.Errorf("ON CONFLICT DO UPDATE requires a conflict target")
+	}
+
+	if !p.curTokenIs(lexer.SET) {
+		return nil, fmt.Errorf("expected SET after DO UPDATE, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	set, err := p.parseAssignments()
+	if err != nil {
+		return nil, err
+	}
+	clause.Set = set
+
+	if p.curTokenIs(lexer.WHERE) {
+		p.nextToken()
+		where, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DO UPDATE WHERE: %w", err)
+		}
+		clause.Where = where
+	}
+
+	return clause, nil
+}
+
+// parseOnDuplicateKeyUpdate parses MySQL's ON DUPLICATE KEY UPDATE assignments
+func (p *Parser) parseOnDuplicateKeyUpdate() ([]*Assignment, error) {
+	if err := p.requireUpsertSyntax(dialect.UpsertSyntaxOnDuplicateKey, "ON DUPLICATE KEY UPDATE"); err != nil {
+		return nil, err
+	}
+	p.nextToken() // consume ON
+	p.nextToken() // consume DUPLICATE
+
+	if !p.curTokenIs(lexer.KEY) {
+		return nil, fmt.Errorf("expected KEY after ON DUPLICATE, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	if !p.curTokenIs(lexer.UPDATE) {
+		return nil, fmt.Errorf("expected UPDATE after ON DUPLICATE KEY, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return p.parseAssignments()
+}
+
+// parseReturningClause parses RETURNING expr [AS alias], ... [INTO var, ...]
+func (p *Parser) parseReturningClause() (*ReturningClause, error) {
+	if err := p.requireFeature(dialect.FeatureReturningClause, "RETURNING"); err != nil {
+		return nil, err
+	}
+	p.nextToken() // consume RETURNING
+
+	columns, err := p.parseSelectList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse RETURNING list: %w", err)
+	}
+	clause := &ReturningClause{Columns: columns}
+
+	// Oracle: RETURNING ... INTO variables or :bind parameters
+	if p.curTokenIs(lexer.INTO) {
+		p.nextToken()
+		for {
+			if !p.curTokenIs(lexer.IDENT) && !p.curTokenIs(lexer.PARAM) {
+				return nil, fmt.Errorf("expected variable after RETURNING INTO, got %s", p.curToken.Literal)
+			}
+			clause.Into = append(clause.Into, p.curToken.Literal)
+			p.nextToken()
+
+			if !p.curTokenIs(lexer.COMMA) {
+				break
+			}
+			p.nextToken()
+		}
+	}
+
+	return clause, nil
+}
+
+// parseOutputClause parses SQL Server's OUTPUT inserted.col, ... [INTO table [(cols)]]
+func (p *Parser) parseOutputClause() (*OutputClause, error) {
+	if err := p.requireFeature(dialect.FeatureOutputClause, "OUTPUT"); err != nil {
+		return nil, err
+	}
+	p.nextToken() // consume OUTPUT
+
+	columns, err := p.parseSelectList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OUTPUT list: %w", err)
+	}
+	clause := &OutputClause{Columns: columns}
+
+	if p.curTokenIs(lexer.INTO) {
+		p.nextToken()
+		parts, err := p.parseQualifiedName("OUTPUT INTO table")
+		if err != nil {
+			return nil, err
+		}
+		clause.Into = &TableReference{}
+		clause.Into.SetQualifiedName(parts)
+
+		if p.curTokenIs(lexer.LPAREN) {
+			intoColumns, err := p.parseIdentifierList("column")
+			if err != nil {
+				return nil, err
+			}
+			clause.IntoColumns = intoColumns
+		}
+	}
+
+	return clause, nil
+}
+
+// parseUpsertClause parses ON CONFLICT or ON DUPLICATE KEY UPDATE, if present
+func (p *Parser) parseUpsertClause(stmt *InsertStatement) error {
+	if !p.curTokenIs(lexer.ON) {
+		return nil
+	}
+
+	switch {
+	case p.peekIdentIs("CONFLICT"):
+		clause, err := p.parseOnConflictClause()
+		if err != nil {
+			return err
+		}
+		stmt.OnConflict = clause
+	case p.peekIdentIs("DUPLICATE"):
+		assignments, err := p.parseOnDuplicateKeyUpdate()
+		if err != nil {
+			return err
+		}
+		stmt.OnDuplicateKeyUpdate = assignments
+	default:
+		return fmt.Errorf("expected CONFLICT or DUPLICATE after ON, got %s", p.peekToken.Literal)
+	}
+
+	return nil
+}
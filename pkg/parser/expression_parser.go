@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+)
+
+// Binding powers of the infix and prefix operators, loosest first
+const (
+	precLowest = iota
+	precOr
+	precAnd
+	precNot
+	precComparison // = <> < > <= >= LIKE IN IS BETWEEN
+	precOperator   // ||, ->, @> and other dialect operators
+	precSum        // + -
+	precProduct    // * / %
+	precUnary      // unary - and +
+)
+
+// comparisonOperators are word and dialect operators that bind like =
+var comparisonOperators = map[string]bool{
+	"ILIKE":  true,
+	"REGEXP": true,
+	"RLIKE":  true,
+	"GLOB":   true,
+	"<=>":    true,
+}
+
+func (p *Parser) parseExpression() (Expression, error) {
+	return p.parseExpressionWithPrecedence(precLowest)
+}
+
+// parseExpressionWithPrecedence parses an expression whose infix operators
+// bind tighter than prec
+func (p *Parser) parseExpressionWithPrecedence(prec int) (Expression, error) {
+	start := p.curToken.Position
+	left, err := p.parsePrefixExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		opPrec := p.infixPrecedence()
+		if opPrec <= prec {
+			return left, nil
+		}
+		if left, err = p.parseInfix(left, opPrec); err != nil {
+			return nil, err
+		}
+		p.recordSpan(left, start)
+	}
+}
+
+// parsePrefixExpression parses an operand, optionally preceded by NOT or
+// a sign
+func (p *Parser) parsePrefixExpression() (Expression, error) {
+	start := p.curToken.Position
+
+	var operator string
+	var prec int
+	switch {
+	case p.curTokenIs(lexer.NOT) && !p.peekTokenIs(lexer.EXISTS):
+		operator, prec = "NOT", precNot
+	case p.curTokenIs(lexer.MINUS), p.curTokenIs(lexer.PLUS):
+		operator, prec = p.curToken.Literal, precUnary
+	default:
+		expr, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		p.recordSpan(expr, start)
+		return expr, nil
+	}
+
+	p.nextToken()
+	operand, err := p.parseExpressionWithPrecedence(prec)
+	if err != nil {
+		return nil, err
+	}
+	expr := &UnaryExpression{Operator: operator, Operand: operand}
+	p.recordSpan(expr, start)
+	return expr, nil
+}
+
+// infixPrecedence returns the binding power of the operator at the current
+// token, or precLowest when it doesn't continue an expression
+func (p *Parser) infixPrecedence() int {
+	switch p.curToken.Type {
+	case lexer.OR:
+		return precOr
+	case lexer.AND:
+		return precAnd
+	case lexer.ASSIGN, lexer.EQ, lexer.NOT_EQ, lexer.LT, lexer.GT, lexer.LTE, lexer.GTE,
+		lexer.LIKE, lexer.IN, lexer.IS, lexer.BETWEEN:
+		return precComparison
+	case lexer.NOT:
+		// NOT IN, NOT LIKE, NOT BETWEEN, NOT ILIKE ...
+		switch p.peekToken.Type {
+		case lexer.IN, lexer.LIKE, lexer.BETWEEN:
+			return precComparison
+		case lexer.IDENT:
+			if wordOperators[strings.ToUpper(p.peekToken.Literal)] {
+				return precComparison
+			}
+		}
+		return precLowest
+	case lexer.PLUS, lexer.MINUS:
+		return precSum
+	case lexer.ASTERISK, lexer.SLASH, lexer.PERCENT:
+		return precProduct
+	}
+
+	if p.isDialectOperator() {
+		if comparisonOperators[strings.ToUpper(p.curToken.Literal)] {
+			return precComparison
+		}
+		return precOperator
+	}
+	return precLowest
+}
+
+// parseInfix parses the operator at the current token and its right-hand
+// side, which binds tighter than prec
+func (p *Parser) parseInfix(left Expression, prec int) (Expression, error) {
+	not := false
+	if p.curTokenIs(lexer.NOT) {
+		not = true
+		p.nextToken()
+	}
+
+	switch p.curToken.Type {
+	case lexer.IN:
+		return p.parseInExpression(left, not)
+	case lexer.BETWEEN:
+		return p.parseBetweenExpression(left, not)
+	case lexer.IS:
+		return p.parseIsExpression(left)
+	}
+
+	operator := p.curToken.Literal
+	if p.isDialectOperator() {
+		operator = strings.ToUpper(operator)
+		if err := p.requireOperator(operator); err != nil {
+			return nil, err
+		}
+	} else if p.curTokenIs(lexer.LIKE) {
+		operator = "LIKE"
+	}
+	if not {
+		operator = "NOT " + operator
+	}
+	p.nextToken()
+
+	right, err := p.parseExpressionWithPrecedence(prec)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := GetBinaryExpression() // Use object pool
+	expr.Left = left
+	expr.Operator = operator
+	expr.Right = right
+	return expr, nil
+}
+
+// parseIsExpression parses IS [NOT] NULL/TRUE/FALSE/UNKNOWN and
+// IS [NOT] DISTINCT FROM
+func (p *Parser) parseIsExpression(left Expression) (Expression, error) {
+	operator := "IS"
+	p.nextToken()
+	if p.curTokenIs(lexer.NOT) {
+		operator = "IS NOT"
+		p.nextToken()
+	}
+
+	var right Expression
+	var err error
+	if p.curTokenIs(lexer.DISTINCT) {
+		p.nextToken()
+		if !p.curTokenIs(lexer.FROM) {
+			return nil, fmt.Errorf("expected FROM after IS DISTINCT, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		operator += " DISTINCT FROM"
+		right, err = p.parseExpressionWithPrecedence(precComparison)
+	} else {
+		right, err = p.parsePrefixExpression()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expr := GetBinaryExpression()
+	expr.Left = left
+	expr.Operator = operator
+	expr.Right = right
+	return expr, nil
+}
+
+// parseBetweenExpression parses [NOT] BETWEEN low AND high
+func (p *Parser) parseBetweenExpression(left Expression, not bool) (Expression, error) {
+	p.nextToken()
+	low, err := p.parseExpressionWithPrecedence(precComparison)
+	if err != nil {
+		return nil, err
+	}
+	if !p.curTokenIs(lexer.AND) {
+		return nil, fmt.Errorf("expected AND in BETWEEN, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+	high, err := p.parseExpressionWithPrecedence(precComparison)
+	if err != nil {
+		return nil, err
+	}
+	return &BetweenExpression{Expression: left, Low: low, High: high, Not: not}, nil
+}
@@ -0,0 +1,379 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+)
+
+// intervalUnits are the units accepted after an INTERVAL value
+var intervalUnits = map[string]bool{
+	"MICROSECOND": true, "MILLISECOND": true, "SECOND": true, "MINUTE": true,
+	"HOUR": true, "DAY": true, "WEEK": true, "MONTH": true, "QUARTER": true, "YEAR": true,
+	// MySQL composite units
+	"SECOND_MICROSECOND": true, "MINUTE_MICROSECOND": true, "MINUTE_SECOND": true,
+	"HOUR_MICROSECOND": true, "HOUR_SECOND": true, "HOUR_MINUTE": true,
+	"DAY_MICROSECOND": true, "DAY_SECOND": true, "DAY_MINUTE": true, "DAY_HOUR": true,
+	"YEAR_MONTH": true,
+}
+
+// parseOperand parses a primary expression followed by any PostgreSQL
+// expr::type casts
+func (p *Parser) parseOperand() (Expression, error) {
+	expr, err := p.parsePrimaryExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.curTokenIs(lexer.DOUBLE_COLON) {
+		if err := p.requireOperator("::"); err != nil {
+			return nil, err
+		}
+		p.nextToken()
+		dataType, err := p.parseCastType(false)
+		if err != nil {
+			return nil, err
+		}
+		expr = &CastExpression{Expression: expr, DataType: dataType, Syntax: "::"}
+	}
+
+	return expr, nil
+}
+
+// parseSpecialFunction parses functions whose arguments aren't a plain
+// comma-separated list, falling back to parseFunctionCall
+func (p *Parser) parseSpecialFunction(name string) (Expression, error) {
+	switch strings.ToUpper(name) {
+	case "CAST", "TRY_CAST":
+		return p.parseCastExpression(name)
+	case "CONVERT", "TRY_CONVERT":
+		if p.dialect != nil && (p.dialect.Name() == "SQL Server" || p.dialect.Name() == "MySQL") {
+			return p.parseConvertExpression(name)
+		}
+	case "EXTRACT":
+		return p.parseExtractExpression()
+	case "SUBSTRING":
+		return p.parseSubstringCall(name)
+	case "TRIM":
+		return p.parseTrimExpression()
+	}
+	return p.parseFunctionCall(name)
+}
+
+// parseCastType parses a cast target type. Unlike parseDataType it accepts
+// WITH/WITHOUT TIME ZONE and, inside parentheses where an alias can't
+// follow, multi-word names (DOUBLE PRECISION, CHARACTER VARYING(10),
+// UNSIGNED INTEGER).
+func (p *Parser) parseCastType(multiWord bool) (*DataTypeDefinition, error) {
+	var words []string
+	for multiWord && p.curTokenIs(lexer.IDENT) && p.peekTokenIs(lexer.IDENT) && !p.peekIdentIs("WITHOUT") && !p.peekIdentIs("ARRAY") {
+		words = append(words, p.curToken.Literal)
+		p.nextToken()
+	}
+
+	dataType, err := p.parseDataType()
+	if err != nil {
+		return nil, err
+	}
+	if len(words) > 0 {
+		dataType.Name = strings.Join(append(words, dataType.Name), " ")
+	}
+
+	if (p.curTokenIs(lexer.WITH) || p.curIdentIs("WITHOUT")) && p.peekIdentIs("TIME") {
+		suffix := strings.ToUpper(p.curToken.Literal)
+		p.nextToken()
+		p.nextToken()
+		if !p.curIdentIs("ZONE") {
+			return nil, fmt.Errorf("expected ZONE after %s TIME, got %s", suffix, p.curToken.Literal)
+		}
+		p.nextToken()
+		dataType.Name += " " + suffix + " TIME ZONE"
+	}
+
+	// PostgreSQL arrays: int[], int[3][], and inside parentheses int ARRAY[3]
+	if p.curTokenIs(lexer.LBRACKET) || (multiWord && p.curIdentIs("ARRAY")) {
+		dataType.IsArray = true
+		if p.curIdentIs("ARRAY") {
+			p.nextToken()
+		}
+		for p.curTokenIs(lexer.LBRACKET) {
+			p.nextToken()
+			if p.curTokenIs(lexer.NUMBER) {
+				p.nextToken()
+			}
+			if !p.curTokenIs(lexer.RBRACKET) {
+				return nil, fmt.Errorf("expected ] in array type, got %s", p.curToken.Literal)
+			}
+			p.nextToken()
+		}
+	}
+
+	return dataType, nil
+}
+
+// parseCastExpression parses CAST(expr AS type) and TRY_CAST(expr AS type)
+func (p *Parser) parseCastExpression(name string) (Expression, error) {
+	p.nextToken() // consume (
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s expression: %w", name, err)
+	}
+
+	if !p.curTokenIs(lexer.AS) {
+		return nil, fmt.Errorf("expected AS in %s, got %s", name, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	dataType, err := p.parseCastType(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' to close %s, got %s", name, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return &CastExpression{
+		Expression: expr,
+		DataType:   dataType,
+		Safe:       strings.HasPrefix(strings.ToUpper(name), "TRY_"),
+		Syntax:     "CAST",
+	}, nil
+}
+
+// parseConvertExpression parses SQL Server's CONVERT(type, expr [, style])
+// and MySQL's CONVERT(expr, type) and CONVERT(expr USING charset)
+func (p *Parser) parseConvertExpression(name string) (Expression, error) {
+	p.nextToken() // consume (
+
+	cast := &CastExpression{
+		Safe:   strings.HasPrefix(strings.ToUpper(name), "TRY_"),
+		Syntax: "CONVERT",
+	}
+
+	if p.dialect.Name() == "SQL Server" {
+		dataType, err := p.parseCastType(true)
+		if err != nil {
+			return nil, err
+		}
+		cast.DataType = dataType
+		cast.TypeFirst = true
+
+		if !p.curTokenIs(lexer.COMMA) {
+			return nil, fmt.Errorf("expected ',' after %s type, got %s", name, p.curToken.Literal)
+		}
+		p.nextToken()
+
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s expression: %w", name, err)
+		}
+		cast.Expression = expr
+
+		if p.curTokenIs(lexer.COMMA) {
+			p.nextToken()
+			style, err := p.parseExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s style: %w", name, err)
+			}
+			cast.Style = style
+		}
+	} else {
+		if cast.Safe {
+			return nil, fmt.Errorf("%s is not supported by %s", strings.ToUpper(name), p.dialect.Name())
+		}
+
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s expression: %w", name, err)
+		}
+		cast.Expression = expr
+
+		switch {
+		case p.curTokenIs(lexer.COMMA):
+			p.nextToken()
+			dataType, err := p.parseCastType(true)
+			if err != nil {
+				return nil, err
+			}
+			cast.DataType = dataType
+		case p.curTokenIs(lexer.USING):
+			p.nextToken()
+			if !p.curTokenIs(lexer.IDENT) {
+				return nil, fmt.Errorf("expected character set after USING, got %s", p.curToken.Literal)
+			}
+			cast.Charset = p.curToken.Literal
+			p.nextToken()
+		default:
+			return nil, fmt.Errorf("expected ',' or USING in %s, got %s", name, p.curToken.Literal)
+		}
+	}
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' to close %s, got %s", name, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return cast, nil
+}
+
+// parseExtractExpression parses EXTRACT(field FROM source)
+func (p *Parser) parseExtractExpression() (Expression, error) {
+	p.nextToken() // consume (
+
+	if !p.curTokenIs(lexer.IDENT) && !p.curTokenIs(lexer.STRING) {
+		return nil, fmt.Errorf("expected field name in EXTRACT, got %s", p.curToken.Literal)
+	}
+	extract := &ExtractExpression{Field: strings.ToUpper(p.curToken.Literal)}
+	p.nextToken()
+
+	if !p.curTokenIs(lexer.FROM) {
+		return nil, fmt.Errorf("expected FROM in EXTRACT, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	source, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EXTRACT source: %w", err)
+	}
+	extract.Source = source
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' to close EXTRACT, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return extract, nil
+}
+
+// parseSubstringCall parses SUBSTRING(s FROM start [FOR length]) as well as
+// the comma form. Both produce a FunctionCall with positional arguments.
+func (p *Parser) parseSubstringCall(name string) (Expression, error) {
+	if p.peekTokenIs(lexer.RPAREN) {
+		return p.parseFunctionCall(name)
+	}
+	p.nextToken() // consume (
+
+	source, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	funcCall := &FunctionCall{Name: name, Arguments: []Expression{source}}
+
+	if p.curTokenIs(lexer.FROM) || p.curTokenIs(lexer.FOR) {
+		var start Expression = &Literal{Value: int64(1)}
+		if p.curTokenIs(lexer.FROM) {
+			p.nextToken()
+			if start, err = p.parseExpression(); err != nil {
+				return nil, fmt.Errorf("failed to parse SUBSTRING start: %w", err)
+			}
+		}
+		funcCall.Arguments = append(funcCall.Arguments, start)
+
+		if p.curTokenIs(lexer.FOR) {
+			p.nextToken()
+			length, err := p.parseExpression()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse SUBSTRING length: %w", err)
+			}
+			funcCall.Arguments = append(funcCall.Arguments, length)
+		}
+	} else {
+		for p.curTokenIs(lexer.COMMA) {
+			p.nextToken()
+			arg, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			funcCall.Arguments = append(funcCall.Arguments, arg)
+		}
+	}
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' to close %s, got %s", name, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return funcCall, nil
+}
+
+// parseTrimExpression parses TRIM([LEADING | TRAILING | BOTH] [chars] FROM s),
+// TRIM(s) and SQLite's TRIM(s, chars)
+func (p *Parser) parseTrimExpression() (Expression, error) {
+	p.nextToken() // consume (
+
+	trim := &TrimExpression{}
+	if p.curIdentIs("LEADING") || p.curIdentIs("TRAILING") || p.curIdentIs("BOTH") {
+		trim.Position = strings.ToUpper(p.curToken.Literal)
+		p.nextToken()
+	}
+
+	if p.curTokenIs(lexer.FROM) {
+		// TRIM(LEADING FROM s)
+		p.nextToken()
+	} else {
+		first, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TRIM argument: %w", err)
+		}
+
+		switch {
+		case p.curTokenIs(lexer.FROM):
+			trim.Characters = first
+			p.nextToken()
+		case trim.Position != "":
+			return nil, fmt.Errorf("expected FROM after TRIM %s, got %s", trim.Position, p.curToken.Literal)
+		default:
+			trim.Source = first
+			if p.curTokenIs(lexer.COMMA) {
+				p.nextToken()
+				chars, err := p.parseExpression()
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse TRIM characters: %w", err)
+				}
+				trim.Characters = chars
+			}
+		}
+	}
+
+	if trim.Source == nil {
+		source, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TRIM source: %w", err)
+		}
+		trim.Source = source
+	}
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' to close TRIM, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return trim, nil
+}
+
+// parseIntervalExpression parses the value and optional unit after INTERVAL:
+// '1 day', '1' DAY, 1 DAY, '1-2' YEAR TO MONTH
+func (p *Parser) parseIntervalExpression() (Expression, error) {
+	value, err := p.parsePrimaryExpression()
+	if err != nil {
+		return nil, err
+	}
+	interval := &IntervalExpression{Value: value}
+
+	if p.curTokenIs(lexer.IDENT) && intervalUnits[strings.ToUpper(p.curToken.Literal)] {
+		interval.Unit = strings.ToUpper(p.curToken.Literal)
+		p.nextToken()
+
+		if p.curIdentIs("TO") && p.peekToken.Type == lexer.IDENT && intervalUnits[strings.ToUpper(p.peekToken.Literal)] {
+			p.nextToken()
+			interval.Unit += " TO " + strings.ToUpper(p.curToken.Literal)
+			p.nextToken()
+		}
+	}
+
+	return interval, nil
+}
@@ -0,0 +1,218 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+)
+
+// Comments returns the comments skipped while lexing, in source order
+func (p *Parser) Comments() []lexer.Comment {
+	return p.l.Comments()
+}
+
+// parseCommentHints parses the /*+ ... */ hint following SELECT, INSERT,
+// UPDATE or DELETE, if any. Like Oracle and MySQL, it ignores text it
+// cannot read as a hint rather than failing the statement.
+func (p *Parser) parseCommentHints() []*Hint {
+	if !p.curTokenIs(lexer.HINT) {
+		return nil
+	}
+	text := p.curToken.Literal
+	p.nextToken()
+
+	var tokens []lexer.Token
+	l := lexer.NewWithDialect(text, p.dialect)
+	for tok := l.NextToken(); tok.Type != lexer.EOF; tok = l.NextToken() {
+		tokens = append(tokens, tok)
+	}
+
+	var hints []*Hint
+	for i := 0; i < len(tokens); i++ {
+		if !isHintWord(tokens[i]) {
+			continue
+		}
+		hint := &Hint{Name: strings.ToUpper(tokens[i].Literal)}
+		if i+1 < len(tokens) && tokens[i+1].Type == lexer.LPAREN {
+			depth := 0
+			for i++; i < len(tokens); i++ {
+				switch tokens[i].Type {
+				case lexer.LPAREN:
+					depth++
+					if depth == 1 {
+						continue
+					}
+				case lexer.RPAREN:
+					depth--
+				case lexer.COMMA:
+					continue
+				}
+				if depth == 0 {
+					break
+				}
+				hint.Args = append(hint.Args, tokens[i].Literal)
+			}
+		}
+		hints = append(hints, hint)
+	}
+
+	return hints
+}
+
+// parseQueryHints parses SQL Server OPTION (MAXDOP 4, RECOMPILE, HASH JOIN)
+func (p *Parser) parseQueryHints() ([]*Hint, error) {
+	if !p.curTokenIs(lexer.OPTION) {
+		return nil, fmt.Errorf("expected OPTION, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	hints, err := p.parseHintList("OPTION")
+	if err != nil {
+		return nil, err
+	}
+	if len(hints) == 0 {
+		return nil, fmt.Errorf("OPTION requires at least one query hint")
+	}
+	return hints, nil
+}
+
+// isTableHintStart reports whether the current token starts a table hint:
+// SQL Server WITH (NOLOCK) or MySQL USE|FORCE|IGNORE INDEX|KEY (...)
+func (p *Parser) isTableHintStart() bool {
+	if p.curTokenIs(lexer.WITH) {
+		return p.peekTokenIs(lexer.LPAREN)
+	}
+	return (p.curIdentIs("USE") || p.curIdentIs("FORCE") || p.curIdentIs("IGNORE")) &&
+		(p.peekTokenIs(lexer.INDEX) || p.peekTokenIs(lexer.KEY))
+}
+
+// parseTableHints parses the table hints following a table reference
+func (p *Parser) parseTableHints() ([]*Hint, error) {
+	var hints []*Hint
+
+	for p.isTableHintStart() {
+		if p.curTokenIs(lexer.WITH) {
+			p.nextToken()
+			withHints, err := p.parseHintList("table hint")
+			if err != nil {
+				return nil, err
+			}
+			hints = append(hints, withHints...)
+			continue
+		}
+
+		// MySQL: USE INDEX [FOR JOIN|ORDER BY|GROUP BY] (idx, ...)
+		hint := &Hint{Name: strings.ToUpper(p.curToken.Literal) + " INDEX"}
+		p.nextToken()
+		p.nextToken()
+		if p.curTokenIs(lexer.FOR) {
+			p.nextToken()
+			words := []string{strings.ToUpper(p.curToken.Literal)}
+			p.nextToken()
+			if p.curTokenIs(lexer.BY) {
+				words = append(words, "BY")
+				p.nextToken()
+			}
+			hint.Name += " FOR " + strings.Join(words, " ")
+		}
+		if !p.curTokenIs(lexer.LPAREN) {
+			return nil, fmt.Errorf("expected '(' after %s, got %s", hint.Name, p.curToken.Literal)
+		}
+		p.nextToken()
+		for !p.curTokenIs(lexer.RPAREN) {
+			if !p.curTokenIs(lexer.IDENT) && !p.curTokenIs(lexer.PRIMARY) {
+				return nil, fmt.Errorf("expected index name in %s, got %s", hint.Name, p.curToken.Literal)
+			}
+			hint.Args = append(hint.Args, p.curToken.Literal)
+			p.nextToken()
+			if p.curTokenIs(lexer.COMMA) {
+				p.nextToken()
+			}
+		}
+		p.nextToken()
+		hints = append(hints, hint)
+	}
+
+	return hints, nil
+}
+
+// parseHintList parses a parenthesized, comma-separated list of hints. A
+// hint is one or more words followed by (args), = value or a number:
+// NOLOCK, INDEX(ix_status), HASH JOIN, MAXDOP 4, OPTIMIZE FOR (@p = 1)
+func (p *Parser) parseHintList(what string) ([]*Hint, error) {
+	if !p.curTokenIs(lexer.LPAREN) {
+		return nil, fmt.Errorf("expected '(' after %s, got %s", what, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	var hints []*Hint
+	for !p.curTokenIs(lexer.RPAREN) {
+		var words []string
+		for isHintWord(p.curToken) {
+			words = append(words, strings.ToUpper(p.curToken.Literal))
+			p.nextToken()
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("expected %s, got %s", what, p.curToken.Literal)
+		}
+		hint := &Hint{Name: strings.Join(words, " ")}
+
+		switch {
+		case p.curTokenIs(lexer.LPAREN):
+			depth := 1
+			for p.nextToken(); depth > 0; p.nextToken() {
+				switch p.curToken.Type {
+				case lexer.EOF:
+					return nil, fmt.Errorf("expected ')' to close %s, got EOF", hint.Name)
+				case lexer.LPAREN:
+					depth++
+				case lexer.RPAREN:
+					depth--
+				}
+				if depth > 0 && !p.curTokenIs(lexer.COMMA) {
+					hint.Args = append(hint.Args, p.curToken.Literal)
+				}
+			}
+		case p.curTokenIs(lexer.EQ):
+			p.nextToken()
+			hint.Args = append(hint.Args, p.curToken.Literal)
+			p.nextToken()
+		case p.curTokenIs(lexer.NUMBER), p.curTokenIs(lexer.PARAM):
+			hint.Args = append(hint.Args, p.curToken.Literal)
+			p.nextToken()
+		}
+		hints = append(hints, hint)
+
+		if p.curTokenIs(lexer.COMMA) {
+			p.nextToken()
+		} else if !p.curTokenIs(lexer.RPAREN) {
+			return nil, fmt.Errorf("expected ',' or ')' in %s, got %s", what, p.curToken.Literal)
+		}
+	}
+	p.nextToken()
+
+	return hints, nil
+}
+
+// isHintWord reports whether tok can name a hint: an identifier or a
+// keyword such as INDEX, FULL or JOIN
+func isHintWord(tok lexer.Token) bool {
+	switch tok.Type {
+	case lexer.IDENT:
+		return true
+	case lexer.EOF, lexer.ILLEGAL, lexer.NUMBER, lexer.STRING, lexer.PARAM, lexer.HINT:
+		return false
+	}
+	lit := tok.Literal
+	if lit == "" {
+		return false
+	}
+	for i := 0; i < len(lit); i++ {
+		c := lit[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,7 @@ package parser
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"strings"
@@ -23,13 +24,17 @@ type Parser struct {
 	curToken  lexer.Token
 	peekToken lexer.Token
 
-	errors []string
+	errors   []string
+	warnings []string
 
 	parseStartTime time.Time
 	tokenCount     int
 
 	ctx     context.Context
 	dialect dialect.Dialect
+
+	prevEnd int           // end offset of the last token consumed
+	spans   map[Node]Span // nil unless EnableSpans was called
 }
 
 func New(input string) *Parser {
@@ -61,9 +66,14 @@ func NewWithDialect(ctx context.Context, input string, d dialect.Dialect) *Parse
 func (p *Parser) nextToken() {
 	select {
 	case <-p.ctx.Done():
-		p.errors = append(p.errors, "parsing cancelled due to timeout")
-		return
+		// Jump to EOF, so that every loop over the tokens ends
+		if !p.curTokenIs(lexer.EOF) || !p.peekTokenIs(lexer.EOF) {
+			p.errors = append(p.errors, "parsing cancelled due to timeout")
+			p.curToken = lexer.Token{Type: lexer.EOF, Position: p.curToken.Position, End: p.curToken.Position}
+			p.peekToken = p.curToken
+		}
 	default:
+		p.prevEnd = p.curToken.End
 		p.curToken = p.peekToken
 		p.peekToken = p.l.NextToken()
 		p.tokenCount++
@@ -102,6 +112,18 @@ func (p *Parser) peekTokenIs(t lexer.TokenType) bool {
 	return p.peekToken.Type == t
 }
 
+// curIdentIs reports whether the current token is the given non-reserved
+// keyword (case-insensitive)
+func (p *Parser) curIdentIs(word string) bool {
+	return p.curToken.Type == lexer.IDENT && strings.EqualFold(p.curToken.Literal, word)
+}
+
+// peekIdentIs reports whether the next token is the given non-reserved
+// keyword (case-insensitive)
+func (p *Parser) peekIdentIs(word string) bool {
+	return p.peekToken.Type == lexer.IDENT && strings.EqualFold(p.peekToken.Literal, word)
+}
+
 func (p *Parser) expectPeek(t lexer.TokenType) bool {
 	if p.peekTokenIs(t) {
 		p.nextToken()
@@ -127,6 +149,19 @@ func (p *Parser) ParseStatement() (Statement, error) {
 		return nil, fmt.Errorf("parsing cancelled: %w", err)
 	}
 
+	start := p.curToken.Position
+	stmt, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+	if p.curTokenIs(lexer.ILLEGAL) && strings.HasPrefix(p.curToken.Literal, "/*") {
+		return nil, fmt.Errorf("unterminated comment at line %d", p.curToken.Line)
+	}
+	p.recordSpan(stmt, start)
+	return stmt, nil
+}
+
+func (p *Parser) parseStatement() (Statement, error) {
 	switch p.curToken.Type {
 	case lexer.WITH:
 		return p.parseWithStatement()
@@ -137,7 +172,7 @@ func (p *Parser) ParseStatement() (Statement, error) {
 		}
 		// Check for set operations (UNION, INTERSECT, EXCEPT)
 		return p.parseSetOperation(stmt)
-	case lexer.INSERT:
+	case lexer.INSERT, lexer.REPLACE:
 		return p.parseInsertStatement()
 	case lexer.UPDATE:
 		return p.parseUpdateStatement()
@@ -179,13 +214,95 @@ func (p *Parser) ParseStatement() (Statement, error) {
 		return p.parseForStatement()
 	case lexer.REPEAT:
 		return p.parseRepeatStatement()
+	case lexer.DECLARE:
+		return p.parseDeclareStatement()
+	case lexer.SET:
+		return p.parseSetStatement()
+	case lexer.IDENT:
+		if p.isBatchCommand() {
+			return p.parseBatchCommand()
+		}
+		return nil, fmt.Errorf("unsupported statement type: %s", p.curToken.Literal)
 	default:
 		return nil, fmt.Errorf("unsupported statement type: %s", p.curToken.Literal)
 	}
 }
 
+// ParseStatements parses a script of statements separated by semicolons,
+// or by line breaks and GO in SQL Server scripts
+func (p *Parser) ParseStatements() ([]Statement, error) {
+	var statements []Statement
+	for {
+		for p.curTokenIs(lexer.SEMICOLON) {
+			p.nextToken()
+		}
+		if err := p.ctx.Err(); err != nil {
+			return statements, fmt.Errorf("parsing cancelled: %w", err)
+		}
+		if p.curTokenIs(lexer.EOF) {
+			return statements, nil
+		}
+
+		stmt, err := p.ParseStatement()
+		if err != nil {
+			return statements, fmt.Errorf("statement %d: %w", len(statements)+1, err)
+		}
+		statements = append(statements, stmt)
+
+		if !p.isStatementEnd() {
+			return statements, fmt.Errorf("statement %d: unexpected %s", len(statements), p.curToken.Literal)
+		}
+	}
+}
+
+// selectModifiers are the MySQL keywords allowed between SELECT and the
+// select list, often written inside /*! ... */ sections
+var selectModifiers = map[string]bool{
+	"DISTINCTROW":         true,
+	"HIGH_PRIORITY":       true,
+	"STRAIGHT_JOIN":       true,
+	"SQL_SMALL_RESULT":    true,
+	"SQL_BIG_RESULT":      true,
+	"SQL_BUFFER_RESULT":   true,
+	"SQL_CACHE":           true,
+	"SQL_NO_CACHE":        true,
+	"SQL_CALC_FOUND_ROWS": true,
+}
+
+// parseSelectModifiers parses MySQL select modifiers such as SQL_NO_CACHE.
+// A modifier followed by FROM, a comma or AS is taken as a column name.
+func (p *Parser) parseSelectModifiers(stmt *SelectStatement) []string {
+	if p.dialect == nil || p.dialect.Name() != "MySQL" {
+		return nil
+	}
+	var modifiers []string
+	for p.curTokenIs(lexer.IDENT) && selectModifiers[strings.ToUpper(p.curToken.Literal)] {
+		switch p.peekToken.Type {
+		case lexer.FROM, lexer.COMMA, lexer.DOT, lexer.AS, lexer.SEMICOLON, lexer.EOF:
+			return modifiers
+		}
+		modifier := strings.ToUpper(p.curToken.Literal)
+		if modifier == "DISTINCTROW" {
+			stmt.Distinct = true
+		}
+		modifiers = append(modifiers, modifier)
+		p.nextToken()
+	}
+	return modifiers
+}
+
 // Parse SELECT statement
 func (p *Parser) parseSelectStatement() (*SelectStatement, error) {
+	start := p.curToken.Position
+	stmt, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	p.recordSpan(stmt, start)
+	return stmt, nil
+}
+
+func (p *Parser) parseSelect() (*SelectStatement, error) {
 	stmt := GetSelectStatement() // Use object pool
 
 	if !p.curTokenIs(lexer.SELECT) {
@@ -194,18 +311,37 @@ func (p *Parser) parseSelectStatement() (*SelectStatement, error) {
 	}
 
 	p.nextToken()
+	stmt.Hints = p.parseCommentHints()
+	stmt.Modifiers = p.parseSelectModifiers(stmt)
 
 	if p.curTokenIs(lexer.DISTINCT) {
 		stmt.Distinct = true
 		p.nextToken()
+
+		// PostgreSQL: DISTINCT ON (expr, ...)
+		if p.curTokenIs(lexer.ON) {
+			if err := p.requireFeature(dialect.FeatureDistinctOn, "DISTINCT ON"); err != nil {
+				return nil, err
+			}
+			p.nextToken()
+			distinctOn, err := p.parseExpressionList("DISTINCT ON")
+			if err != nil {
+				return nil, err
+			}
+			if len(distinctOn) == 0 {
+				return nil, fmt.Errorf("DISTINCT ON requires at least one expression")
+			}
+			stmt.DistinctOn = distinctOn
+		}
 	}
+	stmt.Modifiers = append(stmt.Modifiers, p.parseSelectModifiers(stmt)...)
 
 	if p.curTokenIs(lexer.TOP) {
 		topClause, err := p.parseTopClause()
 		if err != nil {
 			return nil, err
 		}
-		stmt.Top = topClause
+		stmt.Limit = topClause
 	}
 
 	columns, err := p.parseSelectList()
@@ -214,20 +350,33 @@ func (p *Parser) parseSelectStatement() (*SelectStatement, error) {
 	}
 	stmt.Columns = columns
 
+	// SELECT ... INTO #new_table FROM ... (SQL Server) or INTO variable
+	if p.curTokenIs(lexer.INTO) {
+		p.nextToken()
+		parts, err := p.parseQualifiedName("INTO target")
+		if err != nil {
+			return nil, err
+		}
+		into := &TableReference{}
+		into.SetQualifiedName(parts)
+		stmt.Into = into
+	}
+
 	if p.curTokenIs(lexer.FROM) {
-		fromClause, err := p.parseFromClause()
+		fromClause, joins, err := p.parseFromClause()
 		if err != nil {
 			return nil, err
 		}
 		stmt.From = fromClause
+		stmt.Joins = append(stmt.Joins, joins...)
 	}
 
-	for p.curTokenIs(lexer.JOIN) || p.curTokenIs(lexer.INNER) || p.curTokenIs(lexer.LEFT) || p.curTokenIs(lexer.RIGHT) || p.curTokenIs(lexer.FULL) {
-		joinClause, err := p.parseJoinClause()
+	for p.isJoinStart() || (p.curTokenIs(lexer.COMMA) && p.peekIdentIs("LATERAL")) {
+		joins, err := p.parseJoinClause()
 		if err != nil {
 			return nil, err
 		}
-		stmt.Joins = append(stmt.Joins, joinClause)
+		stmt.Joins = append(stmt.Joins, joins...)
 	}
 
 	if p.curTokenIs(lexer.WHERE) {
@@ -237,6 +386,11 @@ func (p *Parser) parseSelectStatement() (*SelectStatement, error) {
 			return nil, err
 		}
 		stmt.Where = whereExpr
+
+		// Oracle: WHERE ROWNUM <= n
+		if stmt.Limit == nil {
+			stmt.Limit = rowNumLimit(whereExpr)
+		}
 	}
 
 	if p.curTokenIs(lexer.GROUP) {
@@ -245,6 +399,13 @@ func (p *Parser) parseSelectStatement() (*SelectStatement, error) {
 			return nil, err
 		}
 		stmt.GroupBy = groupBy
+
+		// MySQL, SQL Server: GROUP BY a, b WITH ROLLUP
+		if p.curTokenIs(lexer.WITH) && p.peekIdentIs("ROLLUP") {
+			p.nextToken()
+			p.nextToken()
+			stmt.WithRollup = true
+		}
 	}
 
 	if p.curTokenIs(lexer.HAVING) {
@@ -256,6 +417,23 @@ func (p *Parser) parseSelectStatement() (*SelectStatement, error) {
 		stmt.Having = havingExpr
 	}
 
+	if p.curIdentIs("WINDOW") {
+		windows, err := p.parseWindowClause()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Windows = windows
+	}
+
+	if p.curIdentIs("QUALIFY") {
+		p.nextToken()
+		qualify, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse QUALIFY: %w", err)
+		}
+		stmt.Qualify = qualify
+	}
+
 	if p.curTokenIs(lexer.ORDER) {
 		orderBy, err := p.parseOrderByClause()
 		if err != nil {
@@ -264,8 +442,11 @@ func (p *Parser) parseSelectStatement() (*SelectStatement, error) {
 		stmt.OrderBy = orderBy
 	}
 
-	// Parse LIMIT clause
-	if p.curTokenIs(lexer.LIMIT) {
+	// Parse LIMIT or OFFSET/FETCH clause
+	if p.isRowLimitStart() {
+		if stmt.Limit != nil && stmt.Limit.Syntax == dialect.LimitSyntaxSQLServer {
+			return nil, fmt.Errorf("TOP cannot be combined with %s", p.curToken.Literal)
+		}
 		limit, err := p.parseLimitClause()
 		if err != nil {
 			return nil, err
@@ -273,47 +454,86 @@ func (p *Parser) parseSelectStatement() (*SelectStatement, error) {
 		stmt.Limit = limit
 	}
 
+	// SQL Server: OPTION (MAXDOP 4, RECOMPILE)
+	if p.curTokenIs(lexer.OPTION) {
+		hints, err := p.parseQueryHints()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Hints = append(stmt.Hints, hints...)
+	}
+
 	return stmt, nil
 }
 
-func (p *Parser) parseTopClause() (*TopClause, error) {
+// parseTopClause parses TOP n, TOP (expr), [PERCENT] and [WITH TIES]
+func (p *Parser) parseTopClause() (*RowLimit, error) {
 	if !p.curTokenIs(lexer.TOP) {
 		return nil, fmt.Errorf("expected TOP, got %s", p.curToken.Literal)
 	}
 
 	p.nextToken()
 
-	if !p.curTokenIs(lexer.NUMBER) {
-		return nil, fmt.Errorf("expected number after TOP, got %s", p.curToken.Literal)
-	}
+	topClause := &RowLimit{Syntax: dialect.LimitSyntaxSQLServer}
 
-	count, err := strconv.Atoi(p.curToken.Literal)
-	if err != nil {
-		return nil, fmt.Errorf("invalid number in TOP clause: %s", p.curToken.Literal)
+	switch {
+	case p.curTokenIs(lexer.LPAREN):
+		// TOP (expression)
+		p.nextToken()
+		count, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse TOP expression: %w", err)
+		}
+		if !p.curTokenIs(lexer.RPAREN) {
+			return nil, fmt.Errorf("expected ')' after TOP expression, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		topClause.Count = count
+	case p.curTokenIs(lexer.NUMBER), p.curTokenIs(lexer.PARAM):
+		// A bare count is a single token: TOP 10 * FROM ...
+		count, err := p.parsePrimaryExpression()
+		if err != nil {
+			return nil, err
+		}
+		topClause.Count = count
+	default:
+		return nil, fmt.Errorf("expected number after TOP, got %s", p.curToken.Literal)
 	}
 
-	topClause := &TopClause{Count: count}
-	p.nextToken()
-
 	// Check for PERCENT
-	if p.curTokenIs(lexer.IDENT) && strings.ToUpper(p.curToken.Literal) == "PERCENT" {
+	if p.curIdentIs("PERCENT") {
 		topClause.Percent = true
 		p.nextToken()
 	}
 
+	// Check for WITH TIES
+	if p.curTokenIs(lexer.WITH) && p.peekIdentIs("TIES") {
+		topClause.WithTies = true
+		p.nextToken()
+		p.nextToken()
+	}
+
 	return topClause, nil
 }
 
+// parseStar parses an unqualified * in a select list
+func (p *Parser) parseStar() *StarExpression {
+	star := &StarExpression{}
+	start := p.curToken.Position
+	p.nextToken()
+	p.recordSpan(star, start)
+	return star
+}
+
 func (p *Parser) parseSelectList() ([]Expression, error) {
 	var columns []Expression
 
 	if p.curTokenIs(lexer.ASTERISK) {
-		columns = append(columns, &StarExpression{})
-		p.nextToken()
+		columns = append(columns, p.parseStar())
 		return columns, nil
 	}
 
-	expr, err := p.parseExpression()
+	expr, err := p.parseSelectItem()
 	if err != nil {
 		return nil, err
 	}
@@ -338,10 +558,9 @@ func (p *Parser) parseSelectList() ([]Expression, error) {
 		p.nextToken()
 
 		if p.curTokenIs(lexer.ASTERISK) {
-			columns = append(columns, &StarExpression{})
-			p.nextToken()
+			columns = append(columns, p.parseStar())
 		} else {
-			expr, err := p.parseExpression()
+			expr, err := p.parseSelectItem()
 			if err != nil {
 				return nil, err
 			}
@@ -367,34 +586,83 @@ func (p *Parser) parseSelectList() ([]Expression, error) {
 	return columns, nil
 }
 
-func (p *Parser) parseFromClause() (*FromClause, error) {
+// parseFromClause parses the FROM list. Joins inside a parenthesized join
+// group are returned separately; comma-separated LATERAL items are left for
+// the join loop.
+func (p *Parser) parseFromClause() (*FromClause, []*JoinClause, error) {
 	if !p.curTokenIs(lexer.FROM) {
-		return nil, fmt.Errorf("expected FROM, got %s", p.curToken.Literal)
+		return nil, nil, fmt.Errorf("expected FROM, got %s", p.curToken.Literal)
 	}
 
 	p.nextToken()
 
 	fromClause := &FromClause{}
 
-	table, err := p.parseTableReference()
+	table, joins, err := p.parseJoinedTable()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	fromClause.Tables = append(fromClause.Tables, *table)
+	parsed := []*TableReference{table}
 
-	for p.curTokenIs(lexer.COMMA) {
+	for p.curTokenIs(lexer.COMMA) && !p.peekIdentIs("LATERAL") {
 		p.nextToken()
-		table, err := p.parseTableReference()
+		table, err := p.parseTableSource()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		fromClause.Tables = append(fromClause.Tables, *table)
+		parsed = append(parsed, table)
+	}
+
+	for i, table := range parsed {
+		p.moveSpan(table, &fromClause.Tables[i])
+	}
+	return fromClause, joins, nil
+}
+
+// parseJoinedTable parses a table reference or a parenthesized join group
+// such as (a JOIN b ON ...). The group is flattened: its first table is
+// returned and its joins follow in source order.
+func (p *Parser) parseJoinedTable() (*TableReference, []*JoinClause, error) {
+	if !p.curTokenIs(lexer.LPAREN) || p.peekTokenIs(lexer.SELECT) {
+		table, err := p.parseTableSource()
+		return table, nil, err
+	}
+	p.nextToken()
+
+	table, joins, err := p.parseJoinedTable()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for p.isJoinStart() {
+		nested, err := p.parseJoinClause()
+		if err != nil {
+			return nil, nil, err
+		}
+		joins = append(joins, nested...)
 	}
 
-	return fromClause, nil
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, nil, fmt.Errorf("expected ')' to close join group, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return table, joins, nil
 }
 
 func (p *Parser) parseTableReference() (*TableReference, error) {
+	start := p.curToken.Position
+	table, err := p.parseTable()
+	if err != nil {
+		return nil, err
+	}
+	p.recordSpan(table, start)
+	return table, nil
+}
+
+func (p *Parser) parseTable() (*TableReference, error) {
 	table := &TableReference{}
 
 	// Check for derived table: (SELECT ...) AS alias
@@ -432,80 +700,201 @@ func (p *Parser) parseTableReference() (*TableReference, error) {
 		}
 	}
 
-	// Regular table reference
-	if !p.curTokenIs(lexer.IDENT) {
-		return nil, fmt.Errorf("expected table name, got %s", p.curToken.Literal)
+	// Regular table reference: [[[server.]catalog.]schema.]name
+	parts, err := p.parseQualifiedName("table")
+	if err != nil {
+		return nil, err
 	}
+	table.SetQualifiedName(parts)
 
-	firstIdent := p.curToken.Literal
-	p.nextToken()
+	if err := p.parseTableAlias(table); err != nil {
+		return nil, err
+	}
 
-	if p.curTokenIs(lexer.DOT) {
-		p.nextToken()
-		if !p.curTokenIs(lexer.IDENT) {
-			return nil, fmt.Errorf("expected table name after dot, got %s", p.curToken.Literal)
+	if p.isTableHintStart() {
+		hints, err := p.parseTableHints()
+		if err != nil {
+			return nil, err
 		}
-		table.Schema = firstIdent
-		table.Name = p.curToken.Literal
-		p.nextToken()
-	} else {
-		table.Name = firstIdent
+		table.Hints = hints
 	}
 
+	return table, nil
+}
+
+// parseTableAlias parses the optional alias of a table reference
+func (p *Parser) parseTableAlias(table *TableReference) error {
 	if p.curTokenIs(lexer.AS) {
 		p.nextToken()
 		if !p.curTokenIs(lexer.IDENT) {
-			return nil, fmt.Errorf("expected alias after AS, got %s", p.curToken.Literal)
+			return fmt.Errorf("expected alias after AS, got %s", p.curToken.Literal)
 		}
 		table.Alias = p.curToken.Literal
 		p.nextToken()
-	} else if p.curTokenIs(lexer.IDENT) {
+	} else if p.curTokenIs(lexer.IDENT) && !p.isClauseIdent() && !p.isTableHintStart() {
 		// Implicit alias (no AS keyword)
 		table.Alias = p.curToken.Literal
 		p.nextToken()
 	}
+	return nil
+}
+
+// parseTableSource parses a FROM or JOIN source, which besides a table or
+// derived table may be a table-valued function: STRING_SPLIT(s, ',') AS t
+func (p *Parser) parseTableSource() (*TableReference, error) {
+	start := p.curToken.Position
+	table, err := p.parseTable()
+	if err != nil {
+		return nil, err
+	}
+
+	// A name directly followed by ( calls a function
+	if p.curTokenIs(lexer.LPAREN) && table.Subquery == nil && table.Alias == "" && len(table.Hints) == 0 {
+		expr, err := p.parseFunctionCall(table.String())
+		if err != nil {
+			return nil, err
+		}
+		call, ok := expr.(*FunctionCall)
+		if !ok {
+			return nil, fmt.Errorf("expected a table-valued function, got %s", expr.String())
+		}
+		table = &TableReference{Function: call}
+		if err := p.parseTableAlias(table); err != nil {
+			return nil, err
+		}
+	}
 
+	p.recordSpan(table, start)
 	return table, nil
 }
 
-func (p *Parser) parseJoinClause() (*JoinClause, error) {
+// maxQualifiedNameParts is the longest object name accepted: server.catalog.schema.name
+const maxQualifiedNameParts = 4
+
+// parseQualifiedName parses a dotted object name such as db.dbo.orders.
+// Empty middle parts are allowed for SQL Server's db..orders shorthand.
+func (p *Parser) parseQualifiedName(what string) ([]string, error) {
+	// SQL Server table variables and PL/SQL-style INTO targets
+	if isVariable(p.curToken) {
+		name := p.curToken.Literal
+		p.nextToken()
+		return []string{name}, nil
+	}
+
+	if !p.curTokenIs(lexer.IDENT) {
+		return nil, fmt.Errorf("expected %s name, got %s", what, p.curToken.Literal)
+	}
+
+	parts := []string{p.curToken.Literal}
+	p.nextToken()
+
+	for p.curTokenIs(lexer.DOT) {
+		p.nextToken()
+
+		if p.curTokenIs(lexer.DOT) {
+			// db..table omits the schema and uses the default one
+			parts = append(parts, "")
+			continue
+		}
+
+		if !p.curTokenIs(lexer.IDENT) {
+			return nil, fmt.Errorf("expected %s name after dot, got %s", what, p.curToken.Literal)
+		}
+		parts = append(parts, p.curToken.Literal)
+		p.nextToken()
+
+		if len(parts) > maxQualifiedNameParts {
+			return nil, fmt.Errorf("%s name has more than %d parts", what, maxQualifiedNameParts)
+		}
+	}
+
+	return parts, nil
+}
+
+// isJoinStart reports whether the current token begins a join
+func (p *Parser) isJoinStart() bool {
+	switch p.curToken.Type {
+	case lexer.JOIN, lexer.INNER, lexer.LEFT, lexer.RIGHT, lexer.FULL:
+		return true
+	case lexer.IDENT:
+		return p.curIdentIs("CROSS") || p.curIdentIs("NATURAL") || p.curIdentIs("STRAIGHT_JOIN") ||
+			(p.curIdentIs("OUTER") && p.peekIdentIs("APPLY"))
+	}
+	return false
+}
+
+// parseJoinClause parses a join. A parenthesized join group on the right
+// is flattened, so its joins are returned after this one.
+func (p *Parser) parseJoinClause() ([]*JoinClause, error) {
+	start := p.curToken.Position
 	joinClause := GetJoinClause()
 
-	// Parse join type (INNER/LEFT/RIGHT/FULL)
-	joinType, err := p.parseJoinType()
-	if err != nil {
+	if p.curTokenIs(lexer.COMMA) {
+		// FROM a, LATERAL (subquery) b is a lateral cross join
+		p.nextToken()
+		joinClause.JoinType = "CROSS"
+	} else if err := p.parseJoinType(joinClause); err != nil {
 		PutJoinClause(joinClause)
 		return nil, err
 	}
-	joinClause.JoinType = joinType
 
-	// Parse table reference
-	table, err := p.parseTableReference()
+	if p.curIdentIs("LATERAL") {
+		if err := p.requireFeature(dialect.FeatureLateral, "LATERAL"); err != nil {
+			PutJoinClause(joinClause)
+			return nil, err
+		}
+		joinClause.Lateral = true
+		p.nextToken()
+	}
+
+	// Parse table reference, derived table or join group
+	table, nested, err := p.parseJoinedTable()
 	if err != nil {
 		PutJoinClause(joinClause)
 		return nil, err
 	}
 	joinClause.Table = *table
+	p.moveSpan(table, &joinClause.Table)
+	joins := append([]*JoinClause{joinClause}, nested...)
 
-	// Parse ON condition
-	if !p.curTokenIs(lexer.ON) {
-		PutJoinClause(joinClause)
-		return nil, fmt.Errorf("expected ON after JOIN table, got %s", p.curToken.Literal)
+	// CROSS, NATURAL and APPLY joins take no condition
+	if joinClause.JoinType == "CROSS" || joinClause.Natural || joinClause.IsApply() {
+		p.recordSpan(joinClause, start)
+		return joins, nil
 	}
 
-	p.nextToken()
-	condition, err := p.parseExpression()
-	if err != nil {
+	switch {
+	case p.curTokenIs(lexer.ON):
+		p.nextToken()
+		condition, err := p.parseExpression()
+		if err != nil {
+			PutJoinClause(joinClause)
+			return nil, err
+		}
+		joinClause.Condition = condition
+	case p.curTokenIs(lexer.USING):
+		p.nextToken()
+		using, err := p.parseIdentifierList("column")
+		if err != nil {
+			PutJoinClause(joinClause)
+			return nil, fmt.Errorf("failed to parse USING: %w", err)
+		}
+		joinClause.Using = using
+	case joinClause.Straight:
+		// MySQL: STRAIGHT_JOIN without ON is a cross join
+	default:
 		PutJoinClause(joinClause)
-		return nil, err
+		return nil, fmt.Errorf("expected ON or USING after JOIN table, got %s", p.curToken.Literal)
 	}
-	joinClause.Condition = condition
 
-	return joinClause, nil
+	p.recordSpan(joinClause, start)
+	return joins, nil
 }
 
-// parseJoinType parses the join type keyword (INNER/LEFT/RIGHT/FULL JOIN)
-func (p *Parser) parseJoinType() (string, error) {
+// parseJoinType parses the join type keywords:
+// [NATURAL] [INNER | {LEFT | RIGHT | FULL} [OUTER]] JOIN, CROSS JOIN,
+// CROSS APPLY, OUTER APPLY and STRAIGHT_JOIN
+func (p *Parser) parseJoinType(jc *JoinClause) error {
 	// Map of join type keywords to their string representation
 	joinTypes := map[lexer.TokenType]string{
 		lexer.INNER: "INNER",
@@ -514,23 +903,53 @@ func (p *Parser) parseJoinType() (string, error) {
 		lexer.FULL:  "FULL",
 	}
 
+	switch {
+	case p.curIdentIs("STRAIGHT_JOIN"):
+		p.nextToken()
+		jc.JoinType = "INNER"
+		jc.Straight = true
+		return nil
+	case p.curIdentIs("CROSS") || p.curIdentIs("OUTER"):
+		keyword := strings.ToUpper(p.curToken.Literal)
+		p.nextToken()
+		if p.curIdentIs("APPLY") {
+			p.nextToken()
+			jc.JoinType = keyword + " APPLY"
+			return nil
+		}
+		if keyword != "CROSS" || !p.curTokenIs(lexer.JOIN) {
+			return fmt.Errorf("expected JOIN or APPLY after %s, got %s", keyword, p.curToken.Literal)
+		}
+		p.nextToken()
+		jc.JoinType = "CROSS"
+		return nil
+	case p.curIdentIs("NATURAL"):
+		p.nextToken()
+		jc.Natural = true
+	}
+
 	// Check if current token is a join type keyword
 	if joinType, ok := joinTypes[p.curToken.Type]; ok {
 		p.nextToken()
+		if joinType != "INNER" && p.curIdentIs("OUTER") {
+			p.nextToken()
+		}
 		if !p.curTokenIs(lexer.JOIN) {
-			return "", fmt.Errorf("expected JOIN after %s, got %s", joinType, p.curToken.Literal)
+			return fmt.Errorf("expected JOIN after %s, got %s", joinType, p.curToken.Literal)
 		}
 		p.nextToken()
-		return joinType, nil
+		jc.JoinType = joinType
+		return nil
 	}
 
 	// Default: plain JOIN means INNER JOIN
 	if p.curTokenIs(lexer.JOIN) {
 		p.nextToken()
-		return "INNER", nil
+		jc.JoinType = "INNER"
+		return nil
 	}
 
-	return "", fmt.Errorf("expected JOIN keyword, got %s", p.curToken.Literal)
+	return fmt.Errorf("expected JOIN keyword, got %s", p.curToken.Literal)
 }
 
 func (p *Parser) parseGroupByClause() ([]Expression, error) {
@@ -547,17 +966,17 @@ func (p *Parser) parseGroupByClause() ([]Expression, error) {
 
 	var expressions []Expression
 
-	// Parse first expression
-	expr, err := p.parseExpression()
+	// Parse first element
+	expr, err := p.parseGroupingElement()
 	if err != nil {
 		return nil, err
 	}
 	expressions = append(expressions, expr)
 
-	// Parse additional expressions
+	// Parse additional elements
 	for p.curTokenIs(lexer.COMMA) {
 		p.nextToken()
-		expr, err := p.parseExpression()
+		expr, err := p.parseGroupingElement()
 		if err != nil {
 			return nil, err
 		}
@@ -567,6 +986,108 @@ func (p *Parser) parseGroupByClause() ([]Expression, error) {
 	return expressions, nil
 }
 
+// parseGroupingElement parses one GROUP BY element: an expression,
+// ROLLUP(...), CUBE(...) or GROUPING SETS (...)
+func (p *Parser) parseGroupingElement() (Expression, error) {
+	switch {
+	case (p.curIdentIs("ROLLUP") || p.curIdentIs("CUBE")) && p.peekTokenIs(lexer.LPAREN):
+		kind := strings.ToUpper(p.curToken.Literal)
+		if err := p.requireFeature(dialect.FeatureGroupingSets, kind); err != nil {
+			return nil, err
+		}
+		p.nextToken()
+		return p.parseGroupingSets(kind)
+	case p.curIdentIs("GROUPING") && p.peekIdentIs("SETS"):
+		if err := p.requireFeature(dialect.FeatureGroupingSets, "GROUPING SETS"); err != nil {
+			return nil, err
+		}
+		p.nextToken()
+		p.nextToken()
+		return p.parseGroupingSets("GROUPING SETS")
+	default:
+		return p.parseExpression()
+	}
+}
+
+// parseGroupingSets parses the parenthesized element list of ROLLUP, CUBE
+// or GROUPING SETS. Elements are expressions or parenthesized lists; only
+// GROUPING SETS allows the empty set () and nested grouping elements.
+func (p *Parser) parseGroupingSets(kind string) (*GroupingSetsExpression, error) {
+	if !p.curTokenIs(lexer.LPAREN) {
+		return nil, fmt.Errorf("expected '(' after %s, got %s", kind, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	gse := &GroupingSetsExpression{Kind: kind}
+	for {
+		var set []Expression
+		if p.curTokenIs(lexer.LPAREN) {
+			list, err := p.parseExpressionList(kind)
+			if err != nil {
+				return nil, err
+			}
+			if len(list) == 0 && kind != "GROUPING SETS" {
+				return nil, fmt.Errorf("empty grouping set is not allowed in %s", kind)
+			}
+			set = list
+		} else {
+			var expr Expression
+			var err error
+			if kind == "GROUPING SETS" {
+				expr, err = p.parseGroupingElement()
+			} else {
+				expr, err = p.parseExpression()
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s element: %w", kind, err)
+			}
+			set = []Expression{expr}
+		}
+		gse.Sets = append(gse.Sets, set)
+
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' to close %s, got %s", kind, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return gse, nil
+}
+
+// parseExpressionList parses (expr, ...), which may be empty
+func (p *Parser) parseExpressionList(what string) ([]Expression, error) {
+	if !p.curTokenIs(lexer.LPAREN) {
+		return nil, fmt.Errorf("expected '(' after %s, got %s", what, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	var exprs []Expression
+	for !p.curTokenIs(lexer.RPAREN) {
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s expression: %w", what, err)
+		}
+		exprs = append(exprs, expr)
+
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(lexer.RPAREN) {
+		return nil, fmt.Errorf("expected ')' after %s list, got %s", what, p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return exprs, nil
+}
+
 func (p *Parser) parseOrderByClause() ([]*OrderByClause, error) {
 	if !p.curTokenIs(lexer.ORDER) {
 		return nil, fmt.Errorf("expected ORDER, got %s", p.curToken.Literal)
@@ -587,128 +1108,252 @@ func (p *Parser) parseOrderByClause() ([]*OrderByClause, error) {
 	}
 	clauses = append(clauses, clause)
 
-	// Parse additional clauses
-	for p.curTokenIs(lexer.COMMA) {
-		p.nextToken()
-		clause, err := p.parseOrderByItem()
-		if err != nil {
+	// Parse additional clauses
+	for p.curTokenIs(lexer.COMMA) {
+		p.nextToken()
+		clause, err := p.parseOrderByItem()
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return clauses, nil
+}
+
+func (p *Parser) parseOrderByItem() (*OrderByClause, error) {
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	clause := &OrderByClause{
+		Expression: expr,
+		Direction:  "ASC", // Default
+	}
+
+	// Check for ASC/DESC
+	if p.curTokenIs(lexer.IDENT) {
+		direction := strings.ToUpper(p.curToken.Literal)
+		if direction == "ASC" || direction == "DESC" {
+			clause.Direction = direction
+			p.nextToken()
+		}
+	}
+
+	return clause, nil
+}
+
+// isRowLimitStart reports whether the current token begins a LIMIT or
+// OFFSET/FETCH clause
+func (p *Parser) isRowLimitStart() bool {
+	return p.curTokenIs(lexer.LIMIT) || p.curTokenIs(lexer.OFFSET) || p.curTokenIs(lexer.FETCH)
+}
+
+// parseLimitClause parses the trailing row-limit clauses:
+//
+//	LIMIT {count | ALL} [OFFSET offset]
+//	LIMIT offset, count                                    (MySQL)
+//	OFFSET offset [LIMIT count]                            (PostgreSQL)
+//	[OFFSET offset {ROW | ROWS}]
+//	[FETCH {FIRST | NEXT} [count] [PERCENT] {ROW | ROWS} {ONLY | WITH TIES}]
+func (p *Parser) parseLimitClause() (*RowLimit, error) {
+	clause := &RowLimit{Syntax: dialect.LimitSyntaxStandard}
+
+	if !p.isRowLimitStart() {
+		return nil, fmt.Errorf("expected LIMIT, OFFSET or FETCH, got %s", p.curToken.Literal)
+	}
+
+	if p.curTokenIs(lexer.LIMIT) {
+		if err := p.parseLimitCount(clause); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.curTokenIs(lexer.OFFSET) {
+		if clause.Offset != nil {
+			return nil, fmt.Errorf("OFFSET given twice")
+		}
+		p.nextToken()
+		offset, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse OFFSET: %w", err)
+		}
+		clause.Offset = offset
+
+		if p.curRowIs() {
+			if err := p.requireFeature(dialect.FeatureOffsetFetch, "OFFSET ... ROWS"); err != nil {
+				return nil, err
+			}
+			clause.Syntax = dialect.LimitSyntaxFetch
+			p.nextToken()
+		}
+	}
+
+	// PostgreSQL also accepts OFFSET m LIMIT n
+	if p.curTokenIs(lexer.LIMIT) && clause.Count == nil && clause.Syntax == dialect.LimitSyntaxStandard {
+		if err := p.parseLimitCount(clause); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.curTokenIs(lexer.FETCH) {
+		if clause.Count != nil {
+			return nil, fmt.Errorf("FETCH cannot be combined with LIMIT")
+		}
+		if err := p.requireFeature(dialect.FeatureOffsetFetch, "FETCH"); err != nil {
+			return nil, err
+		}
+		clause.Syntax = dialect.LimitSyntaxFetch
+		if err := p.parseFetchFirst(clause); err != nil {
 			return nil, err
 		}
-		clauses = append(clauses, clause)
 	}
 
-	return clauses, nil
+	return clause, nil
 }
 
-func (p *Parser) parseOrderByItem() (*OrderByClause, error) {
-	expr, err := p.parseExpression()
-	if err != nil {
-		return nil, err
+// parseLimitCount parses LIMIT {count | ALL} and MySQL's LIMIT offset, count
+func (p *Parser) parseLimitCount(clause *RowLimit) error {
+	p.nextToken() // consume LIMIT
+
+	if p.curTokenIs(lexer.ALL) {
+		p.nextToken()
+		return nil
 	}
 
-	clause := &OrderByClause{
-		Expression: expr,
-		Direction:  "ASC", // Default
+	count, err := p.parseExpression()
+	if err != nil {
+		return fmt.Errorf("failed to parse LIMIT: %w", err)
 	}
+	clause.Count = count
 
-	// Check for ASC/DESC
-	if p.curTokenIs(lexer.IDENT) {
-		direction := strings.ToUpper(p.curToken.Literal)
-		if direction == "ASC" || direction == "DESC" {
-			clause.Direction = direction
-			p.nextToken()
+	// MySQL: LIMIT offset, count
+	if p.curTokenIs(lexer.COMMA) {
+		p.nextToken()
+		count, err := p.parseExpression()
+		if err != nil {
+			return fmt.Errorf("failed to parse LIMIT count: %w", err)
 		}
+		clause.Offset = clause.Count
+		clause.Count = count
 	}
 
-	return clause, nil
+	return nil
 }
 
-func (p *Parser) parseLimitClause() (*LimitClause, error) {
-	if !p.curTokenIs(lexer.LIMIT) {
-		return nil, fmt.Errorf("expected LIMIT, got %s", p.curToken.Literal)
-	}
+// parseFetchFirst parses FETCH {FIRST | NEXT} [count] [PERCENT] {ROW | ROWS} {ONLY | WITH TIES}
+func (p *Parser) parseFetchFirst(clause *RowLimit) error {
+	p.nextToken() // consume FETCH
 
+	if !p.curTokenIs(lexer.FIRST) && !p.curTokenIs(lexer.NEXT) {
+		return fmt.Errorf("expected FIRST or NEXT after FETCH, got %s", p.curToken.Literal)
+	}
 	p.nextToken()
 
-	// Parse count
-	if !p.curTokenIs(lexer.NUMBER) {
-		return nil, fmt.Errorf("expected number after LIMIT, got %s", p.curToken.Literal)
-	}
+	// The count is optional: FETCH FIRST ROW ONLY fetches one row
+	if p.curRowIs() {
+		clause.Count = &Literal{Value: int64(1)}
+	} else {
+		count, err := p.parseExpression()
+		if err != nil {
+			return fmt.Errorf("failed to parse FETCH count: %w", err)
+		}
+		clause.Count = count
 
-	count, err := strconv.Atoi(p.curToken.Literal)
-	if err != nil {
-		return nil, fmt.Errorf("invalid LIMIT count: %s", p.curToken.Literal)
+		if p.curIdentIs("PERCENT") {
+			clause.Percent = true
+			p.nextToken()
+		}
 	}
 
-	clause := &LimitClause{
-		Count:  count,
-		Offset: 0, // Default
+	if !p.curRowIs() {
+		return fmt.Errorf("expected ROWS after FETCH count, got %s", p.curToken.Literal)
 	}
-
 	p.nextToken()
 
-	// Check for OFFSET (MySQL/PostgreSQL style: LIMIT count OFFSET offset)
-	if p.curTokenIs(lexer.OFFSET) {
+	switch {
+	case p.curIdentIs("ONLY"):
+		p.nextToken()
+	case p.curTokenIs(lexer.WITH) && p.peekIdentIs("TIES"):
+		clause.WithTies = true
 		p.nextToken()
-		if !p.curTokenIs(lexer.NUMBER) {
-			return nil, fmt.Errorf("expected number after OFFSET, got %s", p.curToken.Literal)
-		}
-
-		offset, err := strconv.Atoi(p.curToken.Literal)
-		if err != nil {
-			return nil, fmt.Errorf("invalid OFFSET value: %s", p.curToken.Literal)
-		}
-
-		clause.Offset = offset
 		p.nextToken()
+	default:
+		return fmt.Errorf("expected ONLY or WITH TIES, got %s", p.curToken.Literal)
 	}
 
-	return clause, nil
+	return nil
 }
 
-// Basic expression parsing (simplified for now)
-func (p *Parser) parseExpression() (Expression, error) {
-	return p.parseInfixExpression()
+// curRowIs reports whether the current token is ROW or ROWS
+func (p *Parser) curRowIs() bool {
+	return p.curTokenIs(lexer.ROWS) || p.curTokenIs(lexer.ROW) || p.curIdentIs("ROW")
 }
 
-func (p *Parser) parseInfixExpression() (Expression, error) {
-	left, err := p.parsePrimaryExpression()
-	if err != nil {
-		return nil, err
+// rowNumLimit returns the RowLimit of an Oracle WHERE ROWNUM <= n or
+// ROWNUM < n predicate, alone or ANDed with other conditions
+func rowNumLimit(where Expression) *RowLimit {
+	be, ok := where.(*BinaryExpression)
+	if !ok {
+		return nil
 	}
 
-	for p.isInfixOperator(p.curToken.Type) {
-		if p.curToken.Type == lexer.IN {
-			// Special handling for IN expressions
-			inExpr, err := p.parseInExpression(left)
-			if err != nil {
-				return nil, err
-			}
-			left = inExpr
-		} else {
-			operator := p.curToken.Literal
-			p.nextToken()
+	if strings.EqualFold(be.Operator, "AND") {
+		if limit := rowNumLimit(be.Left); limit != nil {
+			return limit
+		}
+		return rowNumLimit(be.Right)
+	}
 
-			right, err := p.parsePrimaryExpression()
-			if err != nil {
-				return nil, err
-			}
+	isRowNum := func(expr Expression) bool {
+		col, ok := expr.(*ColumnReference)
+		return ok && col.Table == "" && strings.EqualFold(col.Column, "ROWNUM")
+	}
+
+	// Infix operators are parsed left to right, so "a = 1 AND ROWNUM <= n"
+	// arrives as ((a = 1 AND ROWNUM) <= n)
+	if and, ok := be.Left.(*BinaryExpression); ok && strings.EqualFold(and.Operator, "AND") && isRowNum(and.Right) {
+		return rowNumLimit(&BinaryExpression{Left: and.Right, Operator: be.Operator, Right: be.Right})
+	}
 
-			expr := GetBinaryExpression() // Use object pool
-			expr.Left = left
-			expr.Operator = operator
-			expr.Right = right
-			left = expr
+	// Normalize n >= ROWNUM to ROWNUM <= n
+	operator, bound := be.Operator, be.Right
+	if !isRowNum(be.Left) {
+		if !isRowNum(be.Right) {
+			return nil
+		}
+		bound = be.Left
+		switch operator {
+		case ">=":
+			operator = "<="
+		case ">":
+			operator = "<"
+		default:
+			return nil
 		}
 	}
 
-	return left, nil
+	limit := &RowLimit{Syntax: dialect.LimitSyntaxOracle}
+	switch operator {
+	case "<=":
+		limit.Count = bound
+	case "<":
+		if n, ok := intLiteralValue(bound); ok {
+			limit.Count = &Literal{Value: n - 1}
+		} else {
+			limit.Count = &BinaryExpression{Left: bound, Operator: "-", Right: &Literal{Value: int64(1)}}
+		}
+	default:
+		return nil
+	}
+	return limit
 }
 
-func (p *Parser) parseInExpression(left Expression) (Expression, error) {
+func (p *Parser) parseInExpression(left Expression, not bool) (Expression, error) {
 	inExpr := &InExpression{
 		Expression: left,
-		Not:        false,
+		Not:        not,
 	}
 
 	// Move past the IN token
@@ -719,6 +1364,7 @@ func (p *Parser) parseInExpression(left Expression) (Expression, error) {
 		return nil, fmt.Errorf("expected '(' after IN, got %s", p.curToken.Literal)
 	}
 
+	lparen := p.curToken.Position
 	p.nextToken()
 
 	// Check if this is a subquery (starts with SELECT)
@@ -766,6 +1412,11 @@ func (p *Parser) parseInExpression(left Expression) (Expression, error) {
 	}
 
 	p.nextToken()
+	if len(inExpr.Values) == 1 {
+		if subquery, ok := inExpr.Values[0].(*SubqueryExpression); ok {
+			p.recordSpan(subquery, lparen)
+		}
+	}
 
 	return inExpr, nil
 }
@@ -773,11 +1424,24 @@ func (p *Parser) parseInExpression(left Expression) (Expression, error) {
 func (p *Parser) parsePrimaryExpression() (Expression, error) {
 	switch p.curToken.Type {
 	case lexer.IDENT:
+		if p.isCharsetIntroducer() {
+			return p.parseIntroducedLiteral()
+		}
 		return p.parseIdentifierExpression()
+	case lexer.NEW, lexer.OLD:
+		// Trigger row references: NEW.column, OLD.column
+		if p.peekTokenIs(lexer.DOT) {
+			return p.parseIdentifierExpression()
+		}
+		return nil, fmt.Errorf("unexpected token in expression: %s", p.curToken.Literal)
 	case lexer.NUMBER:
 		return p.parseNumberLiteral()
 	case lexer.STRING:
 		return p.parseStringLiteral()
+	case lexer.PARAM:
+		expr := &Parameter{Name: p.curToken.Literal}
+		p.nextToken()
+		return expr, nil
 	case lexer.NULL:
 		// Handle NULL literal
 		expr := &Literal{Value: nil}
@@ -800,6 +1464,13 @@ func (p *Parser) parsePrimaryExpression() (Expression, error) {
 		return nil, fmt.Errorf("unexpected token after NOT: %s", p.curToken.Literal)
 	case lexer.EXISTS:
 		return p.parseExistsExpression(false)
+	case lexer.VALUES:
+		// MySQL: VALUES(col) in ON DUPLICATE KEY UPDATE
+		if p.peekTokenIs(lexer.LPAREN) {
+			p.nextToken()
+			return p.parseFunctionCall("VALUES")
+		}
+		return nil, fmt.Errorf("unexpected token in expression: %s", p.curToken.Literal)
 	default:
 		return nil, fmt.Errorf("unexpected token in expression: %s", p.curToken.Literal)
 	}
@@ -809,29 +1480,54 @@ func (p *Parser) parseIdentifierExpression() (Expression, error) {
 	firstIdent := p.curToken.Literal
 	p.nextToken()
 
-	// Check if it's a qualified column (table.column)
+	// Check if it's a qualified column (table.column, schema.table.column,
+	// db.schema.table.column)
 	if p.curTokenIs(lexer.DOT) {
-		p.nextToken()
-		if !p.curTokenIs(lexer.IDENT) && !p.curTokenIs(lexer.ASTERISK) {
-			return nil, fmt.Errorf("expected column name after dot, got %s", p.curToken.Literal)
+		parts := []string{firstIdent}
+		for p.curTokenIs(lexer.DOT) {
+			p.nextToken()
+			if p.curTokenIs(lexer.ASTERISK) {
+				if len(parts) > 2 {
+					return nil, fmt.Errorf("too many qualifiers before *: %s", strings.Join(parts, "."))
+				}
+				expr := &StarExpression{Table: parts[len(parts)-1]}
+				if len(parts) == 2 {
+					expr.Schema = parts[0]
+				}
+				p.nextToken()
+				return expr, nil
+			}
+			if !p.curTokenIs(lexer.IDENT) {
+				return nil, fmt.Errorf("expected column name after dot, got %s", p.curToken.Literal)
+			}
+			parts = append(parts, p.curToken.Literal)
+			p.nextToken()
 		}
 
-		if p.curTokenIs(lexer.ASTERISK) {
-			expr := &StarExpression{Table: firstIdent}
-			p.nextToken()
-			return expr, nil
+		if len(parts) > maxQualifiedNameParts {
+			return nil, fmt.Errorf("too many qualifiers in column reference: %s", strings.Join(parts, "."))
 		}
 
 		expr := GetColumnReference() // Use object pool
-		expr.Table = firstIdent
-		expr.Column = p.curToken.Literal
-		p.nextToken()
+		expr.Column = parts[len(parts)-1]
+		expr.Table = parts[len(parts)-2]
+		if len(parts) > 2 {
+			expr.Schema = parts[len(parts)-3]
+		}
+		if len(parts) > 3 {
+			expr.Catalog = parts[0]
+		}
 		return expr, nil
 	}
 
 	// Check if it's a function call
 	if p.curTokenIs(lexer.LPAREN) {
-		return p.parseFunctionCall(firstIdent)
+		return p.parseSpecialFunction(firstIdent)
+	}
+
+	// INTERVAL '1 day', INTERVAL 1 DAY
+	if strings.EqualFold(firstIdent, "INTERVAL") && (p.curTokenIs(lexer.STRING) || p.curTokenIs(lexer.NUMBER) || p.curTokenIs(lexer.PARAM)) {
+		return p.parseIntervalExpression()
 	}
 
 	// It's a simple column reference
@@ -847,14 +1543,24 @@ func (p *Parser) parseFunctionCall(name string) (Expression, error) {
 
 	p.nextToken()
 
-	var arguments []Expression
+	funcCall := &FunctionCall{
+		Name: name,
+	}
+
+	// Aggregate set quantifier: COUNT(DISTINCT x), COUNT(ALL x)
+	if p.curTokenIs(lexer.DISTINCT) {
+		funcCall.Distinct = true
+		p.nextToken()
+	} else if p.curTokenIs(lexer.ALL) {
+		p.nextToken()
+	}
 
 	if !p.curTokenIs(lexer.RPAREN) {
 		arg, err := p.parseExpression()
 		if err != nil {
 			return nil, err
 		}
-		arguments = append(arguments, arg)
+		funcCall.Arguments = append(funcCall.Arguments, arg)
 
 		for p.curTokenIs(lexer.COMMA) {
 			p.nextToken()
@@ -862,8 +1568,17 @@ func (p *Parser) parseFunctionCall(name string) (Expression, error) {
 			if err != nil {
 				return nil, err
 			}
-			arguments = append(arguments, arg)
+			funcCall.Arguments = append(funcCall.Arguments, arg)
+		}
+	}
+
+	// Ordered-set argument: STRING_AGG(x, ',' ORDER BY y), ARRAY_AGG(x ORDER BY y)
+	if p.curTokenIs(lexer.ORDER) {
+		orderBy, err := p.parseOrderByClause()
+		if err != nil {
+			return nil, err
 		}
+		funcCall.OrderBy = orderBy
 	}
 
 	if !p.curTokenIs(lexer.RPAREN) {
@@ -872,13 +1587,51 @@ func (p *Parser) parseFunctionCall(name string) (Expression, error) {
 
 	p.nextToken() // consume the closing paren
 
-	funcCall := &FunctionCall{
-		Name:      name,
-		Arguments: arguments,
+	// WITHIN GROUP (ORDER BY ...)
+	if p.curIdentIs("WITHIN") && p.peekTokenIs(lexer.GROUP) {
+		p.nextToken()
+		p.nextToken()
+		if !p.curTokenIs(lexer.LPAREN) {
+			return nil, fmt.Errorf("expected '(' after WITHIN GROUP, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		withinGroup, err := p.parseOrderByClause()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse WITHIN GROUP: %w", err)
+		}
+		if !p.curTokenIs(lexer.RPAREN) {
+			return nil, fmt.Errorf("expected ')' after WITHIN GROUP, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		funcCall.WithinGroup = withinGroup
+	}
+
+	// FILTER (WHERE ...)
+	if p.curIdentIs("FILTER") && p.peekTokenIs(lexer.LPAREN) {
+		if err := p.requireFeature(dialect.FeatureFilterClause, "FILTER"); err != nil {
+			return nil, err
+		}
+		p.nextToken()
+		p.nextToken()
+		if !p.curTokenIs(lexer.WHERE) {
+			return nil, fmt.Errorf("expected WHERE in FILTER clause, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		filter, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse FILTER clause: %w", err)
+		}
+		if !p.curTokenIs(lexer.RPAREN) {
+			return nil, fmt.Errorf("expected ')' after FILTER clause, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		funcCall.Filter = filter
 	}
 
 	// Check if this is a window function (followed by OVER)
-	if p.curTokenIs(lexer.OVER) {
+	windowed := p.curTokenIs(lexer.OVER)
+	p.checkFunctionCall(funcCall, windowed)
+	if windowed {
 		return p.parseWindowFunction(funcCall)
 	}
 
@@ -886,18 +1639,29 @@ func (p *Parser) parseFunctionCall(name string) (Expression, error) {
 }
 
 func (p *Parser) parseNumberLiteral() (Expression, error) {
-	literal := &Literal{}
+	literal := &Literal{Kind: p.curToken.Kind}
+	text := p.curToken.Literal
 
-	if strings.Contains(p.curToken.Literal, ".") {
-		value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	switch {
+	case literal.Kind == lexer.LiteralHex && p.hexIsBinary():
+		// MySQL, SQL Server: 0xABCD is a binary string
+		value, err := decodeHex(text[2:])
 		if err != nil {
-			return nil, fmt.Errorf("could not parse %q as float", p.curToken.Literal)
+			return nil, fmt.Errorf("could not parse %q as hex: %w", text, err)
 		}
 		literal.Value = value
-	} else {
-		value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
+	case literal.Kind == lexer.LiteralBit:
+		literal.Value = text[2:]
+	case literal.Kind == lexer.LiteralPlain && strings.ContainsAny(text, ".eE"):
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %q as float", text)
+		}
+		literal.Value = value
+	default:
+		value, err := strconv.ParseInt(text, 0, 64)
 		if err != nil {
-			return nil, fmt.Errorf("could not parse %q as integer", p.curToken.Literal)
+			return nil, fmt.Errorf("could not parse %q as integer", text)
 		}
 		literal.Value = value
 	}
@@ -907,12 +1671,72 @@ func (p *Parser) parseNumberLiteral() (Expression, error) {
 }
 
 func (p *Parser) parseStringLiteral() (Expression, error) {
-	literal := &Literal{Value: p.curToken.Literal}
+	literal := &Literal{Value: p.curToken.Literal, Kind: p.curToken.Kind}
+
+	if literal.Kind == lexer.LiteralHex {
+		value, err := decodeHex(p.curToken.Literal)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse X'%s' as hex: %w", p.curToken.Literal, err)
+		}
+		literal.Value = value
+	}
+
+	p.nextToken()
+	return literal, nil
+}
+
+// hexIsBinary reports whether 0x literals are binary strings rather than
+// integers in the parser's dialect
+func (p *Parser) hexIsBinary() bool {
+	if p.dialect == nil {
+		return false
+	}
+	name := p.dialect.Name()
+	return name == "MySQL" || name == "SQL Server"
+}
+
+// isCharsetIntroducer reports whether the current token is a MySQL
+// character set introducer such as _utf8mb4 in _utf8mb4'text'
+func (p *Parser) isCharsetIntroducer() bool {
+	if p.dialect == nil || p.dialect.Name() != "MySQL" || !strings.HasPrefix(p.curToken.Literal, "_") {
+		return false
+	}
+	return p.peekTokenIs(lexer.STRING) ||
+		(p.peekTokenIs(lexer.NUMBER) && p.peekToken.Kind != lexer.LiteralPlain)
+}
+
+// parseIntroducedLiteral parses _charset'text', _charset 0xABCD and
+// _charset b'101'
+func (p *Parser) parseIntroducedLiteral() (Expression, error) {
+	charset := strings.TrimPrefix(p.curToken.Literal, "_")
 	p.nextToken()
+
+	var expr Expression
+	var err error
+	if p.curTokenIs(lexer.STRING) {
+		expr, err = p.parseStringLiteral()
+	} else {
+		expr, err = p.parseNumberLiteral()
+	}
+	if err != nil {
+		return nil, err
+	}
+	literal := expr.(*Literal)
+	literal.Charset = charset
 	return literal, nil
 }
 
+// decodeHex decodes hex digits, padding an odd count with a leading zero
+// as MySQL does for 0xABC
+func decodeHex(digits string) ([]byte, error) {
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	return hex.DecodeString(digits)
+}
+
 func (p *Parser) parseGroupedExpression() (Expression, error) {
+	lparen := p.curToken.Position
 	p.nextToken()
 
 	// Check if this is a subquery (starts with SELECT)
@@ -927,7 +1751,9 @@ func (p *Parser) parseGroupedExpression() (Expression, error) {
 		}
 		p.nextToken()
 
-		return &SubqueryExpression{Query: subquery}, nil
+		expr := &SubqueryExpression{Query: subquery}
+		p.recordSpan(expr, lparen)
+		return expr, nil
 	}
 
 	exp, err := p.parseExpression()
@@ -939,6 +1765,7 @@ func (p *Parser) parseGroupedExpression() (Expression, error) {
 		return nil, fmt.Errorf("expected ')' to close grouped expression, got %s", p.curToken.Literal)
 	}
 	p.nextToken()
+	p.recordSpan(exp, lparen)
 
 	return exp, nil
 }
@@ -976,25 +1803,33 @@ func (p *Parser) parseExistsExpression(not bool) (Expression, error) {
 	}, nil
 }
 
-func (p *Parser) isInfixOperator(tokenType lexer.TokenType) bool {
-	switch tokenType {
-	case lexer.ASSIGN, lexer.EQ, lexer.NOT_EQ, lexer.LT, lexer.GT, lexer.LTE, lexer.GTE,
-		lexer.AND, lexer.OR, lexer.PLUS, lexer.MINUS, lexer.ASTERISK, lexer.SLASH,
-		lexer.LIKE, lexer.IN, lexer.IS:
-		return true
-	default:
-		return false
-	}
-}
-
 // Stub implementations for other statement types
 func (p *Parser) parseInsertStatement() (*InsertStatement, error) {
 	stmt := &InsertStatement{}
 
-	if !p.curTokenIs(lexer.INSERT) {
+	switch {
+	case p.curTokenIs(lexer.REPLACE):
+		// MySQL: REPLACE INTO
+		if err := p.requireUpsertSyntax(dialect.UpsertSyntaxOnDuplicateKey, "REPLACE INTO"); err != nil {
+			return nil, err
+		}
+		stmt.Replace = true
+		p.nextToken()
+		stmt.Hints = p.parseCommentHints()
+	case p.curTokenIs(lexer.INSERT):
+		p.nextToken()
+		stmt.Hints = p.parseCommentHints()
+		// MySQL: INSERT IGNORE INTO
+		if p.curIdentIs("IGNORE") {
+			if err := p.requireUpsertSyntax(dialect.UpsertSyntaxOnDuplicateKey, "INSERT IGNORE"); err != nil {
+				return nil, err
+			}
+			stmt.Ignore = true
+			p.nextToken()
+		}
+	default:
 		return nil, fmt.Errorf("expected INSERT, got %s", p.curToken.Literal)
 	}
-	p.nextToken()
 
 	// Expect INTO keyword
 	if !p.curTokenIs(lexer.INTO) {
@@ -1008,6 +1843,7 @@ func (p *Parser) parseInsertStatement() (*InsertStatement, error) {
 		return nil, fmt.Errorf("failed to parse table name: %w", err)
 	}
 	stmt.Table = *table
+	p.moveSpan(table, &stmt.Table)
 
 	// Optional: column list (col1, col2, ...)
 	if p.curTokenIs(lexer.LPAREN) {
@@ -1036,6 +1872,15 @@ func (p *Parser) parseInsertStatement() (*InsertStatement, error) {
 		}
 	}
 
+	// Optional: SQL Server OUTPUT clause
+	if p.curIdentIs("OUTPUT") {
+		output, err := p.parseOutputClause()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Output = output
+	}
+
 	// VALUES or SELECT
 	if p.curTokenIs(lexer.VALUES) {
 		p.nextToken()
@@ -1085,6 +1930,29 @@ func (p *Parser) parseInsertStatement() (*InsertStatement, error) {
 		return nil, fmt.Errorf("expected VALUES or SELECT after table name, got %s", p.curToken.Literal)
 	}
 
+	// Optional: ON CONFLICT / ON DUPLICATE KEY UPDATE
+	if err := p.parseUpsertClause(stmt); err != nil {
+		return nil, err
+	}
+
+	// Optional: RETURNING clause
+	if p.curIdentIs("RETURNING") {
+		returning, err := p.parseReturningClause()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Returning = returning
+	}
+
+	// SQL Server: OPTION (MAXDOP 4, RECOMPILE)
+	if p.curTokenIs(lexer.OPTION) {
+		hints, err := p.parseQueryHints()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Hints = append(stmt.Hints, hints...)
+	}
+
 	return stmt, nil
 }
 
@@ -1095,6 +1963,16 @@ func (p *Parser) parseUpdateStatement() (*UpdateStatement, error) {
 		return nil, fmt.Errorf("expected UPDATE, got %s", p.curToken.Literal)
 	}
 	p.nextToken()
+	stmt.Hints = p.parseCommentHints()
+
+	// Optional: SQL Server TOP (n)
+	if p.curTokenIs(lexer.TOP) {
+		top, err := p.parseTopClause()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Limit = top
+	}
 
 	// Parse table name
 	table, err := p.parseTableReference()
@@ -1102,6 +1980,7 @@ func (p *Parser) parseUpdateStatement() (*UpdateStatement, error) {
 		return nil, fmt.Errorf("failed to parse table name: %w", err)
 	}
 	stmt.Table = *table
+	p.moveSpan(table, &stmt.Table)
 
 	// Expect SET keyword
 	if !p.curTokenIs(lexer.SET) {
@@ -1110,36 +1989,19 @@ func (p *Parser) parseUpdateStatement() (*UpdateStatement, error) {
 	p.nextToken()
 
 	// Parse SET assignments: col1 = val1, col2 = val2, ...
-	for {
-		if !p.curTokenIs(lexer.IDENT) {
-			return nil, fmt.Errorf("expected column name in SET clause, got %s", p.curToken.Literal)
-		}
-
-		assignment := &Assignment{
-			Column: p.curToken.Literal,
-		}
-		p.nextToken()
-
-		// Expect = operator
-		if !p.curTokenIs(lexer.ASSIGN) {
-			return nil, fmt.Errorf("expected '=' after column name, got %s", p.curToken.Literal)
-		}
-		p.nextToken()
+	set, err := p.parseAssignments()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Set = set
 
-		// Parse value expression
-		value, err := p.parseExpression()
+	// Optional: SQL Server OUTPUT clause
+	if p.curIdentIs("OUTPUT") {
+		output, err := p.parseOutputClause()
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse value in SET clause: %w", err)
-		}
-		assignment.Value = value
-
-		stmt.Set = append(stmt.Set, assignment)
-
-		// Check for more assignments
-		if !p.curTokenIs(lexer.COMMA) {
-			break
+			return nil, err
 		}
-		p.nextToken() // consume comma
+		stmt.Output = output
 	}
 
 	// Optional: WHERE clause
@@ -1170,6 +2032,24 @@ func (p *Parser) parseUpdateStatement() (*UpdateStatement, error) {
 		stmt.Limit = limit
 	}
 
+	// Optional: RETURNING clause
+	if p.curIdentIs("RETURNING") {
+		returning, err := p.parseReturningClause()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Returning = returning
+	}
+
+	// SQL Server: OPTION (MAXDOP 4, RECOMPILE)
+	if p.curTokenIs(lexer.OPTION) {
+		hints, err := p.parseQueryHints()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Hints = append(stmt.Hints, hints...)
+	}
+
 	return stmt, nil
 }
 
@@ -1180,6 +2060,16 @@ func (p *Parser) parseDeleteStatement() (*DeleteStatement, error) {
 		return nil, fmt.Errorf("expected DELETE, got %s", p.curToken.Literal)
 	}
 	p.nextToken()
+	stmt.Hints = p.parseCommentHints()
+
+	// Optional: SQL Server TOP (n)
+	if p.curTokenIs(lexer.TOP) {
+		top, err := p.parseTopClause()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Limit = top
+	}
 
 	// Expect FROM keyword
 	if !p.curTokenIs(lexer.FROM) {
@@ -1193,6 +2083,16 @@ func (p *Parser) parseDeleteStatement() (*DeleteStatement, error) {
 		return nil, fmt.Errorf("failed to parse table name: %w", err)
 	}
 	stmt.From = *table
+	p.moveSpan(table, &stmt.From)
+
+	// Optional: SQL Server OUTPUT clause
+	if p.curIdentIs("OUTPUT") {
+		output, err := p.parseOutputClause()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Output = output
+	}
 
 	// Optional: WHERE clause
 	if p.curTokenIs(lexer.WHERE) {
@@ -1222,6 +2122,24 @@ func (p *Parser) parseDeleteStatement() (*DeleteStatement, error) {
 		stmt.Limit = limit
 	}
 
+	// Optional: RETURNING clause
+	if p.curIdentIs("RETURNING") {
+		returning, err := p.parseReturningClause()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Returning = returning
+	}
+
+	// SQL Server: OPTION (MAXDOP 4, RECOMPILE)
+	if p.curTokenIs(lexer.OPTION) {
+		hints, err := p.parseQueryHints()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Hints = append(stmt.Hints, hints...)
+	}
+
 	return stmt, nil
 }
 
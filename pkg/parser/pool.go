@@ -48,14 +48,21 @@ func GetSelectStatement() *SelectStatement {
 	stmt := selectStatementPool.Get().(*SelectStatement)
 	// Reset the statement
 	stmt.Distinct = false
-	stmt.Top = nil
+	stmt.DistinctOn = nil
 	stmt.Columns = stmt.Columns[:0]
 	stmt.From = nil
 	stmt.Joins = stmt.Joins[:0]
 	stmt.Where = nil
 	stmt.GroupBy = nil
+	stmt.WithRollup = false
 	stmt.Having = nil
+	stmt.Windows = nil
+	stmt.Qualify = nil
 	stmt.OrderBy = nil
+	stmt.Limit = nil
+	stmt.Hints = nil
+	stmt.Modifiers = nil
+	stmt.Into = nil
 	return stmt
 }
 
@@ -74,6 +81,8 @@ func GetColumnReference() *ColumnReference {
 // PutColumnReference returns a ColumnReference to the pool
 func PutColumnReference(col *ColumnReference) {
 	if col != nil {
+		col.Catalog = ""
+		col.Schema = ""
 		col.Table = ""
 		col.Column = ""
 		columnReferencePool.Put(col)
@@ -106,6 +115,10 @@ func PutJoinClause(join *JoinClause) {
 		join.JoinType = ""
 		join.Table = TableReference{}
 		join.Condition = nil
+		join.Using = nil
+		join.Natural = false
+		join.Lateral = false
+		join.Straight = false
 		joinClausePool.Put(join)
 	}
 }
@@ -203,6 +203,14 @@ func (p *Parser) parseDataType() (*DataTypeDefinition, error) {
 	if p.curTokenIs(lexer.LPAREN) {
 		p.nextToken()
 
+		// NVARCHAR(MAX), VARBINARY(MAX) (SQL Server)
+		if p.curIdentIs("MAX") && p.peekTokenIs(lexer.RPAREN) {
+			dataType.Length = MaxLength
+			p.nextToken()
+			p.nextToken()
+			return dataType, nil
+		}
+
 		// First number (length or precision)
 		if !p.curTokenIs(lexer.NUMBER) {
 			return nil, fmt.Errorf("expected number for data type size/precision")
@@ -475,8 +483,12 @@ func (p *Parser) parseProcedureStatement() (Statement, error) {
 		return p.ParseStatement()
 
 	case lexer.SET:
-		// Assignment: SET var = value
-		return p.parseAssignmentStatement()
+		// Assignment: SET var = value, or a SQL Server session option
+		return p.parseSetStatement()
+
+	case lexer.DECLARE:
+		// DECLARE among statements (SQL Server)
+		return p.parseDeclareStatement()
 
 	case lexer.IF:
 		// IF statement
@@ -563,24 +575,55 @@ func (p *Parser) parseProcedureStatement() (Statement, error) {
 		// ROLLBACK transaction
 		return p.parseRollback()
 
+	case lexer.IDENT:
+		// EXEC, PRINT (SQL Server)
+		if p.curIdentIs("EXEC") || p.curIdentIs("EXECUTE") || p.curIdentIs("PRINT") {
+			return p.parseBatchCommand()
+		}
+		return nil, fmt.Errorf("unexpected statement in procedure body: %s", p.curToken.Literal)
+
 	default:
 		return nil, fmt.Errorf("unexpected statement in procedure body: %s", p.curToken.Literal)
 	}
 }
 
 // parseAssignmentStatement parses SET var = value
+// compoundAssignOperators are the operators of SQL Server's += style
+// assignments, by the token before the =
+var compoundAssignOperators = map[string]string{
+	"+": "+", "-": "-", "*": "*", "/": "/", "%": "%", "&": "&", "|": "|", "^": "^",
+}
+
 func (p *Parser) parseAssignmentStatement() (Statement, error) {
 	stmt := &AssignmentStatement{}
 
 	// Consume SET
 	p.nextToken()
 
-	// Variable name
-	if !p.curTokenIs(lexer.IDENT) {
-		return nil, fmt.Errorf("expected variable name, got %s", p.curToken.Literal)
+	// Variable name: name, @name (SQL Server, MySQL), or a trigger's
+	// NEW.column
+	if p.curTokenIs(lexer.NEW) && p.peekTokenIs(lexer.DOT) {
+		p.nextToken()
+		p.nextToken()
+		if !p.curTokenIs(lexer.IDENT) {
+			return nil, fmt.Errorf("expected column name after NEW., got %s", p.curToken.Literal)
+		}
+		stmt.Variable = "NEW." + p.curToken.Literal
+		p.nextToken()
+	} else {
+		if !p.curTokenIs(lexer.IDENT) && !isVariable(p.curToken) {
+			return nil, fmt.Errorf("expected variable name, got %s", p.curToken.Literal)
+		}
+		stmt.Variable = p.curToken.Literal
+		p.nextToken()
+	}
+
+	// SQL Server compound assignment: SET @x += 1
+	if op, ok := compoundAssignOperators[p.curToken.Literal]; ok && p.peekTokenIs(lexer.ASSIGN) &&
+		p.peekToken.Position == p.curToken.Position+1 && p.isSQLServer() {
+		stmt.Operator = op
+		p.nextToken()
 	}
-	stmt.Variable = p.curToken.Literal
-	p.nextToken()
 
 	// = or :=
 	if !p.curTokenIs(lexer.ASSIGN) {
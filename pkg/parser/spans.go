@@ -0,0 +1,36 @@
+package parser
+
+// Span is the byte range of a node in the parsed text
+type Span struct {
+	Start int
+	End   int // offset just past the node
+}
+
+// EnableSpans makes the parser record the source span of the statements,
+// SELECT queries, expressions, table references and joins it parses, for
+// tools that rewrite the original text
+func (p *Parser) EnableSpans() {
+	p.spans = make(map[Node]Span)
+}
+
+// Span returns the source span of a node parsed with spans enabled.
+// A parenthesized expression's span includes its parentheses.
+func (p *Parser) Span(node Node) (Span, bool) {
+	span, ok := p.spans[node]
+	return span, ok
+}
+
+// recordSpan records that node runs from start to the last token consumed
+func (p *Parser) recordSpan(node Node, start int) {
+	if p.spans != nil {
+		p.spans[node] = Span{Start: start, End: p.prevEnd}
+	}
+}
+
+// moveSpan re-keys a span, for nodes copied into their parent by value
+func (p *Parser) moveSpan(from, to Node) {
+	if span, ok := p.spans[from]; ok {
+		delete(p.spans, from)
+		p.spans[to] = span
+	}
+}
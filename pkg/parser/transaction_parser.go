@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
 )
@@ -130,3 +131,50 @@ func (p *Parser) parseReleaseSavepoint() (Statement, error) {
 
 	return stmt, nil
 }
+
+// isolationLevels are the levels of SET TRANSACTION ISOLATION LEVEL, by
+// their first word
+var isolationLevels = map[string][]string{
+	"READ":         {"UNCOMMITTED", "COMMITTED"},
+	"REPEATABLE":   {"READ"},
+	"SNAPSHOT":     nil,
+	"SERIALIZABLE": nil,
+}
+
+// parseSetTransaction parses SET TRANSACTION statements
+// Syntax:
+//   - SET TRANSACTION ISOLATION LEVEL {READ UNCOMMITTED | READ COMMITTED |
+//     REPEATABLE READ | SNAPSHOT | SERIALIZABLE}
+func (p *Parser) parseSetTransaction() (Statement, error) {
+	p.nextToken() // consume SET
+	p.nextToken() // consume TRANSACTION
+
+	if !p.curIdentIs("ISOLATION") || !p.peekIdentIs("LEVEL") {
+		return nil, fmt.Errorf("expected ISOLATION LEVEL after SET TRANSACTION, got %s", p.curToken.Literal)
+	}
+	p.nextToken()
+	p.nextToken()
+
+	first := strings.ToUpper(p.curToken.Literal)
+	seconds, ok := isolationLevels[first]
+	if !ok || !p.curTokenIs(lexer.IDENT) {
+		return nil, fmt.Errorf("unknown isolation level %s", p.curToken.Literal)
+	}
+	level := first
+	p.nextToken()
+
+	if len(seconds) > 0 {
+		second := strings.ToUpper(p.curToken.Literal)
+		found := false
+		for _, s := range seconds {
+			found = found || s == second
+		}
+		if !found || !p.curTokenIs(lexer.IDENT) {
+			return nil, fmt.Errorf("unknown isolation level %s %s", level, p.curToken.Literal)
+		}
+		level += " " + second
+		p.nextToken()
+	}
+
+	return &SetTransactionStatement{IsolationLevel: level}, nil
+}
@@ -0,0 +1,329 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+)
+
+// isVariable reports whether tok is a local @variable (not an @@global)
+func isVariable(tok lexer.Token) bool {
+	return tok.Type == lexer.PARAM && strings.HasPrefix(tok.Literal, "@") && !strings.HasPrefix(tok.Literal, "@@")
+}
+
+// isSQLServer reports whether the parser follows SQL Server rules
+func (p *Parser) isSQLServer() bool {
+	return p.dialect == nil || p.dialect.Name() == "SQL Server"
+}
+
+// isStatementEnd reports whether the current token ends a statement. T-SQL
+// scripts often leave out semicolons, so the start of the next statement
+// counts as well.
+func (p *Parser) isStatementEnd() bool {
+	switch p.curToken.Type {
+	case lexer.SEMICOLON, lexer.EOF, lexer.END,
+		lexer.SELECT, lexer.INSERT, lexer.UPDATE, lexer.DELETE, lexer.MERGE, lexer.WITH,
+		lexer.CREATE, lexer.DROP, lexer.ALTER, lexer.DECLARE, lexer.SET,
+		lexer.BEGIN, lexer.COMMIT, lexer.ROLLBACK, lexer.IF, lexer.WHILE, lexer.RETURN:
+		return true
+	}
+	return p.isBatchCommand()
+}
+
+// isBatchCommand reports whether the current token starts a statement the
+// lexer leaves as an identifier
+func (p *Parser) isBatchCommand() bool {
+	return p.curIdentIs("GO") || p.curIdentIs("PRINT") || p.curIdentIs("EXEC") ||
+		p.curIdentIs("EXECUTE") || p.curIdentIs("USE")
+}
+
+// parseBatchCommand parses GO, PRINT, EXEC and USE
+func (p *Parser) parseBatchCommand() (Statement, error) {
+	switch strings.ToUpper(p.curToken.Literal) {
+	case "GO":
+		return p.parseGoStatement()
+	case "PRINT":
+		return p.parsePrintStatement()
+	case "USE":
+		return p.parseUseStatement()
+	default:
+		return p.parseExecStatement()
+	}
+}
+
+// parseDeclareStatement parses DECLARE @a INT = 1, @b NVARCHAR(50),
+// DECLARE @t TABLE (...) and DECLARE name CURSOR FOR SELECT ...
+func (p *Parser) parseDeclareStatement() (*DeclareStatement, error) {
+	stmt := &DeclareStatement{}
+	p.nextToken() // DECLARE
+
+	if p.curTokenIs(lexer.IDENT) && p.peekTokenIs(lexer.CURSOR) {
+		cursor := &CursorDecl{Name: p.curToken.Literal}
+		p.nextToken()
+		p.nextToken()
+
+		// Cursor options: LOCAL, FAST_FORWARD, READ_ONLY, ...
+		for !p.curTokenIs(lexer.FOR) && !p.curTokenIs(lexer.EOF) {
+			p.nextToken()
+		}
+		if !p.curTokenIs(lexer.FOR) {
+			return nil, fmt.Errorf("expected FOR after CURSOR, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+
+		query, err := p.parseSelectStatement()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cursor query: %w", err)
+		}
+		cursor.Query = query
+		stmt.Cursor = cursor
+		return stmt, nil
+	}
+
+	for {
+		variable, err := p.parseVariableDecl()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Variables = append(stmt.Variables, variable)
+
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	return stmt, nil
+}
+
+// parseVariableDecl parses @name [AS] type [= value] or @name TABLE (...)
+func (p *Parser) parseVariableDecl() (*VariableDecl, error) {
+	if !isVariable(p.curToken) {
+		return nil, fmt.Errorf("expected variable name, got %s", p.curToken.Literal)
+	}
+	variable := &VariableDecl{Name: p.curToken.Literal}
+	p.nextToken()
+
+	if p.curTokenIs(lexer.AS) {
+		p.nextToken()
+	}
+
+	if p.curTokenIs(lexer.TABLE) {
+		p.nextToken()
+		columns, _, err := p.parseTableElements("DECLARE TABLE")
+		if err != nil {
+			return nil, err
+		}
+		variable.DataType = &DataTypeDefinition{Name: "TABLE"}
+		variable.Table = columns
+		return variable, nil
+	}
+
+	dataType, err := p.parseDataType()
+	if err != nil {
+		return nil, err
+	}
+	variable.DataType = dataType
+
+	if p.curTokenIs(lexer.ASSIGN) {
+		p.nextToken()
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		variable.Default = value
+	}
+
+	return variable, nil
+}
+
+// parseSetStatement parses SET @var = value, SET name = value, SET
+// TRANSACTION and SQL Server session options such as SET NOCOUNT ON
+func (p *Parser) parseSetStatement() (Statement, error) {
+	if p.peekTokenIs(lexer.TRANSACTION) {
+		return p.parseSetTransaction()
+	}
+	if !p.peekTokenIs(lexer.IDENT) {
+		return p.parseAssignmentStatement()
+	}
+	p.nextToken() // SET
+	name := p.curToken.Literal
+	p.nextToken()
+
+	if p.curTokenIs(lexer.ASSIGN) {
+		p.nextToken()
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return &AssignmentStatement{Variable: name, Value: value}, nil
+	}
+
+	stmt := &SetOptionStatement{Options: []string{strings.ToUpper(name)}}
+	for p.curTokenIs(lexer.COMMA) {
+		p.nextToken()
+		if !p.curTokenIs(lexer.IDENT) {
+			return nil, fmt.Errorf("expected option name, got %s", p.curToken.Literal)
+		}
+		stmt.Options = append(stmt.Options, strings.ToUpper(p.curToken.Literal))
+		p.nextToken()
+	}
+
+	switch {
+	case p.curTokenIs(lexer.ON):
+		stmt.Value = "ON"
+	case p.curIdentIs("OFF"):
+		stmt.Value = "OFF"
+	default:
+		return nil, fmt.Errorf("expected ON or OFF after SET %s, got %s", strings.Join(stmt.Options, ", "), p.curToken.Literal)
+	}
+	p.nextToken()
+
+	return stmt, nil
+}
+
+// parseSelectItem parses a select list expression, including SQL Server's
+// SELECT @var = expr
+func (p *Parser) parseSelectItem() (Expression, error) {
+	if isVariable(p.curToken) && p.peekTokenIs(lexer.ASSIGN) && p.isSQLServer() {
+		assignment := &VariableAssignment{Variable: p.curToken.Literal}
+		p.nextToken()
+		p.nextToken()
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		assignment.Value = value
+		return assignment, nil
+	}
+	return p.parseExpression()
+}
+
+// parseExecStatement parses EXEC [@rc =] procedure [args] and EXEC (sql)
+func (p *Parser) parseExecStatement() (*ExecStatement, error) {
+	stmt := &ExecStatement{}
+	p.nextToken() // EXEC or EXECUTE
+
+	// EXEC ('SELECT ...' + @where)
+	if p.curTokenIs(lexer.LPAREN) {
+		p.nextToken()
+		sql, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if !p.curTokenIs(lexer.RPAREN) {
+			return nil, fmt.Errorf("expected ')' after EXEC string, got %s", p.curToken.Literal)
+		}
+		p.nextToken()
+		stmt.SQL = sql
+		return stmt, nil
+	}
+
+	if isVariable(p.curToken) && p.peekTokenIs(lexer.ASSIGN) {
+		stmt.ReturnVariable = p.curToken.Literal
+		p.nextToken()
+		p.nextToken()
+	}
+
+	if isVariable(p.curToken) {
+		// EXEC @procedure_name
+		stmt.Procedure = p.curToken.Literal
+		p.nextToken()
+	} else {
+		parts, err := p.parseQualifiedName("procedure")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Procedure = strings.Join(parts, ".")
+	}
+
+	for !p.isStatementEnd() {
+		arg, err := p.parseExecArgument()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Arguments = append(stmt.Arguments, arg)
+
+		if !p.curTokenIs(lexer.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	// sp_executesql @stmt, @params, ... runs its first argument
+	name := stmt.Procedure[strings.LastIndex(stmt.Procedure, ".")+1:]
+	if strings.EqualFold(name, "sp_executesql") && len(stmt.Arguments) > 0 {
+		stmt.SQL = stmt.Arguments[0].Value
+	}
+
+	return stmt, nil
+}
+
+// parseExecArgument parses [@name =] value [OUTPUT]
+func (p *Parser) parseExecArgument() (*ExecArgument, error) {
+	arg := &ExecArgument{}
+
+	if isVariable(p.curToken) && p.peekTokenIs(lexer.ASSIGN) {
+		arg.Name = p.curToken.Literal
+		p.nextToken()
+		p.nextToken()
+	}
+
+	if p.curTokenIs(lexer.DEFAULT) {
+		arg.Value = &Literal{Value: "DEFAULT"}
+		p.nextToken()
+	} else {
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EXEC argument: %w", err)
+		}
+		arg.Value = value
+	}
+
+	if p.curIdentIs("OUTPUT") || p.curTokenIs(lexer.OUT) {
+		arg.Output = true
+		p.nextToken()
+	}
+
+	return arg, nil
+}
+
+// parseUseStatement parses USE database
+func (p *Parser) parseUseStatement() (*UseStatement, error) {
+	p.nextToken() // USE
+	if !p.curTokenIs(lexer.IDENT) {
+		return nil, fmt.Errorf("expected database name, got %s", p.curToken.Literal)
+	}
+	stmt := &UseStatement{Database: p.curToken.Literal}
+	p.nextToken()
+	return stmt, nil
+}
+
+// parsePrintStatement parses PRINT expression
+func (p *Parser) parsePrintStatement() (*PrintStatement, error) {
+	p.nextToken() // PRINT
+	value, err := p.parseExpression()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PRINT: %w", err)
+	}
+	return &PrintStatement{Value: value}, nil
+}
+
+// parseGoStatement parses GO [count]
+func (p *Parser) parseGoStatement() (*GoStatement, error) {
+	stmt := &GoStatement{}
+	p.nextToken() // GO
+
+	if p.curTokenIs(lexer.NUMBER) {
+		count, err := strconv.Atoi(p.curToken.Literal)
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("expected a positive GO count, got %s", p.curToken.Literal)
+		}
+		stmt.Count = count
+		p.nextToken()
+	}
+
+	return stmt, nil
+}
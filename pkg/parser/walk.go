@@ -0,0 +1,32 @@
+package parser
+
+// Operands returns the operands of an operator, cast, IN, BETWEEN or other
+// scalar expression, or nil for any other expression. Optional operands,
+// such as the style of a CAST, are nil when absent.
+func Operands(expr Expression) []Expression {
+	switch e := expr.(type) {
+	case *BinaryExpression:
+		return []Expression{e.Left, e.Right}
+	case *UnaryExpression:
+		return []Expression{e.Operand}
+	case *CastExpression:
+		return []Expression{e.Expression, e.Style}
+	case *ExtractExpression:
+		return []Expression{e.Source}
+	case *TrimExpression:
+		return []Expression{e.Source, e.Characters}
+	case *IntervalExpression:
+		return []Expression{e.Value}
+	case *InExpression:
+		return append([]Expression{e.Expression}, e.Values...)
+	case *BetweenExpression:
+		return []Expression{e.Expression, e.Low, e.High}
+	case *GroupingSetsExpression:
+		var exprs []Expression
+		for _, set := range e.Sets {
+			exprs = append(exprs, set...)
+		}
+		return exprs
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package privileges
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+)
+
+// Grants renders the report as the GRANT statements of a dialect.
+// Privileges a dialect cannot grant, e.g. dropping a PostgreSQL table,
+// which only its owner can do, are rendered as comments.
+func (r *Report) Grants(d dialect.Dialect, grantee string) ([]string, error) {
+	var render func(Requirement, string) []string
+	switch d.Name() {
+	case "PostgreSQL":
+		render = postgresGrants
+	case "MySQL":
+		render = mysqlGrants
+	case "SQL Server":
+		render = sqlServerGrants
+	case "Oracle":
+		render = oracleGrants
+	default:
+		return nil, fmt.Errorf("%s has no GRANT statement", d.Name())
+	}
+
+	grants := []string{}
+	seen := make(map[string]bool)
+	for _, req := range r.Requirements {
+		for _, grant := range render(req, grantee) {
+			if !seen[grant] {
+				seen[grant] = true
+				grants = append(grants, grant)
+			}
+		}
+	}
+	return grants, nil
+}
+
+func postgresGrants(req Requirement, grantee string) []string {
+	switch req.Privilege {
+	case Select, Insert, Update, References:
+		return []string{fmt.Sprintf("GRANT %s%s ON %s TO %s;", req.Privilege, columnList(req.Columns), req.Object, grantee)}
+	case Delete, Trigger:
+		return []string{fmt.Sprintf("GRANT %s ON %s TO %s;", req.Privilege, req.Object, grantee)}
+	case Execute:
+		return []string{fmt.Sprintf("GRANT EXECUTE ON %s %s TO %s;", req.ObjectType, req.Object, grantee)}
+	case Create:
+		return []string{fmt.Sprintf("GRANT CREATE ON SCHEMA %s TO %s;", schemaOf(req.Object, "public"), grantee)}
+	}
+	return []string{ownership(req)}
+}
+
+func mysqlGrants(req Requirement, grantee string) []string {
+	switch req.Privilege {
+	case Select, Insert, Update, References:
+		return []string{fmt.Sprintf("GRANT %s%s ON %s TO %s;", req.Privilege, columnList(req.Columns), req.Object, grantee)}
+	case Execute:
+		return []string{fmt.Sprintf("GRANT EXECUTE ON %s %s TO %s;", req.ObjectType, req.Object, grantee)}
+	case Create:
+		switch req.ObjectType {
+		case ObjectView:
+			return []string{fmt.Sprintf("GRANT CREATE VIEW ON %s TO %s;", req.Object, grantee)}
+		case ObjectProcedure, ObjectFunction:
+			// ON * is the current database
+			database := "*"
+			if schema := schemaOf(req.Object, ""); schema != "" {
+				database = schema + ".*"
+			}
+			return []string{fmt.Sprintf("GRANT CREATE ROUTINE ON %s TO %s;", database, grantee)}
+		}
+	case Drop:
+		switch req.ObjectType {
+		case ObjectProcedure, ObjectFunction:
+			return []string{fmt.Sprintf("GRANT ALTER ROUTINE ON %s %s TO %s;", req.ObjectType, req.Object, grantee)}
+		case "DATABASE", "SCHEMA":
+			return []string{fmt.Sprintf("GRANT DROP ON %s.* TO %s;", req.Object, grantee)}
+		}
+	}
+	return []string{fmt.Sprintf("GRANT %s ON %s TO %s;", req.Privilege, req.Object, grantee)}
+}
+
+func sqlServerGrants(req Requirement, grantee string) []string {
+	switch req.Privilege {
+	case Select, Update, References:
+		return []string{fmt.Sprintf("GRANT %s ON %s%s TO %s;", req.Privilege, req.Object, columnList(req.Columns), grantee)}
+	case Insert, Delete, Execute:
+		return []string{fmt.Sprintf("GRANT %s ON %s TO %s;", req.Privilege, req.Object, grantee)}
+	case Create:
+		// Creating an object also needs ALTER on its schema
+		return []string{
+			fmt.Sprintf("GRANT CREATE %s TO %s;", req.ObjectType, grantee),
+			fmt.Sprintf("GRANT ALTER ON SCHEMA::%s TO %s;", schemaOf(req.Object, "dbo"), grantee),
+		}
+	case Drop:
+		return []string{fmt.Sprintf("GRANT CONTROL ON %s TO %s;", req.Object, grantee)}
+	}
+	// ALTER on a table covers its indexes and triggers
+	return []string{fmt.Sprintf("GRANT ALTER ON %s TO %s;", req.Object, grantee)}
+}
+
+func oracleGrants(req Requirement, grantee string) []string {
+	switch req.Privilege {
+	case Insert, Update, References:
+		return []string{fmt.Sprintf("GRANT %s%s ON %s TO %s;", req.Privilege, columnList(req.Columns), req.Object, grantee)}
+	case Select, Delete, Execute, Alter, Index:
+		// SELECT cannot be granted on columns
+		return []string{fmt.Sprintf("GRANT %s ON %s TO %s;", req.Privilege, req.Object, grantee)}
+	case Create:
+		objectType := req.ObjectType
+		if objectType == ObjectFunction {
+			objectType = ObjectProcedure
+		}
+		return []string{fmt.Sprintf("GRANT CREATE %s TO %s;", objectType, grantee)}
+	case Trigger:
+		return []string{fmt.Sprintf("GRANT CREATE TRIGGER TO %s;", grantee)}
+	}
+	return []string{ownership(req)}
+}
+
+func columnList(columns []string) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(columns, ", ") + ")"
+}
+
+// schemaOf returns the schema of a qualified name, or def
+func schemaOf(object, def string) string {
+	if i := strings.LastIndex(object, "."); i >= 0 {
+		return object[:i]
+	}
+	return def
+}
+
+func ownership(req Requirement) string {
+	return fmt.Sprintf("-- %s on %s %s requires ownership", req.Privilege, strings.ToLower(req.ObjectType), req.Object)
+}
@@ -0,0 +1,592 @@
+package privileges
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Privilege is a privilege on a database object
+type Privilege string
+
+const (
+	Select     Privilege = "SELECT"
+	Insert     Privilege = "INSERT"
+	Update     Privilege = "UPDATE"
+	Delete     Privilege = "DELETE"
+	References Privilege = "REFERENCES"
+	Trigger    Privilege = "TRIGGER" // create triggers on a table
+	Index      Privilege = "INDEX"   // create indexes on a table
+	Execute    Privilege = "EXECUTE"
+	Create     Privilege = "CREATE"
+	Alter      Privilege = "ALTER"
+	Drop       Privilege = "DROP"
+)
+
+// privilegeOrder is the order privileges are reported in
+var privilegeOrder = []Privilege{Select, Insert, Update, Delete, References, Trigger, Index, Execute, Create, Alter, Drop}
+
+// OnColumns reports whether the privilege can be needed on some columns
+// only
+func (p Privilege) OnColumns() bool {
+	return p == Select || p == Insert || p == Update || p == References
+}
+
+// Object types
+const (
+	ObjectTable     = "TABLE"
+	ObjectView      = "VIEW"
+	ObjectProcedure = "PROCEDURE"
+	ObjectFunction  = "FUNCTION"
+)
+
+// Requirement is a privilege needed on an object
+type Requirement struct {
+	Object     string    `json:"object"`
+	ObjectType string    `json:"object_type"`
+	Privilege  Privilege `json:"privilege"`
+	Columns    []string  `json:"columns,omitempty"` // empty when needed on the whole object
+}
+
+// Report is the privileges a set of statements needs
+type Report struct {
+	Requirements []Requirement `json:"requirements"`
+	Warnings     []string      `json:"warnings,omitempty"`
+}
+
+// Collector gathers the least privileges statements need. Reads are
+// precise to the column: SELECT covers the columns a statement reads in
+// any clause, UPDATE the columns it assigns, INSERT the columns it lists.
+// Views, procedure, function and trigger bodies are followed.
+type Collector struct {
+	dialect   dialect.Dialect
+	resolver  *schema.Resolver
+	needs     map[string]*need
+	ctes      []map[string]bool
+	variables map[string]bool // parameters and variables of the routine being read
+	warnings  []string
+}
+
+type need struct {
+	Requirement
+	whole bool // every column
+}
+
+// NewCollector creates a collector. With a schema, unqualified columns of
+// queries over several tables are placed; without one they make the
+// SELECT cover the whole tables.
+func NewCollector(d dialect.Dialect, s *schema.Schema) *Collector {
+	c := &Collector{dialect: d, needs: make(map[string]*need)}
+	if s != nil {
+		c.resolver = schema.NewResolver(d, s)
+	}
+	return c
+}
+
+// Report returns the privileges collected, by object and privilege
+func (c *Collector) Report() *Report {
+	report := &Report{Requirements: []Requirement{}, Warnings: c.warnings}
+	for _, n := range c.needs {
+		req := n.Requirement
+		if n.whole || !req.Privilege.OnColumns() {
+			req.Columns = nil
+		}
+		report.Requirements = append(report.Requirements, req)
+	}
+
+	rank := make(map[Privilege]int, len(privilegeOrder))
+	for i, p := range privilegeOrder {
+		rank[p] = i
+	}
+	sort.Slice(report.Requirements, func(i, j int) bool {
+		a, b := report.Requirements[i], report.Requirements[j]
+		if !strings.EqualFold(a.Object, b.Object) {
+			return strings.ToLower(a.Object) < strings.ToLower(b.Object)
+		}
+		return rank[a.Privilege] < rank[b.Privilege]
+	})
+	return report
+}
+
+// require records a privilege on an object, on some columns or on all of
+// them when columns is nil. An empty list needs the object without naming
+// columns: it is granted whole unless other statements name some.
+func (c *Collector) require(object, objectType string, privilege Privilege, columns []string) {
+	key := strings.ToLower(object) + "|" + string(privilege)
+	n, ok := c.needs[key]
+	if !ok {
+		n = &need{Requirement: Requirement{Object: object, ObjectType: objectType, Privilege: privilege}}
+		c.needs[key] = n
+	}
+	if columns == nil {
+		n.whole = true
+	}
+	for _, col := range columns {
+		if !containsFold(n.Columns, col) {
+			n.Columns = append(n.Columns, col)
+		}
+	}
+}
+
+func (c *Collector) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	for _, w := range c.warnings {
+		if w == message {
+			return
+		}
+	}
+	c.warnings = append(c.warnings, message)
+}
+
+// Add collects the privileges a statement needs
+func (c *Collector) Add(stmt parser.Statement) {
+	c.statement(stmt, nil)
+}
+
+// statement collects a statement; parent is the enclosing query of a
+// subquery
+func (c *Collector) statement(stmt parser.Statement, parent *scope) {
+	switch s := stmt.(type) {
+	case *parser.SelectStatement:
+		if s != nil {
+			c.query(s, parent)
+		}
+	case *parser.SetOperation:
+		c.statement(s.Left, parent)
+		c.statement(s.Right, parent)
+	case *parser.WithStatement:
+		c.with(s, parent)
+	case *parser.InsertStatement:
+		c.insert(s)
+	case *parser.UpdateStatement:
+		c.update(s)
+	case *parser.DeleteStatement:
+		target := c.source(&s.From)
+		c.require(target.object, ObjectTable, Delete, nil)
+		sc := &scope{sources: []*source{target}}
+		c.reads(s.Where, sc)
+		c.returning(s.Returning, s.Output, sc)
+	case *parser.MergeStatement:
+		c.merge(s)
+	case *parser.CreateTableStatement:
+		c.require(s.Table.String(), ObjectTable, Create, nil)
+		for _, col := range s.Columns {
+			c.references(col.References)
+		}
+		for _, constraint := range s.Constraints {
+			c.references(constraint.References)
+		}
+		c.statement(s.Query, nil)
+	case *parser.CreateViewStatement:
+		c.require(s.ViewName.String(), ObjectView, Create, nil)
+		c.statement(s.SelectStmt, nil)
+	case *parser.CreateIndexStatement:
+		c.require(s.Table.String(), ObjectTable, Index, nil)
+	case *parser.AlterTableStatement:
+		c.require(s.Table.String(), ObjectTable, Alter, nil)
+		for _, action := range s.Actions {
+			if action.Constraint != nil {
+				c.references(action.Constraint.References)
+			}
+			if action.Column != nil {
+				c.references(action.Column.References)
+			}
+		}
+	case *parser.DropStatement:
+		if s.ObjectType == "INDEX" {
+			c.warn("DROP INDEX %s needs the privileges of the index's table", s.ObjectName)
+			return
+		}
+		c.require(s.ObjectName, s.ObjectType, Drop, nil)
+	case *parser.ExecStatement:
+		if s.Procedure == "" {
+			c.warn("dynamic SQL is not analyzed: %s", s.String())
+			return
+		}
+		c.require(s.Procedure, ObjectProcedure, Execute, nil)
+	case *parser.ExplainStatement:
+		c.statement(s.Statement, nil)
+	case *parser.DeclareStatement:
+		for _, v := range s.Variables {
+			c.declare(v.Name)
+		}
+		if s.Cursor != nil {
+			c.statement(s.Cursor.Query, nil)
+		}
+	case *parser.CreateProcedureStatement:
+		c.require(s.Name, ObjectProcedure, Create, nil)
+		c.routine(s.Parameters, s.Body)
+	case *parser.CreateFunctionStatement:
+		c.require(s.Name, ObjectFunction, Create, nil)
+		c.routine(s.Parameters, s.Body)
+	case *parser.CreateTriggerStatement:
+		c.require(s.TableName.String(), ObjectTable, Trigger, nil)
+		c.routine(nil, s.Body)
+	default:
+		c.control(stmt)
+	}
+}
+
+// references records REFERENCES on the columns a foreign key points to
+func (c *Collector) references(ref *parser.ForeignKeyReference) {
+	if ref == nil {
+		return
+	}
+	c.require(ref.Table, ObjectTable, References, ref.Columns)
+}
+
+func (c *Collector) with(ws *parser.WithStatement, parent *scope) {
+	// Without RECURSIVE, a CTE is visible to the ones after it
+	names := make(map[string]bool, len(ws.CTEs))
+	if ws.Recursive {
+		for _, cte := range ws.CTEs {
+			names[strings.ToLower(cte.Name)] = true
+		}
+	}
+	c.ctes = append(c.ctes, names)
+	for _, cte := range ws.CTEs {
+		c.statement(cte.Query, parent)
+		names[strings.ToLower(cte.Name)] = true
+	}
+	c.statement(ws.Query, parent)
+	c.ctes = c.ctes[:len(c.ctes)-1]
+}
+
+func (c *Collector) isCTE(ref *parser.TableReference) bool {
+	if ref.Schema != "" || ref.Catalog != "" {
+		return false
+	}
+	for _, names := range c.ctes {
+		if names[strings.ToLower(ref.Name)] {
+			return true
+		}
+	}
+	return false
+}
+
+// query collects a SELECT whose correlated columns may read parent
+func (c *Collector) query(sel *parser.SelectStatement, parent *scope) {
+	sc := &scope{parent: parent}
+	if sel.From != nil {
+		for i := range sel.From.Tables {
+			sc.sources = append(sc.sources, c.from(&sel.From.Tables[i], parent))
+		}
+	}
+	for _, join := range sel.Joins {
+		sc.sources = append(sc.sources, c.from(&join.Table, parent))
+	}
+
+	// Table-valued functions read their arguments, which may be columns
+	// of the sources before them, as in CROSS APPLY
+	if sel.From != nil {
+		for _, ref := range sel.From.Tables {
+			if ref.Function != nil {
+				c.reads(ref.Function, sc)
+			}
+		}
+	}
+	for _, join := range sel.Joins {
+		if join.Table.Function != nil {
+			c.reads(join.Table.Function, sc)
+		}
+	}
+
+	if sel.Into != nil && sel.Into.Name != "" && !strings.HasPrefix(sel.Into.Name, "@") {
+		c.require(sel.Into.String(), ObjectTable, Create, nil)
+	}
+
+	for i, join := range sel.Joins {
+		c.reads(join.Condition, sc)
+		// USING columns are read from the joined table and the one before
+		right := len(sc.sources) - len(sel.Joins) + i
+		for _, col := range join.Using {
+			for _, src := range sc.sources[max(right-1, 0) : right+1] {
+				c.readColumn(src, col)
+			}
+		}
+	}
+	for _, expr := range sel.DistinctOn {
+		c.reads(expr, sc)
+	}
+	for _, col := range sel.Columns {
+		c.reads(col, sc)
+	}
+	c.reads(sel.Where, sc)
+	for _, expr := range sel.GroupBy {
+		c.reads(expr, sc)
+	}
+	c.reads(sel.Having, sc)
+	for _, window := range sel.Windows {
+		c.over(window.Spec, sc)
+	}
+	c.reads(sel.Qualify, sc)
+	for _, item := range sel.OrderBy {
+		// ORDER BY may name an output column rather than a table's
+		if col, ok := item.Expression.(*parser.ColumnReference); ok && col.Table == "" && selectsAlias(sel, col.Column) {
+			continue
+		}
+		c.reads(item.Expression, sc)
+	}
+}
+
+func selectsAlias(sel *parser.SelectStatement, name string) bool {
+	for _, col := range sel.Columns {
+		if aliased, ok := col.(*parser.AliasedExpression); ok && strings.EqualFold(aliased.Alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// from returns the source a FROM or JOIN reference reads
+func (c *Collector) from(ref *parser.TableReference, parent *scope) *source {
+	if ref.Subquery != nil {
+		// Derived tables see the enclosing query's scope, not their siblings
+		c.query(ref.Subquery, parent)
+		return &source{alias: ref.Alias}
+	}
+	if ref.Function != nil {
+		return &source{alias: ref.Alias}
+	}
+	if c.isCTE(ref) {
+		return &source{name: ref.Name, alias: ref.Alias}
+	}
+	src := c.source(ref)
+	// A table read only for its rows, e.g. by COUNT(*), still needs SELECT
+	c.require(src.object, ObjectTable, Select, []string{})
+	return src
+}
+
+// source returns a table as a source of columns
+func (c *Collector) source(ref *parser.TableReference) *source {
+	src := &source{object: ref.String(), name: ref.Name, alias: ref.Alias}
+	if c.resolver != nil {
+		src.table, _ = c.resolver.ResolveTable(ref)
+	}
+	return src
+}
+
+func (c *Collector) insert(s *parser.InsertStatement) {
+	target := c.source(&s.Table)
+	var columns []string // every column
+	if len(s.Columns) > 0 {
+		columns = s.Columns
+	}
+	c.require(target.object, ObjectTable, Insert, columns)
+
+	for _, row := range s.Values {
+		for _, value := range row {
+			c.reads(value, nil)
+		}
+	}
+	if s.Select != nil {
+		c.query(s.Select, nil)
+	}
+
+	// Upserts update the conflicting row
+	sc := &scope{sources: []*source{target}}
+	var set []*parser.Assignment
+	if oc := s.OnConflict; oc != nil {
+		set = oc.Set
+		c.reads(oc.Where, sc)
+	}
+	set = append(set, s.OnDuplicateKeyUpdate...)
+	if len(set) > 0 {
+		c.assign(target, set, sc)
+	}
+	c.returning(s.Returning, s.Output, sc)
+}
+
+func (c *Collector) update(s *parser.UpdateStatement) {
+	target := c.source(&s.Table)
+	sc := &scope{sources: []*source{target}}
+	c.assign(target, s.Set, sc)
+	c.reads(s.Where, sc)
+	for _, item := range s.OrderBy {
+		c.reads(item.Expression, sc)
+	}
+	c.returning(s.Returning, s.Output, sc)
+}
+
+// assign records UPDATE on assigned columns and SELECT on the columns the
+// new values read
+func (c *Collector) assign(target *source, set []*parser.Assignment, sc *scope) {
+	columns := []string{}
+	for _, assignment := range set {
+		columns = append(columns, unqualified(assignment.Column))
+		c.reads(assignment.Value, sc)
+	}
+	c.require(target.object, ObjectTable, Update, columns)
+}
+
+// returning records SELECT on the columns RETURNING and OUTPUT read, and
+// INSERT on an OUTPUT INTO table
+func (c *Collector) returning(returning *parser.ReturningClause, output *parser.OutputClause, sc *scope) {
+	if returning != nil {
+		for _, col := range returning.Columns {
+			c.reads(col, sc)
+		}
+	}
+	if output != nil {
+		// inserted.x and deleted.x are pseudo-tables of the target
+		for _, col := range output.Columns {
+			c.reads(col, sc)
+		}
+		if output.Into != nil {
+			c.require(output.Into.String(), ObjectTable, Insert, nil)
+		}
+	}
+}
+
+func (c *Collector) merge(s *parser.MergeStatement) {
+	target := c.source(&s.TargetTable)
+	sc := &scope{sources: []*source{target}}
+
+	switch src := s.SourceTable.(type) {
+	case parser.TableReference:
+		sc.sources = append(sc.sources, c.mergeSource(&src, s.SourceAlias))
+	case *parser.TableReference:
+		sc.sources = append(sc.sources, c.mergeSource(src, s.SourceAlias))
+	case *parser.SelectStatement:
+		c.query(src, nil)
+		sc.sources = append(sc.sources, &source{alias: s.SourceAlias})
+	}
+	c.reads(s.OnCondition, sc)
+
+	for _, clauses := range [][]*parser.MergeWhenClause{s.WhenMatched, s.WhenNotMatched, s.WhenNotMatchedBy} {
+		for _, when := range clauses {
+			c.reads(when.Condition, sc)
+			if when.Action == nil {
+				continue
+			}
+			for _, value := range when.Action.Values {
+				c.reads(value, sc)
+			}
+			columns := []string{}
+			for _, col := range when.Action.Columns {
+				columns = append(columns, unqualified(col))
+			}
+			switch when.Action.ActionType {
+			case "UPDATE":
+				c.require(target.object, ObjectTable, Update, columns)
+			case "INSERT":
+				if len(columns) == 0 {
+					columns = nil
+				}
+				c.require(target.object, ObjectTable, Insert, columns)
+			case "DELETE":
+				c.require(target.object, ObjectTable, Delete, nil)
+			}
+		}
+	}
+}
+
+func (c *Collector) mergeSource(ref *parser.TableReference, alias string) *source {
+	src := c.from(ref, nil)
+	if alias != "" {
+		src.alias = alias
+	}
+	return src
+}
+
+// routine collects a procedure, function or trigger body
+func (c *Collector) routine(params []*parser.ProcedureParameter, body *parser.ProcedureBody) {
+	outer := c.variables
+	c.variables = make(map[string]bool)
+	for name := range outer {
+		c.variables[name] = true
+	}
+	for _, param := range params {
+		c.declare(param.Name)
+	}
+	if body != nil {
+		for _, v := range body.Variables {
+			c.declare(v.Name)
+		}
+		for _, cursor := range body.Cursors {
+			c.declare(cursor.Name)
+			c.statement(cursor.Query, nil)
+		}
+		c.block(body.Statements)
+		if body.ExceptionBlock != nil {
+			for _, when := range body.ExceptionBlock.WhenClauses {
+				c.block(when.Body)
+			}
+		}
+	}
+	c.variables = outer
+}
+
+func (c *Collector) declare(name string) {
+	if c.variables == nil {
+		c.variables = make(map[string]bool)
+	}
+	c.variables[strings.ToLower(name)] = true
+}
+
+// block collects the statements of a routine body
+func (c *Collector) block(statements []parser.Statement) {
+	for _, stmt := range statements {
+		c.statement(stmt, nil)
+	}
+}
+
+// control collects the conditions and blocks of control flow
+func (c *Collector) control(stmt parser.Statement) {
+	switch s := stmt.(type) {
+	case *parser.IfStatement:
+		c.reads(s.Condition, nil)
+		c.block(s.ThenBlock)
+		for _, elseIf := range s.ElseIfList {
+			c.reads(elseIf.Condition, nil)
+			c.block(elseIf.Block)
+		}
+		c.block(s.ElseBlock)
+	case *parser.WhileStatement:
+		c.reads(s.Condition, nil)
+		c.block(s.Block)
+	case *parser.LoopStatement:
+		c.block(s.Block)
+	case *parser.RepeatStatement:
+		c.block(s.Body)
+		c.reads(s.Condition, nil)
+	case *parser.ForStatement:
+		c.declare(s.Variable)
+		c.block(s.Block)
+	case *parser.CaseStatement:
+		for _, when := range s.WhenList {
+			c.reads(when.Condition, nil)
+			c.block(when.Block)
+		}
+		c.block(s.ElseBlock)
+	case *parser.TryStatement:
+		c.block(s.TryBlock)
+		if s.CatchBlock != nil {
+			c.block(s.CatchBlock.Body)
+		}
+	case *parser.ReturnStatement:
+		c.reads(s.Value, nil)
+	case *parser.AssignmentStatement:
+		c.reads(s.Value, nil)
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func unqualified(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,174 @@
+package privileges
+
+import (
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// source is a relation a query reads columns from. CTEs and derived
+// tables have no object: their bodies are collected on their own.
+type source struct {
+	object string // qualified table name; empty for CTEs and derived tables
+	name   string
+	alias  string
+	table  *schema.Table // nil when there is no schema or the table is unknown
+}
+
+// matches reports whether a column qualifier names the source
+func (s *source) matches(qualifier string) bool {
+	if s.alias != "" {
+		return strings.EqualFold(s.alias, qualifier)
+	}
+	return s.name != "" && strings.EqualFold(s.name, qualifier)
+}
+
+// scope is the sources of a query; parent is the enclosing query a
+// correlated subquery reads from
+type scope struct {
+	sources []*source
+	parent  *scope
+}
+
+// reads records SELECT on the columns an expression reads and collects its
+// subqueries
+func (c *Collector) reads(expr parser.Expression, sc *scope) {
+	switch e := expr.(type) {
+	case nil:
+	case *parser.ColumnReference:
+		c.column(e, sc)
+	case *parser.StarExpression:
+		c.star(e.Table, sc)
+	case *parser.AliasedExpression:
+		c.reads(e.Expression, sc)
+	case *parser.SubqueryExpression:
+		if e.Query != nil {
+			c.query(e.Query, sc)
+		}
+	case *parser.ExistsExpression:
+		c.statement(e.Subquery, sc)
+	case *parser.FunctionCall:
+		for _, arg := range e.Arguments {
+			// COUNT(*) reads rows, not columns
+			if _, ok := arg.(*parser.StarExpression); !ok {
+				c.reads(arg, sc)
+			}
+		}
+		c.reads(e.Filter, sc)
+		for _, item := range e.OrderBy {
+			c.reads(item.Expression, sc)
+		}
+		for _, item := range e.WithinGroup {
+			c.reads(item.Expression, sc)
+		}
+	case *parser.WindowFunction:
+		if e.Function != nil {
+			c.reads(e.Function, sc)
+		}
+		c.over(e.OverClause, sc)
+	case *parser.CaseExpression:
+		c.reads(e.Input, sc)
+		for _, when := range e.WhenClauses {
+			c.reads(when.Condition, sc)
+			c.reads(when.Result, sc)
+		}
+		c.reads(e.ElseResult, sc)
+	default:
+		for _, child := range parser.Operands(expr) {
+			c.reads(child, sc)
+		}
+	}
+}
+
+func (c *Collector) over(oc *parser.OverClause, sc *scope) {
+	if oc == nil {
+		return
+	}
+	for _, expr := range oc.PartitionBy {
+		c.reads(expr, sc)
+	}
+	for _, item := range oc.OrderBy {
+		c.reads(item.Expression, sc)
+	}
+}
+
+// column places a column read in the innermost scope that has it
+func (c *Collector) column(col *parser.ColumnReference, sc *scope) {
+	if col.Table != "" {
+		// Unknown qualifiers are NEW, OLD, EXCLUDED, inserted, deleted or
+		// record variables
+		for s := sc; s != nil; s = s.parent {
+			for _, src := range s.sources {
+				if src.matches(col.Table) {
+					c.readColumn(src, col.Column)
+					return
+				}
+			}
+		}
+		return
+	}
+	if c.variables[strings.ToLower(col.Column)] || strings.HasPrefix(col.Column, "@") {
+		return
+	}
+
+	for s := sc; s != nil; s = s.parent {
+		var matches []*source
+		known := true
+		for _, src := range s.sources {
+			if src.table == nil {
+				known = false
+			} else if src.table.HasColumn(col.Column) {
+				matches = append(matches, src)
+			}
+		}
+		if len(matches) == 1 {
+			c.readColumn(matches[0], col.Column)
+			return
+		}
+		if len(matches) == 0 && known {
+			continue // a correlated column
+		}
+		if len(s.sources) == 0 {
+			continue
+		}
+		if len(s.sources) == 1 {
+			c.readColumn(s.sources[0], col.Column)
+			return
+		}
+
+		// Ambiguous without a schema: SELECT has to cover every table
+		c.warn("column %s is not qualified; SELECT covers every table of its query", col.Column)
+		for _, src := range s.sources {
+			if src.object != "" {
+				c.require(src.object, ObjectTable, Select, nil)
+			}
+		}
+		return
+	}
+}
+
+// readColumn records SELECT on a column of a source
+func (c *Collector) readColumn(src *source, column string) {
+	if src.object == "" {
+		return
+	}
+	if column == "*" {
+		c.require(src.object, ObjectTable, Select, nil)
+		return
+	}
+	c.require(src.object, ObjectTable, Select, []string{column})
+}
+
+// star records SELECT on every column of a qualified table, or of every
+// table of the query
+func (c *Collector) star(qualifier string, sc *scope) {
+	if sc == nil {
+		return
+	}
+	for _, src := range sc.sources {
+		if qualifier == "" || src.matches(qualifier) {
+			c.readColumn(src, "*")
+		}
+	}
+}
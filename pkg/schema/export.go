@@ -0,0 +1,393 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportFormat identifies an output format supported by the Exporter
+type ExportFormat string
+
+const (
+	ExportFormatJSON    ExportFormat = "json"
+	ExportFormatYAML    ExportFormat = "yaml"
+	ExportFormatDDL     ExportFormat = "ddl"
+	ExportFormatDOT     ExportFormat = "dot"
+	ExportFormatMermaid ExportFormat = "mermaid"
+)
+
+// ParseExportFormat converts a format name (json, yaml, ddl, dot, mermaid) into an ExportFormat
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return ExportFormatJSON, nil
+	case "yaml", "yml":
+		return ExportFormatYAML, nil
+	case "ddl", "sql":
+		return ExportFormatDDL, nil
+	case "dot", "graphviz":
+		return ExportFormatDOT, nil
+	case "mermaid", "mmd":
+		return ExportFormatMermaid, nil
+	default:
+		return "", fmt.Errorf("unsupported schema export format: %s", name)
+	}
+}
+
+// Exporter writes schemas to JSON, YAML, DDL or ER diagram formats.
+// JSON and YAML output uses the same layout as SchemaLoader, so exported
+// files can be loaded back.
+type Exporter struct {
+	dialect dialect.Dialect
+}
+
+// NewExporter creates a new exporter. The dialect controls identifier
+// quoting in DDL output.
+func NewExporter(d dialect.Dialect) *Exporter {
+	return &Exporter{
+		dialect: d,
+	}
+}
+
+// Export writes the schema to w in the given format
+func (e *Exporter) Export(s *Schema, format ExportFormat, w io.Writer) error {
+	switch format {
+	case ExportFormatJSON:
+		return e.ExportJSON(s, w)
+	case ExportFormatYAML:
+		return e.ExportYAML(s, w)
+	case ExportFormatDDL:
+		return e.ExportDDL(s, w)
+	case ExportFormatDOT:
+		return e.ExportDOT(s, w)
+	case ExportFormatMermaid:
+		return e.ExportMermaid(s, w)
+	default:
+		return fmt.Errorf("unsupported schema export format: %s", format)
+	}
+}
+
+// ExportJSON writes the schema as JSON loadable by SchemaLoader.LoadFromJSON
+func (e *Exporter) ExportJSON(s *Schema, w io.Writer) error {
+	data, err := json.MarshalIndent(newSchemaDocument(s), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON schema: %w", err)
+	}
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
+
+// ExportYAML writes the schema as YAML loadable by SchemaLoader.LoadFromYAML
+func (e *Exporter) ExportYAML(s *Schema, w io.Writer) error {
+	data, err := yaml.Marshal(newSchemaDocument(s))
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML schema: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// ExportDDL writes CREATE TABLE and CREATE INDEX statements for the schema.
+// Tables are ordered so that referenced tables are created first.
+func (e *Exporter) ExportDDL(s *Schema, w io.Writer) error {
+	var buf bytes.Buffer
+
+	for i, table := range dependencyOrder(s) {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		e.writeCreateTable(&buf, table)
+
+		for _, idx := range table.OrderedIndexes() {
+			e.writeCreateIndex(&buf, table, idx)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (e *Exporter) writeCreateTable(buf *bytes.Buffer, table *Table) {
+	var primaryKey []string
+	for _, col := range table.OrderedColumns() {
+		if col.IsPrimaryKey {
+			primaryKey = append(primaryKey, col.Name)
+		}
+	}
+
+	lines := make([]string, 0, len(table.Columns)+1)
+	var foreignKeys []string
+
+	for _, col := range table.OrderedColumns() {
+		line := "  " + e.quote(col.Name)
+		if col.DataType != nil {
+			line += " " + col.DataType.String()
+			if !col.DataType.Nullable && !col.IsPrimaryKey {
+				line += " NOT NULL"
+			}
+		}
+		if col.DefaultValue != nil {
+			line += " DEFAULT " + formatDefaultValue(col.DefaultValue)
+		}
+		if col.IsPrimaryKey && len(primaryKey) == 1 {
+			line += " PRIMARY KEY"
+		}
+		if col.IsUnique && !col.IsPrimaryKey {
+			line += " UNIQUE"
+		}
+		lines = append(lines, line)
+
+		if col.IsForeignKey && col.ForeignKey != nil {
+			foreignKeys = append(foreignKeys, fmt.Sprintf("  FOREIGN KEY (%s) REFERENCES %s (%s)",
+				e.quote(col.Name), e.quote(col.ForeignKey.Table), e.quote(col.ForeignKey.Column)))
+		}
+	}
+
+	if len(primaryKey) > 1 {
+		quoted := make([]string, len(primaryKey))
+		for i, name := range primaryKey {
+			quoted[i] = e.quote(name)
+		}
+		lines = append(lines, fmt.Sprintf("  PRIMARY KEY (%s)", strings.Join(quoted, ", ")))
+	}
+	lines = append(lines, foreignKeys...)
+
+	fmt.Fprintf(buf, "CREATE TABLE %s (\n%s\n);\n", e.tableName(table), strings.Join(lines, ",\n"))
+}
+
+func (e *Exporter) writeCreateIndex(buf *bytes.Buffer, table *Table, idx *Index) {
+	quoted := make([]string, len(idx.Columns))
+	for i, name := range idx.Columns {
+		quoted[i] = e.quote(name)
+	}
+
+	unique := ""
+	if idx.IsUnique {
+		unique = "UNIQUE "
+	}
+
+	fmt.Fprintf(buf, "CREATE %sINDEX %s ON %s (%s);\n",
+		unique, e.quote(idx.Name), e.tableName(table), strings.Join(quoted, ", "))
+}
+
+// ExportDOT writes the schema as a Graphviz ER diagram
+func (e *Exporter) ExportDOT(s *Schema, w io.Writer) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "digraph %q {\n", s.Name)
+	buf.WriteString("  rankdir=LR;\n")
+	buf.WriteString("  node [shape=record, fontname=\"Helvetica\"];\n")
+
+	tables := s.OrderedTables()
+	for _, table := range tables {
+		fields := make([]string, 0, len(table.Columns))
+		for _, col := range table.OrderedColumns() {
+			field := col.Name
+			if col.DataType != nil {
+				field += " : " + col.DataType.String()
+			}
+			if keys := columnKeys(col); len(keys) > 0 {
+				field += " (" + strings.Join(keys, ", ") + ")"
+			}
+			fields = append(fields, escapeRecordLabel(field)+"\\l")
+		}
+		fmt.Fprintf(&buf, "  %q [label=\"{%s|%s}\"];\n",
+			table.Name, escapeRecordLabel(table.Name), strings.Join(fields, ""))
+	}
+
+	for _, table := range tables {
+		for _, col := range table.OrderedColumns() {
+			if !col.IsForeignKey || col.ForeignKey == nil {
+				continue
+			}
+			fmt.Fprintf(&buf, "  %q -> %q [label=%q];\n",
+				table.Name, col.ForeignKey.Table, col.Name+" -> "+col.ForeignKey.Column)
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ExportMermaid writes the schema as a Mermaid erDiagram
+func (e *Exporter) ExportMermaid(s *Schema, w io.Writer) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("erDiagram\n")
+
+	tables := s.OrderedTables()
+	for _, table := range tables {
+		fmt.Fprintf(&buf, "    %s {\n", mermaidName(table.Name))
+		for _, col := range table.OrderedColumns() {
+			typeName := "UNKNOWN"
+			comment := ""
+			if col.DataType != nil {
+				typeName = mermaidName(col.DataType.Name)
+				if full := col.DataType.String(); full != col.DataType.Name {
+					comment = fmt.Sprintf(" %q", full)
+				}
+			}
+			keys := ""
+			if k := columnKeys(col); len(k) > 0 {
+				keys = " " + strings.Join(k, ", ")
+			}
+			fmt.Fprintf(&buf, "        %s %s%s%s\n", typeName, mermaidName(col.Name), keys, comment)
+		}
+		buf.WriteString("    }\n")
+	}
+
+	for _, table := range tables {
+		for _, col := range table.OrderedColumns() {
+			if !col.IsForeignKey || col.ForeignKey == nil {
+				continue
+			}
+			cardinality := "}o--||"
+			if col.IsUnique || col.IsPrimaryKey {
+				cardinality = "|o--||"
+			}
+			fmt.Fprintf(&buf, "    %s %s %s : %q\n",
+				mermaidName(table.Name), cardinality, mermaidName(col.ForeignKey.Table), col.Name)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// quote quotes an identifier for the exporter's dialect
+func (e *Exporter) quote(identifier string) string {
+	if e.dialect == nil {
+		return identifier
+	}
+	return e.dialect.QuoteIdentifier(identifier)
+}
+
+// tableName returns the quoted, optionally schema-qualified table name
+func (e *Exporter) tableName(table *Table) string {
+	if table.Schema != "" {
+		return e.quote(table.Schema) + "." + e.quote(table.Name)
+	}
+	return e.quote(table.Name)
+}
+
+// dependencyOrder returns tables so that foreign key targets precede the
+// tables referencing them. Cycles fall back to alphabetical order.
+func dependencyOrder(s *Schema) []*Table {
+	tables := s.OrderedTables()
+	ordered := make([]*Table, 0, len(tables))
+	visited := make(map[string]bool, len(tables))
+	visiting := make(map[string]bool)
+
+	var visit func(table *Table)
+	visit = func(table *Table) {
+		key := tableKey(table.Schema, table.Name)
+		if visited[key] || visiting[key] {
+			return
+		}
+		visiting[key] = true
+		for _, col := range table.OrderedColumns() {
+			if col.ForeignKey == nil {
+				continue
+			}
+			if ref, ok := s.GetTable(col.ForeignKey.Table); ok {
+				visit(ref)
+			}
+		}
+		visiting[key] = false
+		visited[key] = true
+		ordered = append(ordered, table)
+	}
+
+	for _, table := range tables {
+		visit(table)
+	}
+
+	return ordered
+}
+
+// columnKeys returns the key markers (PK, FK, UK) for a column
+func columnKeys(col *Column) []string {
+	var keys []string
+	if col.IsPrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if col.IsForeignKey {
+		keys = append(keys, "FK")
+	}
+	if col.IsUnique && !col.IsPrimaryKey {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
+
+// formatDefaultValue renders a column default for DDL output. SQL
+// expressions, and strings that look like them (CURRENT_TIMESTAMP, NOW()),
+// are emitted as-is.
+func formatDefaultValue(value interface{}) string {
+	switch v := value.(type) {
+	case SQLExpression:
+		return string(v)
+	case string:
+		if isSQLExpression(v) {
+			return v
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func isSQLExpression(value string) bool {
+	if value == "" {
+		return false
+	}
+	if strings.HasSuffix(value, ")") && strings.Contains(value, "(") {
+		return true
+	}
+	upper := strings.ToUpper(value)
+	if upper != value {
+		return false
+	}
+	for _, r := range value {
+		if !(r >= 'A' && r <= 'Z' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeRecordLabel escapes characters with special meaning in Graphviz record labels
+func escapeRecordLabel(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`, `"`, `\"`, `{`, `\{`, `}`, `\}`,
+		`|`, `\|`, `<`, `\<`, `>`, `\>`,
+	)
+	return replacer.Replace(s)
+}
+
+// mermaidName converts a name to a Mermaid-safe entity or attribute token
+func mermaidName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,583 @@
+package schema
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+)
+
+// Catalog query names
+const (
+	CatalogColumnsQuery = "columns"
+	CatalogIndexesQuery = "indexes"
+)
+
+// CatalogQuery is a catalog (information_schema, pg_catalog, sys.*, ...)
+// query whose result set, saved as CSV or JSON, can be loaded back with
+// SchemaLoader.LoadFromCatalog.
+//
+// The columns query returns one row per column with the fields table_schema,
+// table_name, column_name, ordinal_position, data_type, length,
+// numeric_precision, numeric_scale, nullable, primary_key, unique_key, fk_table, fk_column and
+// column_default. The indexes query returns one row per indexed column with
+// the fields table_schema, table_name, index_name, column_name,
+// column_position and is_unique.
+type CatalogQuery struct {
+	Name        string // CatalogColumnsQuery or CatalogIndexesQuery
+	Description string
+	SQL         string
+}
+
+// IntrospectionQueries returns the catalog queries for a dialect
+func IntrospectionQueries(d dialect.Dialect) ([]CatalogQuery, error) {
+	if d == nil {
+		return nil, fmt.Errorf("no dialect given for introspection queries")
+	}
+
+	var columns, indexes string
+	switch d.Name() {
+	case "PostgreSQL":
+		columns, indexes = postgresColumnsQuery, postgresIndexesQuery
+	case "MySQL":
+		columns, indexes = mysqlColumnsQuery, mysqlIndexesQuery
+	case "SQL Server":
+		columns, indexes = sqlServerColumnsQuery, sqlServerIndexesQuery
+	case "SQLite":
+		columns, indexes = sqliteColumnsQuery, sqliteIndexesQuery
+	case "Oracle":
+		columns, indexes = oracleColumnsQuery, oracleIndexesQuery
+	default:
+		return nil, fmt.Errorf("no introspection queries for dialect %s", d.Name())
+	}
+
+	return []CatalogQuery{
+		{
+			Name:        CatalogColumnsQuery,
+			Description: fmt.Sprintf("%s columns, keys and defaults", d.Name()),
+			SQL:         columns,
+		},
+		{
+			Name:        CatalogIndexesQuery,
+			Description: fmt.Sprintf("%s index definitions", d.Name()),
+			SQL:         indexes,
+		},
+	}, nil
+}
+
+const postgresColumnsQuery = `SELECT c.table_schema,
+       c.table_name,
+       c.column_name,
+       c.ordinal_position,
+       upper(c.data_type) AS data_type,
+       c.character_maximum_length AS length,
+       c.numeric_precision AS numeric_precision,
+       c.numeric_scale AS numeric_scale,
+       CASE WHEN c.is_nullable = 'YES' THEN 1 ELSE 0 END AS nullable,
+       CASE WHEN EXISTS (
+           SELECT 1
+           FROM pg_catalog.pg_constraint con
+           JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+           JOIN pg_catalog.pg_namespace ns ON ns.oid = cl.relnamespace
+           JOIN pg_catalog.pg_attribute a ON a.attrelid = cl.oid AND a.attnum = ANY (con.conkey)
+           WHERE con.contype = 'p' AND ns.nspname = c.table_schema
+             AND cl.relname = c.table_name AND a.attname = c.column_name
+       ) THEN 1 ELSE 0 END AS primary_key,
+       CASE WHEN EXISTS (
+           SELECT 1
+           FROM pg_catalog.pg_constraint con
+           JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+           JOIN pg_catalog.pg_namespace ns ON ns.oid = cl.relnamespace
+           JOIN pg_catalog.pg_attribute a ON a.attrelid = cl.oid AND a.attnum = ANY (con.conkey)
+           WHERE con.contype = 'u' AND array_length(con.conkey, 1) = 1 AND ns.nspname = c.table_schema
+             AND cl.relname = c.table_name AND a.attname = c.column_name
+       ) THEN 1 ELSE 0 END AS unique_key,
+       fk.fk_table,
+       fk.fk_column,
+       c.column_default
+FROM information_schema.columns c
+LEFT JOIN LATERAL (
+    SELECT rcl.relname AS fk_table, ra.attname AS fk_column
+    FROM pg_catalog.pg_constraint con
+    JOIN pg_catalog.pg_class cl ON cl.oid = con.conrelid
+    JOIN pg_catalog.pg_namespace ns ON ns.oid = cl.relnamespace
+    JOIN pg_catalog.pg_attribute a ON a.attrelid = cl.oid AND a.attnum = con.conkey[1]
+    JOIN pg_catalog.pg_class rcl ON rcl.oid = con.confrelid
+    JOIN pg_catalog.pg_attribute ra ON ra.attrelid = rcl.oid AND ra.attnum = con.confkey[1]
+    WHERE con.contype = 'f' AND array_length(con.conkey, 1) = 1 AND ns.nspname = c.table_schema
+      AND cl.relname = c.table_name AND a.attname = c.column_name
+    LIMIT 1
+) fk ON true
+WHERE c.table_schema NOT IN ('pg_catalog', 'information_schema')
+ORDER BY c.table_schema, c.table_name, c.ordinal_position;`
+
+const postgresIndexesQuery = `SELECT ns.nspname AS table_schema,
+       tbl.relname AS table_name,
+       idx.relname AS index_name,
+       a.attname AS column_name,
+       k.ord AS column_position,
+       CASE WHEN i.indisunique THEN 1 ELSE 0 END AS is_unique
+FROM pg_catalog.pg_index i
+JOIN pg_catalog.pg_class tbl ON tbl.oid = i.indrelid
+JOIN pg_catalog.pg_class idx ON idx.oid = i.indexrelid
+JOIN pg_catalog.pg_namespace ns ON ns.oid = tbl.relnamespace
+CROSS JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord)
+JOIN pg_catalog.pg_attribute a ON a.attrelid = tbl.oid AND a.attnum = k.attnum
+WHERE ns.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')
+ORDER BY ns.nspname, tbl.relname, idx.relname, k.ord;`
+
+const mysqlColumnsQuery = `SELECT c.TABLE_SCHEMA AS table_schema,
+       c.TABLE_NAME AS table_name,
+       c.COLUMN_NAME AS column_name,
+       c.ORDINAL_POSITION AS ordinal_position,
+       UPPER(c.DATA_TYPE) AS data_type,
+       c.CHARACTER_MAXIMUM_LENGTH AS length,
+       c.NUMERIC_PRECISION AS numeric_precision,
+       c.NUMERIC_SCALE AS numeric_scale,
+       CASE WHEN c.IS_NULLABLE = 'YES' THEN 1 ELSE 0 END AS nullable,
+       CASE WHEN c.COLUMN_KEY = 'PRI' THEN 1 ELSE 0 END AS primary_key,
+       CASE WHEN c.COLUMN_KEY = 'UNI' THEN 1 ELSE 0 END AS unique_key,
+       (SELECT k.REFERENCED_TABLE_NAME FROM information_schema.KEY_COLUMN_USAGE k
+         WHERE k.TABLE_SCHEMA = c.TABLE_SCHEMA AND k.TABLE_NAME = c.TABLE_NAME
+           AND k.COLUMN_NAME = c.COLUMN_NAME AND k.REFERENCED_TABLE_NAME IS NOT NULL
+         ORDER BY k.CONSTRAINT_NAME LIMIT 1) AS fk_table,
+       (SELECT k.REFERENCED_COLUMN_NAME FROM information_schema.KEY_COLUMN_USAGE k
+         WHERE k.TABLE_SCHEMA = c.TABLE_SCHEMA AND k.TABLE_NAME = c.TABLE_NAME
+           AND k.COLUMN_NAME = c.COLUMN_NAME AND k.REFERENCED_TABLE_NAME IS NOT NULL
+         ORDER BY k.CONSTRAINT_NAME LIMIT 1) AS fk_column,
+       c.COLUMN_DEFAULT AS column_default
+FROM information_schema.COLUMNS c
+WHERE c.TABLE_SCHEMA = DATABASE()
+ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION;`
+
+const mysqlIndexesQuery = `SELECT s.TABLE_SCHEMA AS table_schema,
+       s.TABLE_NAME AS table_name,
+       s.INDEX_NAME AS index_name,
+       s.COLUMN_NAME AS column_name,
+       s.SEQ_IN_INDEX AS column_position,
+       CASE WHEN s.NON_UNIQUE = 0 THEN 1 ELSE 0 END AS is_unique
+FROM information_schema.STATISTICS s
+WHERE s.TABLE_SCHEMA = DATABASE()
+ORDER BY s.TABLE_NAME, s.INDEX_NAME, s.SEQ_IN_INDEX;`
+
+const sqlServerColumnsQuery = `SELECT s.name AS table_schema,
+       t.name AS table_name,
+       c.name AS column_name,
+       c.column_id AS ordinal_position,
+       UPPER(ty.name) AS data_type,
+       CASE WHEN ty.name IN ('varchar', 'char', 'varbinary', 'binary') THEN c.max_length
+            WHEN ty.name IN ('nvarchar', 'nchar') AND c.max_length > 0 THEN c.max_length / 2
+            ELSE NULL END AS length,
+       CASE WHEN ty.name IN ('decimal', 'numeric') THEN c.precision ELSE NULL END AS numeric_precision,
+       CASE WHEN ty.name IN ('decimal', 'numeric') THEN c.scale ELSE NULL END AS numeric_scale,
+       CAST(c.is_nullable AS INT) AS nullable,
+       CASE WHEN EXISTS (
+           SELECT 1 FROM sys.indexes i
+           JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+           WHERE i.object_id = t.object_id AND i.is_primary_key = 1 AND ic.column_id = c.column_id
+       ) THEN 1 ELSE 0 END AS primary_key,
+       CASE WHEN EXISTS (
+           SELECT 1 FROM sys.indexes i
+           JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+           WHERE i.object_id = t.object_id AND i.is_unique_constraint = 1 AND ic.column_id = c.column_id
+       ) THEN 1 ELSE 0 END AS unique_key,
+       fk.fk_table,
+       fk.fk_column,
+       dc.definition AS column_default
+FROM sys.tables t
+JOIN sys.schemas s ON s.schema_id = t.schema_id
+JOIN sys.columns c ON c.object_id = t.object_id
+JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+OUTER APPLY (
+    SELECT TOP 1 rt.name AS fk_table, rc.name AS fk_column
+    FROM sys.foreign_key_columns fkc
+    JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id
+    JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+    WHERE fkc.parent_object_id = t.object_id AND fkc.parent_column_id = c.column_id
+    ORDER BY fkc.constraint_object_id
+) fk
+LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id
+ORDER BY s.name, t.name, c.column_id;`
+
+const sqlServerIndexesQuery = `SELECT s.name AS table_schema,
+       t.name AS table_name,
+       i.name AS index_name,
+       c.name AS column_name,
+       ic.key_ordinal AS column_position,
+       CAST(i.is_unique AS INT) AS is_unique
+FROM sys.indexes i
+JOIN sys.tables t ON t.object_id = i.object_id
+JOIN sys.schemas s ON s.schema_id = t.schema_id
+JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE i.name IS NOT NULL AND ic.key_ordinal > 0
+ORDER BY s.name, t.name, i.name, ic.key_ordinal;`
+
+const sqliteColumnsQuery = `SELECT 'main' AS table_schema,
+       m.name AS table_name,
+       p.name AS column_name,
+       p.cid + 1 AS ordinal_position,
+       UPPER(p.type) AS data_type,
+       NULL AS length,
+       NULL AS numeric_precision,
+       NULL AS numeric_scale,
+       CASE WHEN p."notnull" = 0 AND p.pk = 0 THEN 1 ELSE 0 END AS nullable,
+       CASE WHEN p.pk > 0 THEN 1 ELSE 0 END AS primary_key,
+       0 AS unique_key,
+       (SELECT fk."table" FROM pragma_foreign_key_list(m.name) fk
+         WHERE fk."from" = p.name ORDER BY fk.id LIMIT 1) AS fk_table,
+       (SELECT fk."to" FROM pragma_foreign_key_list(m.name) fk
+         WHERE fk."from" = p.name ORDER BY fk.id LIMIT 1) AS fk_column,
+       p.dflt_value AS column_default
+FROM sqlite_master m
+JOIN pragma_table_info(m.name) p
+WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%'
+ORDER BY m.name, p.cid;`
+
+const sqliteIndexesQuery = `SELECT 'main' AS table_schema,
+       m.name AS table_name,
+       il.name AS index_name,
+       ii.name AS column_name,
+       ii.seqno + 1 AS column_position,
+       il."unique" AS is_unique
+FROM sqlite_master m
+JOIN pragma_index_list(m.name) il
+JOIN pragma_index_info(il.name) ii
+WHERE m.type = 'table' AND m.name NOT LIKE 'sqlite_%'
+ORDER BY m.name, il.name, ii.seqno;`
+
+const oracleColumnsQuery = `SELECT c.owner AS table_schema,
+       c.table_name,
+       c.column_name,
+       c.column_id AS ordinal_position,
+       c.data_type,
+       CASE WHEN c.data_type IN ('VARCHAR2', 'NVARCHAR2', 'CHAR', 'NCHAR', 'RAW') THEN c.char_length ELSE NULL END AS length,
+       c.data_precision AS numeric_precision,
+       c.data_scale AS numeric_scale,
+       CASE WHEN c.nullable = 'Y' THEN 1 ELSE 0 END AS nullable,
+       CASE WHEN EXISTS (
+           SELECT 1 FROM all_constraints k
+           JOIN all_cons_columns kc ON kc.owner = k.owner AND kc.constraint_name = k.constraint_name
+           WHERE k.constraint_type = 'P' AND k.owner = c.owner
+             AND kc.table_name = c.table_name AND kc.column_name = c.column_name
+       ) THEN 1 ELSE 0 END AS primary_key,
+       CASE WHEN EXISTS (
+           SELECT 1 FROM all_constraints k
+           JOIN all_cons_columns kc ON kc.owner = k.owner AND kc.constraint_name = k.constraint_name
+           WHERE k.constraint_type = 'U' AND k.owner = c.owner
+             AND kc.table_name = c.table_name AND kc.column_name = c.column_name
+       ) THEN 1 ELSE 0 END AS unique_key,
+       (SELECT MIN(rc.table_name) FROM all_constraints k
+          JOIN all_cons_columns kc ON kc.owner = k.owner AND kc.constraint_name = k.constraint_name
+          JOIN all_cons_columns rc ON rc.owner = k.r_owner AND rc.constraint_name = k.r_constraint_name AND rc.position = kc.position
+         WHERE k.constraint_type = 'R' AND k.owner = c.owner
+           AND kc.table_name = c.table_name AND kc.column_name = c.column_name) AS fk_table,
+       (SELECT MIN(rc.column_name) FROM all_constraints k
+          JOIN all_cons_columns kc ON kc.owner = k.owner AND kc.constraint_name = k.constraint_name
+          JOIN all_cons_columns rc ON rc.owner = k.r_owner AND rc.constraint_name = k.r_constraint_name AND rc.position = kc.position
+         WHERE k.constraint_type = 'R' AND k.owner = c.owner
+           AND kc.table_name = c.table_name AND kc.column_name = c.column_name) AS fk_column,
+       c.data_default AS column_default
+FROM all_tab_columns c
+JOIN all_tables t ON t.owner = c.owner AND t.table_name = c.table_name
+WHERE c.owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+ORDER BY c.table_name, c.column_id`
+
+const oracleIndexesQuery = `SELECT ic.table_owner AS table_schema,
+       ic.table_name,
+       ic.index_name,
+       ic.column_name,
+       ic.column_position,
+       CASE WHEN i.uniqueness = 'UNIQUE' THEN 1 ELSE 0 END AS is_unique
+FROM all_ind_columns ic
+JOIN all_indexes i ON i.owner = ic.index_owner AND i.index_name = ic.index_name
+WHERE ic.table_owner = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA')
+ORDER BY ic.table_name, ic.index_name, ic.column_position`
+
+// CatalogColumnRow is one row of a columns catalog query result
+type CatalogColumnRow struct {
+	TableSchema     string
+	TableName       string
+	ColumnName      string
+	OrdinalPosition int
+	DataType        string
+	Length          int
+	Precision       int
+	Scale           int
+	Nullable        bool
+	PrimaryKey      bool
+	UniqueKey       bool
+	FKTable         string
+	FKColumn        string
+	ColumnDefault   string
+}
+
+// CatalogIndexRow is one row of an indexes catalog query result
+type CatalogIndexRow struct {
+	TableSchema    string
+	TableName      string
+	IndexName      string
+	ColumnName     string
+	ColumnPosition int
+	IsUnique       bool
+}
+
+// LoadFromCatalog builds a schema from catalog query results. The indexes
+// rows are optional.
+func (sl *SchemaLoader) LoadFromCatalog(name string, columns []CatalogColumnRow, indexes []CatalogIndexRow) (*Schema, error) {
+	schema := NewSchema(name)
+
+	for _, row := range columns {
+		if row.TableName == "" || row.ColumnName == "" {
+			return nil, fmt.Errorf("catalog row is missing table_name or column_name")
+		}
+
+		table, ok := schema.GetQualifiedTable(row.TableSchema, row.TableName)
+		if !ok {
+			table = NewTable(row.TableName)
+			table.Schema = row.TableSchema
+			schema.AddTable(table)
+		}
+
+		col := &Column{
+			Name:         row.ColumnName,
+			IsPrimaryKey: row.PrimaryKey,
+			IsUnique:     row.UniqueKey,
+			Position:     row.OrdinalPosition,
+			DataType:     normalizeCatalogType(row.DataType, row.Length, row.Precision, row.Scale),
+		}
+		col.DataType.Nullable = row.Nullable
+		if row.ColumnDefault != "" {
+			col.DefaultValue = SQLExpression(row.ColumnDefault) // catalogs store defaults as SQL
+		}
+		if row.FKTable != "" {
+			col.IsForeignKey = true
+			col.ForeignKey = &ForeignKeyRef{
+				Table:  row.FKTable,
+				Column: row.FKColumn,
+			}
+		}
+
+		table.AddColumn(col)
+	}
+
+	// Group index rows by table and index name, keeping column order
+	sorted := make([]CatalogIndexRow, len(indexes))
+	copy(sorted, indexes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ColumnPosition < sorted[j].ColumnPosition
+	})
+	for _, row := range sorted {
+		table, ok := schema.GetQualifiedTable(row.TableSchema, row.TableName)
+		if !ok {
+			continue // Index on a table outside the loaded columns
+		}
+
+		idx, ok := table.GetIndex(row.IndexName)
+		if !ok {
+			idx = &Index{
+				Name:     row.IndexName,
+				Table:    table.Name,
+				IsUnique: row.IsUnique,
+			}
+			table.AddIndex(idx)
+		}
+		idx.Columns = append(idx.Columns, row.ColumnName)
+	}
+
+	return schema, nil
+}
+
+// LoadFromCatalogCSV builds a schema from catalog query results saved as CSV
+// with a header row. The indexes reader may be nil.
+func (sl *SchemaLoader) LoadFromCatalogCSV(name string, columns io.Reader, indexes io.Reader) (*Schema, error) {
+	columnRecords, err := readCatalogCSV(columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog columns CSV: %w", err)
+	}
+
+	var indexRecords []map[string]string
+	if indexes != nil {
+		indexRecords, err = readCatalogCSV(indexes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog indexes CSV: %w", err)
+		}
+	}
+
+	return sl.LoadFromCatalog(name, catalogColumnRows(columnRecords), catalogIndexRows(indexRecords))
+}
+
+// LoadFromCatalogJSON builds a schema from catalog query results saved as a
+// JSON array of row objects. The indexes data may be nil.
+func (sl *SchemaLoader) LoadFromCatalogJSON(name string, columns []byte, indexes []byte) (*Schema, error) {
+	columnRecords, err := readCatalogJSON(columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse catalog columns JSON: %w", err)
+	}
+
+	var indexRecords []map[string]string
+	if indexes != nil {
+		indexRecords, err = readCatalogJSON(indexes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse catalog indexes JSON: %w", err)
+		}
+	}
+
+	return sl.LoadFromCatalog(name, catalogColumnRows(columnRecords), catalogIndexRows(indexRecords))
+}
+
+// readCatalogCSV reads CSV records into maps keyed by lower-cased header names
+func readCatalogCSV(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	}
+
+	var records []map[string]string
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := make(map[string]string, len(header))
+		for i, value := range fields {
+			if i < len(header) {
+				record[header[i]] = value
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// readCatalogJSON reads a JSON array of objects into maps keyed by lower-cased field names
+func readCatalogJSON(data []byte) ([]map[string]string, error) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+
+	records := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]string, len(row))
+		for key, value := range row {
+			if value == nil {
+				continue
+			}
+			record[strings.ToLower(key)] = fmt.Sprintf("%v", value)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func catalogColumnRows(records []map[string]string) []CatalogColumnRow {
+	rows := make([]CatalogColumnRow, 0, len(records))
+	for _, r := range records {
+		rows = append(rows, CatalogColumnRow{
+			TableSchema:     catalogString(r["table_schema"]),
+			TableName:       catalogString(r["table_name"]),
+			ColumnName:      catalogString(r["column_name"]),
+			OrdinalPosition: catalogInt(r["ordinal_position"]),
+			DataType:        catalogString(r["data_type"]),
+			Length:          catalogInt(r["length"]),
+			Precision:       catalogInt(r["numeric_precision"]),
+			Scale:           catalogInt(r["numeric_scale"]),
+			Nullable:        catalogBool(r["nullable"]),
+			PrimaryKey:      catalogBool(r["primary_key"]),
+			UniqueKey:       catalogBool(r["unique_key"]),
+			FKTable:         catalogString(r["fk_table"]),
+			FKColumn:        catalogString(r["fk_column"]),
+			ColumnDefault:   catalogString(r["column_default"]),
+		})
+	}
+	return rows
+}
+
+func catalogIndexRows(records []map[string]string) []CatalogIndexRow {
+	rows := make([]CatalogIndexRow, 0, len(records))
+	for _, r := range records {
+		rows = append(rows, CatalogIndexRow{
+			TableSchema:    catalogString(r["table_schema"]),
+			TableName:      catalogString(r["table_name"]),
+			IndexName:      catalogString(r["index_name"]),
+			ColumnName:     catalogString(r["column_name"]),
+			ColumnPosition: catalogInt(r["column_position"]),
+			IsUnique:       catalogBool(r["is_unique"]),
+		})
+	}
+	return rows
+}
+
+// catalogString treats the textual NULL markers written by database clients as empty
+func catalogString(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, "null") || value == `\N` {
+		return ""
+	}
+	return value
+}
+
+func catalogInt(value string) int {
+	value = catalogString(value)
+	if value == "" {
+		return 0
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return int(f)
+	}
+	return 0
+}
+
+func catalogBool(value string) bool {
+	switch strings.ToLower(catalogString(value)) {
+	case "1", "true", "t", "yes", "y":
+		return true
+	default:
+		return false
+	}
+}
+
+// normalizeCatalogType converts a catalog data type into a DataType
+func normalizeCatalogType(name string, length, precision, scale int) *DataType {
+	typeName := strings.ToUpper(strings.TrimSpace(name))
+
+	// Oracle reports TIMESTAMP(6) and similar with the precision in the name
+	if i := strings.Index(typeName, "("); i > 0 {
+		typeName = strings.TrimSpace(typeName[:i])
+	}
+	typeName = NormalizeTypeName(typeName)
+
+	dt := &DataType{Name: typeName}
+	switch typeName {
+	case "VARCHAR", "CHAR", "NVARCHAR", "NCHAR", "VARBINARY", "BINARY":
+		if length > 0 {
+			dt.Length = length
+		}
+	case "DECIMAL", "NUMERIC":
+		dt.Precision = precision
+		dt.Scale = scale
+	}
+
+	return dt
+}
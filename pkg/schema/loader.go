@@ -1,10 +1,13 @@
 package schema
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -22,45 +25,72 @@ func NewSchemaLoader() *SchemaLoader {
 	}
 }
 
+// schemaDocument is the on-disk representation shared by the JSON and YAML
+// loaders and by the exporter, so exported files load back unchanged.
+type schemaDocument struct {
+	Name   string          `json:"name" yaml:"name"`
+	Tables []tableDocument `json:"tables" yaml:"tables"`
+}
+
+type tableDocument struct {
+	Name    string           `json:"name" yaml:"name"`
+	Schema  string           `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Columns []columnDocument `json:"columns" yaml:"columns"`
+	Indexes []indexDocument  `json:"indexes,omitempty" yaml:"indexes,omitempty"`
+	Rows    int64            `json:"row_count,omitempty" yaml:"row_count,omitempty"`
+}
+
+type columnDocument struct {
+	Name         string      `json:"name" yaml:"name"`
+	Type         string      `json:"type" yaml:"type"`
+	Length       int         `json:"length,omitempty" yaml:"length,omitempty"`
+	Precision    int         `json:"precision,omitempty" yaml:"precision,omitempty"`
+	Scale        int         `json:"scale,omitempty" yaml:"scale,omitempty"`
+	Nullable     bool        `json:"nullable,omitempty" yaml:"nullable,omitempty"`
+	PrimaryKey   bool        `json:"primary_key,omitempty" yaml:"primary_key,omitempty"`
+	Unique       bool        `json:"unique,omitempty" yaml:"unique,omitempty"`
+	ForeignKey   bool        `json:"foreign_key,omitempty" yaml:"foreign_key,omitempty"`
+	FKTable      string      `json:"fk_table,omitempty" yaml:"fk_table,omitempty"`
+	FKColumn     string      `json:"fk_column,omitempty" yaml:"fk_column,omitempty"`
+	DefaultValue interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	Distinct     int64       `json:"distinct_values,omitempty" yaml:"distinct_values,omitempty"`
+}
+
+type indexDocument struct {
+	Name     string   `json:"name" yaml:"name"`
+	Columns  []string `json:"columns" yaml:"columns"`
+	IsUnique bool     `json:"unique,omitempty" yaml:"unique,omitempty"`
+}
+
 // LoadFromJSON loads a schema from JSON
 func (sl *SchemaLoader) LoadFromJSON(data []byte) (*Schema, error) {
-	var schemaData struct {
-		Name   string `json:"name"`
-		Tables []struct {
-			Name    string `json:"name"`
-			Schema  string `json:"schema,omitempty"`
-			Columns []struct {
-				Name         string      `json:"name"`
-				Type         string      `json:"type"`
-				Length       int         `json:"length,omitempty"`
-				Precision    int         `json:"precision,omitempty"`
-				Scale        int         `json:"scale,omitempty"`
-				Nullable     bool        `json:"nullable,omitempty"`
-				PrimaryKey   bool        `json:"primary_key,omitempty"`
-				Unique       bool        `json:"unique,omitempty"`
-				ForeignKey   bool        `json:"foreign_key,omitempty"`
-				FKTable      string      `json:"fk_table,omitempty"`
-				FKColumn     string      `json:"fk_column,omitempty"`
-				DefaultValue interface{} `json:"default,omitempty"`
-			} `json:"columns"`
-			Indexes []struct {
-				Name     string   `json:"name"`
-				Columns  []string `json:"columns"`
-				IsUnique bool     `json:"unique,omitempty"`
-			} `json:"indexes,omitempty"`
-		} `json:"tables"`
+	var doc schemaDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
 	}
 
-	if err := json.Unmarshal(data, &schemaData); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	return doc.toSchema(), nil
+}
+
+// LoadFromYAML loads a schema from YAML
+func (sl *SchemaLoader) LoadFromYAML(data []byte) (*Schema, error) {
+	var doc schemaDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML schema: %w", err)
 	}
 
-	schema := NewSchema(schemaData.Name)
+	return doc.toSchema(), nil
+}
+
+// toSchema converts the document into a Schema
+func (doc *schemaDocument) toSchema() *Schema {
+	schema := NewSchema(doc.Name)
 
 	// Parse tables
-	for _, tableData := range schemaData.Tables {
+	for _, tableData := range doc.Tables {
 		table := NewTable(tableData.Name)
 		table.Schema = tableData.Schema
+		table.Rows = tableData.Rows
 
 		// Parse columns
 		for _, colData := range tableData.Columns {
@@ -70,6 +100,7 @@ func (sl *SchemaLoader) LoadFromJSON(data []byte) (*Schema, error) {
 				IsUnique:     colData.Unique,
 				IsForeignKey: colData.ForeignKey,
 				DefaultValue: colData.DefaultValue,
+				Distinct:     colData.Distinct,
 				DataType: &DataType{
 					Name:      strings.ToUpper(colData.Type),
 					Length:    colData.Length,
@@ -103,94 +134,60 @@ func (sl *SchemaLoader) LoadFromJSON(data []byte) (*Schema, error) {
 		schema.AddTable(table)
 	}
 
-	return schema, nil
+	return schema
 }
 
-// LoadFromYAML loads a schema from YAML
-func (sl *SchemaLoader) LoadFromYAML(data []byte) (*Schema, error) {
-	var schemaData struct {
-		Name   string `yaml:"name"`
-		Tables []struct {
-			Name    string `yaml:"name"`
-			Schema  string `yaml:"schema,omitempty"`
-			Columns []struct {
-				Name         string      `yaml:"name"`
-				Type         string      `yaml:"type"`
-				Length       int         `yaml:"length,omitempty"`
-				Precision    int         `yaml:"precision,omitempty"`
-				Scale        int         `yaml:"scale,omitempty"`
-				Nullable     bool        `yaml:"nullable,omitempty"`
-				PrimaryKey   bool        `yaml:"primary_key,omitempty"`
-				Unique       bool        `yaml:"unique,omitempty"`
-				ForeignKey   bool        `yaml:"foreign_key,omitempty"`
-				FKTable      string      `yaml:"fk_table,omitempty"`
-				FKColumn     string      `yaml:"fk_column,omitempty"`
-				DefaultValue interface{} `yaml:"default,omitempty"`
-			} `yaml:"columns"`
-			Indexes []struct {
-				Name     string   `yaml:"name"`
-				Columns  []string `yaml:"columns"`
-				IsUnique bool     `yaml:"unique,omitempty"`
-			} `yaml:"indexes,omitempty"`
-		} `yaml:"tables"`
-	}
-
-	if err := yaml.Unmarshal(data, &schemaData); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML schema: %w", err)
-	}
+// newSchemaDocument converts a Schema into its on-disk representation with
+// tables sorted by name and columns in declaration order
+func newSchemaDocument(s *Schema) *schemaDocument {
+	doc := &schemaDocument{Name: s.Name}
 
-	schema := NewSchema(schemaData.Name)
-
-	// Parse tables
-	for _, tableData := range schemaData.Tables {
-		table := NewTable(tableData.Name)
-		table.Schema = tableData.Schema
+	for _, table := range s.OrderedTables() {
+		tableData := tableDocument{
+			Name:   table.Name,
+			Schema: table.Schema,
+			Rows:   table.Rows,
+		}
 
-		// Parse columns
-		for _, colData := range tableData.Columns {
-			col := &Column{
-				Name:         colData.Name,
-				IsPrimaryKey: colData.PrimaryKey,
-				IsUnique:     colData.Unique,
-				IsForeignKey: colData.ForeignKey,
-				DefaultValue: colData.DefaultValue,
-				DataType: &DataType{
-					Name:      strings.ToUpper(colData.Type),
-					Length:    colData.Length,
-					Precision: colData.Precision,
-					Scale:     colData.Scale,
-					Nullable:  colData.Nullable,
-				},
+		for _, col := range table.OrderedColumns() {
+			colData := columnDocument{
+				Name:         col.Name,
+				PrimaryKey:   col.IsPrimaryKey,
+				Unique:       col.IsUnique,
+				ForeignKey:   col.IsForeignKey,
+				DefaultValue: col.DefaultValue,
+				Distinct:     col.Distinct,
 			}
-
-			if colData.ForeignKey && colData.FKTable != "" {
-				col.ForeignKey = &ForeignKeyRef{
-					Table:  colData.FKTable,
-					Column: colData.FKColumn,
-				}
+			if col.DataType != nil {
+				colData.Type = col.DataType.Name
+				colData.Length = col.DataType.Length
+				colData.Precision = col.DataType.Precision
+				colData.Scale = col.DataType.Scale
+				colData.Nullable = col.DataType.Nullable
 			}
-
-			table.AddColumn(col)
+			if col.ForeignKey != nil {
+				colData.FKTable = col.ForeignKey.Table
+				colData.FKColumn = col.ForeignKey.Column
+			}
+			tableData.Columns = append(tableData.Columns, colData)
 		}
 
-		// Parse indexes
-		for _, idxData := range tableData.Indexes {
-			idx := &Index{
-				Name:     idxData.Name,
-				Table:    tableData.Name,
-				Columns:  idxData.Columns,
-				IsUnique: idxData.IsUnique,
-			}
-			table.AddIndex(idx)
+		for _, idx := range table.OrderedIndexes() {
+			tableData.Indexes = append(tableData.Indexes, indexDocument{
+				Name:     idx.Name,
+				Columns:  idx.Columns,
+				IsUnique: idx.IsUnique,
+			})
 		}
 
-		schema.AddTable(table)
+		doc.Tables = append(doc.Tables, tableData)
 	}
 
-	return schema, nil
+	return doc
 }
 
-// LoadFromFile loads a schema from a file (auto-detects JSON/YAML)
+// LoadFromFile loads a schema from a file (auto-detects JSON/YAML). A .csv
+// file is read as the saved result of the columns catalog query.
 func (sl *SchemaLoader) LoadFromFile(filename string) (*Schema, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -208,6 +205,9 @@ func (sl *SchemaLoader) LoadFromFile(filename string) (*Schema, error) {
 		return sl.LoadFromJSON(data)
 	} else if strings.HasSuffix(strings.ToLower(filename), ".yaml") || strings.HasSuffix(strings.ToLower(filename), ".yml") {
 		return sl.LoadFromYAML(data)
+	} else if strings.HasSuffix(strings.ToLower(filename), ".csv") {
+		name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		return sl.LoadFromCatalogCSV(name, bytes.NewReader(data), nil)
 	}
 
 	// Try JSON first, then YAML
@@ -235,3 +235,15 @@ func (sl *SchemaLoader) HasSchema(name string) bool {
 	_, ok := sl.schemas[strings.ToLower(name)]
 	return ok
 }
+
+// Schemas returns the loaded schemas sorted by name
+func (sl *SchemaLoader) Schemas() []*Schema {
+	schemas := make([]*Schema, 0, len(sl.schemas))
+	for _, s := range sl.schemas {
+		schemas = append(schemas, s)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return strings.ToLower(schemas[i].Name) < strings.ToLower(schemas[j].Name)
+	})
+	return schemas
+}
@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+// Resolver resolves possibly qualified table names across several loaded
+// schemas. Each Schema is treated as a database (catalog); Table.Schema is
+// the namespace inside it.
+//
+// Unqualified names are looked up in the default database first, through the
+// search path (PostgreSQL search_path, SQL Server default schema, SQLite main).
+// For MySQL, where a schema is a database, set the current database with
+// SetDefaultDatabase.
+type Resolver struct {
+	schemas         []*Schema
+	defaultDatabase string
+	searchPath      []string
+}
+
+// NewResolver creates a resolver over the given schemas, using the dialect's
+// default schema as the search path. The dialect may be nil.
+func NewResolver(d dialect.Dialect, schemas ...*Schema) *Resolver {
+	r := &Resolver{}
+	if d != nil && d.DefaultSchema() != "" {
+		r.searchPath = []string{d.DefaultSchema()}
+	}
+	for _, s := range schemas {
+		r.AddSchema(s)
+	}
+	return r
+}
+
+// Resolver returns a resolver over every schema loaded so far
+func (sl *SchemaLoader) Resolver(d dialect.Dialect) *Resolver {
+	return NewResolver(d, sl.Schemas()...)
+}
+
+// AddSchema adds a database to the resolver. The first schema added is the
+// default database unless SetDefaultDatabase says otherwise.
+func (r *Resolver) AddSchema(s *Schema) {
+	if s == nil {
+		return
+	}
+	r.schemas = append(r.schemas, s)
+}
+
+// Schemas returns the databases known to the resolver
+func (r *Resolver) Schemas() []*Schema {
+	return r.schemas
+}
+
+// SetDefaultDatabase sets the database unqualified names resolve against
+// (MySQL's current database, SQL Server's USE)
+func (r *Resolver) SetDefaultDatabase(name string) {
+	r.defaultDatabase = name
+}
+
+// SetSearchPath sets the schemas searched, in order, for unqualified names
+func (r *Resolver) SetSearchPath(schemas ...string) {
+	r.searchPath = schemas
+}
+
+// SearchPath returns the schemas searched for unqualified names
+func (r *Resolver) SearchPath() []string {
+	return r.searchPath
+}
+
+// ResolveTable resolves a parsed table reference. Derived tables and
+// linked-server references are not resolvable.
+func (r *Resolver) ResolveTable(ref *parser.TableReference) (*Table, bool) {
+	if ref == nil || ref.Subquery != nil || ref.Name == "" || ref.Server != "" {
+		return nil, false
+	}
+	return r.Resolve(ref.Catalog, ref.Schema, ref.Name)
+}
+
+// Resolve finds a table by catalog (database), namespace (schema) and name.
+// Empty parts are unqualified. A two-part name whose qualifier is not a
+// schema is retried as database.table, which is how MySQL reads it.
+func (r *Resolver) Resolve(catalog, namespace, name string) (*Table, bool) {
+	if catalog != "" {
+		db, ok := r.database(catalog)
+		if !ok {
+			return nil, false
+		}
+		return r.lookup(db, namespace, name)
+	}
+
+	databases := r.databases()
+
+	if namespace == "" {
+		for _, db := range databases {
+			if table, ok := r.lookup(db, "", name); ok {
+				return table, true
+			}
+		}
+		return nil, false
+	}
+
+	// Exact schema match in any database
+	for _, db := range databases {
+		if table, ok := db.Tables[tableKey(namespace, name)]; ok {
+			return table, true
+		}
+	}
+
+	// database.table
+	if db, ok := r.database(namespace); ok {
+		if table, ok := r.lookup(db, "", name); ok {
+			return table, true
+		}
+	}
+
+	// Tables declared without a schema match any namespace
+	for _, db := range databases {
+		if table, ok := db.GetQualifiedTable(namespace, name); ok {
+			return table, true
+		}
+	}
+
+	return nil, false
+}
+
+// lookup finds a table inside one database. Off the search path, an
+// unqualified name only matches a table declared without a schema; with no
+// search path it matches the table in any schema.
+func (r *Resolver) lookup(db *Schema, namespace, name string) (*Table, bool) {
+	if namespace != "" {
+		return db.GetQualifiedTable(namespace, name)
+	}
+	if len(r.searchPath) == 0 {
+		return db.GetTable(name)
+	}
+	for _, ns := range r.searchPath {
+		if table, ok := db.Tables[tableKey(ns, name)]; ok {
+			return table, true
+		}
+	}
+	table, ok := db.Tables[tableKey("", name)]
+	return table, ok
+}
+
+// database finds a loaded schema by name (case-insensitive)
+func (r *Resolver) database(name string) (*Schema, bool) {
+	for _, s := range r.schemas {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// databases returns the schemas with the default database first
+func (r *Resolver) databases() []*Schema {
+	if r.defaultDatabase == "" {
+		return r.schemas
+	}
+	ordered := make([]*Schema, 0, len(r.schemas))
+	if db, ok := r.database(r.defaultDatabase); ok {
+		ordered = append(ordered, db)
+	}
+	for _, s := range r.schemas {
+		if !strings.EqualFold(s.Name, r.defaultDatabase) {
+			ordered = append(ordered, s)
+		}
+	}
+	return ordered
+}
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -28,6 +29,43 @@ func (dt *DataType) String() string {
 	return dt.Name
 }
 
+// typeAliases maps type spellings of the dialects and catalogs to the
+// names used by DataType
+var typeAliases = map[string]string{
+	"CHARACTER VARYING":           "VARCHAR",
+	"CHARACTER":                   "CHAR",
+	"VARCHAR2":                    "VARCHAR",
+	"NVARCHAR2":                   "NVARCHAR",
+	"INTEGER":                     "INT",
+	"INT4":                        "INT",
+	"INT8":                        "BIGINT",
+	"INT2":                        "SMALLINT",
+	"SIGNED":                      "BIGINT",
+	"SIGNED INTEGER":              "BIGINT",
+	"UNSIGNED":                    "BIGINT",
+	"UNSIGNED INTEGER":            "BIGINT",
+	"DOUBLE PRECISION":            "DOUBLE",
+	"FLOAT8":                      "DOUBLE",
+	"FLOAT4":                      "REAL",
+	"BOOL":                        "BOOLEAN",
+	"NUMBER":                      "NUMERIC",
+	"DATETIME2":                   "DATETIME",
+	"TIMESTAMPTZ":                 "TIMESTAMP",
+	"TIMESTAMP WITH TIME ZONE":    "TIMESTAMP",
+	"TIMESTAMP WITHOUT TIME ZONE": "TIMESTAMP",
+	"TIME WITHOUT TIME ZONE":      "TIME",
+}
+
+// NormalizeTypeName returns the DataType name of a type spelling, e.g.
+// INT for int4 and VARCHAR for "character varying"
+func NormalizeTypeName(name string) string {
+	typeName := strings.ToUpper(strings.Join(strings.Fields(name), " "))
+	if alias, ok := typeAliases[typeName]; ok {
+		return alias
+	}
+	return typeName
+}
+
 // IsCompatibleWith checks if this data type is compatible with another
 func (dt *DataType) IsCompatibleWith(other *DataType) bool {
 	// Exact match
@@ -52,6 +90,14 @@ func (dt *DataType) IsCompatibleWith(other *DataType) bool {
 		return true
 	}
 
+	// Binary types
+	binaryTypes := map[string]bool{
+		"BINARY": true, "VARBINARY": true, "BLOB": true, "BYTEA": true, "BIT": true, "VARBIT": true,
+	}
+	if binaryTypes[dt.Name] && binaryTypes[other.Name] {
+		return true
+	}
+
 	// Date/time types
 	dateTypes := map[string]bool{
 		"DATE": true, "TIME": true, "DATETIME": true, "TIMESTAMP": true,
@@ -71,9 +117,15 @@ type Column struct {
 	IsUnique     bool
 	IsForeignKey bool
 	ForeignKey   *ForeignKeyRef // Reference to another table
-	DefaultValue interface{}
+	DefaultValue interface{}    // a literal value, or a SQLExpression
+	Position     int            // 1-based ordinal position within the table
+	Distinct     int64          // Number of distinct values, 0 when unknown
 }
 
+// SQLExpression is a column default written in SQL, such as one read from
+// a database catalog, which is exported as is
+type SQLExpression string
+
 // ForeignKeyRef represents a foreign key reference
 type ForeignKeyRef struct {
 	Table  string
@@ -86,6 +138,7 @@ type Table struct {
 	Schema  string             // Schema/database name (optional)
 	Columns map[string]*Column // Column name -> Column
 	Indexes map[string]*Index  // Index name -> Index
+	Rows    int64              // Row count, 0 when unknown
 }
 
 // NewTable creates a new table
@@ -97,11 +150,34 @@ func NewTable(name string) *Table {
 	}
 }
 
-// AddColumn adds a column to the table
+// AddColumn adds a column to the table. Columns without an explicit
+// position are appended after the existing ones.
 func (t *Table) AddColumn(col *Column) {
+	if col.Position == 0 {
+		if existing, ok := t.Columns[strings.ToLower(col.Name)]; ok {
+			col.Position = existing.Position
+		} else {
+			col.Position = len(t.Columns) + 1
+		}
+	}
 	t.Columns[strings.ToLower(col.Name)] = col
 }
 
+// OrderedColumns returns the table's columns in ordinal position order
+func (t *Table) OrderedColumns() []*Column {
+	columns := make([]*Column, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		columns = append(columns, col)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		if columns[i].Position != columns[j].Position {
+			return columns[i].Position < columns[j].Position
+		}
+		return strings.ToLower(columns[i].Name) < strings.ToLower(columns[j].Name)
+	})
+	return columns
+}
+
 // GetColumn retrieves a column by name (case-insensitive)
 func (t *Table) GetColumn(name string) (*Column, bool) {
 	col, ok := t.Columns[strings.ToLower(name)]
@@ -125,6 +201,58 @@ func (t *Table) GetIndex(name string) (*Index, bool) {
 	return idx, ok
 }
 
+// OrderedIndexes returns the table's indexes sorted by name
+func (t *Table) OrderedIndexes() []*Index {
+	indexes := make([]*Index, 0, len(t.Indexes))
+	for _, idx := range t.Indexes {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return strings.ToLower(indexes[i].Name) < strings.ToLower(indexes[j].Name)
+	})
+	return indexes
+}
+
+// HasUniqueKey reports whether the columns, in any order, are exactly the
+// primary key, a unique column or the columns of a unique index
+func (t *Table) HasUniqueKey(columns []string) bool {
+	want := make(map[string]bool, len(columns))
+	for _, name := range columns {
+		want[strings.ToLower(name)] = true
+	}
+	sameColumns := func(names []string) bool {
+		if len(names) != len(want) {
+			return false
+		}
+		for _, name := range names {
+			if !want[strings.ToLower(name)] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var primaryKey []string
+	for _, col := range t.Columns {
+		if col.IsPrimaryKey {
+			primaryKey = append(primaryKey, col.Name)
+		}
+		if col.IsUnique && sameColumns([]string{col.Name}) {
+			return true
+		}
+	}
+	if len(primaryKey) > 0 && sameColumns(primaryKey) {
+		return true
+	}
+
+	for _, idx := range t.Indexes {
+		if idx.IsUnique && sameColumns(idx.Columns) {
+			return true
+		}
+	}
+	return false
+}
+
 // Index represents a database index
 type Index struct {
 	Name     string
@@ -147,23 +275,74 @@ func NewSchema(name string) *Schema {
 	}
 }
 
-// AddTable adds a table to the schema
+// AddTable adds a table to the schema. Tables with a schema are keyed by
+// their qualified name so same-named tables in different schemas coexist.
 func (s *Schema) AddTable(table *Table) {
-	s.Tables[strings.ToLower(table.Name)] = table
+	s.Tables[tableKey(table.Schema, table.Name)] = table
 }
 
-// GetTable retrieves a table by name (case-insensitive)
+// GetTable retrieves a table by name (case-insensitive). The name may be
+// qualified (sales.orders); an unqualified name matches a table in any
+// schema, preferring one without a schema and then the first by schema name.
 func (s *Schema) GetTable(name string) (*Table, bool) {
-	table, ok := s.Tables[strings.ToLower(name)]
-	return table, ok
+	if table, ok := s.Tables[strings.ToLower(name)]; ok {
+		return table, true
+	}
+
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return s.GetQualifiedTable(name[:i], name[i+1:])
+	}
+
+	var match *Table
+	for _, table := range s.Tables {
+		if !strings.EqualFold(table.Name, name) {
+			continue
+		}
+		if match == nil || strings.ToLower(table.Schema) < strings.ToLower(match.Schema) {
+			match = table
+		}
+	}
+	return match, match != nil
+}
+
+// GetQualifiedTable retrieves a table by schema and name (case-insensitive).
+// Tables declared without a schema match any namespace.
+func (s *Schema) GetQualifiedTable(namespace, name string) (*Table, bool) {
+	if table, ok := s.Tables[tableKey(namespace, name)]; ok {
+		return table, true
+	}
+	if table, ok := s.Tables[strings.ToLower(name)]; ok && table.Schema == "" {
+		return table, true
+	}
+	return nil, false
+}
+
+// OrderedTables returns the schema's tables sorted by schema and name
+func (s *Schema) OrderedTables() []*Table {
+	tables := make([]*Table, 0, len(s.Tables))
+	for _, table := range s.Tables {
+		tables = append(tables, table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tableKey(tables[i].Schema, tables[i].Name) < tableKey(tables[j].Schema, tables[j].Name)
+	})
+	return tables
 }
 
 // HasTable checks if a table exists (case-insensitive)
 func (s *Schema) HasTable(name string) bool {
-	_, ok := s.Tables[strings.ToLower(name)]
+	_, ok := s.GetTable(name)
 	return ok
 }
 
+// tableKey returns the map key for a table: "schema.name" or "name"
+func tableKey(namespace, name string) string {
+	if namespace == "" {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(namespace + "." + name)
+}
+
 // GetColumn retrieves a column from a table (case-insensitive)
 func (s *Schema) GetColumn(tableName, columnName string) (*Column, error) {
 	table, ok := s.GetTable(tableName)
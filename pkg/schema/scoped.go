@@ -0,0 +1,199 @@
+package schema
+
+import (
+	"strings"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+// ObjectScope is how long a table created by a script stays visible
+type ObjectScope int
+
+const (
+	ScopeBatch   ObjectScope = iota // @table variables, until the next GO
+	ScopeSession                    // #temp tables, until dropped
+	ScopeGlobal                     // ##temp tables, until dropped
+)
+
+// String returns the scope name
+func (s ObjectScope) String() string {
+	switch s {
+	case ScopeBatch:
+		return "batch"
+	case ScopeSession:
+		return "session"
+	default:
+		return "global"
+	}
+}
+
+// scopedTable is a temp table or table variable declared by the script
+type scopedTable struct {
+	table *Table
+	scope ObjectScope
+}
+
+// objectScope returns the scope a table name implies, if it is temporary
+func objectScope(name string) (ObjectScope, bool) {
+	switch {
+	case strings.HasPrefix(name, "@"):
+		return ScopeBatch, true
+	case strings.HasPrefix(name, "##"):
+		return ScopeGlobal, true
+	case strings.HasPrefix(name, "#"):
+		return ScopeSession, true
+	}
+	return 0, false
+}
+
+// ScopedTable returns a temp table or table variable declared by the
+// statements validated so far
+func (v *Validator) ScopedTable(name string) (*Table, ObjectScope, bool) {
+	if st, ok := v.scoped[strings.ToLower(name)]; ok {
+		return st.table, st.scope, true
+	}
+	return nil, 0, false
+}
+
+// declare adds a temp table or table variable to the validator's scope
+func (v *Validator) declare(table *Table) {
+	scope, ok := objectScope(table.Name)
+	if !ok {
+		return
+	}
+	if v.scoped == nil {
+		v.scoped = make(map[string]*scopedTable)
+	}
+	v.scoped[strings.ToLower(table.Name)] = &scopedTable{table: table, scope: scope}
+}
+
+// trackScopedObjects records the temp tables and table variables a
+// statement creates, drops or lets go out of scope
+func (v *Validator) trackScopedObjects(stmt parser.Statement) {
+	switch s := stmt.(type) {
+	case *parser.CreateTableStatement:
+		if _, ok := objectScope(s.Table.Name); ok {
+			v.declare(tableFromDefinitions(s.Table.Name, s.Columns))
+		}
+	case *parser.DeclareStatement:
+		for _, variable := range s.Variables {
+			if variable.Table != nil {
+				v.declare(tableFromDefinitions(variable.Name, variable.Table))
+			}
+		}
+	case *parser.SelectStatement:
+		if s.Into != nil {
+			if _, ok := objectScope(s.Into.Name); ok {
+				v.declare(v.tableFromSelect(s.Into.Name, s))
+			}
+		}
+	case *parser.DropStatement:
+		if strings.EqualFold(s.ObjectType, "TABLE") {
+			delete(v.scoped, strings.ToLower(s.ObjectName))
+		}
+	case *parser.GoStatement:
+		for name, st := range v.scoped {
+			if st.scope == ScopeBatch {
+				delete(v.scoped, name)
+			}
+		}
+	case *parser.UseStatement:
+		v.resolver.SetDefaultDatabase(s.Database)
+	}
+}
+
+// resolveTable resolves a table reference, looking at the script's temp
+// tables and table variables before the schemas
+func (v *Validator) resolveTable(ref *parser.TableReference) (*Table, bool) {
+	if ref != nil && ref.Subquery == nil && ref.Schema == "" && ref.Catalog == "" {
+		if st, ok := v.scoped[strings.ToLower(ref.Name)]; ok {
+			return st.table, true
+		}
+	}
+	return v.resolver.ResolveTable(ref)
+}
+
+// tableFromDefinitions builds a table from CREATE TABLE or DECLARE TABLE
+// column definitions
+func tableFromDefinitions(name string, defs []*parser.ColumnDefinition) *Table {
+	table := NewTable(name)
+	for _, def := range defs {
+		table.AddColumn(&Column{
+			Name: def.Name,
+			DataType: &DataType{
+				Name:      strings.ToUpper(def.DataType),
+				Length:    def.Length,
+				Precision: def.Precision,
+				Scale:     def.Scale,
+				Nullable:  !def.NotNull && !def.PrimaryKey,
+			},
+			IsPrimaryKey: def.PrimaryKey,
+			IsUnique:     def.Unique,
+		})
+	}
+	return table
+}
+
+// tableFromSelect builds the table a SELECT ... INTO creates, copying the
+// types of the columns it selects where they can be resolved
+func (v *Validator) tableFromSelect(name string, stmt *parser.SelectStatement) *Table {
+	table := NewTable(name)
+	scope := v.selectScope(stmt)
+	for _, expr := range stmt.Columns {
+		alias := ""
+		if aliased, ok := expr.(*parser.AliasedExpression); ok {
+			alias, expr = aliased.Alias, aliased.Expression
+		}
+
+		if star, ok := expr.(*parser.StarExpression); ok {
+			for _, source := range v.starSources(star, scope) {
+				for _, c := range source.OrderedColumns() {
+					table.AddColumn(&Column{Name: c.Name, DataType: c.DataType})
+				}
+			}
+			continue
+		}
+
+		col, ok := expr.(*parser.ColumnReference)
+		column := &Column{Name: alias, DataType: &DataType{Name: "UNKNOWN", Nullable: true}}
+		if ok {
+			if column.Name == "" {
+				column.Name = col.Column
+			}
+			if source := v.columnSource(col, scope); source != nil {
+				if c, found := source.GetColumn(col.Column); found {
+					column.DataType = c.DataType
+				}
+			}
+		}
+		if column.Name != "" {
+			table.AddColumn(column)
+		}
+	}
+	return table
+}
+
+// starSources returns the tables a * or t.* column expands to
+func (v *Validator) starSources(star *parser.StarExpression, scope *tableScope) []*Table {
+	if star.Table == "" {
+		return scope.tables
+	}
+	if table, _ := v.resolveQualifier(&parser.ColumnReference{Schema: star.Schema, Table: star.Table}, scope); table != nil {
+		return []*Table{table}
+	}
+	return nil
+}
+
+// columnSource returns the table a column reference reads from, or nil
+func (v *Validator) columnSource(col *parser.ColumnReference, scope *tableScope) *Table {
+	if col.Table != "" {
+		table, _ := v.resolveQualifier(col, scope)
+		return table
+	}
+	for _, table := range scope.tables {
+		if table.HasColumn(col.Column) {
+			return table
+		}
+	}
+	return nil
+}
@@ -2,21 +2,30 @@ package schema
 
 import (
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
 )
 
 // TypeChecker performs type checking on SQL expressions
 type TypeChecker struct {
-	schema    *Schema
+	resolver  *Resolver
 	validator *Validator
 }
 
 // NewTypeChecker creates a new type checker
 func NewTypeChecker(schema *Schema) *TypeChecker {
+	return NewTypeCheckerWithResolver(NewResolver(nil, schema))
+}
+
+// NewTypeCheckerWithResolver creates a type checker that resolves qualified
+// names across all of the resolver's schemas
+func NewTypeCheckerWithResolver(resolver *Resolver) *TypeChecker {
 	return &TypeChecker{
-		schema:    schema,
-		validator: NewValidator(schema),
+		resolver:  resolver,
+		validator: NewValidatorWithResolver(resolver),
 	}
 }
 
@@ -64,7 +73,7 @@ func (tc *TypeChecker) checkSelectStatement(stmt *parser.SelectStatement) []*Val
 func (tc *TypeChecker) checkInsertStatement(stmt *parser.InsertStatement) []*ValidationError {
 	errors := make([]*ValidationError, 0)
 
-	table, ok := tc.schema.GetTable(stmt.Table.Name)
+	table, ok := tc.resolver.ResolveTable(&stmt.Table)
 	if !ok {
 		return errors // Table validation already done
 	}
@@ -101,20 +110,20 @@ func (tc *TypeChecker) checkInsertStatement(stmt *parser.InsertStatement) []*Val
 		}
 	}
 
+	// Check upsert assignments
+	if stmt.OnConflict != nil {
+		errors = append(errors, tc.checkAssignments(stmt.OnConflict.Set, table, stmt.Table.Name)...)
+	}
+	errors = append(errors, tc.checkAssignments(stmt.OnDuplicateKeyUpdate, table, stmt.Table.Name)...)
+
 	return errors
 }
 
-// checkUpdateStatement checks types in an UPDATE statement
-func (tc *TypeChecker) checkUpdateStatement(stmt *parser.UpdateStatement) []*ValidationError {
+// checkAssignments checks that assigned values fit their columns
+func (tc *TypeChecker) checkAssignments(assignments []*parser.Assignment, table *Table, tableName string) []*ValidationError {
 	errors := make([]*ValidationError, 0)
 
-	table, ok := tc.schema.GetTable(stmt.Table.Name)
-	if !ok {
-		return errors
-	}
-
-	// Check SET clause type compatibility
-	for _, assignment := range stmt.Set {
+	for _, assignment := range assignments {
 		col, ok := table.GetColumn(assignment.Column)
 		if !ok {
 			continue
@@ -125,12 +134,27 @@ func (tc *TypeChecker) checkUpdateStatement(stmt *parser.UpdateStatement) []*Val
 			errors = append(errors, &ValidationError{
 				Type:    "TYPE_MISMATCH",
 				Message: fmt.Sprintf("Type mismatch for column '%s': expected %s, got %s", assignment.Column, col.DataType, valueType),
-				Table:   stmt.Table.Name,
+				Table:   tableName,
 				Column:  assignment.Column,
 			})
 		}
 	}
 
+	return errors
+}
+
+// checkUpdateStatement checks types in an UPDATE statement
+func (tc *TypeChecker) checkUpdateStatement(stmt *parser.UpdateStatement) []*ValidationError {
+	errors := make([]*ValidationError, 0)
+
+	table, ok := tc.resolver.ResolveTable(&stmt.Table)
+	if !ok {
+		return errors
+	}
+
+	// Check SET clause type compatibility
+	errors = append(errors, tc.checkAssignments(stmt.Set, table, stmt.Table.Name)...)
+
 	// Check WHERE clause
 	if stmt.Where != nil {
 		fakeSelect := &parser.SelectStatement{
@@ -144,23 +168,36 @@ func (tc *TypeChecker) checkUpdateStatement(stmt *parser.UpdateStatement) []*Val
 	return errors
 }
 
+// booleanOperators are the comparison operators, which yield a boolean
+var booleanOperators = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, ">": true, "<=": true, ">=": true, "<=>": true,
+	"LIKE": true, "NOT LIKE": true, "ILIKE": true, "NOT ILIKE": true,
+	"REGEXP": true, "NOT REGEXP": true, "RLIKE": true, "NOT RLIKE": true, "GLOB": true, "NOT GLOB": true,
+	"IS": true, "IS NOT": true, "IS DISTINCT FROM": true, "IS NOT DISTINCT FROM": true,
+}
+
 // checkBooleanExpression checks if an expression is boolean
 func (tc *TypeChecker) checkBooleanExpression(expr parser.Expression, stmt *parser.SelectStatement) []*ValidationError {
 	errors := make([]*ValidationError, 0)
 
 	switch e := expr.(type) {
 	case *parser.BinaryExpression:
-		// Check if operator is a comparison operator
-		comparisonOps := map[string]bool{
-			"=": true, "!=": true, "<>": true, "<": true, ">": true, "<=": true, ">=": true,
-			"AND": true, "OR": true, "LIKE": true, "IN": true, "IS": true,
+		if e.Operator == "AND" || e.Operator == "OR" {
+			// Both operands are conditions themselves
+			errors = append(errors, tc.checkBooleanExpression(e.Left, stmt)...)
+			errors = append(errors, tc.checkBooleanExpression(e.Right, stmt)...)
+			break
 		}
 
-		if !comparisonOps[e.Operator] {
+		if !booleanOperators[strings.ToUpper(e.Operator)] {
 			errors = append(errors, &ValidationError{
 				Type:    "NON_BOOLEAN_EXPRESSION",
 				Message: fmt.Sprintf("Non-boolean operator '%s' used in boolean context", e.Operator),
 			})
+			break
+		}
+		if strings.HasPrefix(e.Operator, "IS") {
+			break // IS NULL, IS TRUE and IS DISTINCT FROM accept any operands
 		}
 
 		// Check type compatibility of operands
@@ -176,10 +213,6 @@ func (tc *TypeChecker) checkBooleanExpression(expr parser.Expression, stmt *pars
 			}
 		}
 
-		// Recursively check sub-expressions
-		errors = append(errors, tc.checkBooleanExpression(e.Left, stmt)...)
-		errors = append(errors, tc.checkBooleanExpression(e.Right, stmt)...)
-
 	case *parser.UnaryExpression:
 		if e.Operator == "NOT" {
 			errors = append(errors, tc.checkBooleanExpression(e.Operand, stmt)...)
@@ -198,6 +231,18 @@ func (tc *TypeChecker) checkBooleanExpression(expr parser.Expression, stmt *pars
 			}
 		}
 
+	case *parser.BetweenExpression:
+		exprType := tc.inferExpressionType(e.Expression, stmt)
+		for _, bound := range []parser.Expression{e.Low, e.High} {
+			boundType := tc.inferExpressionType(bound, stmt)
+			if exprType != nil && boundType != nil && !exprType.IsCompatibleWith(boundType) {
+				errors = append(errors, &ValidationError{
+					Type:    "TYPE_MISMATCH",
+					Message: fmt.Sprintf("Type mismatch in BETWEEN: %s vs %s", exprType, boundType),
+				})
+			}
+		}
+
 	case *parser.ExistsExpression:
 		// EXISTS is always boolean - check subquery
 		if selectStmt, ok := e.Subquery.(*parser.SelectStatement); ok {
@@ -212,6 +257,15 @@ func (tc *TypeChecker) checkBooleanExpression(expr parser.Expression, stmt *pars
 func (tc *TypeChecker) inferExpressionType(expr parser.Expression, stmt *parser.SelectStatement) *DataType {
 	switch e := expr.(type) {
 	case *parser.Literal:
+		switch e.Kind {
+		case lexer.LiteralBit:
+			return &DataType{Name: "BIT"}
+		case lexer.LiteralNational:
+			if v, ok := e.Value.(string); ok {
+				return &DataType{Name: "NVARCHAR", Length: utf8.RuneCountInString(v)}
+			}
+		}
+
 		// Infer type from literal value
 		switch v := e.Value.(type) {
 		case int, int64, int32:
@@ -220,6 +274,8 @@ func (tc *TypeChecker) inferExpressionType(expr parser.Expression, stmt *parser.
 			return &DataType{Name: "FLOAT"}
 		case string:
 			return &DataType{Name: "VARCHAR", Length: len(v)}
+		case []byte:
+			return &DataType{Name: "VARBINARY", Length: len(v)}
 		case bool:
 			return &DataType{Name: "BOOLEAN"}
 		case nil:
@@ -228,20 +284,27 @@ func (tc *TypeChecker) inferExpressionType(expr parser.Expression, stmt *parser.
 
 	case *parser.ColumnReference:
 		// Look up column type from schema
+		scope := tc.validator.selectScope(stmt)
 		if e.Table != "" {
-			if col, err := tc.schema.GetColumn(e.Table, e.Column); err == nil {
-				return col.DataType
+			if table, _ := tc.validator.resolveQualifier(e, scope); table != nil {
+				if col, ok := table.GetColumn(e.Column); ok {
+					return col.DataType
+				}
 			}
-		} else if stmt != nil && stmt.From != nil {
+		} else {
 			// Try to find column in any table
-			for _, tableRef := range stmt.From.Tables {
-				if col, err := tc.schema.GetColumn(tableRef.Name, e.Column); err == nil {
+			for _, table := range scope.tables {
+				if col, ok := table.GetColumn(e.Column); ok {
 					return col.DataType
 				}
 			}
 		}
 
 	case *parser.BinaryExpression:
+		if e.Operator == "AND" || e.Operator == "OR" || booleanOperators[strings.ToUpper(e.Operator)] {
+			return &DataType{Name: "BOOLEAN"}
+		}
+
 		// Arithmetic operators return numeric types
 		leftType := tc.inferExpressionType(e.Left, stmt)
 		rightType := tc.inferExpressionType(e.Right, stmt)
@@ -260,6 +323,22 @@ func (tc *TypeChecker) inferExpressionType(expr parser.Expression, stmt *parser.
 	case *parser.FunctionCall:
 		// Infer type based on function name
 		return tc.inferFunctionReturnType(e)
+
+	case *parser.CastExpression:
+		// A cast has its target type
+		if e.DataType != nil {
+			return castTargetType(e.DataType)
+		}
+		return &DataType{Name: "VARCHAR"}
+
+	case *parser.ExtractExpression:
+		return &DataType{Name: "INT"}
+
+	case *parser.TrimExpression:
+		return &DataType{Name: "VARCHAR"}
+
+	case *parser.IntervalExpression:
+		return &DataType{Name: "INTERVAL"}
 	}
 
 	return nil
@@ -287,3 +366,13 @@ func (tc *TypeChecker) inferFunctionReturnType(fn *parser.FunctionCall) *DataTyp
 
 	return nil
 }
+
+// castTargetType converts a CAST target into a schema data type
+func castTargetType(dt *parser.DataTypeDefinition) *DataType {
+	return &DataType{
+		Name:      NormalizeTypeName(dt.Name),
+		Length:    dt.Length,
+		Precision: dt.Precision,
+		Scale:     dt.Scale,
+	}
+}
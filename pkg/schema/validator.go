@@ -2,6 +2,7 @@ package schema
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
 )
@@ -21,13 +22,22 @@ func (ve *ValidationError) Error() string {
 
 // Validator validates SQL statements against a schema
 type Validator struct {
-	schema *Schema
+	resolver *Resolver
+	scoped   map[string]*scopedTable // temp tables and table variables, by lower-cased name
 }
 
 // NewValidator creates a new validator
 func NewValidator(schema *Schema) *Validator {
 	return &Validator{
-		schema: schema,
+		resolver: NewResolver(nil, schema),
+	}
+}
+
+// NewValidatorWithResolver creates a validator that resolves qualified names
+// across all of the resolver's schemas
+func NewValidatorWithResolver(resolver *Resolver) *Validator {
+	return &Validator{
+		resolver: resolver,
 	}
 }
 
@@ -36,7 +46,7 @@ func (v *Validator) ValidateStatement(stmt parser.Statement) []*ValidationError
 	errors := make([]*ValidationError, 0)
 
 	// Validate that schema is not nil
-	if v.schema == nil {
+	if v.resolver == nil || len(v.resolver.Schemas()) == 0 {
 		errors = append(errors, &ValidationError{
 			Type:    "SCHEMA_NOT_LOADED",
 			Message: "Schema is not loaded",
@@ -57,6 +67,8 @@ func (v *Validator) ValidateStatement(stmt parser.Statement) []*ValidationError
 		errors = append(errors, v.validateWithStatement(s)...)
 	}
 
+	v.trackScopedObjects(stmt)
+
 	return errors
 }
 
@@ -88,24 +100,118 @@ func (v *Validator) validateSelectStatement(stmt *parser.SelectStatement) []*Val
 		}
 	}
 
+	scope := v.selectScope(stmt)
+
+	// Validate USING columns
+	errors = append(errors, v.validateJoinUsing(stmt)...)
+
+	// Validate DISTINCT ON
+	for _, expr := range stmt.DistinctOn {
+		errors = append(errors, v.validateExpression(expr, scope)...)
+	}
+
 	// Validate columns in SELECT list
 	for _, col := range stmt.Columns {
-		errors = append(errors, v.validateExpression(col, stmt)...)
+		errors = append(errors, v.validateExpression(col, scope)...)
 	}
 
 	// Validate WHERE clause
 	if stmt.Where != nil {
-		errors = append(errors, v.validateExpression(stmt.Where, stmt)...)
+		errors = append(errors, v.validateExpression(stmt.Where, scope)...)
 	}
 
 	// Validate GROUP BY
 	for _, expr := range stmt.GroupBy {
-		errors = append(errors, v.validateExpression(expr, stmt)...)
+		errors = append(errors, v.validateExpression(expr, scope)...)
 	}
 
 	// Validate HAVING
 	if stmt.Having != nil {
-		errors = append(errors, v.validateExpression(stmt.Having, stmt)...)
+		errors = append(errors, v.validateExpression(stmt.Having, scope)...)
+	}
+
+	// Validate named windows
+	for _, window := range stmt.Windows {
+		errors = append(errors, v.validateOverClause(window.Spec, scope)...)
+	}
+
+	// Validate QUALIFY
+	if stmt.Qualify != nil {
+		errors = append(errors, v.validateExpression(stmt.Qualify, scope)...)
+	}
+
+	return errors
+}
+
+// validateOverClause validates the PARTITION BY and ORDER BY columns of a
+// window specification
+func (v *Validator) validateOverClause(oc *parser.OverClause, scope *tableScope) []*ValidationError {
+	errors := make([]*ValidationError, 0)
+	if oc == nil {
+		return errors
+	}
+
+	for _, expr := range oc.PartitionBy {
+		errors = append(errors, v.validateExpression(expr, scope)...)
+	}
+	for _, ob := range oc.OrderBy {
+		errors = append(errors, v.validateExpression(ob.Expression, scope)...)
+	}
+
+	return errors
+}
+
+// validateJoinUsing checks that each JOIN ... USING column exists on both
+// sides of the join
+func (v *Validator) validateJoinUsing(stmt *parser.SelectStatement) []*ValidationError {
+	errors := make([]*ValidationError, 0)
+
+	// Tables joined so far (the left side of each join). Derived and
+	// unknown tables can't be checked, so their columns are assumed.
+	var left []*Table
+	leftUnknown := false
+	if stmt.From != nil {
+		for i := range stmt.From.Tables {
+			if table, ok := v.resolveTable(&stmt.From.Tables[i]); ok {
+				left = append(left, table)
+			} else {
+				leftUnknown = true
+			}
+		}
+	}
+
+	for _, join := range stmt.Joins {
+		right, ok := v.resolveTable(&join.Table)
+		for _, colName := range join.Using {
+			if ok && !right.HasColumn(colName) {
+				errors = append(errors, &ValidationError{
+					Type:    "COLUMN_NOT_FOUND",
+					Message: fmt.Sprintf("USING column '%s' not found in table '%s'", colName, join.Table.Name),
+					Table:   join.Table.Name,
+					Column:  colName,
+				})
+				continue
+			}
+			found := leftUnknown
+			for _, table := range left {
+				if table.HasColumn(colName) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				errors = append(errors, &ValidationError{
+					Type:    "COLUMN_NOT_FOUND",
+					Message: fmt.Sprintf("USING column '%s' not found on the left side of the join", colName),
+					Column:  colName,
+				})
+			}
+		}
+		if ok {
+			left = append(left, right)
+		} else {
+			leftUnknown = true
+		}
 	}
 
 	return errors
@@ -125,20 +231,36 @@ func (v *Validator) validateInsertStatement(stmt *parser.InsertStatement) []*Val
 		return errors // Can't continue without valid table
 	}
 
-	table, _ := v.schema.GetTable(stmt.Table.Name)
+	table, _ := v.resolveTable(&stmt.Table)
 
 	// Validate columns
-	for _, colName := range stmt.Columns {
-		if !table.HasColumn(colName) {
+	errors = append(errors, validateColumnNames(table, stmt.Table.Name, stmt.Columns)...)
+
+	// EXCLUDED (ON CONFLICT) and inserted (OUTPUT) name the new row
+	scope := v.dmlScope(&stmt.Table, "excluded", "inserted")
+
+	// Validate ON CONFLICT target and DO UPDATE clause
+	if oc := stmt.OnConflict; oc != nil {
+		targetErrors := validateColumnNames(table, stmt.Table.Name, oc.Columns)
+		errors = append(errors, targetErrors...)
+		if len(oc.Columns) > 0 && len(targetErrors) == 0 && !table.HasUniqueKey(oc.Columns) {
 			errors = append(errors, &ValidationError{
-				Type:    "COLUMN_NOT_FOUND",
-				Message: fmt.Sprintf("Column '%s' not found in table '%s'", colName, stmt.Table.Name),
+				Type:    "NO_UNIQUE_CONSTRAINT",
+				Message: fmt.Sprintf("No unique index or primary key on '%s' matches ON CONFLICT (%s)", stmt.Table.Name, strings.Join(oc.Columns, ", ")),
 				Table:   stmt.Table.Name,
-				Column:  colName,
 			})
 		}
+		errors = append(errors, v.validateAssignments(oc.Set, table, stmt.Table.Name, scope)...)
+		if oc.Where != nil {
+			errors = append(errors, v.validateExpression(oc.Where, scope)...)
+		}
 	}
 
+	// Validate ON DUPLICATE KEY UPDATE
+	errors = append(errors, v.validateAssignments(stmt.OnDuplicateKeyUpdate, table, stmt.Table.Name, scope)...)
+
+	errors = append(errors, v.validateReturning(stmt.Returning, stmt.Output, scope)...)
+
 	return errors
 }
 
@@ -156,25 +278,21 @@ func (v *Validator) validateUpdateStatement(stmt *parser.UpdateStatement) []*Val
 		return errors
 	}
 
-	table, _ := v.schema.GetTable(stmt.Table.Name)
+	table, _ := v.resolveTable(&stmt.Table)
+
+	// inserted and deleted (OUTPUT) name the new and old row
+	scope := v.dmlScope(&stmt.Table, "inserted", "deleted")
 
 	// Validate SET columns
-	for _, assignment := range stmt.Set {
-		if !table.HasColumn(assignment.Column) {
-			errors = append(errors, &ValidationError{
-				Type:    "COLUMN_NOT_FOUND",
-				Message: fmt.Sprintf("Column '%s' not found in table '%s'", assignment.Column, stmt.Table.Name),
-				Table:   stmt.Table.Name,
-				Column:  assignment.Column,
-			})
-		}
-	}
+	errors = append(errors, v.validateAssignments(stmt.Set, table, stmt.Table.Name, scope)...)
 
 	// Validate WHERE clause
 	if stmt.Where != nil {
 		errors = append(errors, v.validateExpressionForTable(stmt.Where, &stmt.Table)...)
 	}
 
+	errors = append(errors, v.validateReturning(stmt.Returning, stmt.Output, scope)...)
+
 	return errors
 }
 
@@ -197,6 +315,64 @@ func (v *Validator) validateDeleteStatement(stmt *parser.DeleteStatement) []*Val
 		errors = append(errors, v.validateExpressionForTable(stmt.Where, &stmt.From)...)
 	}
 
+	errors = append(errors, v.validateReturning(stmt.Returning, stmt.Output, v.dmlScope(&stmt.From, "deleted"))...)
+
+	return errors
+}
+
+// validateColumnNames checks that each named column exists in the table
+func validateColumnNames(table *Table, tableName string, columns []string) []*ValidationError {
+	errors := make([]*ValidationError, 0)
+
+	for _, colName := range columns {
+		if !table.HasColumn(colName) {
+			errors = append(errors, &ValidationError{
+				Type:    "COLUMN_NOT_FOUND",
+				Message: fmt.Sprintf("Column '%s' not found in table '%s'", colName, tableName),
+				Table:   tableName,
+				Column:  colName,
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateAssignments validates SET-style assignments against the target table
+func (v *Validator) validateAssignments(assignments []*parser.Assignment, table *Table, tableName string, scope *tableScope) []*ValidationError {
+	errors := make([]*ValidationError, 0)
+
+	for _, assignment := range assignments {
+		errors = append(errors, validateColumnNames(table, tableName, []string{assignment.Column})...)
+		errors = append(errors, v.validateExpression(assignment.Value, scope)...)
+	}
+
+	return errors
+}
+
+// validateReturning validates RETURNING and OUTPUT column lists
+func (v *Validator) validateReturning(returning *parser.ReturningClause, output *parser.OutputClause, scope *tableScope) []*ValidationError {
+	errors := make([]*ValidationError, 0)
+
+	if returning != nil {
+		for _, col := range returning.Columns {
+			errors = append(errors, v.validateExpression(col, scope)...)
+		}
+	}
+
+	if output != nil {
+		for _, col := range output.Columns {
+			errors = append(errors, v.validateExpression(col, scope)...)
+		}
+		if output.Into != nil && !v.validateTableReference(output.Into) {
+			errors = append(errors, &ValidationError{
+				Type:    "TABLE_NOT_FOUND",
+				Message: fmt.Sprintf("Table '%s' in OUTPUT INTO not found in schema", output.Into.Name),
+				Table:   output.Into.Name,
+			})
+		}
+	}
+
 	return errors
 }
 
@@ -224,47 +400,43 @@ func (v *Validator) validateTableReference(tableRef *parser.TableReference) bool
 		return true
 	}
 
-	// Check if table exists in schema
-	return v.schema.HasTable(tableRef.Name)
+	// Check if table exists in one of the schemas
+	_, ok := v.resolveTable(tableRef)
+	return ok
 }
 
 // validateExpression validates an expression against the schema
-func (v *Validator) validateExpression(expr parser.Expression, stmt *parser.SelectStatement) []*ValidationError {
+func (v *Validator) validateExpression(expr parser.Expression, scope *tableScope) []*ValidationError {
 	errors := make([]*ValidationError, 0)
 
 	switch e := expr.(type) {
 	case *parser.ColumnReference:
 		// Validate column reference
 		if e.Table != "" {
-			// Fully qualified column (table.column)
-			if !v.schema.HasTable(e.Table) {
+			// Qualified column (alias.column, table.column, schema.table.column)
+			table, found := v.resolveQualifier(e, scope)
+			if !found {
+				qualifier := columnQualifier(e)
 				errors = append(errors, &ValidationError{
 					Type:    "TABLE_NOT_FOUND",
-					Message: fmt.Sprintf("Table '%s' not found", e.Table),
+					Message: fmt.Sprintf("Table '%s' not found", qualifier),
+					Table:   qualifier,
+				})
+			} else if table != nil && !table.HasColumn(e.Column) {
+				errors = append(errors, &ValidationError{
+					Type:    "COLUMN_NOT_FOUND",
+					Message: fmt.Sprintf("Column '%s' not found in table '%s'", e.Column, e.Table),
 					Table:   e.Table,
+					Column:  e.Column,
 				})
-			} else {
-				table, _ := v.schema.GetTable(e.Table)
-				if !table.HasColumn(e.Column) {
-					errors = append(errors, &ValidationError{
-						Type:    "COLUMN_NOT_FOUND",
-						Message: fmt.Sprintf("Column '%s' not found in table '%s'", e.Column, e.Table),
-						Table:   e.Table,
-						Column:  e.Column,
-					})
-				}
 			}
 		} else {
-			// Unqualified column - check in all tables in FROM clause
+			// Unqualified column - check in all tables in FROM and JOIN clauses
 			found := false
-			if stmt.From != nil {
-				for _, tableRef := range stmt.From.Tables {
-					if table, ok := v.schema.GetTable(tableRef.Name); ok {
-						if table.HasColumn(e.Column) {
-							found = true
-							break
-						}
-					}
+			for _, table := range scope.tables {
+				if table.HasColumn(e.Column) {
+					found = true
+					break
 				}
 			}
 			if !found {
@@ -278,18 +450,70 @@ func (v *Validator) validateExpression(expr parser.Expression, stmt *parser.Sele
 
 	case *parser.BinaryExpression:
 		// Recursively validate left and right sides
-		errors = append(errors, v.validateExpression(e.Left, stmt)...)
-		errors = append(errors, v.validateExpression(e.Right, stmt)...)
+		errors = append(errors, v.validateExpression(e.Left, scope)...)
+		errors = append(errors, v.validateExpression(e.Right, scope)...)
 
 	case *parser.FunctionCall:
 		// Validate function arguments
 		for _, arg := range e.Arguments {
-			errors = append(errors, v.validateExpression(arg, stmt)...)
+			errors = append(errors, v.validateExpression(arg, scope)...)
+		}
+		for _, ob := range e.OrderBy {
+			errors = append(errors, v.validateExpression(ob.Expression, scope)...)
+		}
+		for _, ob := range e.WithinGroup {
+			errors = append(errors, v.validateExpression(ob.Expression, scope)...)
+		}
+		if e.Filter != nil {
+			errors = append(errors, v.validateExpression(e.Filter, scope)...)
+		}
+
+	case *parser.WindowFunction:
+		errors = append(errors, v.validateExpression(e.Function, scope)...)
+		errors = append(errors, v.validateOverClause(e.OverClause, scope)...)
+
+	case *parser.GroupingSetsExpression:
+		for _, set := range e.Sets {
+			for _, expr := range set {
+				errors = append(errors, v.validateExpression(expr, scope)...)
+			}
+		}
+
+	case *parser.CastExpression:
+		errors = append(errors, v.validateExpression(e.Expression, scope)...)
+
+	case *parser.ExtractExpression:
+		errors = append(errors, v.validateExpression(e.Source, scope)...)
+
+	case *parser.TrimExpression:
+		errors = append(errors, v.validateExpression(e.Source, scope)...)
+		if e.Characters != nil {
+			errors = append(errors, v.validateExpression(e.Characters, scope)...)
 		}
 
 	case *parser.SubqueryExpression:
 		// Validate subquery
 		errors = append(errors, v.validateSelectStatement(e.Query)...)
+
+	case *parser.AliasedExpression:
+		errors = append(errors, v.validateExpression(e.Expression, scope)...)
+
+	case *parser.VariableAssignment:
+		errors = append(errors, v.validateExpression(e.Value, scope)...)
+
+	case *parser.UnaryExpression:
+		errors = append(errors, v.validateExpression(e.Operand, scope)...)
+
+	case *parser.InExpression:
+		errors = append(errors, v.validateExpression(e.Expression, scope)...)
+		for _, value := range e.Values {
+			errors = append(errors, v.validateExpression(value, scope)...)
+		}
+
+	case *parser.BetweenExpression:
+		errors = append(errors, v.validateExpression(e.Expression, scope)...)
+		errors = append(errors, v.validateExpression(e.Low, scope)...)
+		errors = append(errors, v.validateExpression(e.High, scope)...)
 	}
 
 	return errors
@@ -301,7 +525,7 @@ func (v *Validator) validateExpressionForTable(expr parser.Expression, tableRef
 
 	switch e := expr.(type) {
 	case *parser.ColumnReference:
-		table, ok := v.schema.GetTable(tableRef.Name)
+		table, ok := v.resolveTable(tableRef)
 		if !ok {
 			return errors // Table not found error already reported
 		}
@@ -322,3 +546,81 @@ func (v *Validator) validateExpressionForTable(expr parser.Expression, tableRef
 
 	return errors
 }
+
+// tableScope holds the tables visible to a statement's expressions, keyed
+// by alias and by name
+type tableScope struct {
+	entries map[string]*Table // nil for derived tables and unresolved names
+	tables  []*Table          // resolved tables in FROM/JOIN order
+}
+
+// newTableScope resolves the given table references
+func (v *Validator) newTableScope(refs ...*parser.TableReference) *tableScope {
+	scope := &tableScope{entries: make(map[string]*Table)}
+	for _, ref := range refs {
+		table, _ := v.resolveTable(ref)
+		if table != nil {
+			scope.tables = append(scope.tables, table)
+		}
+		if ref.Alias != "" {
+			scope.entries[strings.ToLower(ref.Alias)] = table
+		}
+		if ref.Name != "" {
+			if _, exists := scope.entries[strings.ToLower(ref.Name)]; !exists {
+				scope.entries[strings.ToLower(ref.Name)] = table
+			}
+		}
+	}
+	return scope
+}
+
+// dmlScope returns the scope of a DML statement's target table. The pseudo
+// tables (EXCLUDED, inserted, deleted) are aliases for the target's rows.
+func (v *Validator) dmlScope(ref *parser.TableReference, pseudoTables ...string) *tableScope {
+	scope := v.newTableScope(ref)
+	table, _ := v.resolveTable(ref)
+	for _, name := range pseudoTables {
+		scope.entries[name] = table
+	}
+	return scope
+}
+
+// selectScope returns the scope of a SELECT's FROM and JOIN clauses
+func (v *Validator) selectScope(stmt *parser.SelectStatement) *tableScope {
+	var refs []*parser.TableReference
+	if stmt != nil && stmt.From != nil {
+		for i := range stmt.From.Tables {
+			refs = append(refs, &stmt.From.Tables[i])
+		}
+	}
+	if stmt != nil {
+		for _, join := range stmt.Joins {
+			refs = append(refs, &join.Table)
+		}
+	}
+	return v.newTableScope(refs...)
+}
+
+// resolveQualifier returns the table a qualified column refers to. found is
+// true with a nil table for derived tables, which can't be checked.
+func (v *Validator) resolveQualifier(col *parser.ColumnReference, scope *tableScope) (table *Table, found bool) {
+	if col.Schema == "" && scope != nil {
+		if table, ok := scope.entries[strings.ToLower(col.Table)]; ok {
+			if table != nil {
+				return table, true
+			}
+			// Derived table alias, or a table already reported missing
+			return nil, true
+		}
+	}
+	return v.resolver.Resolve(col.Catalog, col.Schema, col.Table)
+}
+
+// columnQualifier returns the dotted qualifier of a column reference
+func columnQualifier(col *parser.ColumnReference) string {
+	parts := []string{col.Catalog, col.Schema, col.Table}
+	for len(parts) > 0 && parts[0] == "" {
+		parts = parts[1:]
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,241 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/monitor"
+)
+
+// Test that fingerprints ignore formatting but not values
+func TestQueryFingerprint(t *testing.T) {
+	d := dialect.GetDialect("mysql")
+	base := analyzer.QueryFingerprint("SELECT id FROM users WHERE age > 30", d)
+
+	same := []string{
+		"select id\n  from users\n where age > 30",
+		"SELECT id FROM users -- adults\nWHERE age > 30",
+		"SELECT /* list */ id FROM users WHERE age>30",
+	}
+	for _, sql := range same {
+		if fp := analyzer.QueryFingerprint(sql, d); fp != base {
+			t.Errorf("Expected %q to have the fingerprint of the base query", sql)
+		}
+	}
+	if analyzer.QueryFingerprint("SELECT id FROM users WHERE age > 31", d) == base {
+		t.Error("Expected a different value to change the fingerprint")
+	}
+	if analyzer.QueryFingerprint("SELECT id FROM users WHERE age > '30'", d) == base {
+		t.Error("Expected a string literal to differ from a number")
+	}
+}
+
+func cachedAnalysis(queryType string) analyzer.QueryAnalysis {
+	return analyzer.QueryAnalysis{QueryType: queryType, Tables: []analyzer.TableInfo{{Name: queryType}}}
+}
+
+// Test LRU eviction and the cache counters
+func TestAnalysisCacheLRU(t *testing.T) {
+	cache := analyzer.NewAnalysisCache(analyzer.CacheConfig{MaxEntries: 2})
+	cache.Put("a", cachedAnalysis("a"))
+	cache.Put("b", cachedAnalysis("b"))
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	cache.Put("c", cachedAnalysis("c"))
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if got, ok := cache.Get("a"); !ok || got.Tables[0].Name != "a" {
+		t.Errorf("Expected a to stay cached, got %+v", got)
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Hits != 2 || stats.Misses != 1 || stats.Evictions != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// Cached analyses are copies
+	got, _ := cache.Get("c")
+	got.Tables[0].Name = "changed"
+	if again, _ := cache.Get("c"); again.Tables[0].Name != "c" {
+		t.Error("Expected a cached analysis to be unaffected by changes to a returned copy")
+	}
+}
+
+// Test that ARC keeps frequently used entries through a scan of new ones
+func TestAnalysisCacheARC(t *testing.T) {
+	for _, policy := range []string{analyzer.CachePolicyLRU, analyzer.CachePolicyARC} {
+		cache := analyzer.NewAnalysisCache(analyzer.CacheConfig{MaxEntries: 4, Policy: policy})
+		cache.Put("hot", cachedAnalysis("hot"))
+		cache.Get("hot")
+		for i := 0; i < 10; i++ {
+			cache.Put(fmt.Sprintf("scan%d", i), cachedAnalysis("scan"))
+		}
+
+		_, ok := cache.Get("hot")
+		if ok != (policy == analyzer.CachePolicyARC) {
+			t.Errorf("%s: expected the hot entry cached to be %v, got %v", policy, policy == analyzer.CachePolicyARC, ok)
+		}
+		if stats := cache.Stats(); stats.Entries != 4 {
+			t.Errorf("%s: expected 4 entries, got %d", policy, stats.Entries)
+		}
+	}
+}
+
+// Test the memory bound and expiry
+func TestAnalysisCacheBounds(t *testing.T) {
+	cache := analyzer.NewAnalysisCache(analyzer.CacheConfig{MaxBytes: 150})
+	cache.Put("a", cachedAnalysis("a"))
+	cache.Put("b", cachedAnalysis("b"))
+	if stats := cache.Stats(); stats.Entries != 1 || stats.Bytes > 150 {
+		t.Errorf("Expected one entry within 150 bytes, got %+v", stats)
+	}
+
+	cache = analyzer.NewAnalysisCache(analyzer.CacheConfig{TTL: time.Millisecond})
+	cache.Put("a", cachedAnalysis("a"))
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Expected the entry to expire")
+	}
+	if stats := cache.Stats(); stats.Expirations != 1 || stats.Entries != 0 {
+		t.Errorf("Expected one expiration, got %+v", stats)
+	}
+}
+
+// Test that a saved cache loads into a new one
+func TestAnalysisCachePersistence(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.json")
+	cache := analyzer.NewAnalysisCache(analyzer.CacheConfig{})
+	cache.Put("a", cachedAnalysis("a"))
+	cache.Put("b", cachedAnalysis("b"))
+	if err := cache.Save(filename); err != nil {
+		t.Fatalf("Failed to save cache: %v", err)
+	}
+
+	loaded := analyzer.NewAnalysisCache(analyzer.CacheConfig{})
+	if err := loaded.Load(filename); err != nil {
+		t.Fatalf("Failed to load cache: %v", err)
+	}
+	if got, ok := loaded.Get("b"); !ok || got.QueryType != "b" {
+		t.Errorf("Expected b to be loaded, got %+v", got)
+	}
+	if loaded.Stats().Entries != 2 {
+		t.Errorf("Expected 2 entries, got %d", loaded.Stats().Entries)
+	}
+}
+
+// Test that analyzers with different settings share a cache safely
+func TestAnalysisCacheSharing(t *testing.T) {
+	cache := analyzer.NewAnalysisCache(analyzer.CacheConfig{})
+	mysql := analyzer.NewWithDialect(dialect.GetDialect("mysql"))
+	mysql.SetCache(cache)
+	postgres := analyzer.NewWithDialect(dialect.GetDialect("postgresql"))
+	postgres.SetCache(cache)
+
+	first := "SELECT id FROM users WHERE age > 30"
+	second := "DELETE FROM sessions"
+	fp := analyzer.QueryFingerprint(first, nil)
+	mysql.AnalyzeWithCache(parseWithDialect(t, first, "mysql"), fp)
+	// The analyzer reuses its buffers; the cached analysis must not change
+	mysql.AnalyzeWithCache(parseWithDialect(t, second, "mysql"), analyzer.QueryFingerprint(second, nil))
+
+	analysis := mysql.AnalyzeWithCache(parseWithDialect(t, first, "mysql"), fp)
+	if analysis.QueryType != "SELECT" || len(analysis.Tables) != 1 || analysis.Tables[0].Name != "users" {
+		t.Errorf("Expected the cached SELECT analysis, got %+v", analysis)
+	}
+	if stats := cache.Stats(); stats.Hits != 1 {
+		t.Errorf("Expected 1 hit, got %+v", stats)
+	}
+
+	// Another dialect is a different key
+	postgres.AnalyzeWithCache(parseWithDialect(t, first, "postgresql"), fp)
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Entries != 3 {
+		t.Errorf("Expected the PostgreSQL analysis to be cached apart, got %+v", stats)
+	}
+
+	// Workers of a ConcurrentAnalyzer hit entries keyed by the query text
+	ca := analyzer.NewConcurrentAnalyzer(1)
+	ca.SetCache(cache)
+	jobs := []analyzer.AnalysisJob{
+		{ID: "1", Query: first, Stmt: parseWithDialect(t, first, "sqlserver")},
+		{ID: "2", Query: "select id from users\nwhere age > 30", Stmt: parseWithDialect(t, first, "sqlserver")},
+	}
+	ca.AnalyzeConcurrently(context.Background(), jobs)
+	if stats := ca.GetCacheStats(); stats["cache_hits"].(uint64) != 2 {
+		t.Errorf("Expected the reformatted query to hit, got %v", stats)
+	}
+
+	// Each statement of a script is keyed by its own text
+	scripts := analyzer.NewConcurrentAnalyzer(1)
+	scripts.SetCache(analyzer.NewAnalysisCache(analyzer.CacheConfig{}))
+	for range scripts.AnalyzeSeq(context.Background(), slices.Values([]analyzer.AnalysisJob{
+		{ID: "script", Query: "SELECT id FROM users; DELETE FROM sessions"},
+		{ID: "single", Query: "delete from sessions"},
+	}), analyzer.PipelineOptions{Dialect: dialect.GetDialect("postgresql")}) {
+	}
+	if stats := scripts.GetCacheStats(); stats["cache_hits"].(uint64) != 1 {
+		t.Errorf("Expected the statement of the script to hit, got %v", stats)
+	}
+
+	// So do repeated queries of a log
+	processor := monitor.NewLogProcessor("mysql")
+	processor.SetCache(cache)
+	lines := make(chan string, 2)
+	lines <- "SELECT name FROM products WHERE price > 10"
+	lines <- "select name  from products where price > 10"
+	close(lines)
+	processor.Start(context.Background(), lines)
+	if stats := cache.Stats(); stats.Hits != 3 {
+		t.Errorf("Expected the repeated log query to hit, got %+v", stats)
+	}
+}
+
+// Test that suggestions are cached with the analysis, by rule settings
+func TestAnalysisCacheSuggestions(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "cache.json")
+	sql := "SELECT * FROM users"
+	fp := analyzer.QueryFingerprint(sql, nil)
+	cache := analyzer.NewAnalysisCache(analyzer.CacheConfig{})
+	a := analyzer.NewWithDialect(dialect.GetDialect("mysql"))
+	a.SetCache(cache)
+	first := a.OptimizeWithCache(parseWithDialect(t, sql, "mysql"), fp)
+	if len(first.EnhancedSuggestions) == 0 || len(first.Suggestions) == 0 {
+		t.Fatalf("Expected suggestions for SELECT *, got %+v", first)
+	}
+	if err := cache.Save(filename); err != nil {
+		t.Fatalf("Failed to save cache: %v", err)
+	}
+
+	// A later run reuses them
+	loaded := analyzer.NewAnalysisCache(analyzer.CacheConfig{})
+	if err := loaded.Load(filename); err != nil {
+		t.Fatalf("Failed to load cache: %v", err)
+	}
+	a = analyzer.NewWithDialect(dialect.GetDialect("mysql"))
+	a.SetCache(loaded)
+	again := a.OptimizeWithCache(parseWithDialect(t, sql, "mysql"), fp)
+	if stats := loaded.Stats(); stats.Hits != 1 || len(again.EnhancedSuggestions) != len(first.EnhancedSuggestions) {
+		t.Errorf("Expected the cached suggestions, got %+v and %v", stats, again.EnhancedSuggestions)
+	}
+
+	// Other rule settings are a different key
+	disabled := false
+	engine := analyzer.NewOptimizationEngine(dialect.GetDialect("mysql"))
+	if err := engine.ConfigureRules(map[string]analyzer.RuleConfig{"SELECT_STAR": {Enabled: &disabled}}); err != nil {
+		t.Fatal(err)
+	}
+	a.SetOptimizationEngine(engine)
+	for _, s := range a.OptimizeWithCache(parseWithDialect(t, sql, "mysql"), fp).EnhancedSuggestions {
+		if s.Rule == "SELECT_STAR" {
+			t.Error("Expected the disabled rule not to come from the cache")
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+func tokenLiterals(sql, dialectName string) []string {
+	l := lexer.NewWithDialect(sql, dialect.GetDialect(dialectName))
+	var literals []string
+	for tok := l.NextToken(); tok.Type != lexer.EOF; tok = l.NextToken() {
+		literals = append(literals, tok.Literal)
+	}
+	return literals
+}
+
+// Test that comments are skipped per dialect
+func TestLexerComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		sql      string
+		expected []string
+	}{
+		{"Line comment", "postgresql", "SELECT a -- trailing\nFROM t", []string{"SELECT", "a", "FROM", "t"}},
+		{"Block comment", "mysql", "SELECT /* cols */ a FROM t", []string{"SELECT", "a", "FROM", "t"}},
+		{"Nested block comment", "postgresql", "SELECT /* outer /* inner */ still */ a FROM t", []string{"SELECT", "a", "FROM", "t"}},
+		{"Unnested block comment", "mysql", "SELECT /* a /* b */ c FROM t", []string{"SELECT", "c", "FROM", "t"}},
+		{"MySQL hash comment", "mysql", "SELECT a # note\nFROM t", []string{"SELECT", "a", "FROM", "t"}},
+		{"MySQL conditional comment", "mysql", "SELECT /*!50000 SQL_NO_CACHE */ a FROM t", []string{"SELECT", "SQL_NO_CACHE", "a", "FROM", "t"}},
+		{"Hint outside statement start", "oracle", "SELECT a /*+ FULL(t) */ FROM t", []string{"SELECT", "a", "FROM", "t"}},
+		{"Hint in PostgreSQL", "postgresql", "SELECT /*+ FULL(t) */ a FROM t", []string{"SELECT", "a", "FROM", "t"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenLiterals(tt.sql, tt.dialect); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected tokens %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	l := lexer.NewWithDialect("SELECT a /* never closed", dialect.GetDialect("postgresql"))
+	var last lexer.Token
+	for tok := l.NextToken(); tok.Type != lexer.EOF; tok = l.NextToken() {
+		last = tok
+	}
+	if last.Type != lexer.ILLEGAL {
+		t.Errorf("Expected ILLEGAL for an unterminated comment, got %s", last.Type)
+	}
+
+	p := parser.NewWithDialect(context.Background(), "-- header\nSELECT a /* cols */ FROM t", dialect.GetDialect("postgresql"))
+	if _, err := p.ParseStatement(); err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	comments := p.Comments()
+	if len(comments) != 2 || comments[0].Text != "-- header" || comments[1].Text != "/* cols */" || comments[1].Line != 2 {
+		t.Errorf("Unexpected comments: %+v", comments)
+	}
+}
+
+// Test optimizer hints, SQL Server query hints and table hints
+func TestStatementHints(t *testing.T) {
+	hintStrings := func(hints []*parser.Hint) []string {
+		var out []string
+		for _, h := range hints {
+			out = append(out, h.String())
+		}
+		return out
+	}
+
+	stmt := parseWithDialect(t, "SELECT /*+ INDEX(o idx_status) FULL(c) PARALLEL(4) */ o.id FROM orders o JOIN customers c ON o.customer_id = c.id", "oracle").(*parser.SelectStatement)
+	if got := hintStrings(stmt.Hints); !reflect.DeepEqual(got, []string{"INDEX(o idx_status)", "FULL(c)", "PARALLEL(4)"}) {
+		t.Errorf("Unexpected Oracle hints: %v", got)
+	}
+
+	stmt = parseWithDialect(t, "SELECT /*+ MAX_EXECUTION_TIME(1000) */ id FROM orders o FORCE INDEX (idx_status) WHERE status = 'paid'", "mysql").(*parser.SelectStatement)
+	if got := hintStrings(stmt.Hints); !reflect.DeepEqual(got, []string{"MAX_EXECUTION_TIME(1000)"}) {
+		t.Errorf("Unexpected MySQL hints: %v", got)
+	}
+	if table := stmt.From.Tables[0]; table.Alias != "o" || len(table.Hints) != 1 || table.Hints[0].String() != "FORCE INDEX(idx_status)" {
+		t.Errorf("Unexpected MySQL index hint: %+v", table)
+	}
+
+	stmt = parseWithDialect(t, "SELECT id FROM orders o WITH (NOLOCK, INDEX(ix_status)) JOIN users u WITH (READPAST) ON o.user_id = u.id OPTION (MAXDOP 4, RECOMPILE, HASH JOIN)", "sqlserver").(*parser.SelectStatement)
+	if got := hintStrings(stmt.From.Tables[0].Hints); !reflect.DeepEqual(got, []string{"NOLOCK", "INDEX(ix_status)"}) {
+		t.Errorf("Unexpected table hints: %v", got)
+	}
+	if got := hintStrings(stmt.Joins[0].Table.Hints); !reflect.DeepEqual(got, []string{"READPAST"}) {
+		t.Errorf("Unexpected join table hints: %v", got)
+	}
+	if got := hintStrings(stmt.Hints); !reflect.DeepEqual(got, []string{"MAXDOP(4)", "RECOMPILE", "HASH JOIN"}) {
+		t.Errorf("Unexpected query hints: %v", got)
+	}
+
+	update := parseWithDialect(t, "UPDATE /*+ INDEX(users idx_email) */ users SET name = 'x' WHERE id = 1", "oracle").(*parser.UpdateStatement)
+	if len(update.Hints) != 1 || update.Hints[0].Name != "INDEX" {
+		t.Errorf("Unexpected UPDATE hints: %+v", update.Hints)
+	}
+
+	del := parseWithDialect(t, "DELETE FROM orders WHERE id = 1 OPTION (MAXDOP 1)", "sqlserver").(*parser.DeleteStatement)
+	if len(del.Hints) != 1 || del.Hints[0].Name != "MAXDOP" {
+		t.Errorf("Unexpected DELETE hints: %+v", del.Hints)
+	}
+
+	for _, sql := range []string{"SELECT id FROM orders OPTION ()", "SELECT id FROM orders WITH (NOLOCK"} {
+		if _, err := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect("sqlserver")).ParseStatement(); err == nil {
+			t.Errorf("Expected error for %q", sql)
+		}
+	}
+}
+
+// Test that MySQL select modifiers, also inside /*! ... */, keep the rest
+// of the statement
+func TestSelectModifiers(t *testing.T) {
+	tests := []struct {
+		sql       string
+		modifiers []string
+		distinct  bool
+	}{
+		{"SELECT /*!40001 SQL_NO_CACHE */ a FROM t WHERE x = 1", []string{"SQL_NO_CACHE"}, false},
+		{"SELECT /*! STRAIGHT_JOIN */ a FROM t WHERE x = 1", []string{"STRAIGHT_JOIN"}, false},
+		{"SELECT DISTINCT HIGH_PRIORITY SQL_CALC_FOUND_ROWS a FROM t WHERE x = 1", []string{"HIGH_PRIORITY", "SQL_CALC_FOUND_ROWS"}, true},
+		{"SELECT DISTINCTROW a FROM t WHERE x = 1", []string{"DISTINCTROW"}, true},
+		{"SELECT sql_cache, a FROM t WHERE x = 1", nil, false},
+	}
+	for _, tt := range tests {
+		stmt := parseWithDialect(t, tt.sql, "mysql").(*parser.SelectStatement)
+		if !reflect.DeepEqual(stmt.Modifiers, tt.modifiers) || stmt.Distinct != tt.distinct {
+			t.Errorf("%s: unexpected modifiers %v, distinct %v", tt.sql, stmt.Modifiers, stmt.Distinct)
+		}
+		if stmt.From == nil || stmt.Where == nil {
+			t.Errorf("%s: expected FROM and WHERE to be parsed", tt.sql)
+		}
+		if col, ok := stmt.Columns[len(stmt.Columns)-1].(*parser.ColumnReference); !ok || col.Column != "a" {
+			t.Errorf("%s: expected the column a last, got %v", tt.sql, stmt.Columns)
+		}
+	}
+
+	for _, sql := range []string{"SELECT a FROM t /* unterminated", "SELECT a /*!40001 FROM t"} {
+		if _, err := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect("mysql")).ParseStatement(); err == nil {
+			t.Errorf("Expected error for %q", sql)
+		}
+	}
+}
+
+// Test the analyzer rules that read hints
+func TestHintOptimizations(t *testing.T) {
+	tests := []struct {
+		dialect  string
+		sql      string
+		ruleType string
+		expected bool
+	}{
+		{"sqlserver", "SELECT id FROM orders WITH (NOLOCK)", "SQLSERVER_NOLOCK_WARNING", true},
+		{"sqlserver", "SELECT o.id FROM orders o JOIN users u WITH (READUNCOMMITTED) ON o.user_id = u.id", "SQLSERVER_NOLOCK_WARNING", true},
+		{"sqlserver", "SELECT id FROM orders WITH (READPAST)", "SQLSERVER_NOLOCK_WARNING", false},
+		{"oracle", "SELECT /*+ INDEX(orders idx_status) */ id FROM orders o", "ORACLE_HINT_UNKNOWN_TABLE", true},
+		{"oracle", "SELECT /*+ INDEX(o idx_status) */ id FROM orders o", "ORACLE_HINT_UNKNOWN_TABLE", false},
+		{"oracle", "SELECT /*+ LEADING(a) */ a.id FROM t1 a, t2 b, t3 c, t4 d", "ORACLE_HINT_SUGGESTION", false},
+	}
+
+	for _, tt := range tests {
+		engine := analyzer.NewOptimizationEngine(dialect.GetDialect(tt.dialect))
+		found := false
+		for _, s := range engine.AnalyzeOptimizations(parseWithDialect(t, tt.sql, tt.dialect)) {
+			if s.Type == tt.ruleType {
+				found = true
+			}
+		}
+		if found != tt.expected {
+			t.Errorf("%s: expected %s=%v, got %v", tt.sql, tt.ruleType, tt.expected, found)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test the structural features the complexity score counts
+func TestComplexityModel(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		score    int
+		shape    string
+		depth    int
+		expected map[string]int // points of some factors
+	}{
+		{
+			"simple filter",
+			"SELECT id, name FROM users WHERE age > 30",
+			4, analyzer.JoinShapeNone, 0,
+			map[string]int{"tables": 1, "select_columns": 2, "predicates": 1},
+		},
+		{
+			"nested and correlated subqueries",
+			"SELECT name FROM users u WHERE EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.total > (SELECT AVG(total) FROM orders))",
+			18, analyzer.JoinShapeNone, 2,
+			map[string]int{"subqueries": 6, "correlated_subqueries": 3, "predicates": 3},
+		},
+		{
+			"recursive CTE",
+			"WITH RECURSIVE t AS (SELECT id FROM users UNION ALL SELECT u.id FROM users u JOIN t ON t.id = u.age) SELECT id FROM t",
+			19, analyzer.JoinShapeChain, 0,
+			map[string]int{"ctes": 2, "cte_references": 2, "recursive_ctes": 5, "set_operations": 2},
+		},
+		{
+			"window function and CASE branches",
+			"SELECT id, ROW_NUMBER() OVER (ORDER BY id), CASE WHEN age > 1 THEN 'a' WHEN age > 2 THEN 'b' ELSE 'c' END FROM users",
+			11, analyzer.JoinShapeNone, 0,
+			map[string]int{"window_functions": 2, "case_branches": 3, "predicates": 2},
+		},
+		{
+			"star join",
+			"SELECT f.id FROM f JOIN a ON a.id = f.a JOIN b ON b.id = f.b JOIN c ON c.id = f.c",
+			16, analyzer.JoinShapeStar, 0,
+			map[string]int{"joins": 6, "star_join": 2},
+		},
+		{
+			"cyclic join",
+			"SELECT u.name FROM users u JOIN orders o ON o.user_id = u.id JOIN products p ON p.id = o.product_id AND p.id = u.id",
+			15, analyzer.JoinShapeCyclic, 0,
+			map[string]int{"cyclic_join": 4},
+		},
+		{
+			"Cartesian product",
+			"SELECT u.name FROM users u, orders o",
+			13, analyzer.JoinShapeDisconnected, 0,
+			map[string]int{"disconnected_join": 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := analyzer.MeasureComplexity(parseWithDialect(t, tt.sql, "postgresql"), analyzer.DefaultComplexityWeights())
+			if report.Score != tt.score || report.JoinShape != tt.shape || report.MaxSubqueryDepth != tt.depth {
+				t.Errorf("Expected score %d, shape %q and depth %d, got %+v", tt.score, tt.shape, tt.depth, report)
+			}
+			for name, points := range tt.expected {
+				if report.Points(name) != points {
+					t.Errorf("Expected %d points for %s, got %d", points, name, report.Points(name))
+				}
+			}
+		})
+	}
+}
+
+// Test that the configured weights and threshold set the score and risk
+func TestComplexityRiskLevel(t *testing.T) {
+	stmt := parseWithDialect(t, "SELECT u.name FROM users u JOIN orders o ON o.user_id = u.id WHERE o.status = 'paid'", "mysql")
+
+	tests := []struct {
+		threshold int
+		risk      string
+	}{
+		{20, "LOW"},
+		{10, "MEDIUM"},
+		{5, "HIGH"},
+	}
+	for _, tt := range tests {
+		a := analyzer.NewWithDialect(dialect.GetDialect("mysql"))
+		a.SetComplexityModel(analyzer.DefaultComplexityWeights(), tt.threshold)
+		analysis := a.Analyze(stmt)
+		if analysis.Complexity != 7 || analysis.Performance.RiskLevel != tt.risk {
+			t.Errorf("Threshold %d: expected complexity 7 and %s risk, got %d and %s",
+				tt.threshold, tt.risk, analysis.Complexity, analysis.Performance.RiskLevel)
+		}
+	}
+
+	// Without join weights the score drops; a Cartesian product stays HIGH
+	weights := analyzer.DefaultComplexityWeights()
+	weights.Join = 0
+	weights.DisconnectedJoin = 0
+	a := analyzer.NewWithDialect(dialect.GetDialect("mysql"))
+	a.SetComplexityModel(weights, 100)
+	if analysis := a.Analyze(stmt); analysis.Complexity != 5 {
+		t.Errorf("Expected complexity 5 without join weights, got %d", analysis.Complexity)
+	}
+	if analysis := a.Analyze(parseWithDialect(t, "SELECT u.name FROM users u, orders o", "mysql")); analysis.Performance.RiskLevel != "HIGH" {
+		t.Errorf("Expected a Cartesian product to be HIGH risk, got %s", analysis.Performance.RiskLevel)
+	}
+}
+
+// Test row and cost estimates from schema statistics
+func TestCostEstimate(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	d := dialect.GetDialect("mysql")
+
+	tests := []struct {
+		name string
+		sql  string
+		rows int64
+		seek bool // on the first table
+	}{
+		{"range scan", "SELECT id FROM users WHERE age > 30", 3334, false},
+		{"equality on an indexed column", "SELECT id FROM orders WHERE status = 'paid'", 50000, true},
+		{"unique column", "SELECT id FROM users WHERE email = 'a@b.c'", 1, true},
+		{"IN list", "SELECT id FROM products WHERE category IN ('a', 'b')", 50, true},
+		{"limit caps rows", "SELECT id FROM orders WHERE status = 'paid' LIMIT 10", 10, true},
+		{"foreign key join", "SELECT u.name FROM users u JOIN orders o ON o.user_id = u.id WHERE o.status = 'paid'", 50000, false},
+		{"Cartesian product", "SELECT u.name FROM users u, products p", 5000000, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate, ok := analyzer.EstimateCost(parseWithDialect(t, tt.sql, "mysql"), d, s)
+			if !ok {
+				t.Fatal("Expected an estimate")
+			}
+			if estimate.Rows != tt.rows || estimate.Tables[0].Seek != tt.seek {
+				t.Errorf("Expected %d rows and seek %v, got %+v", tt.rows, tt.seek, estimate)
+			}
+			if estimate.Cost <= 0 {
+				t.Errorf("Expected a positive cost, got %f", estimate.Cost)
+			}
+		})
+	}
+
+	// A seek costs less than a scan of the same table
+	seek, _ := analyzer.EstimateCost(parseWithDialect(t, "SELECT id FROM orders WHERE status = 'paid'", "mysql"), d, s)
+	scan, _ := analyzer.EstimateCost(parseWithDialect(t, "SELECT id FROM orders WHERE total = 5", "mysql"), d, s)
+	if seek.Cost >= scan.Cost {
+		t.Errorf("Expected the seek (%f) to cost less than the scan (%f)", seek.Cost, scan.Cost)
+	}
+
+	// Tables without statistics leave the estimate out
+	if _, ok := analyzer.EstimateCost(parseWithDialect(t, "SELECT id FROM invoices", "mysql"), d, s); ok {
+		t.Error("Expected no estimate for a table without statistics")
+	}
+
+	a := analyzer.NewWithDialect(d)
+	a.SetSchema(s)
+	analysis := a.Analyze(parseWithDialect(t, "SELECT id FROM orders WHERE created_at > '2024-01-01'", "mysql"))
+	if analysis.Performance.EstimatedRows != 83334 || len(analysis.Performance.IndexRecommendations) != 1 {
+		t.Errorf("Unexpected performance metrics %+v", analysis.Performance)
+	}
+}
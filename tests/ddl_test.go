@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
 	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
 	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
 )
@@ -387,6 +388,211 @@ func TestAlterTable(t *testing.T) {
 	}
 }
 
+// Test ALTER TABLE actions: RENAME, ALTER COLUMN, constraints and action lists
+func TestAlterTableActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		dialect string
+		check   func(t *testing.T, actions []*parser.AlterAction)
+	}{
+		{
+			name:    "RENAME TO",
+			sql:     `ALTER TABLE users RENAME TO customers`,
+			dialect: "postgresql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				if actions[0].ActionType != "RENAME" || actions[0].NewName != "customers" {
+					t.Errorf("Unexpected action: %+v", actions[0])
+				}
+			},
+		},
+		{
+			name:    "RENAME COLUMN",
+			sql:     `ALTER TABLE users RENAME COLUMN name TO full_name`,
+			dialect: "mysql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				if actions[0].ActionType != "RENAME_COLUMN" || actions[0].ColumnName != "name" || actions[0].NewName != "full_name" {
+					t.Errorf("Unexpected action: %+v", actions[0])
+				}
+			},
+		},
+		{
+			name:    "ALTER COLUMN SET DEFAULT and SET NOT NULL",
+			sql:     `ALTER TABLE users ALTER COLUMN status SET DEFAULT 'active', ALTER COLUMN email SET NOT NULL`,
+			dialect: "postgresql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				if len(actions) != 2 {
+					t.Fatalf("Expected 2 actions, got %d", len(actions))
+				}
+				if actions[0].ColumnChange != "SET_DEFAULT" || actions[0].Default == nil {
+					t.Errorf("Unexpected first action: %+v", actions[0])
+				}
+				if actions[1].ColumnName != "email" || actions[1].ColumnChange != "SET_NOT_NULL" {
+					t.Errorf("Unexpected second action: %+v", actions[1])
+				}
+			},
+		},
+		{
+			name:    "ALTER COLUMN DROP DEFAULT",
+			sql:     `ALTER TABLE users ALTER COLUMN status DROP DEFAULT`,
+			dialect: "postgresql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				if actions[0].ColumnChange != "DROP_DEFAULT" {
+					t.Errorf("Expected DROP_DEFAULT, got %s", actions[0].ColumnChange)
+				}
+			},
+		},
+		{
+			name:    "ALTER COLUMN TYPE USING",
+			sql:     `ALTER TABLE users ALTER COLUMN age TYPE BIGINT USING age`,
+			dialect: "postgresql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				a := actions[0]
+				if a.ColumnChange != "TYPE" || a.Column == nil || a.Column.DataType != "BIGINT" || a.Using == nil {
+					t.Errorf("Unexpected action: %+v", a)
+				}
+			},
+		},
+		{
+			name:    "ADD CONSTRAINT NOT VALID",
+			sql:     `ALTER TABLE orders ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id) NOT VALID`,
+			dialect: "postgresql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				a := actions[0]
+				if a.Constraint == nil || a.Constraint.ConstraintType != "FOREIGN_KEY" || !a.NotValid {
+					t.Errorf("Unexpected action: %+v", a)
+				}
+			},
+		},
+		{
+			name:    "ADD CHECK constraint",
+			sql:     `ALTER TABLE orders ADD CONSTRAINT chk_total CHECK (total > 0)`,
+			dialect: "postgresql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				c := actions[0].Constraint
+				if c == nil || c.ConstraintType != "CHECK" || c.Name != "chk_total" || c.Check == nil {
+					t.Errorf("Unexpected constraint: %+v", c)
+				}
+			},
+		},
+		{
+			name:    "VALIDATE CONSTRAINT",
+			sql:     `ALTER TABLE orders VALIDATE CONSTRAINT fk_user`,
+			dialect: "postgresql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				if actions[0].ActionType != "VALIDATE_CONSTRAINT" || actions[0].ConstraintName != "fk_user" {
+					t.Errorf("Unexpected action: %+v", actions[0])
+				}
+			},
+		},
+		{
+			name:    "SQL Server ALTER COLUMN",
+			sql:     `ALTER TABLE orders ALTER COLUMN total DECIMAL(12,2) NOT NULL`,
+			dialect: "sqlserver",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				a := actions[0]
+				if a.ColumnChange != "TYPE" || a.Column == nil || a.Column.Precision != 12 || !a.Column.NotNull {
+					t.Errorf("Unexpected action: %+v", a)
+				}
+			},
+		},
+		{
+			name:    "SQL Server WITH NOCHECK",
+			sql:     `ALTER TABLE orders WITH NOCHECK ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)`,
+			dialect: "sqlserver",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				if !actions[0].NoCheck || actions[0].Constraint == nil {
+					t.Errorf("Unexpected action: %+v", actions[0])
+				}
+			},
+		},
+		{
+			name:    "Oracle MODIFY list",
+			sql:     `ALTER TABLE orders MODIFY (total NUMBER(12,2), status VARCHAR2(20) NOT NULL)`,
+			dialect: "oracle",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				a := actions[0]
+				if a.ActionType != "MODIFY" || len(a.Columns) != 2 || a.Columns[1].Name != "status" {
+					t.Errorf("Unexpected action: %+v", a)
+				}
+			},
+		},
+		{
+			name:    "Multiple actions",
+			sql:     `ALTER TABLE orders ADD COLUMN note TEXT, DROP COLUMN IF EXISTS legacy, DROP CONSTRAINT fk_old`,
+			dialect: "postgresql",
+			check: func(t *testing.T, actions []*parser.AlterAction) {
+				if len(actions) != 3 {
+					t.Fatalf("Expected 3 actions, got %d", len(actions))
+				}
+				if actions[1].ActionType != "DROP" || !actions[1].IfExists || actions[1].ColumnName != "legacy" {
+					t.Errorf("Unexpected DROP COLUMN action: %+v", actions[1])
+				}
+				if actions[2].ActionType != "DROP_CONSTRAINT" || actions[2].ConstraintName != "fk_old" {
+					t.Errorf("Unexpected DROP CONSTRAINT action: %+v", actions[2])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewWithDialect(context.Background(), tt.sql, dialect.GetDialect(tt.dialect))
+			stmt, err := p.ParseStatement()
+			if err != nil {
+				t.Fatalf("Failed to parse: %v", err)
+			}
+
+			alterStmt, ok := stmt.(*parser.AlterTableStatement)
+			if !ok {
+				t.Fatalf("Expected AlterTableStatement, got %T", stmt)
+			}
+			if alterStmt.Action != alterStmt.Actions[0] {
+				t.Error("Expected Action to be the first of Actions")
+			}
+			tt.check(t, alterStmt.Actions)
+		})
+	}
+}
+
+// Test that the analyzer reports columns touched by every ALTER action
+func TestAnalyzeAlterTable(t *testing.T) {
+	sql := `ALTER TABLE orders RENAME COLUMN note TO comment, ALTER COLUMN total SET NOT NULL, ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)`
+	p := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect("postgresql"))
+	stmt, err := p.ParseStatement()
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+
+	analysis := analyzer.New().Analyze(stmt)
+
+	columns := make(map[string]string)
+	for _, col := range analysis.Columns {
+		columns[col.Name] = col.Usage
+	}
+	expected := map[string]string{
+		"note":    "ALTER RENAME_COLUMN",
+		"comment": "ALTER RENAME_COLUMN",
+		"total":   "ALTER ALTER_COLUMN",
+		"user_id": "ALTER ADD",
+	}
+	for name, usage := range expected {
+		if columns[name] != usage {
+			t.Errorf("Expected column %s with usage %q, got %q", name, usage, columns[name])
+		}
+	}
+
+	foundRef := false
+	for _, table := range analysis.Tables {
+		if table.Name == "users" && table.Usage == "REFERENCES" {
+			foundRef = true
+		}
+	}
+	if !foundRef {
+		t.Error("Expected referenced table users in analysis")
+	}
+}
+
 // Test CREATE INDEX statements
 func TestCreateIndex(t *testing.T) {
 	tests := []struct {
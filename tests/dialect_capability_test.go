@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+// parseVersioned parses sql against a dialect pinned to version and
+// returns its warnings and parse error
+func parseVersioned(t *testing.T, sql, dialectName, version string) ([]string, error) {
+	t.Helper()
+
+	d, err := dialect.GetDialectVersion(dialectName, version)
+	if err != nil {
+		t.Fatalf("Failed to get %s %s: %v", dialectName, version, err)
+	}
+	p := parser.NewWithDialect(context.Background(), sql, d)
+	_, err = p.ParseStatement()
+	return p.Warnings(), err
+}
+
+// Test version parsing and comparison
+func TestDialectVersions(t *testing.T) {
+	v, err := dialect.ParseVersion("8.0.14")
+	if err != nil || v != (dialect.Version{Major: 8, Minor: 0, Patch: 14}) {
+		t.Fatalf("Unexpected version %v (%v)", v, err)
+	}
+	if !v.AtLeast(dialect.Version{Major: 8}) || v.AtLeast(dialect.Version{Major: 8, Minor: 1}) {
+		t.Errorf("Unexpected AtLeast results for %s", v)
+	}
+	if _, err := dialect.ParseVersion("eight"); err == nil {
+		t.Error("Expected an error for an invalid version")
+	}
+
+	d, err := dialect.GetDialectVersion("sqlserver", "13")
+	if err != nil || d.GetVersion() != (dialect.Version{Major: 2016}) {
+		t.Errorf("Expected SQL Server 13 to be 2016, got %v (%v)", d.GetVersion(), err)
+	}
+	if d := dialect.GetDialect("mysql"); d.GetVersion() != d.GetSpec().Latest {
+		t.Errorf("Expected the latest MySQL release, got %s", d.GetVersion())
+	}
+
+	mysql57, _ := dialect.GetDialectVersion("mysql", "5.7")
+	if mysql57.SupportsFeature(dialect.FeatureWindowFunctions) || !dialect.GetDialect("mysql").SupportsFeature(dialect.FeatureWindowFunctions) {
+		t.Error("Expected window functions from MySQL 8 on")
+	}
+}
+
+// Test syntax that depends on the dialect and its version
+func TestDialectGrammarCapabilities(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		version string
+		sql     string
+		err     string // "" when the query should parse
+	}{
+		{"Window on MySQL 8", "mysql", "", "SELECT RANK() OVER (ORDER BY total) FROM orders", ""},
+		{"Window on MySQL 5.7", "mysql", "5.7", "SELECT RANK() OVER (ORDER BY total) FROM orders", "requires MySQL 8"},
+		{"CTE on MySQL 5.7", "mysql", "5.7", "WITH t AS (SELECT 1 AS x) SELECT x FROM t", "requires MySQL 8"},
+		{"ILIKE on PostgreSQL", "postgresql", "", "SELECT id FROM users WHERE name ILIKE 'a%'", ""},
+		{"ILIKE on MySQL", "mysql", "", "SELECT id FROM users WHERE name ILIKE 'a%'", "ILIKE"},
+		{"JSON arrow on MySQL 5.7.13", "mysql", "5.7.13", "SELECT doc->>'$.name' FROM docs", ""},
+		{"JSON arrow on MySQL 5.7", "mysql", "5.7", "SELECT doc->>'$.name' FROM docs", "requires MySQL 5.7.13"},
+		{"Concatenation on PostgreSQL", "postgresql", "", "SELECT first_name || last_name FROM users", ""},
+		{"Concatenation on SQL Server", "sqlserver", "", "SELECT first_name || last_name FROM users", "||"},
+		{"Null-safe equality on MySQL", "mysql", "", "SELECT id FROM users WHERE a <=> b", ""},
+		{"FILTER on PostgreSQL", "postgresql", "", "SELECT COUNT(*) FILTER (WHERE active) FROM users", ""},
+		{"FILTER on MySQL", "mysql", "", "SELECT COUNT(*) FILTER (WHERE active) FROM users", "FILTER"},
+		{"ROLLUP on SQLite", "sqlite", "", "SELECT a, COUNT(*) FROM t GROUP BY ROLLUP (a)", "ROLLUP"},
+		{"OFFSET FETCH on SQL Server 2012", "sqlserver", "2012", "SELECT id FROM t ORDER BY id OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY", ""},
+		{"OFFSET FETCH on SQL Server 2008", "sqlserver", "2008", "SELECT id FROM t ORDER BY id OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY", "requires SQL Server 2012"},
+		{"FETCH FIRST on Oracle 11g", "oracle", "11", "SELECT id FROM t ORDER BY id FETCH FIRST 10 ROWS ONLY", "requires Oracle 12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseVersioned(t, tt.sql, tt.dialect, tt.version)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("Expected no error, got %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+// Test function catalog lookups and the warnings they drive
+func TestDialectFunctionCatalog(t *testing.T) {
+	fs, ok := dialect.GetDialect("sqlserver").LookupFunction("string_agg")
+	if !ok || fs.Kind != dialect.FunctionAggregate || fs.Since != (dialect.Version{Major: 2017}) {
+		t.Fatalf("Unexpected STRING_AGG signature %+v", fs)
+	}
+	if !fs.AcceptsArgs(2) || fs.AcceptsArgs(3) {
+		t.Errorf("Expected STRING_AGG to take exactly 2 arguments")
+	}
+
+	tests := []struct {
+		dialect string
+		version string
+		sql     string
+		warning string // "" when no warning is expected
+	}{
+		{"sqlserver", "2016", "SELECT STRING_AGG(name, ',') FROM users", "requires SQL Server 2017"},
+		{"sqlserver", "", "SELECT STRING_AGG(name, ',') FROM users", ""},
+		{"oracle", "", "SELECT NVL(a, b, c) FROM t", "NVL"},
+		{"postgresql", "", "SELECT ROW_NUMBER() FROM t", "OVER"},
+		{"postgresql", "", "SELECT my_func(1, 2, 3) FROM t", ""},
+	}
+
+	for _, tt := range tests {
+		warnings, err := parseVersioned(t, tt.sql, tt.dialect, tt.version)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.sql, err)
+			continue
+		}
+		joined := strings.Join(warnings, "; ")
+		if tt.warning == "" && len(warnings) > 0 || tt.warning != "" && !strings.Contains(joined, tt.warning) {
+			t.Errorf("%s: expected warning %q, got %v", tt.sql, tt.warning, warnings)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/monitor"
+)
+
+// Test dialect detection from SQL text
+func TestDetectDialect(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected string
+		evidence string
+	}{
+		{"Brackets and TOP", "SELECT TOP 10 [name] FROM [users] WITH (NOLOCK)", "sqlserver", "TOP"},
+		{"GO batches", "CREATE TABLE t (id INT)\nGO\nSELECT * FROM t\nGO 2", "sqlserver", "GO batch separator"},
+		{"Variables and NVARCHAR", "DECLARE @name NVARCHAR(50) = N'x'", "sqlserver", "@variables"},
+		{"Backticks and LIMIT", "SELECT `id` FROM `users` LIMIT 5", "mysql", "`backtick` identifiers"},
+		{"DELIMITER", "DELIMITER $$\nCREATE PROCEDURE p() BEGIN SELECT 1; END $$\nDELIMITER ;", "mysql", "DELIMITER command"},
+		{"AUTO_INCREMENT", "CREATE TABLE t (id INT AUTO_INCREMENT PRIMARY KEY)", "mysql", "AUTO_INCREMENT"},
+		{"Casts and parameters", "SELECT id::text FROM users WHERE name ILIKE $1", "postgresql", ":: casts"},
+		{"Dollar bodies", "CREATE FUNCTION f() RETURNS int AS $body$ SELECT TOP 1 [x] $body$ LANGUAGE sql", "postgresql", "$$ bodies"},
+		{"SERIAL", "CREATE TABLE t (id SERIAL PRIMARY KEY, doc JSONB)", "postgresql", "SERIAL"},
+		{"ROWNUM and NVL", "SELECT NVL(a, 0) FROM dual WHERE ROWNUM <= 10", "oracle", "ROWNUM"},
+		{"VARCHAR2", "CREATE TABLE t (name VARCHAR2(50), n NUMBER(10))", "oracle", "VARCHAR2"},
+		{"Q-quote", "SELECT q'[it's LIMIT]' FROM t", "oracle", "q'[...]' strings"},
+		{"PRAGMA", "PRAGMA foreign_keys = ON", "sqlite", "PRAGMA"},
+		{"AUTOINCREMENT", "CREATE TABLE t (id INTEGER PRIMARY KEY AUTOINCREMENT)", "sqlite", "AUTOINCREMENT"},
+		{"Hash comments", "# nightly report\nSELECT name FROM users # TOP [x]\n#NVARCHAR\nLIMIT 5", "mysql", "# comments"},
+		{"Temp tables", "SELECT * INTO #t FROM\n#staging JOIN ##shared s ON s.id = 1", "sqlserver", "#temp tables"},
+		{"Backslash escapes", "SELECT 'it\\'s [x] TOP' FROM `users`", "mysql", "`backtick` identifiers"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guesses := dialect.Detect(tt.sql)
+			if len(guesses) == 0 || guesses[0].Dialect != tt.expected {
+				t.Fatalf("Expected %s first, got %+v", tt.expected, guesses)
+			}
+			found := false
+			for _, e := range guesses[0].Evidence {
+				found = found || e == tt.evidence
+			}
+			if !found {
+				t.Errorf("Expected evidence %q, got %v", tt.evidence, guesses[0].Evidence)
+			}
+			if len(guesses) > 1 && guesses[1].Confidence > guesses[0].Confidence {
+				t.Errorf("Expected guesses ranked by confidence, got %+v", guesses)
+			}
+		})
+	}
+}
+
+// Test that strings and comments carry no evidence
+func TestDetectDialectIgnoresStringsAndComments(t *testing.T) {
+	sql := "SELECT 'TOP [x] LIMIT', name -- ROWNUM `y`\n/* ::text NVL */ FROM users"
+	if guesses := dialect.Detect(sql); guesses != nil {
+		t.Errorf("Expected no evidence, got %+v", guesses)
+	}
+
+	d, guess := dialect.DetectDialect(sql)
+	if d.Name() != "SQL Server" || guess.Confidence != 0 {
+		t.Errorf("Expected the SQL Server fallback, got %s (%+v)", d.Name(), guess)
+	}
+
+	d, guess = dialect.DetectDialect("SELECT * FROM t LIMIT 1 RETURNING id")
+	if d.Name() != "PostgreSQL" || guess.Confidence <= 0 {
+		t.Errorf("Expected PostgreSQL, got %s (%+v)", d.Name(), guess)
+	}
+}
+
+// Test per-query detection in the log processor
+func TestLogProcessorDetectsDialect(t *testing.T) {
+	processor := monitor.NewLogProcessor(dialect.Auto)
+
+	var detected []string
+	processor.SetQueryHandler(func(pq *monitor.ProcessedQuery) {
+		detected = append(detected, pq.Dialect)
+	})
+
+	lines := make(chan string, 3)
+	lines <- "SELECT TOP 5 [id] FROM [users]"
+	lines <- "SELECT `id` FROM `users` LIMIT 5"
+	lines <- "SELECT id FROM users WHERE ROWNUM <= 5"
+	close(lines)
+	processor.Start(context.Background(), lines)
+
+	expected := []string{"SQL Server", "MySQL", "Oracle"}
+	if len(detected) != len(expected) {
+		t.Fatalf("Expected %d queries, got %v", len(expected), detected)
+	}
+	for i := range expected {
+		if detected[i] != expected[i] {
+			t.Errorf("Query %d: expected %s, got %s", i, expected[i], detected[i])
+		}
+	}
+}
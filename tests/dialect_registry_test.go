@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/internal/config"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+)
+
+// duckDBDialect stands in for a dialect registered outside this module
+type duckDBDialect struct{ dialect.PostgreSQLDialect }
+
+func (d *duckDBDialect) Name() string { return "DuckDB" }
+
+var registerDuckDB sync.Once
+
+// Test name and alias lookup of the built-in dialects
+func TestDialectLookup(t *testing.T) {
+	for name, expected := range map[string]string{
+		"mysql":      "MySQL",
+		"PostgreSQL": "PostgreSQL",
+		"postgres":   "PostgreSQL",
+		"mssql":      "SQL Server",
+		"sqlite3":    "SQLite",
+		" oracle ":   "Oracle",
+	} {
+		d, err := dialect.Lookup(name)
+		if err != nil || d.Name() != expected {
+			t.Errorf("Lookup(%q): expected %s, got %v (%v)", name, expected, d, err)
+		}
+	}
+
+	_, err := dialect.Lookup("postgress")
+	if err == nil || !strings.Contains(err.Error(), `unknown SQL dialect "postgress"`) || !strings.Contains(err.Error(), "postgresql") {
+		t.Errorf("Expected an unknown dialect error listing the available ones, got %v", err)
+	}
+	if _, err := dialect.GetDialectVersion("postgress", "16"); err == nil {
+		t.Error("Expected GetDialectVersion to reject an unknown dialect")
+	}
+	if d := dialect.GetDialect("postgress"); d.Name() != "SQL Server" {
+		t.Errorf("Expected GetDialect to fall back to SQL Server, got %s", d.Name())
+	}
+
+	a, _ := dialect.Lookup("mysql")
+	b, _ := dialect.Lookup("mysql")
+	if a == b {
+		t.Error("Expected Lookup to return a new instance each time")
+	}
+}
+
+// Test registering a custom dialect
+func TestDialectRegister(t *testing.T) {
+	registerDuckDB.Do(func() {
+		dialect.Register("duckdb", []string{"duck"}, func() dialect.Dialect { return &duckDBDialect{} })
+	})
+
+	d, err := dialect.Lookup("DUCK")
+	if err != nil || d.Name() != "DuckDB" {
+		t.Fatalf("Expected DuckDB, got %v (%v)", d, err)
+	}
+	if !d.SupportsFeature(dialect.FeatureCTE) {
+		t.Error("Expected the embedded PostgreSQL features")
+	}
+	found := false
+	for _, name := range dialect.Names() {
+		found = found || name == "duckdb"
+	}
+	if !found {
+		t.Errorf("Expected duckdb in %v", dialect.Names())
+	}
+	if _, err := dialect.GetDialectVersion("duckdb", "1.0"); err == nil {
+		t.Error("Expected an error pinning a dialect without versions")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected registering an existing alias to panic")
+		}
+	}()
+	dialect.Register("pg2", []string{"postgres"}, func() dialect.Dialect { return &duckDBDialect{} })
+}
+
+// Test that configuration validation rejects unknown dialects
+func TestConfigDialectValidation(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Parser.Dialect = "postgres"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected the postgres alias to be valid, got %v", err)
+	}
+
+	cfg.Parser.Dialect = "postgress"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "unknown SQL dialect") {
+		t.Errorf("Expected an unknown dialect error, got %v", err)
+	}
+
+	cfg.Parser.Dialect = "mysql"
+	cfg.Parser.DialectVersion = "5.x"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an invalid version error")
+	}
+}
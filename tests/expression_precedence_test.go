@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+// Test that operators bind by precedence rather than left to right
+func TestExpressionPrecedence(t *testing.T) {
+	tests := []struct {
+		where    string
+		expected string
+	}{
+		{"a = 1 OR b = 2 AND c = 3", "((a = 1) OR ((b = 2) AND (c = 3)))"},
+		{"a + b * 2 > 10", "((a + (b * 2)) > 10)"},
+		{"NOT a = 1 AND b <> 2", "(NOT (a = 1) AND (b <> 2))"},
+		{"a IS NOT NULL AND b IS NULL", "((a IS NOT NULL) AND (b IS NULL))"},
+		{"a BETWEEN 1 AND 5 AND b = 2", "(a BETWEEN 1 AND 5 AND (b = 2))"},
+		{"a NOT LIKE 'x%' OR -a < 0", "((a NOT LIKE x%) OR (-a < 0))"},
+		{"(a = 1 OR b = 2) AND c = 3", "(((a = 1) OR (b = 2)) AND (c = 3))"},
+	}
+
+	for _, tt := range tests {
+		stmt := parseWithDialect(t, "SELECT x FROM t WHERE "+tt.where, "postgresql")
+		where := stmt.(*parser.SelectStatement).Where
+		if got := where.String(); got != tt.expected {
+			t.Errorf("WHERE %s: expected %s, got %s", tt.where, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/fix"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test each automatic fix and that the fixed SQL parses
+func TestFixRewrites(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		dialect  string
+		sql      string
+		expected string
+	}{
+		{
+			"expand star",
+			"postgresql",
+			"SELECT * FROM products WHERE stock > 0",
+			"SELECT id, name, price, category, stock FROM products WHERE stock > 0",
+		},
+		{
+			"expand qualified star",
+			"postgresql",
+			"SELECT p.*, o.quantity FROM orders o JOIN products p ON p.id = o.product_id",
+			"SELECT p.id, p.name, p.price, p.category, p.stock, o.quantity FROM orders o JOIN products p ON p.id = o.product_id",
+		},
+		{
+			"in to exists",
+			"postgresql",
+			"SELECT name FROM users u WHERE u.id IN (SELECT o.user_id FROM orders o WHERE o.total > 100 OR o.quantity > 5) AND u.age > 18",
+			"SELECT name FROM users u WHERE EXISTS (SELECT 1 FROM orders o WHERE (o.total > 100 OR o.quantity > 5) AND o.user_id = u.id) AND u.age > 18",
+		},
+		{
+			"in to exists qualifies the outer column",
+			"mysql",
+			"SELECT name FROM users WHERE id IN (SELECT user_id FROM orders)",
+			"SELECT name FROM users WHERE EXISTS (SELECT 1 FROM orders WHERE user_id = users.id)",
+		},
+		{
+			"remove nolock",
+			"sqlserver",
+			"SELECT u.name FROM users u WITH (NOLOCK) JOIN orders o WITH (NOLOCK, INDEX(ix_user)) ON o.user_id = u.id",
+			"SELECT u.name FROM users u JOIN orders o WITH (INDEX(ix_user)) ON o.user_id = u.id",
+		},
+		{
+			"comma join",
+			"mysql",
+			"SELECT u.name, p.name FROM users u, orders o, products p WHERE u.id = o.user_id AND o.status = 'paid' AND p.id = o.product_id",
+			"SELECT u.name, p.name FROM users u JOIN orders o ON u.id = o.user_id JOIN products p ON p.id = o.product_id WHERE o.status = 'paid'",
+		},
+		{
+			"year to date range",
+			"mysql",
+			"SELECT id FROM orders WHERE YEAR(created_at) = 2024 AND status = 'paid'",
+			"SELECT id FROM orders WHERE created_at >= '2024-01-01' AND created_at < '2025-01-01' AND status = 'paid'",
+		},
+		{
+			"year range under OR",
+			"sqlserver",
+			"SELECT id FROM orders o WHERE status = 'open' OR DATEPART(year, o.created_at) BETWEEN 2020 AND 2021",
+			"SELECT id FROM orders o WHERE status = 'open' OR (o.created_at >= '20200101' AND o.created_at < '20220101')",
+		},
+		{
+			"comma join without conditions",
+			"mysql",
+			"SELECT u.name FROM users u, products p",
+			"SELECT u.name FROM users u CROSS JOIN products p",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := fix.NewFixer(dialect.GetDialect(tt.dialect), s).Fix(context.Background(), tt.sql)
+			if err != nil {
+				t.Fatalf("Failed to fix: %v", err)
+			}
+			if result.SQL != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, result.SQL)
+			}
+			if !result.Changed() {
+				t.Error("Expected fixes to be applied")
+			}
+			parseWithDialect(t, result.SQL, tt.dialect)
+		})
+	}
+}
+
+// Test that fixes which can change results wait for AllowUnsafe
+func TestUnsafeFixes(t *testing.T) {
+	sql := "UPDATE users SET name = 'x' WHERE email = NULL;\nDELETE FROM orders"
+
+	fixer := fix.NewFixer(dialect.GetDialect("mysql"), nil)
+	result, err := fixer.Fix(context.Background(), sql)
+	if err != nil {
+		t.Fatalf("Failed to fix: %v", err)
+	}
+	if result.Changed() || len(result.Skipped) != 2 {
+		t.Fatalf("Expected 2 skipped unsafe fixes, got %+v", result)
+	}
+
+	fixer.AllowUnsafe(true)
+	result, err = fixer.Fix(context.Background(), sql)
+	if err != nil {
+		t.Fatalf("Failed to fix: %v", err)
+	}
+	expected := "UPDATE users SET name = 'x' WHERE email IS NULL;\nDELETE FROM orders LIMIT 1000"
+	if result.SQL != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, result.SQL)
+	}
+
+	// SQL Server bounds a delete with TOP; PostgreSQL can't
+	result, _ = fixer.Fix(context.Background(), "DELETE FROM orders")
+	if result.SQL != "DELETE FROM orders LIMIT 1000" {
+		t.Errorf("Unexpected MySQL fix %q", result.SQL)
+	}
+	sqlServer := fix.NewFixer(dialect.GetDialect("sqlserver"), nil)
+	sqlServer.AllowUnsafe(true)
+	if result, _ := sqlServer.Fix(context.Background(), "DELETE FROM orders"); result.SQL != "DELETE TOP (1000) FROM orders" {
+		t.Errorf("Unexpected SQL Server fix %q", result.SQL)
+	}
+	postgres := fix.NewFixer(dialect.GetDialect("postgresql"), nil)
+	postgres.AllowUnsafe(true)
+	if result, _ := postgres.Fix(context.Background(), "DELETE FROM orders"); result.Changed() {
+		t.Errorf("Expected no PostgreSQL fix, got %q", result.SQL)
+	}
+}
+
+// Test that fixes are left out when they don't apply or are disabled
+func TestFixSkips(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected string // the SQL itself when nothing is fixed
+	}{
+		{"star without schema", "SELECT * FROM users", ""},
+		{"negated IN", "SELECT name FROM users u WHERE u.id NOT IN (SELECT o.user_id FROM orders o)", ""},
+		{"IN with aggregate subquery", "SELECT name FROM users u WHERE u.id IN (SELECT o.user_id FROM orders o GROUP BY o.user_id)", ""},
+		{"outer name shadowed", "SELECT name FROM users u WHERE u.id IN (SELECT u.id FROM users u)", ""},
+		// The tables are still joined, with the condition left in WHERE
+		{"unqualified join condition", "SELECT name FROM users u, orders o WHERE user_id = id", "SELECT name FROM users u CROSS JOIN orders o WHERE user_id = id"},
+	}
+
+	for _, tt := range tests {
+		result, err := fix.NewFixer(dialect.GetDialect("postgresql"), nil).Fix(context.Background(), tt.sql)
+		if err != nil {
+			t.Fatalf("%s: failed to fix: %v", tt.name, err)
+		}
+		expected := tt.expected
+		if expected == "" {
+			expected = tt.sql
+		}
+		if result.SQL != expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, expected, result.SQL)
+		}
+	}
+
+	fixer := fix.NewFixer(dialect.GetDialect("mysql"), nil)
+	fixer.DisableRule("cartesian_product")
+	if result, _ := fixer.Fix(context.Background(), "SELECT u.name FROM users u, orders o WHERE u.id = o.user_id"); result.Changed() {
+		t.Errorf("Expected the disabled rule not to be fixed, got %q", result.SQL)
+	}
+
+	if _, err := fixer.Fix(context.Background(), "SELECT FROM WHERE"); err == nil {
+		t.Error("Expected an error for SQL that doesn't parse")
+	}
+}
+
+// Test the unified diff of a fixed file
+func TestFixDiff(t *testing.T) {
+	before := "-- report\nSELECT u.name\nFROM users u, orders o\nWHERE u.id = o.user_id;\n"
+	result, err := fix.NewFixer(dialect.GetDialect("mysql"), nil).Fix(context.Background(), before)
+	if err != nil {
+		t.Fatalf("Failed to fix: %v", err)
+	}
+
+	expected := `--- a/report.sql
++++ b/report.sql
+@@ -1,4 +1,3 @@
+ -- report
+ SELECT u.name
+-FROM users u, orders o
+-WHERE u.id = o.user_id;
++FROM users u JOIN orders o ON u.id = o.user_id;
+`
+	if diff := fix.Diff("report.sql", before, result.SQL); diff != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, diff)
+	}
+	if fix.Diff("report.sql", before, before) != "" {
+		t.Error("Expected no diff for unchanged text")
+	}
+}
@@ -0,0 +1,214 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+func firstColumn(t *testing.T, sql, dialectName string) parser.Expression {
+	t.Helper()
+
+	col := parseWithDialect(t, sql, dialectName).(*parser.SelectStatement).Columns[0]
+	if aliased, ok := col.(*parser.AliasedExpression); ok {
+		return aliased.Expression
+	}
+	return col
+}
+
+// Test aggregate modifiers: DISTINCT, ORDER BY in arguments, WITHIN GROUP and FILTER
+func TestAggregateFunctionGrammar(t *testing.T) {
+	fn := firstColumn(t, "SELECT COUNT(DISTINCT user_id) FROM orders", "postgresql").(*parser.FunctionCall)
+	if !fn.Distinct || len(fn.Arguments) != 1 {
+		t.Errorf("Expected COUNT(DISTINCT user_id), got %+v", fn)
+	}
+
+	fn = firstColumn(t, "SELECT COUNT(*) FILTER (WHERE status = 'paid') AS paid FROM orders", "postgresql").(*parser.FunctionCall)
+	if fn.Filter == nil {
+		t.Error("Expected FILTER clause")
+	}
+
+	fn = firstColumn(t, "SELECT STRING_AGG(name, ',' ORDER BY name DESC) FROM users", "postgresql").(*parser.FunctionCall)
+	if len(fn.Arguments) != 2 || len(fn.OrderBy) != 1 || fn.OrderBy[0].Direction != "DESC" {
+		t.Errorf("Expected STRING_AGG with ORDER BY, got %+v", fn)
+	}
+
+	fn = firstColumn(t, "SELECT PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY total) FROM orders", "postgresql").(*parser.FunctionCall)
+	if len(fn.WithinGroup) != 1 {
+		t.Errorf("Expected WITHIN GROUP ordering, got %+v", fn)
+	}
+
+	wf := firstColumn(t, "SELECT SUM(total) FILTER (WHERE total > 0) OVER (PARTITION BY user_id) FROM orders", "postgresql").(*parser.WindowFunction)
+	if wf.Function.Filter == nil || wf.OverClause == nil {
+		t.Errorf("Expected filtered window aggregate, got %+v", wf)
+	}
+}
+
+// Test CAST, TRY_CAST, :: and CONVERT
+func TestCastGrammar(t *testing.T) {
+	tests := []struct {
+		dialect  string
+		sql      string
+		expected string // CastExpression.String()
+		dataType string
+	}{
+		{"postgresql", "SELECT CAST(total AS DECIMAL(10,2)) FROM orders", "CAST(total AS DECIMAL(10,2))", "DECIMAL"},
+		{"postgresql", "SELECT total::int FROM orders", "total::int", "int"},
+		{"postgresql", "SELECT created_at::timestamp with time zone FROM orders", "created_at::timestamp WITH TIME ZONE", "timestamp WITH TIME ZONE"},
+		{"postgresql", "SELECT CAST(total AS DOUBLE PRECISION) FROM orders", "CAST(total AS DOUBLE PRECISION)", "DOUBLE PRECISION"},
+		{"sqlserver", "SELECT TRY_CAST(total AS INT) FROM orders", "TRY_CAST(total AS INT)", "INT"},
+		{"sqlserver", "SELECT CONVERT(VARCHAR(10), created_at, 120) FROM orders", "CONVERT(VARCHAR(10), created_at, 120)", "VARCHAR"},
+		{"mysql", "SELECT CONVERT(total, CHAR) FROM orders", "CONVERT(total, CHAR)", "CHAR"},
+		{"mysql", "SELECT CAST(total AS UNSIGNED INTEGER) FROM orders", "CAST(total AS UNSIGNED INTEGER)", "UNSIGNED INTEGER"},
+	}
+
+	for _, tt := range tests {
+		cast, ok := firstColumn(t, tt.sql, tt.dialect).(*parser.CastExpression)
+		if !ok {
+			t.Errorf("%s: expected CastExpression", tt.sql)
+			continue
+		}
+		if cast.String() != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, cast.String())
+		}
+		if cast.DataType.Name != tt.dataType {
+			t.Errorf("%s: expected type %q, got %q", tt.sql, tt.dataType, cast.DataType.Name)
+		}
+	}
+
+	// PostgreSQL array types keep the rest of the query
+	stmt := parseWithDialect(t, "SELECT x::int[] AS arr, CAST(tags AS text ARRAY), y::varchar(10)[3][] FROM t WHERE y = 1", "postgresql").(*parser.SelectStatement)
+	if stmt.From == nil || stmt.Where == nil || len(stmt.Columns) != 3 {
+		t.Fatalf("Expected three columns, FROM and WHERE, got %+v", stmt)
+	}
+	for i, expected := range []string{"int[]", "text[]", "varchar(10)[]"} {
+		expr := stmt.Columns[i]
+		if aliased, ok := expr.(*parser.AliasedExpression); ok {
+			expr = aliased.Expression
+		}
+		if dt := expr.(*parser.CastExpression).DataType; !dt.IsArray || dt.String() != expected {
+			t.Errorf("Expected array type %s, got %+v", expected, dt)
+		}
+	}
+
+	cast := firstColumn(t, "SELECT CONVERT(name USING utf8mb4) FROM users", "mysql").(*parser.CastExpression)
+	if cast.Charset != "utf8mb4" || cast.DataType != nil {
+		t.Errorf("Expected CONVERT USING charset, got %+v", cast)
+	}
+
+	// CAST needs AS; TRY_CONVERT is SQL Server only
+	for _, sql := range []string{"SELECT CAST(total INT) FROM orders", "SELECT TRY_CONVERT(total, CHAR) FROM orders"} {
+		if _, err := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect("mysql")).ParseStatement(); err == nil {
+			t.Errorf("Expected error for %q", sql)
+		}
+	}
+}
+
+// Test EXTRACT, SUBSTRING ... FROM ... FOR, TRIM and INTERVAL
+func TestSpecialFormFunctions(t *testing.T) {
+	extract := firstColumn(t, "SELECT EXTRACT(year FROM created_at) FROM orders", "postgresql").(*parser.ExtractExpression)
+	if extract.Field != "YEAR" || extract.Source.String() != "created_at" {
+		t.Errorf("Unexpected EXTRACT: %s", extract)
+	}
+
+	substring := firstColumn(t, "SELECT SUBSTRING(name FROM 2 FOR 3) FROM users", "postgresql").(*parser.FunctionCall)
+	if len(substring.Arguments) != 3 {
+		t.Errorf("Expected SUBSTRING(name FROM 2 FOR 3) as 3 arguments, got %d", len(substring.Arguments))
+	}
+
+	trims := []struct {
+		sql      string
+		position string
+		chars    bool
+	}{
+		{"SELECT TRIM(LEADING 'x' FROM name) FROM users", "LEADING", true},
+		{"SELECT TRIM(BOTH FROM name) FROM users", "BOTH", false},
+		{"SELECT TRIM('x' FROM name) FROM users", "", true},
+		{"SELECT TRIM(name) FROM users", "", false},
+	}
+	for _, tt := range trims {
+		trim := firstColumn(t, tt.sql, "postgresql").(*parser.TrimExpression)
+		if trim.Position != tt.position || (trim.Characters != nil) != tt.chars || trim.Source.String() != "name" {
+			t.Errorf("%s: unexpected TRIM %+v", tt.sql, trim)
+		}
+	}
+
+	intervals := []struct {
+		dialect string
+		sql     string
+		unit    string
+	}{
+		{"postgresql", "SELECT INTERVAL '1 day' FROM orders", ""},
+		{"mysql", "SELECT INTERVAL 7 DAY FROM orders", "DAY"},
+		{"oracle", "SELECT INTERVAL '1-2' YEAR TO MONTH FROM orders", "YEAR TO MONTH"},
+	}
+	for _, tt := range intervals {
+		interval := firstColumn(t, tt.sql, tt.dialect).(*parser.IntervalExpression)
+		if interval.Unit != tt.unit {
+			t.Errorf("%s: expected unit %q, got %q", tt.sql, tt.unit, interval.Unit)
+		}
+	}
+}
+
+// Test that CAST target types feed the type checker
+func TestTypeCheckCast(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	checker := schema.NewTypeChecker(s)
+
+	tests := []struct {
+		sql      string
+		mismatch bool
+	}{
+		{"SELECT id FROM users WHERE age = 'abc'", true},
+		{"SELECT id FROM users WHERE age = CAST('42' AS INTEGER)", false},
+		{"SELECT id FROM users WHERE name = age::varchar", false},
+		{"SELECT id FROM users WHERE created_at > CAST('2024-01-01' AS timestamptz)", false},
+		{"SELECT id FROM users WHERE age = CAST(name AS DATE)", true},
+		{"UPDATE users SET age = CAST(name AS INT) WHERE id = 1", false},
+		{"INSERT INTO users (id, created_at) VALUES (1, CAST('x' AS VARCHAR(10)))", true},
+	}
+
+	for _, tt := range tests {
+		mismatch := false
+		for _, e := range checker.CheckStatement(parseWithDialect(t, tt.sql, "postgresql")) {
+			if e.Type == "TYPE_MISMATCH" {
+				mismatch = true
+			}
+		}
+		if mismatch != tt.mismatch {
+			t.Errorf("%s: expected TYPE_MISMATCH=%v, got %v", tt.sql, tt.mismatch, mismatch)
+		}
+	}
+}
+
+// Test that columns inside the new forms are analyzed and validated
+func TestFunctionArgumentColumns(t *testing.T) {
+	stmt := parseWithDialect(t, "SELECT COUNT(*) FILTER (WHERE status = 'paid'), EXTRACT(YEAR FROM created_at), CAST(total AS INT) FROM orders", "postgresql")
+
+	result := analyzer.New().Analyze(stmt)
+	seen := make(map[string]bool)
+	for _, col := range result.Columns {
+		seen[col.Name] = true
+	}
+	for _, want := range []string{"status", "created_at", "total"} {
+		if !seen[want] {
+			t.Errorf("Expected column %s in analysis, got %+v", want, result.Columns)
+		}
+	}
+
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	errors := schema.NewValidator(s).ValidateStatement(parseWithDialect(t, "SELECT TRIM(LEADING 'x' FROM nickname) FROM users", "postgresql"))
+	if len(errors) != 1 || errors[0].Type != "COLUMN_NOT_FOUND" {
+		t.Errorf("Expected COLUMN_NOT_FOUND for nickname, got %v", errors)
+	}
+}
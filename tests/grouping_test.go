@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test ROLLUP, CUBE, GROUPING SETS and WITH ROLLUP and the grouping sets they produce
+func TestGroupingSets(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		sql     string
+		sets    [][]string
+	}{
+		{"Rollup", "postgresql", "SELECT status, user_id, SUM(total) FROM orders GROUP BY ROLLUP(status, user_id)",
+			[][]string{{"status", "user_id"}, {"status"}, {}}},
+		{"Cube", "postgresql", "SELECT status, user_id, SUM(total) FROM orders GROUP BY CUBE(status, user_id)",
+			[][]string{{"status", "user_id"}, {"status"}, {"user_id"}, {}}},
+		{"Grouping sets", "postgresql", "SELECT status, user_id, SUM(total) FROM orders GROUP BY GROUPING SETS ((status), (user_id), ())",
+			[][]string{{"status"}, {"user_id"}, {}}},
+		{"Composite rollup element", "sqlserver", "SELECT status, user_id, product_id FROM orders GROUP BY ROLLUP((status, user_id), product_id)",
+			[][]string{{"status", "user_id", "product_id"}, {"status", "user_id"}, {}}},
+		{"Plain column and rollup", "postgresql", "SELECT status, user_id FROM orders GROUP BY status, ROLLUP(user_id)",
+			[][]string{{"status", "user_id"}, {"status"}}},
+		{"Nested rollup", "postgresql", "SELECT status, user_id FROM orders GROUP BY GROUPING SETS (status, ROLLUP(user_id))",
+			[][]string{{"status"}, {"user_id"}, {}}},
+		{"With rollup", "mysql", "SELECT status, user_id, SUM(total) FROM orders GROUP BY status, user_id WITH ROLLUP",
+			[][]string{{"status", "user_id"}, {"status"}, {}}},
+		{"Plain group by", "mysql", "SELECT status FROM orders GROUP BY status", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzer.New().Analyze(parseWithDialect(t, tt.sql, tt.dialect))
+			if !reflect.DeepEqual(result.GroupingSets, tt.sets) {
+				t.Errorf("Expected grouping sets %v, got %v", tt.sets, result.GroupingSets)
+			}
+		})
+	}
+
+	stmt := parseWithDialect(t, "SELECT status FROM orders GROUP BY GROUPING SETS ((status, user_id), ())", "postgresql").(*parser.SelectStatement)
+	gse, ok := stmt.GroupBy[0].(*parser.GroupingSetsExpression)
+	if !ok || gse.Kind != "GROUPING SETS" || len(gse.Sets) != 2 || len(gse.Sets[1]) != 0 {
+		t.Fatalf("Unexpected GROUPING SETS: %+v", stmt.GroupBy)
+	}
+	if gse.String() != "GROUPING SETS((status, user_id), ())" {
+		t.Errorf("Unexpected String(): %s", gse.String())
+	}
+
+	if _, err := parser.NewWithDialect(context.Background(), "SELECT status FROM orders GROUP BY ROLLUP(status, ())", dialect.GetDialect("postgresql")).ParseStatement(); err == nil {
+		t.Error("Expected error for an empty set in ROLLUP")
+	}
+}
+
+// Test DISTINCT ON, WINDOW and QUALIFY
+func TestSelectModernClauses(t *testing.T) {
+	stmt := parseWithDialect(t, "SELECT DISTINCT ON (user_id) user_id, total FROM orders ORDER BY user_id, created_at DESC", "postgresql").(*parser.SelectStatement)
+	if !stmt.Distinct || len(stmt.DistinctOn) != 1 || len(stmt.Columns) != 2 {
+		t.Errorf("Unexpected DISTINCT ON: %+v", stmt.DistinctOn)
+	}
+
+	if _, err := parser.NewWithDialect(context.Background(), "SELECT DISTINCT ON (user_id) user_id FROM orders", dialect.GetDialect("mysql")).ParseStatement(); err == nil {
+		t.Error("Expected DISTINCT ON to be rejected by MySQL")
+	}
+
+	stmt = parseWithDialect(t,
+		"SELECT user_id, SUM(total) OVER w, RANK() OVER (w ORDER BY total DESC) FROM orders WINDOW w AS (PARTITION BY user_id), w2 AS (ORDER BY created_at) ORDER BY user_id",
+		"postgresql").(*parser.SelectStatement)
+	if len(stmt.Windows) != 2 || stmt.Windows[0].Name != "w" || len(stmt.Windows[0].Spec.PartitionBy) != 1 {
+		t.Fatalf("Unexpected WINDOW clause: %+v", stmt.Windows)
+	}
+	sum := stmt.Columns[1].(*parser.WindowFunction)
+	if sum.OverClause.WindowName != "w" || sum.String() != "SUM OVER w" {
+		t.Errorf("Expected OVER w, got %s", sum.String())
+	}
+	rank := stmt.Columns[2].(*parser.WindowFunction)
+	if rank.OverClause.WindowName != "w" || len(rank.OverClause.OrderBy) != 1 {
+		t.Errorf("Expected OVER (w ORDER BY total DESC), got %+v", rank.OverClause)
+	}
+	if len(stmt.OrderBy) != 1 {
+		t.Errorf("Expected ORDER BY after WINDOW, got %+v", stmt.OrderBy)
+	}
+
+	stmt = parseWithDialect(t,
+		"SELECT user_id, total FROM orders QUALIFY ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY total DESC) = 1",
+		"oracle").(*parser.SelectStatement)
+	if stmt.From == nil || stmt.From.Tables[0].Alias != "" || stmt.Qualify == nil {
+		t.Errorf("Expected QUALIFY rather than a table alias: %+v", stmt)
+	}
+}
+
+// Test validation and analysis of columns in the new clauses
+func TestValidateModernClauses(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	validator := schema.NewValidator(s)
+
+	tests := []struct {
+		name    string
+		sql     string
+		missing string // "" when the query is valid
+	}{
+		{"Valid rollup", "SELECT status, SUM(total) FROM orders GROUP BY ROLLUP(status, user_id)", ""},
+		{"Unknown rollup column", "SELECT status FROM orders GROUP BY ROLLUP(status, region)", "region"},
+		{"Unknown grouping set column", "SELECT status FROM orders GROUP BY GROUPING SETS ((status), (region))", "region"},
+		{"Unknown DISTINCT ON column", "SELECT DISTINCT ON (region) status FROM orders", "region"},
+		{"Unknown window column", "SELECT SUM(total) OVER w FROM orders WINDOW w AS (PARTITION BY region)", "region"},
+		{"Unknown OVER column", "SELECT SUM(total) OVER (ORDER BY shipped_at) FROM orders", "shipped_at"},
+		{"Unknown QUALIFY column", "SELECT status FROM orders QUALIFY region = 1", "region"},
+		{"Valid qualify", "SELECT status FROM orders QUALIFY ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY total DESC) = 1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.ValidateStatement(parseWithDialect(t, tt.sql, "postgresql"))
+			if tt.missing == "" {
+				if len(errors) > 0 {
+					t.Errorf("Expected no errors, got %v", errors)
+				}
+				return
+			}
+			if len(errors) != 1 || errors[0].Type != "COLUMN_NOT_FOUND" || errors[0].Column != tt.missing {
+				t.Errorf("Expected COLUMN_NOT_FOUND for %s, got %v", tt.missing, errors)
+			}
+		})
+	}
+
+	result := analyzer.New().Analyze(parseWithDialect(t, "SELECT DISTINCT ON (user_id) total FROM orders WINDOW w AS (PARTITION BY status) QUALIFY quantity > 1", "postgresql"))
+	usages := make(map[string]bool)
+	for _, col := range result.Columns {
+		usages[col.Name+":"+col.Usage] = true
+	}
+	for _, want := range []string{"user_id:DISTINCT_ON", "status:WINDOW", "quantity:QUALIFY"} {
+		if !usages[want] {
+			t.Errorf("Expected column usage %s, got %+v", want, result.Columns)
+		}
+	}
+}
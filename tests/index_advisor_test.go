@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test the key column order and the DDL of recommended indexes
+func TestIndexAdvisor(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		dialect  string
+		sql      string
+		expected []string // DDL of each recommendation
+	}{
+		{
+			"equality, range, then sort",
+			"mysql",
+			"SELECT id FROM orders WHERE created_at > '2024-01-01' AND status = 'paid' ORDER BY total",
+			[]string{"CREATE INDEX idx_orders_status_created_at_total ON orders (status, created_at, total);"},
+		},
+		{
+			"sort after equality",
+			"mysql",
+			"SELECT id FROM orders WHERE status = 'paid' ORDER BY created_at",
+			[]string{"CREATE INDEX idx_orders_status_created_at ON orders (status, created_at);"},
+		},
+		{
+			"covering columns on PostgreSQL",
+			"postgresql",
+			"SELECT total, quantity FROM orders WHERE status = 'paid' AND created_at >= '2024-01-01'",
+			[]string{`CREATE INDEX idx_orders_status_created_at ON orders (status, created_at) INCLUDE (total, quantity);`},
+		},
+		{
+			"covering columns on SQL Server",
+			"sqlserver",
+			"SELECT u.name, o.total FROM users u JOIN orders o ON o.product_id = u.id WHERE u.age > 30",
+			[]string{
+				"CREATE NONCLUSTERED INDEX idx_users_age ON users (age) INCLUDE (name, id);",
+				"CREATE NONCLUSTERED INDEX idx_orders_product_id ON orders (product_id) INCLUDE (total);",
+			},
+		},
+		{
+			"existing index covers the key",
+			"postgresql",
+			"SELECT u.name, o.total FROM users u JOIN orders o ON o.user_id = u.id",
+			nil,
+		},
+		{
+			"unique key",
+			"mysql",
+			"SELECT name FROM users WHERE email = 'a@b.c' AND age > 18",
+			nil,
+		},
+		{
+			"leading wildcard can't seek",
+			"mysql",
+			"DELETE FROM products WHERE name LIKE '%phone'",
+			nil,
+		},
+		{
+			"prefix LIKE in an update",
+			"mysql",
+			"UPDATE products SET stock = 0 WHERE name LIKE 'phone%'",
+			[]string{"CREATE INDEX idx_products_name ON products (name);"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := parseWithDialect(t, tt.sql, tt.dialect)
+			recs := analyzer.NewIndexAdvisor(dialect.GetDialect(tt.dialect), s).Advise(stmt)
+
+			var ddl []string
+			for _, rec := range recs {
+				ddl = append(ddl, rec.DDL)
+			}
+			if strings.Join(ddl, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(ddl, "\n"))
+			}
+		})
+	}
+}
+
+// Test that an index extending an existing one names it
+func TestIndexAdvisorSupersedes(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	stmt := parseWithDialect(t, "SELECT id FROM orders WHERE status = 'paid' AND created_at > '2024-01-01'", "mysql")
+	recs := analyzer.NewIndexAdvisor(dialect.GetDialect("mysql"), s).Advise(stmt)
+	if len(recs) != 1 {
+		t.Fatalf("Expected 1 recommendation, got %d", len(recs))
+	}
+	if recs[0].Supersede != "idx_orders_status" {
+		t.Errorf("Expected the index to extend idx_orders_status, got %q", recs[0].Supersede)
+	}
+	if len(recs[0].Include) != 0 {
+		t.Errorf("Expected no INCLUDE columns on MySQL, got %v", recs[0].Include)
+	}
+
+	// Unique indexes and the primary key enforce constraints and stay
+	for _, sql := range []string{
+		"SELECT name FROM users WHERE email > 'm' ORDER BY created_at",
+		"SELECT name FROM users WHERE id > 100 ORDER BY created_at",
+	} {
+		stmt := parseWithDialect(t, sql, "mysql")
+		recs := analyzer.NewIndexAdvisor(dialect.GetDialect("mysql"), s).Advise(stmt)
+		if len(recs) != 1 {
+			t.Fatalf("%s: expected 1 recommendation, got %d", sql, len(recs))
+		}
+		if recs[0].Supersede != "" {
+			t.Errorf("%s: expected no index to be superseded, got %q", sql, recs[0].Supersede)
+		}
+	}
+}
+
+// Test that a workload folds shorter keys into longer ones and ranks
+// indexes by the queries that use them
+func TestIndexAdvisorWorkload(t *testing.T) {
+	queries := []string{
+		"SELECT name FROM customers WHERE region = 'EU'",
+		"SELECT name FROM customers WHERE region = 'EU' AND signup_date > '2024-01-01'",
+		"SELECT name FROM customers WHERE region = 'US'",
+		"SELECT id FROM invoices WHERE paid = 0",
+		"SELECT id FROM invoices WHERE customer_id = 7 ORDER BY issued_at",
+	}
+
+	advisor := analyzer.NewIndexAdvisor(dialect.GetDialect("mysql"), nil)
+	for _, sql := range queries {
+		advisor.Add(parseWithDialect(t, sql, "mysql"))
+	}
+
+	recs := advisor.Recommendations()
+	expected := []struct {
+		table     string
+		columns   string
+		frequency int
+	}{
+		{"customers", "region, signup_date", 3},
+		{"invoices", "customer_id, issued_at", 1},
+		{"invoices", "paid", 1},
+	}
+	if len(recs) != len(expected) {
+		t.Fatalf("Expected %d recommendations, got %+v", len(expected), recs)
+	}
+	for i, exp := range expected {
+		rec := recs[i]
+		if rec.Table != exp.table || strings.Join(rec.Columns, ", ") != exp.columns || rec.Frequency != exp.frequency {
+			t.Errorf("Recommendation %d: expected %s (%s) x%d, got %s (%s) x%d", i,
+				exp.table, exp.columns, exp.frequency, rec.Table, strings.Join(rec.Columns, ", "), rec.Frequency)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lineage"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test parsing of the join forms beyond INNER/LEFT/RIGHT/FULL ... ON
+func TestJoinGrammar(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		sql      string
+		joins    []string // JoinClause.String() of each join, in order
+		rightTbl string   // Table name or alias of the first join
+	}{
+		{"Cross join", "postgresql", "SELECT * FROM users u CROSS JOIN orders o", []string{"CROSS JOIN"}, "o"},
+		{"Natural join", "postgresql", "SELECT * FROM users NATURAL LEFT OUTER JOIN orders", []string{"NATURAL LEFT JOIN"}, "orders"},
+		{"Outer keyword", "mysql", "SELECT * FROM users u RIGHT OUTER JOIN orders o ON o.user_id = u.id", []string{"RIGHT JOIN"}, "o"},
+		{"Using", "postgresql", "SELECT * FROM users JOIN orders USING (id)", []string{"INNER JOIN"}, "orders"},
+		{"Lateral", "postgresql", "SELECT * FROM users u LEFT JOIN LATERAL (SELECT total FROM orders WHERE orders.user_id = u.id) t ON true", []string{"LEFT JOIN LATERAL"}, "t"},
+		{"Comma lateral", "postgresql", "SELECT * FROM users u, LATERAL (SELECT total FROM orders WHERE orders.user_id = u.id) t", []string{"CROSS JOIN LATERAL"}, "t"},
+		{"Apply", "sqlserver", "SELECT * FROM users u CROSS APPLY (SELECT total FROM orders WHERE orders.user_id = u.id) t OUTER APPLY (SELECT 1 AS one) x", []string{"CROSS APPLY", "OUTER APPLY"}, "t"},
+		{"Straight join", "mysql", "SELECT * FROM users u STRAIGHT_JOIN orders o ON o.user_id = u.id", []string{"STRAIGHT_JOIN"}, "o"},
+		{"Derived table", "postgresql", "SELECT * FROM users u JOIN (SELECT user_id FROM orders) o ON o.user_id = u.id", []string{"INNER JOIN"}, "o"},
+		{"Nested group", "postgresql", "SELECT * FROM users u LEFT JOIN (orders o JOIN products p ON p.id = o.product_id) ON o.user_id = u.id", []string{"LEFT JOIN", "INNER JOIN"}, "o"},
+		{"Leading group", "postgresql", "SELECT * FROM (users u JOIN orders o ON o.user_id = u.id) JOIN products p ON p.id = o.product_id", []string{"INNER JOIN", "INNER JOIN"}, "o"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := parseWithDialect(t, tt.sql, tt.dialect).(*parser.SelectStatement)
+
+			if len(stmt.Joins) != len(tt.joins) {
+				t.Fatalf("Expected %d joins, got %d", len(tt.joins), len(stmt.Joins))
+			}
+			for i, want := range tt.joins {
+				if got := stmt.Joins[i].String(); got != want {
+					t.Errorf("Join %d: expected %q, got %q", i, want, got)
+				}
+			}
+			first := stmt.Joins[0].Table
+			if name := first.Alias; name != tt.rightTbl && first.Name != tt.rightTbl {
+				t.Errorf("Expected first join table %q, got %q (alias %q)", tt.rightTbl, first.Name, first.Alias)
+			}
+		})
+	}
+
+	using := parseWithDialect(t, "SELECT * FROM users JOIN orders USING (id, region)", "postgresql").(*parser.SelectStatement).Joins[0]
+	if len(using.Using) != 2 || using.Using[1] != "region" || using.Condition != nil {
+		t.Errorf("Unexpected USING join: %+v", using)
+	}
+
+	// A join other than CROSS/NATURAL/APPLY still needs ON or USING
+	if _, err := parser.NewWithDialect(context.Background(), "SELECT * FROM users JOIN orders", dialect.GetDialect("postgresql")).ParseStatement(); err == nil {
+		t.Error("Expected error for JOIN without ON or USING")
+	}
+}
+
+// Test table-valued functions as APPLY and FROM sources
+func TestTableValuedFunctions(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		sql      string
+		function string
+		alias    string
+	}{
+		{"Cross apply", "sqlserver", "SELECT a.id, s.value FROM articles a CROSS APPLY STRING_SPLIT(a.tags, ',') s", "STRING_SPLIT", "s"},
+		{"Outer apply", "sqlserver", "SELECT a.id, f.total FROM accounts a OUTER APPLY dbo.fn(a.x) AS f", "dbo.fn", "f"},
+		{"Lateral", "postgresql", "SELECT u.id, g FROM users u CROSS JOIN LATERAL generate_series(1, u.visits) g", "generate_series", "g"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := parseWithDialect(t, tt.sql, tt.dialect).(*parser.SelectStatement)
+			if len(stmt.Joins) != 1 {
+				t.Fatalf("Expected 1 join, got %d", len(stmt.Joins))
+			}
+			table := stmt.Joins[0].Table
+			if table.Function == nil || table.Function.Name != tt.function || len(table.Function.Arguments) == 0 {
+				t.Fatalf("Expected a call to %s, got %+v", tt.function, table)
+			}
+			if table.Alias != tt.alias {
+				t.Errorf("Expected alias %q, got %q", tt.alias, table.Alias)
+			}
+		})
+	}
+
+	stmt := parseWithDialect(t, "SELECT n FROM generate_series(1, 10) n, users", "postgresql").(*parser.SelectStatement)
+	if len(stmt.From.Tables) != 2 || stmt.From.Tables[0].Function == nil || stmt.From.Tables[0].Alias != "n" {
+		t.Fatalf("Expected a FROM function source, got %+v", stmt.From.Tables)
+	}
+
+	// The function's arguments are column reads, not tables
+	result := analyzer.New().Analyze(parseWithDialect(t, tests[0].sql, "sqlserver"))
+	if len(result.Tables) != 1 || result.Tables[0].Name != "articles" {
+		t.Errorf("Expected only the articles table, got %+v", result.Tables)
+	}
+
+	// and the function's columns derive from them
+	l, err := lineage.NewExtractor(nil, nil).Extract(parseWithDialect(t, tests[0].sql, "sqlserver"))
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if sources := l.Columns[1].Sources; len(sources) != 1 || sources[0] != (lineage.Column{Table: "articles", Column: "tags"}) {
+		t.Errorf("Expected s.value to derive from articles.tags, got %+v", sources)
+	}
+}
+
+// Test that only joins without a join condition are Cartesian products
+func TestJoinCartesianProduct(t *testing.T) {
+	tests := []struct {
+		sql      string
+		severity string // "" when no CARTESIAN_PRODUCT is expected
+	}{
+		{"SELECT * FROM users JOIN orders USING (id)", ""},
+		{"SELECT * FROM users NATURAL JOIN orders", ""},
+		{"SELECT * FROM users u, LATERAL (SELECT total FROM orders WHERE orders.user_id = u.id) t", ""},
+		{"SELECT * FROM users CROSS JOIN orders", "WARNING"},
+		{"SELECT * FROM users STRAIGHT_JOIN orders", "CRITICAL"},
+		// Comma-separated tables joined by WHERE conditions
+		{"SELECT * FROM users u, orders o WHERE u.id = o.user_id", ""},
+		{"SELECT * FROM users, orders, products WHERE orders.product_id = products.id AND users.id = orders.user_id", ""},
+		{"SELECT * FROM users u, orders o WHERE u.age > 30 AND o.total > 100", "CRITICAL"},
+		{"SELECT * FROM users u, orders o, products p WHERE u.id = o.user_id", "CRITICAL"},
+	}
+
+	engine := analyzer.NewOptimizationEngine(dialect.GetDialect("mysql"))
+	for _, tt := range tests {
+		stmt := parseWithDialect(t, tt.sql, "mysql")
+
+		severity := ""
+		for _, s := range engine.AnalyzeOptimizations(stmt) {
+			if s.Type == "CARTESIAN_PRODUCT" {
+				severity = s.Severity
+			}
+		}
+		if severity != tt.severity {
+			t.Errorf("%s: expected CARTESIAN_PRODUCT severity %q, got %q", tt.sql, tt.severity, severity)
+		}
+	}
+
+	result := analyzer.New().Analyze(parseWithDialect(t, "SELECT * FROM users JOIN orders USING (id)", "postgresql"))
+	if len(result.Joins) != 1 || result.Joins[0].Condition != "USING (id)" {
+		t.Errorf("Expected USING condition in analysis, got %+v", result.Joins)
+	}
+}
+
+// Test validation of USING columns
+func TestValidateJoinUsing(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	validator := schema.NewValidator(s)
+
+	if errors := validator.ValidateStatement(parseWithDialect(t, "SELECT * FROM users JOIN orders USING (id)", "postgresql")); len(errors) > 0 {
+		t.Errorf("Expected no errors, got %v", errors)
+	}
+
+	errors := validator.ValidateStatement(parseWithDialect(t, "SELECT * FROM users JOIN orders USING (user_id)", "postgresql"))
+	if len(errors) != 1 || errors[0].Type != "COLUMN_NOT_FOUND" {
+		t.Errorf("Expected COLUMN_NOT_FOUND for user_id missing from users, got %v", errors)
+	}
+}
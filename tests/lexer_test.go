@@ -90,3 +90,31 @@ func TestStringLiterals(t *testing.T) {
 		t.Fatalf("expected 'hello world', got %q", tok.Literal)
 	}
 }
+
+// Test that tokens carry the byte offsets of their first and last characters
+func TestTokenOffsets(t *testing.T) {
+	input := "SELECT a <> 'x y'\n  FROM t"
+
+	tests := []struct {
+		expectedType  lexer.TokenType
+		expectedStart int
+		expectedEnd   int
+	}{
+		{lexer.SELECT, 0, 6},
+		{lexer.IDENT, 7, 8},
+		{lexer.NOT_EQ, 9, 11},
+		{lexer.STRING, 12, 17},
+		{lexer.FROM, 20, 24},
+		{lexer.IDENT, 25, 26},
+		{lexer.EOF, 26, 26},
+	}
+
+	l := lexer.New(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Position != tt.expectedStart || tok.End != tt.expectedEnd {
+			t.Fatalf("tests[%d] - expected %q at [%d, %d), got %q at [%d, %d)",
+				i, tt.expectedType, tt.expectedStart, tt.expectedEnd, tok.Type, tok.Position, tok.End)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lineage"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// extractLineage parses sql as PostgreSQL and extracts its lineage
+// against the test schema
+func extractLineage(t *testing.T, sql string) *lineage.Lineage {
+	t.Helper()
+
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	l, err := lineage.NewExtractor(dialect.GetDialect("postgresql"), s).Extract(parseWithDialect(t, sql, "postgresql"))
+	if err != nil {
+		t.Fatalf("Failed to extract lineage of %q: %v", sql, err)
+	}
+	return l
+}
+
+// describe renders a column's lineage as "name <- sources [transformations]"
+func describe(col *lineage.ColumnLineage) string {
+	sources := make([]string, len(col.Sources))
+	for i, source := range col.Sources {
+		sources[i] = source.String()
+	}
+	transformations := make([]string, len(col.Transformations))
+	for i, t := range col.Transformations {
+		transformations[i] = string(t)
+	}
+	return col.Name + " <- " + strings.Join(sources, ", ") + " [" + strings.Join(transformations, ", ") + "]"
+}
+
+// Test column lineage through the supported statements
+func TestColumnLineage(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		target   string
+		expected []string
+	}{
+		{
+			"Aggregate view",
+			"CREATE VIEW report AS SELECT u.name, SUM(o.total) AS total FROM users u JOIN orders o ON u.id = o.user_id GROUP BY u.name",
+			"report",
+			[]string{"name <- users.name [DIRECT]", "total <- orders.total [AGGREGATE]"},
+		},
+		{
+			"CASE and expressions",
+			"SELECT CASE WHEN stock > 0 THEN name ELSE 'none' END AS tag, price * 1.2 AS gross, 1 AS one FROM products",
+			"",
+			[]string{"tag <- products.stock, products.name [CASE]", "gross <- products.price [EXPRESSION]", "one <-  [EXPRESSION]"},
+		},
+		{
+			"CTE and derived table",
+			"WITH t AS (SELECT user_id, total * 2 AS dbl FROM orders) SELECT x.user_id, x.dbl FROM (SELECT * FROM t) x",
+			"",
+			[]string{"user_id <- orders.user_id [DIRECT]", "dbl <- orders.total [EXPRESSION]"},
+		},
+		{
+			"Set operation",
+			"SELECT id, name FROM users UNION ALL SELECT id, UPPER(name) FROM products",
+			"",
+			[]string{"id <- users.id, products.id [DIRECT]", "name <- users.name, products.name [DIRECT, EXPRESSION]"},
+		},
+		{
+			"Star expansion",
+			"INSERT INTO products SELECT * FROM products",
+			"products",
+			[]string{"id <- products.id [DIRECT]", "name <- products.name [DIRECT]", "price <- products.price [DIRECT]", "category <- products.category [DIRECT]", "stock <- products.stock [DIRECT]"},
+		},
+		{
+			"CREATE TABLE AS SELECT",
+			"CREATE TABLE ranked AS SELECT id, ROW_NUMBER() OVER (PARTITION BY status ORDER BY created_at) AS rn FROM orders",
+			"ranked",
+			[]string{"id <- orders.id [DIRECT]", "rn <-  [WINDOW]"},
+		},
+		{
+			"UPDATE",
+			"UPDATE orders SET total = quantity * 2 WHERE status = 'open'",
+			"orders",
+			[]string{"total <- orders.quantity [EXPRESSION]"},
+		},
+		{
+			"MERGE",
+			"MERGE INTO products t USING orders s ON t.id = s.product_id WHEN MATCHED THEN UPDATE SET t.stock = t.stock - s.quantity WHEN NOT MATCHED THEN INSERT (id, stock) VALUES (s.product_id, s.quantity)",
+			"products",
+			[]string{"stock <- products.stock, orders.quantity [DIRECT, EXPRESSION]", "id <- orders.product_id [DIRECT]"},
+		},
+		{
+			"Recursive CTE",
+			"WITH RECURSIVE r (n, src) AS (SELECT 1, id FROM users UNION ALL SELECT n + 1, src FROM r WHERE n < 5) SELECT n, src FROM r",
+			"",
+			[]string{"n <-  [EXPRESSION]", "src <- users.id [DIRECT]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := extractLineage(t, tt.sql)
+			if l.Target != tt.target {
+				t.Errorf("Expected target %q, got %q", tt.target, l.Target)
+			}
+			if len(l.Columns) != len(tt.expected) {
+				t.Fatalf("Expected %d columns, got %d: %+v", len(tt.expected), len(l.Columns), l.Columns)
+			}
+			for i, col := range l.Columns {
+				if got := describe(col); got != tt.expected[i] {
+					t.Errorf("Column %d: expected %q, got %q", i, tt.expected[i], got)
+				}
+			}
+		})
+	}
+}
+
+// Test that filter, join and grouping columns are reported separately
+func TestLineageFilters(t *testing.T) {
+	l := extractLineage(t, "SELECT u.name FROM users u JOIN orders o ON u.id = o.user_id WHERE o.status = 'paid' AND o.product_id IN (SELECT id FROM products WHERE stock > 0) GROUP BY u.name HAVING COUNT(*) > 1")
+
+	var filters []string
+	for _, f := range l.Filters {
+		filters = append(filters, f.Clause+" "+f.Column.String())
+	}
+	expected := "JOIN users.id, JOIN orders.user_id, WHERE products.stock, WHERE orders.status, WHERE orders.product_id, WHERE products.id, GROUP BY users.name"
+	if got := strings.Join(filters, ", "); got != expected {
+		t.Errorf("Expected filters %q, got %q", expected, got)
+	}
+	if strings.Join(l.Inputs, ",") != "users,orders,products" {
+		t.Errorf("Unexpected inputs %v", l.Inputs)
+	}
+}
+
+// Test lineage without a schema
+func TestLineageWithoutSchema(t *testing.T) {
+	stmt := parseWithDialect(t, "SELECT a.*, b FROM foo a JOIN bar ON a.k = bar.k", "postgresql")
+	l, err := lineage.NewExtractor(nil, nil).Extract(stmt)
+	if err != nil {
+		t.Fatalf("Failed to extract lineage: %v", err)
+	}
+
+	if len(l.Columns) != 2 || describe(l.Columns[0]) != "* <- foo.* [DIRECT]" || describe(l.Columns[1]) != "b <- foo.b, bar.b [DIRECT]" {
+		t.Errorf("Unexpected columns: %s, %s", describe(l.Columns[0]), describe(l.Columns[1]))
+	}
+	if len(l.Warnings) != 2 {
+		t.Errorf("Expected star and ambiguity warnings, got %v", l.Warnings)
+	}
+
+	if _, err := lineage.NewExtractor(nil, nil).Extract(parseWithDialect(t, "DELETE FROM foo", "postgresql")); err == nil {
+		t.Error("Expected an error for DELETE")
+	}
+}
+
+// Test the JSON and OpenLineage exports
+func TestLineageExport(t *testing.T) {
+	l := extractLineage(t, "SELECT u.name, SUM(o.total) AS spent FROM users u JOIN orders o ON u.id = o.user_id WHERE o.status = 'paid' GROUP BY u.name")
+
+	data, err := l.JSON()
+	if err != nil {
+		t.Fatalf("Failed to export JSON: %v", err)
+	}
+	var decoded lineage.Lineage
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Columns) != 2 {
+		t.Fatalf("Expected the JSON to round-trip, got %v (%s)", err, data)
+	}
+
+	facet := l.OpenLineage("postgres://db:5432")
+	spent := facet.Fields["spent"].InputFields
+	if len(spent) != 1 || spent[0].Name != "orders" || spent[0].Field != "total" || spent[0].Transformations[0].Subtype != "AGGREGATION" {
+		t.Errorf("Unexpected spent lineage: %+v", spent)
+	}
+	if !strings.Contains(facet.SchemaURL, "ColumnLineageDatasetFacet") {
+		t.Errorf("Unexpected schema URL %s", facet.SchemaURL)
+	}
+
+	subtypes := make(map[string]string)
+	for _, input := range facet.Dataset {
+		for _, tr := range input.Transformations {
+			subtypes[input.Name+"."+input.Field] += tr.Subtype + " "
+		}
+	}
+	if subtypes["orders.status"] != "FILTER " || subtypes["users.name"] != "GROUP_BY " || subtypes["users.id"] != "JOIN " {
+		t.Errorf("Unexpected dataset dependencies: %v", subtypes)
+	}
+}
+
+// Test CREATE TABLE ... AS SELECT parsing
+func TestParseCreateTableAsSelect(t *testing.T) {
+	stmt := parseWithDialect(t, "CREATE TABLE archive AS SELECT * FROM orders WHERE status = 'done'", "postgresql").(*parser.CreateTableStatement)
+	if stmt.Table.Name != "archive" || stmt.Query == nil || len(stmt.Columns) != 0 {
+		t.Errorf("Unexpected CREATE TABLE AS: %+v", stmt)
+	}
+
+	stmt = parseWithDialect(t, "CREATE TABLE archive (id INT) SELECT id FROM orders", "mysql").(*parser.CreateTableStatement)
+	if stmt.Query == nil || len(stmt.Columns) != 1 {
+		t.Errorf("Expected MySQL CREATE TABLE ... SELECT, got %+v", stmt)
+	}
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/internal/config"
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+// ruleSeverities returns the rules reported for a statement with their severity
+func ruleSeverities(suggestions []analyzer.EnhancedOptimizationSuggestion) map[string]string {
+	rules := make(map[string]string)
+	for _, s := range suggestions {
+		rules[s.Rule] = s.Severity
+	}
+	return rules
+}
+
+// Test enabling, disabling and re-grading rules by ID
+func TestConfigureRules(t *testing.T) {
+	off := false
+	engine := analyzer.NewOptimizationEngine(dialect.GetDialect("postgresql"))
+	err := engine.ConfigureRules(map[string]analyzer.RuleConfig{
+		"SELECT_STAR":              {Enabled: &off},
+		"missing_where":            {Severity: "error"},
+		"SQLSERVER_NOLOCK_WARNING": {Enabled: &off}, // another dialect's rule
+	})
+	if err != nil {
+		t.Fatalf("Failed to configure rules: %v", err)
+	}
+
+	rules := ruleSeverities(engine.AnalyzeOptimizations(parseWithDialect(t, "SELECT * FROM users", "postgresql")))
+	if _, ok := rules["SELECT_STAR"]; ok {
+		t.Error("Expected SELECT_STAR to be disabled")
+	}
+	if rules["MISSING_WHERE"] != "ERROR" {
+		t.Errorf("Expected MISSING_WHERE as ERROR, got %q", rules["MISSING_WHERE"])
+	}
+
+	if err := engine.ConfigureRules(map[string]analyzer.RuleConfig{"NO_SUCH_RULE": {Enabled: &off}}); err == nil {
+		t.Error("Expected an error for an unknown rule")
+	}
+	if err := engine.ConfigureRules(map[string]analyzer.RuleConfig{"SELECT_STAR": {Severity: "LOUD"}}); err == nil {
+		t.Error("Expected an error for an unknown severity")
+	}
+}
+
+// Test per-path overrides and validation of the lint configuration
+func TestLintConfigOverrides(t *testing.T) {
+	on, off := true, false
+	lint := config.LintConfig{
+		Rules: map[string]analyzer.RuleConfig{
+			"SELECT_STAR":   {Severity: "ERROR"},
+			"MISSING_WHERE": {Enabled: &off},
+		},
+		Overrides: []config.LintOverride{
+			{Paths: []string{"migrations/"}, Rules: map[string]analyzer.RuleConfig{"SELECT_STAR": {Enabled: &off}}},
+			{Paths: []string{"*_report.sql"}, Rules: map[string]analyzer.RuleConfig{"MISSING_WHERE": {Enabled: &on}}},
+		},
+	}
+
+	rules := lint.RulesFor("migrations/001_init.sql")
+	if star := rules["SELECT_STAR"]; star.Enabled == nil || *star.Enabled || star.Severity != "ERROR" {
+		t.Errorf("Expected SELECT_STAR disabled in migrations, got %+v", star)
+	}
+	if where := lint.RulesFor("./reports/daily_report.sql")["MISSING_WHERE"]; where.Enabled == nil || !*where.Enabled {
+		t.Errorf("Expected MISSING_WHERE enabled for reports, got %+v", where)
+	}
+	if star := lint.RulesFor("queries/users.sql")["SELECT_STAR"]; star.Enabled != nil {
+		t.Errorf("Expected the project-wide SELECT_STAR settings, got %+v", star)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Lint = lint
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected a valid config, got %v", err)
+	}
+	for _, broken := range []config.LintConfig{
+		{Rules: map[string]analyzer.RuleConfig{"SELECT_STARS": {Enabled: &off}}},
+		{FailOn: "SEVERE"},
+		{Overrides: []config.LintOverride{{Rules: lint.Rules}}},
+	} {
+		cfg.Lint = broken
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "lint") {
+			t.Errorf("Expected a lint error for %+v, got %v", broken, err)
+		}
+	}
+}
+
+// Test sqlens: comment directives
+func TestInlineSuppressions(t *testing.T) {
+	sql := `-- sqlens:disable-next-line SELECT_STAR
+SELECT * FROM users;
+SELECT * FROM users u, orders o /* sqlens:disable CARTESIAN_PRODUCT, MISSING_WHERE */;
+SELECT * FROM orders o, users u;
+-- sqlens:enable MISSING_WHERE
+SELECT * FROM products`
+
+	p := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect("mysql"))
+	if _, err := p.ParseStatements(); err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	sup := analyzer.ParseSuppressions(p.Comments())
+	if len(sup.Directives()) != 3 {
+		t.Fatalf("Expected 3 directives, got %+v", sup.Directives())
+	}
+
+	tests := []struct {
+		rule       string
+		start, end int
+		suppressed bool
+	}{
+		{"SELECT_STAR", 2, 2, true},
+		{"MISSING_WHERE", 2, 2, false},
+		{"SELECT_STAR", 3, 3, false},
+		{"CARTESIAN_PRODUCT", 3, 3, true},
+		{"CARTESIAN_PRODUCT", 4, 4, true},
+		{"MISSING_WHERE", 4, 4, true},
+		{"MISSING_WHERE", 6, 6, false},
+		{"CARTESIAN_PRODUCT", 6, 6, true},
+	}
+	for _, tt := range tests {
+		if got := sup.Suppressed(tt.rule, tt.start, tt.end); got != tt.suppressed {
+			t.Errorf("%s on line %d: expected suppressed=%v, got %v", tt.rule, tt.start, tt.suppressed, got)
+		}
+	}
+
+	engine := analyzer.NewOptimizationEngine(dialect.GetDialect("mysql"))
+	suggestions := engine.AnalyzeOptimizations(parseWithDialect(t, "SELECT * FROM users", "mysql"))
+	rules := ruleSeverities(sup.Filter(suggestions, 2, 2))
+	if _, ok := rules["SELECT_STAR"]; ok || len(rules) == 0 {
+		t.Errorf("Expected only SELECT_STAR filtered out, got %v", rules)
+	}
+}
+
+// Test recording accepted violations and reporting only new ones
+func TestLintBaseline(t *testing.T) {
+	engine := analyzer.NewOptimizationEngine(dialect.GetDialect("postgresql"))
+	pg := dialect.GetDialect("postgresql")
+	fingerprint := func(sql string) string { return analyzer.QueryFingerprint(sql, pg) }
+	legacy := parseWithDialect(t, "SELECT * FROM users", "postgresql")
+
+	baseline := analyzer.NewBaseline()
+	baseline.Add("./legacy/report.sql", fingerprint("SELECT * FROM users"), engine.AnalyzeOptimizations(legacy))
+
+	file := filepath.Join(t.TempDir(), "baseline.json")
+	if err := baseline.Save(file); err != nil {
+		t.Fatalf("Failed to save baseline: %v", err)
+	}
+	loaded, err := analyzer.LoadBaseline(file)
+	if err != nil {
+		t.Fatalf("Failed to load baseline: %v", err)
+	}
+
+	// The same statement, reformatted, is still accepted
+	reformatted := parseWithDialect(t, "select *\n  from users", "postgresql")
+	if left := loaded.Filter("legacy/report.sql", fingerprint("select *\n  from users"), engine.AnalyzeOptimizations(reformatted)); len(left) != 0 {
+		t.Errorf("Expected every violation to be accepted, got %v", ruleSeverities(left))
+	}
+
+	changed := parseWithDialect(t, "SELECT * FROM users u, orders o", "postgresql")
+	left := ruleSeverities(loaded.Filter("legacy/report.sql", fingerprint("SELECT * FROM users u, orders o"), engine.AnalyzeOptimizations(changed)))
+	if _, ok := left["CARTESIAN_PRODUCT"]; !ok {
+		t.Errorf("Expected violations of a changed statement to be reported, got %v", left)
+	}
+	if left := loaded.Filter("other.sql", fingerprint("SELECT * FROM users"), engine.AnalyzeOptimizations(legacy)); len(left) == 0 {
+		t.Error("Expected the baseline to be specific to its file")
+	}
+
+	// Another statement with as many columns is not accepted
+	other := parseWithDialect(t, "SELECT * FROM orders", "postgresql")
+	if left := loaded.Filter("legacy/report.sql", fingerprint("SELECT * FROM orders"), engine.AnalyzeOptimizations(other)); len(left) == 0 {
+		t.Error("Expected violations of a different statement to be reported")
+	}
+}
@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test dialect-specific string and number literal forms
+func TestLexerLiterals(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		input    string
+		tokType  lexer.TokenType
+		literal  string
+		kind     lexer.LiteralKind
+		position int
+	}{
+		{"Doubled quote", "postgresql", "'it''s'", lexer.STRING, "it's", lexer.LiteralPlain, 0},
+		{"Backslash is literal", "postgresql", `'C:\dir'`, lexer.STRING, `C:\dir`, lexer.LiteralPlain, 0},
+		{"MySQL backslash escapes", "mysql", `'it\'s\n\%'`, lexer.STRING, "it's\n\\%", lexer.LiteralPlain, 0},
+		{"National", "sqlserver", " N'naïve'", lexer.STRING, "naïve", lexer.LiteralNational, 1},
+		{"Escape string", "postgresql", `E'a\tb\x41\101\u00e9'`, lexer.STRING, "a\tbAAé", lexer.LiteralEscape, 0},
+		{"E is a name outside PostgreSQL", "mysql", "E'x'", lexer.IDENT, "E", lexer.LiteralPlain, 0},
+		{"Unicode escape string", "postgresql", `U&'d\0061t\+000061'`, lexer.STRING, "data", lexer.LiteralUnicode, 0},
+		{"Bad unicode escape", "postgresql", `U&'d\00'`, lexer.ILLEGAL, `U&'d\00'`, lexer.LiteralPlain, 0},
+		{"Hex string", "mysql", "X'4D7953'", lexer.STRING, "4D7953", lexer.LiteralHex, 0},
+		{"Bad hex string", "mysql", "X'4G'", lexer.ILLEGAL, "X'4G'", lexer.LiteralPlain, 0},
+		{"Hex number", "sqlserver", "0xABCD", lexer.NUMBER, "0xABCD", lexer.LiteralHex, 0},
+		{"Bit string", "postgresql", "B'1010'", lexer.STRING, "1010", lexer.LiteralBit, 0},
+		{"MySQL bit number", "mysql", "0b1010", lexer.NUMBER, "0b1010", lexer.LiteralBit, 0},
+		{"Oracle q-quote", "oracle", "q'[it's]'", lexer.STRING, "it's", lexer.LiteralQuoted, 0},
+		{"Oracle q-quote custom delimiter", "oracle", "Q'#a]'b#'", lexer.STRING, "a]'b", lexer.LiteralQuoted, 0},
+		{"Oracle national q-quote", "oracle", "nq'{x}'", lexer.STRING, "x", lexer.LiteralNational, 0},
+		{"Dollar quote", "postgresql", "$$it's$$", lexer.STRING, "it's", lexer.LiteralDollar, 0},
+		{"Scientific", "postgresql", "1.5e-3", lexer.NUMBER, "1.5e-3", lexer.LiteralPlain, 0},
+		{"Leading dot", "postgresql", ".5", lexer.NUMBER, ".5", lexer.LiteralPlain, 0},
+		{"Unterminated", "postgresql", "'abc", lexer.ILLEGAL, "'abc", lexer.LiteralPlain, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tok := lexer.NewWithDialect(tt.input, dialect.GetDialect(tt.dialect)).NextToken()
+			if tok.Type != tt.tokType || tok.Literal != tt.literal || tok.Kind != tt.kind || tok.Position != tt.position {
+				t.Errorf("Expected %s %q (%s) at %d, got %s %q (%s) at %d",
+					tt.tokType, tt.literal, tt.kind, tt.position, tok.Type, tok.Literal, tok.Kind, tok.Position)
+			}
+		})
+	}
+
+	if got := tokenLiterals("SELECT t.id, 1e10, E FROM t", "postgresql"); !reflect.DeepEqual(got, []string{"SELECT", "t", ".", "id", ",", "1e10", ",", "E", "FROM", "t"}) {
+		t.Errorf("Unexpected tokens: %v", got)
+	}
+}
+
+// Test the values and kinds literals are parsed into
+func TestParseLiterals(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+		value   interface{}
+		kind    lexer.LiteralKind
+		charset string
+	}{
+		{"postgresql", "SELECT 1e3 FROM t", 1000.0, lexer.LiteralPlain, ""},
+		{"postgresql", "SELECT .5 FROM t", 0.5, lexer.LiteralPlain, ""},
+		{"postgresql", "SELECT 0x1F FROM t", int64(31), lexer.LiteralHex, ""},
+		{"sqlserver", "SELECT 0x1F FROM t", []byte{0x1f}, lexer.LiteralHex, ""},
+		{"mysql", "SELECT X'ABC' FROM t", []byte{0x0a, 0xbc}, lexer.LiteralHex, ""},
+		{"postgresql", "SELECT B'101' FROM t", "101", lexer.LiteralBit, ""},
+		{"sqlserver", "SELECT N'héllo' FROM t", "héllo", lexer.LiteralNational, ""},
+		{"mysql", "SELECT _utf8mb4'abc' FROM t", "abc", lexer.LiteralPlain, "utf8mb4"},
+		{"mysql", "SELECT _binary 0x41 FROM t", []byte("A"), lexer.LiteralHex, "binary"},
+	}
+
+	for _, tt := range tests {
+		lit, ok := firstColumn(t, tt.sql, tt.dialect).(*parser.Literal)
+		if !ok {
+			t.Errorf("%s: expected a literal", tt.sql)
+			continue
+		}
+		if !reflect.DeepEqual(lit.Value, tt.value) || lit.Kind != tt.kind || lit.Charset != tt.charset {
+			t.Errorf("%s: expected %#v (%s, %q), got %#v (%s, %q)", tt.sql, tt.value, tt.kind, tt.charset, lit.Value, lit.Kind, lit.Charset)
+		}
+	}
+
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	checker := schema.NewTypeChecker(s)
+	for sql, mismatch := range map[string]bool{
+		"SELECT id FROM users WHERE name = N'Zoë'": false,
+		"SELECT id FROM users WHERE age = 1.5e1":   false,
+		"SELECT id FROM users WHERE name = 0x41":   true,
+	} {
+		found := false
+		for _, e := range checker.CheckStatement(parseWithDialect(t, sql, "sqlserver")) {
+			if e.Type == "TYPE_MISMATCH" {
+				found = true
+			}
+		}
+		if found != mismatch {
+			t.Errorf("%s: expected TYPE_MISMATCH=%v, got %v", sql, mismatch, found)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// migrationSuggestions returns the MIGRATION suggestions for a statement
+func migrationSuggestions(t *testing.T, engine *analyzer.OptimizationEngine, sql, dialectName string) []analyzer.EnhancedOptimizationSuggestion {
+	t.Helper()
+
+	p := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect(dialectName))
+	stmt, err := p.ParseStatement()
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", sql, err)
+	}
+
+	var migration []analyzer.EnhancedOptimizationSuggestion
+	for _, s := range engine.AnalyzeOptimizations(stmt) {
+		if s.Category == "MIGRATION" {
+			migration = append(migration, s)
+		}
+	}
+	return migration
+}
+
+func TestMigrationSafetyRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		sql           string
+		dialect       string
+		expectedTypes []string
+	}{
+		{
+			name:          "PostgreSQL index without CONCURRENTLY",
+			sql:           "CREATE INDEX idx_orders_user ON orders (user_id)",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_INDEX_WITHOUT_CONCURRENTLY"},
+		},
+		{
+			name:    "PostgreSQL index with CONCURRENTLY",
+			sql:     "CREATE INDEX CONCURRENTLY idx_orders_user ON orders (user_id)",
+			dialect: "postgresql",
+		},
+		{
+			name:          "PostgreSQL volatile default",
+			sql:           "ALTER TABLE orders ADD COLUMN token UUID DEFAULT gen_random_uuid()",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_VOLATILE_DEFAULT"},
+		},
+		{
+			name:          "PostgreSQL volatile default in parentheses",
+			sql:           "ALTER TABLE orders ADD COLUMN bucket INT DEFAULT (random() * 10)::int",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_VOLATILE_DEFAULT"},
+		},
+		{
+			name:          "PostgreSQL cast volatile default",
+			sql:           "ALTER TABLE orders ADD COLUMN seen_at TEXT DEFAULT CAST((clock_timestamp()) AS TEXT)",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_VOLATILE_DEFAULT"},
+		},
+		{
+			name:          "PostgreSQL :: cast volatile default",
+			sql:           "ALTER TABLE orders ADD COLUMN bucket INT DEFAULT random()::int",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_VOLATILE_DEFAULT"},
+		},
+		{
+			name:    "PostgreSQL constant default",
+			sql:     "ALTER TABLE orders ADD COLUMN status VARCHAR(20) DEFAULT 'new'",
+			dialect: "postgresql",
+		},
+		{
+			name:          "PostgreSQL column type change",
+			sql:           "ALTER TABLE orders ALTER COLUMN total TYPE NUMERIC(12,2)",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_COLUMN_TYPE_CHANGE"},
+		},
+		{
+			name:          "PostgreSQL SET NOT NULL",
+			sql:           "ALTER TABLE orders ALTER COLUMN user_id SET NOT NULL",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_SET_NOT_NULL"},
+		},
+		{
+			name:          "PostgreSQL foreign key without NOT VALID",
+			sql:           "ALTER TABLE orders ADD CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id)",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_CONSTRAINT_WITHOUT_NOT_VALID"},
+		},
+		{
+			name:    "PostgreSQL NOT VALID and VALIDATE",
+			sql:     "ALTER TABLE orders ADD CONSTRAINT chk_total CHECK (total >= 0) NOT VALID",
+			dialect: "postgresql",
+		},
+		{
+			name:          "PostgreSQL NOT NULL column without default",
+			sql:           "ALTER TABLE orders ADD COLUMN region VARCHAR(10) NOT NULL",
+			dialect:       "postgresql",
+			expectedTypes: []string{"POSTGRESQL_ADD_NOT_NULL_COLUMN"},
+		},
+		{
+			name:          "MySQL MODIFY copies the table",
+			sql:           "ALTER TABLE orders MODIFY COLUMN total DECIMAL(12,2)",
+			dialect:       "mysql",
+			expectedTypes: []string{"MYSQL_TABLE_COPY"},
+		},
+		{
+			name:          "MySQL explicit COPY",
+			sql:           "ALTER TABLE orders ADD COLUMN note TEXT, ALGORITHM=COPY",
+			dialect:       "mysql",
+			expectedTypes: []string{"MYSQL_BLOCKING_ALGORITHM"},
+		},
+		{
+			name:          "MySQL instant change without ALGORITHM",
+			sql:           "ALTER TABLE orders ADD COLUMN note TEXT",
+			dialect:       "mysql",
+			expectedTypes: []string{"MYSQL_PIN_INSTANT"},
+		},
+		{
+			name:    "MySQL instant change with ALGORITHM",
+			sql:     "ALTER TABLE orders ADD COLUMN note TEXT, ALGORITHM=INSTANT",
+			dialect: "mysql",
+		},
+		{
+			name:          "SQL Server index without ONLINE",
+			sql:           "CREATE NONCLUSTERED INDEX idx_orders_user ON orders (user_id)",
+			dialect:       "sqlserver",
+			expectedTypes: []string{"SQLSERVER_INDEX_WITHOUT_ONLINE"},
+		},
+		{
+			name:    "SQL Server index with ONLINE",
+			sql:     "CREATE INDEX idx_orders_user ON orders (user_id) INCLUDE (total) WITH (ONLINE = ON, FILLFACTOR = 90)",
+			dialect: "sqlserver",
+		},
+		{
+			name:          "Dropping a column",
+			sql:           "ALTER TABLE orders DROP COLUMN legacy",
+			dialect:       "sqlite",
+			expectedTypes: []string{"BREAKING_COLUMN_CHANGE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := analyzer.NewOptimizationEngine(dialect.GetDialect(tt.dialect))
+			suggestions := migrationSuggestions(t, engine, tt.sql, tt.dialect)
+
+			if len(suggestions) != len(tt.expectedTypes) {
+				t.Fatalf("Expected %d migration suggestions, got %d: %+v", len(tt.expectedTypes), len(suggestions), suggestions)
+			}
+			for i, want := range tt.expectedTypes {
+				if suggestions[i].Type != want {
+					t.Errorf("Expected suggestion %s, got %s", want, suggestions[i].Type)
+				}
+				if suggestions[i].FixSuggestion == "" {
+					t.Errorf("Expected a fix suggestion for %s", want)
+				}
+			}
+		})
+	}
+}
+
+// Test that columns still referenced by the schema or recorded users are errors
+func TestMigrationReferencedColumns(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	engine := analyzer.NewOptimizationEngine(dialect.GetDialect("postgresql"))
+	engine.SetSchema(s)
+	engine.AddColumnReference("users", "email", "view active_users")
+
+	suggestions := migrationSuggestions(t, engine, "ALTER TABLE users RENAME COLUMN id TO user_id", "postgresql")
+	if len(suggestions) != 1 || suggestions[0].Type != "REFERENCED_COLUMN_CHANGE" || suggestions[0].Severity != "ERROR" {
+		t.Fatalf("Expected REFERENCED_COLUMN_CHANGE error, got %+v", suggestions)
+	}
+	if !strings.Contains(suggestions[0].Description, "foreign key orders.user_id") {
+		t.Errorf("Expected the foreign key to be listed: %s", suggestions[0].Description)
+	}
+
+	suggestions = migrationSuggestions(t, engine, "ALTER TABLE users DROP COLUMN email", "postgresql")
+	if len(suggestions) != 1 || !strings.Contains(suggestions[0].Description, "view active_users") {
+		t.Errorf("Expected recorded reference in description, got %+v", suggestions)
+	}
+}
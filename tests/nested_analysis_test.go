@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+)
+
+// Test that tables of nested queries are collected with their scope path
+func TestNestedAnalysis(t *testing.T) {
+	tests := []struct {
+		name      string
+		dialect   string
+		sql       string
+		queryType string
+		tables    []string // name usage@scope, with (cte) for CTE reads
+	}{
+		{
+			"subqueries in every clause",
+			"postgresql",
+			"SELECT u.name, (SELECT COUNT(*) FROM orders o WHERE o.user_id = u.id) AS n FROM users u JOIN (SELECT id FROM products) p ON p.id = u.id WHERE u.id IN (SELECT user_id FROM bans) AND EXISTS (SELECT 1 FROM roles)",
+			"SELECT",
+			[]string{"users SELECT@", "products SELECT@from:p", "orders SELECT@select:1", "bans SELECT@where:1", "roles SELECT@where:2"},
+		},
+		{
+			"CTEs and set operations",
+			"postgresql",
+			"WITH recent AS (SELECT user_id FROM orders), users AS (SELECT id FROM users WHERE id IN (SELECT user_id FROM recent)) SELECT id FROM users UNION SELECT user_id FROM recent",
+			"SELECT",
+			[]string{"orders SELECT@cte:recent", "users SELECT@cte:users", "recent SELECT@cte:users/where:1 (cte)", "users SELECT@set:1 (cte)", "recent SELECT@set:2 (cte)"},
+		},
+		{
+			"INSERT ... SELECT",
+			"mysql",
+			"INSERT INTO archive (id) SELECT id FROM orders WHERE status IN (SELECT code FROM statuses)",
+			"INSERT",
+			[]string{"archive INSERT@", "orders SELECT@source", "statuses SELECT@source/where:1"},
+		},
+		{
+			"MERGE",
+			"sqlserver",
+			"MERGE INTO inventory AS t USING (SELECT product_id, SUM(qty) AS qty FROM shipments GROUP BY product_id) AS s ON t.product_id = s.product_id WHEN MATCHED THEN UPDATE SET qty = t.qty + s.qty;",
+			"MERGE",
+			[]string{"inventory MERGE@", "shipments SELECT@source"},
+		},
+		{
+			"UPDATE and DELETE subqueries",
+			"mysql",
+			"DELETE FROM sessions WHERE user_id NOT IN (SELECT id FROM users)",
+			"DELETE",
+			[]string{"sessions DELETE@", "users SELECT@where:1"},
+		},
+		{
+			"view",
+			"postgresql",
+			"CREATE VIEW active AS SELECT id FROM users WHERE id IN (SELECT user_id FROM orders)",
+			"CREATE VIEW",
+			[]string{"active CREATE@", "users SELECT@view:active", "orders SELECT@view:active/where:1"},
+		},
+		{
+			"procedure",
+			"mysql",
+			"CREATE PROCEDURE purge() BEGIN IF EXISTS (SELECT 1 FROM users) THEN UPDATE orders SET status = 'x'; END IF; DELETE FROM logs; END",
+			"CREATE PROCEDURE",
+			[]string{"users SELECT@procedure:purge/condition:1", "orders UPDATE@procedure:purge", "logs DELETE@procedure:purge"},
+		},
+		{
+			"trigger",
+			"mysql",
+			"CREATE TRIGGER audit_orders AFTER INSERT ON orders FOR EACH ROW BEGIN INSERT INTO audit (id) VALUES (NEW.id); END",
+			"CREATE TRIGGER",
+			[]string{"orders TRIGGER@", "audit INSERT@trigger:audit_orders"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := analyzer.New().Analyze(parseWithDialect(t, tt.sql, tt.dialect))
+			if result.QueryType != tt.queryType {
+				t.Errorf("Expected query type %q, got %q", tt.queryType, result.QueryType)
+			}
+
+			var tables []string
+			for _, table := range result.Tables {
+				entry := fmt.Sprintf("%s %s@%s", table.Name, table.Usage, table.Scope)
+				if table.CTE {
+					entry += " (cte)"
+				}
+				tables = append(tables, entry)
+			}
+			if strings.Join(tables, "\n") != strings.Join(tt.tables, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tt.tables, "\n"), strings.Join(tables, "\n"))
+			}
+		})
+	}
+}
+
+// Test the CTEs and column scopes of a WITH statement
+func TestNestedAnalysisCTEs(t *testing.T) {
+	sql := "WITH RECURSIVE tree AS (SELECT id FROM nodes WHERE parent_id IS NULL UNION ALL SELECT n.id FROM nodes n JOIN tree t ON n.parent_id = t.id) SELECT id FROM tree LIMIT 10"
+	result := analyzer.New().Analyze(parseWithDialect(t, sql, "postgresql"))
+
+	if len(result.CTEs) != 1 || result.CTEs[0].Name != "tree" || !result.CTEs[0].Recursive {
+		t.Fatalf("Expected the recursive CTE tree, got %+v", result.CTEs)
+	}
+	if result.Pagination == nil || result.Pagination.Count != "10" {
+		t.Errorf("Expected the main query's limit, got %+v", result.Pagination)
+	}
+
+	scopes := make(map[string]string)
+	for _, col := range result.Columns {
+		scopes[col.Table+"."+col.Name+" "+col.Usage] = col.Scope
+	}
+	expected := map[string]string{
+		".parent_id WHERE": "cte:tree/set:1",
+		"n.parent_id JOIN": "cte:tree/set:2",
+		".id SELECT":       "",
+		"t.id JOIN":        "cte:tree/set:2",
+	}
+	for column, scope := range expected {
+		if got, ok := scopes[column]; !ok || got != scope {
+			t.Errorf("Expected %s in scope %q, got %q", column, scope, got)
+		}
+	}
+}
@@ -52,6 +52,20 @@ func TestEnhancedOptimizations(t *testing.T) {
 			expectedSuggestions: 1,
 			expectedTypes:       []string{"INEFFICIENT_SUBQUERY"},
 		},
+		{
+			name:                "Comparison with NULL",
+			sql:                 "SELECT name FROM users WHERE email = NULL OR age <> NULL",
+			dialect:             "postgresql",
+			expectedSuggestions: 2,
+			expectedTypes:       []string{"NULL_COMPARISON"},
+		},
+		{
+			name:                "DELETE without WHERE",
+			sql:                 "DELETE FROM sessions",
+			dialect:             "mysql",
+			expectedSuggestions: 1,
+			expectedTypes:       []string{"UNBOUNDED_DELETE"},
+		},
 		{
 			name:                "Complex query with multiple issues",
 			sql:                 "SELECT * FROM users u, orders o WHERE UPPER(u.email) = 'TEST' AND o.total > 100",
@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test that queries are parsed in their dialect and results keep job order
+func TestPipelineParsesAndOrders(t *testing.T) {
+	jobs := make(chan analyzer.AnalysisJob)
+	go func() {
+		defer close(jobs)
+		jobs <- analyzer.AnalysisJob{ID: "pg", Query: "SELECT data->>'name' FROM users; DELETE FROM sessions WHERE id = 1", Dialect: dialect.GetDialect("postgresql")}
+		jobs <- analyzer.AnalysisJob{ID: "bad", Query: "SELECT FROM WHERE"}
+		for i := 0; i < 50; i++ {
+			jobs <- analyzer.AnalysisJob{ID: fmt.Sprint(i), Query: fmt.Sprintf("SELECT `name` FROM t%d LIMIT 5", i)}
+		}
+	}()
+
+	ca := analyzer.NewConcurrentAnalyzer(4)
+	opts := analyzer.PipelineOptions{Ordered: true, Dialect: dialect.GetDialect("mysql")}
+	var results []analyzer.AnalysisResult
+	for result := range ca.AnalyzeStream(context.Background(), jobs, opts) {
+		results = append(results, result)
+	}
+
+	if len(results) != 52 {
+		t.Fatalf("Expected 52 results, got %d", len(results))
+	}
+	for i, result := range results {
+		if result.Index != i {
+			t.Fatalf("Expected result %d in order, got index %d", i, result.Index)
+		}
+	}
+
+	pg := results[0]
+	if pg.Error != nil || pg.Dialect != "PostgreSQL" || len(pg.Analyses) != 2 {
+		t.Fatalf("Expected two PostgreSQL statements, got %+v", pg)
+	}
+	if pg.Analyses[0].Tables[0].Name != "users" || pg.Analyses[1].QueryType != "DELETE" || pg.Analysis.QueryType != "SELECT" {
+		t.Errorf("Expected an analysis per statement, got %+v", pg.Analyses)
+	}
+	if results[1].Error == nil {
+		t.Error("Expected a parse error")
+	}
+	if last := results[51]; last.ID != "49" || last.Analysis.Tables[0].Name != "t49" || last.Analysis.Pagination == nil {
+		t.Errorf("Expected the last MySQL query, got %+v", last)
+	}
+}
+
+// Test the optimization and validation stages
+func TestPipelineStages(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	disabled := false
+	opts := analyzer.PipelineOptions{
+		Dialect:  dialect.GetDialect("mysql"),
+		Optimize: true,
+		Schema:   s,
+		Rules: func(id string) map[string]analyzer.RuleConfig {
+			if strings.HasPrefix(id, "legacy/") {
+				return map[string]analyzer.RuleConfig{"MISSING_WHERE": {Enabled: &disabled}}
+			}
+			return nil
+		},
+	}
+	query := "SELECT * FROM users;\n-- sqlens:disable-next-line SELECT_STAR\nSELECT * FROM orders;\nSELECT missing FROM users"
+	jobs := []analyzer.AnalysisJob{{ID: "app/a.sql", Query: query}, {ID: "legacy/a.sql", Query: query}}
+
+	ca := analyzer.NewConcurrentAnalyzer(2)
+	var results []analyzer.AnalysisResult
+	for result := range ca.AnalyzeSeq(context.Background(), func(yield func(analyzer.AnalysisJob) bool) {
+		for _, job := range jobs {
+			if !yield(job) {
+				return
+			}
+		}
+	}, opts) {
+		results = append(results, result)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	rules := func(analysis analyzer.QueryAnalysis) map[string]bool {
+		found := make(map[string]bool)
+		for _, s := range analysis.EnhancedSuggestions {
+			found[s.Rule] = true
+		}
+		return found
+	}
+	for _, result := range results {
+		if result.Error != nil || len(result.Analyses) != 3 {
+			t.Fatalf("%s: expected 3 statements, got %+v", result.ID, result)
+		}
+		if !rules(result.Analyses[0])["SELECT_STAR"] || rules(result.Analyses[1])["SELECT_STAR"] {
+			t.Errorf("%s: expected SELECT_STAR suppressed on the second statement only", result.ID)
+		}
+		legacy := strings.HasPrefix(result.ID, "legacy/")
+		if rules(result.Analyses[0])["MISSING_WHERE"] == legacy {
+			t.Errorf("%s: expected MISSING_WHERE enabled to be %v", result.ID, !legacy)
+		}
+		if len(result.Validation) != 1 || result.Validation[0].Type != "COLUMN_NOT_FOUND" {
+			t.Errorf("%s: expected the unknown column, got %v", result.ID, result.Validation)
+		}
+	}
+}
+
+// Test that a job doesn't see the temp tables of the jobs before it, and
+// that each statement is fingerprinted by its own text
+func TestPipelineJobIsolation(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	sqlserver := dialect.GetDialect("sqlserver")
+	jobs := []analyzer.AnalysisJob{
+		{ID: "a.sql", Query: "SELECT id INTO #recent FROM users;\nSELECT id FROM #recent"},
+		{ID: "b.sql", Query: "SELECT id FROM #recent"},
+	}
+
+	ca := analyzer.NewConcurrentAnalyzer(1)
+	opts := analyzer.PipelineOptions{Workers: 1, Ordered: true, Dialect: sqlserver, Schema: s}
+	var results []analyzer.AnalysisResult
+	for result := range ca.AnalyzeSeq(context.Background(), slices.Values(jobs), opts) {
+		results = append(results, result)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if len(results[0].Validation) != 0 {
+		t.Errorf("Expected a.sql to validate, got %v", results[0].Validation)
+	}
+	if len(results[1].Validation) == 0 || results[1].Validation[0].Type != "TABLE_NOT_FOUND" {
+		t.Errorf("Expected #recent to be unknown to b.sql, got %v", results[1].Validation)
+	}
+
+	fingerprints := results[0].Fingerprints
+	if len(fingerprints) != 2 || fingerprints[1] != analyzer.QueryFingerprint("SELECT id FROM #recent", sqlserver) {
+		t.Errorf("Expected a fingerprint per statement, got %v", fingerprints)
+	}
+	if results[1].Fingerprints[0] != fingerprints[1] {
+		t.Error("Expected the same statement to have the same fingerprint in any file")
+	}
+}
+
+// Test cancellation and stopping early
+func TestPipelineCancellation(t *testing.T) {
+	jobs := make([]analyzer.AnalysisJob, 20)
+	for i := range jobs {
+		jobs[i] = analyzer.AnalysisJob{ID: fmt.Sprint(i), Query: "SELECT id FROM users"}
+	}
+	ca := analyzer.NewConcurrentAnalyzer(2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := ca.AnalyzeConcurrently(ctx, jobs)
+	if len(results) != len(jobs) {
+		t.Fatalf("Expected a result per job, got %d", len(results))
+	}
+	for _, result := range results {
+		if !errors.Is(result.Error, context.Canceled) {
+			t.Fatalf("Expected cancelled jobs, got %+v", result)
+		}
+	}
+
+	seen := 0
+	for range ca.AnalyzeSeq(context.Background(), func(yield func(analyzer.AnalysisJob) bool) {
+		for _, job := range jobs {
+			if !yield(job) {
+				return
+			}
+		}
+	}, analyzer.PipelineOptions{}) {
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	if seen != 3 {
+		t.Errorf("Expected to stop after 3 results, got %d", seen)
+	}
+}
+
+// Test that a job whose timeout expires while parsing ends with the
+// timeout, even between statements
+func TestPipelineTimeout(t *testing.T) {
+	query := strings.Repeat(";", 1<<20) + " SELECT id FROM users; SELECT id FROM orders"
+	ca := analyzer.NewConcurrentAnalyzer(1)
+
+	done := make(chan analyzer.AnalysisResult, 1)
+	go func() {
+		for result := range ca.AnalyzeSeq(context.Background(), slices.Values([]analyzer.AnalysisJob{{ID: "long", Query: query}}), analyzer.PipelineOptions{
+			Dialect: dialect.GetDialect("postgresql"),
+			Timeout: time.Millisecond,
+		}) {
+			done <- result
+		}
+	}()
+
+	select {
+	case result := <-done:
+		if !errors.Is(result.Error, context.DeadlineExceeded) {
+			t.Errorf("Expected the job to time out, got %v", result.Error)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected parsing to stop when the job times out")
+	}
+}
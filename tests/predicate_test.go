@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test that predicate rules report the column and operator they concern
+func TestPredicateRules(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	type finding struct {
+		rule, column, operator string
+	}
+	tests := []struct {
+		name       string
+		dialect    string
+		withSchema bool
+		sql        string
+		expected   []finding
+	}{
+		{
+			"function around a column",
+			"mysql", false,
+			"SELECT id FROM users WHERE LOWER(email) = 'a@b.c' AND age + 1 > 30",
+			[]finding{{"FUNCTION_IN_WHERE", "email", "="}, {"FUNCTION_IN_WHERE", "age", ">"}},
+		},
+		{
+			"function on an unindexed column with a schema",
+			"mysql", true,
+			"SELECT id FROM users WHERE LOWER(email) = 'a@b.c' AND age + 1 > 30",
+			[]finding{{"FUNCTION_IN_WHERE", "email", "="}},
+		},
+		{
+			"function in a join condition",
+			"postgresql", false,
+			"SELECT u.name FROM users u JOIN orders o ON CAST(o.user_id AS TEXT) = u.name",
+			[]finding{{"FUNCTION_IN_WHERE", "user_id", "="}},
+		},
+		{
+			"leading wildcard",
+			"postgresql", false,
+			"SELECT id FROM users WHERE name LIKE '%son' AND email LIKE 'a%'",
+			[]finding{{"LEADING_WILDCARD", "name", "LIKE"}},
+		},
+		{
+			"OR across columns",
+			"mysql", false,
+			"SELECT id FROM users WHERE (name = 'a' OR email = 'b') AND (age = 1 OR age = 2)",
+			[]finding{{"OR_ACROSS_COLUMNS", "name, email", "OR"}},
+		},
+		{
+			"NOT IN over a nullable subquery",
+			"mysql", true,
+			"SELECT id FROM products WHERE id NOT IN (SELECT age FROM users) AND id NOT IN (SELECT user_id FROM orders)",
+			[]finding{{"NOT_IN_NULL", "id", "NOT IN"}},
+		},
+		{
+			"NOT IN over a list with NULL",
+			"mysql", false,
+			"SELECT id FROM users WHERE age NOT IN (1, NULL)",
+			[]finding{{"NOT_IN_NULL", "age", "NOT IN"}},
+		},
+		{
+			"implicit conversion",
+			"mysql", true,
+			"SELECT id FROM users WHERE email = 42 AND age = '42'",
+			[]finding{{"IMPLICIT_CONVERSION", "email", "="}},
+		},
+		{
+			"Unicode literal on SQL Server",
+			"sqlserver", true,
+			"SELECT id FROM users WHERE email IN (N'a@b.c')",
+			[]finding{{"IMPLICIT_CONVERSION", "email", "IN"}},
+		},
+		{
+			"not equal on an indexed column",
+			"mysql", true,
+			"SELECT id FROM orders WHERE status <> 'paid' AND total != 0",
+			[]finding{{"NOT_EQUAL_INDEXED", "status", "<>"}},
+		},
+		{
+			"always-true OR",
+			"mysql", false,
+			"SELECT id FROM users WHERE name = 'a' OR 1 = 1",
+			[]finding{{"SQL_INJECTION_RISK", "", "OR"}},
+		},
+		{
+			"concatenated dynamic SQL",
+			"sqlserver", false,
+			"EXEC ('SELECT * FROM users WHERE name = ''' + @name + '''')",
+			[]finding{{"SQL_INJECTION_RISK", "@name", "+"}},
+		},
+	}
+
+	rules := map[string]bool{
+		"FUNCTION_IN_WHERE": true, "LEADING_WILDCARD": true, "OR_ACROSS_COLUMNS": true, "NOT_IN_NULL": true,
+		"IMPLICIT_CONVERSION": true, "NOT_EQUAL_INDEXED": true, "SQL_INJECTION_RISK": true,
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := analyzer.NewOptimizationEngine(dialect.GetDialect(tt.dialect))
+			if tt.withSchema {
+				engine.SetSchema(s)
+			}
+			var found []finding
+			for _, suggestion := range engine.AnalyzeOptimizations(parseWithDialect(t, tt.sql, tt.dialect)) {
+				if rules[suggestion.Rule] {
+					found = append(found, finding{suggestion.Rule, suggestion.ColumnName, suggestion.Operator})
+				}
+			}
+			if len(found) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, found)
+			}
+			for i := range found {
+				if found[i] != tt.expected[i] {
+					t.Errorf("Expected %v, got %v", tt.expected[i], found[i])
+				}
+			}
+		})
+	}
+}
+
+// Test the date range suggested for comparisons of a column's year
+func TestYearComparisonRewrite(t *testing.T) {
+	tests := []struct {
+		dialect  string
+		where    string
+		expected string
+	}{
+		{"mysql", "YEAR(created_at) = 2024", "created_at >= '2024-01-01' AND created_at < '2025-01-01'"},
+		{"mysql", "2024 < YEAR(created_at)", "created_at >= '2025-01-01'"},
+		{"postgresql", "EXTRACT(YEAR FROM created_at) <= 2023", "created_at < '2024-01-01'"},
+		{"sqlserver", "DATEPART(year, created_at) BETWEEN 2020 AND 2022", "created_at >= '20200101' AND created_at < '20230101'"},
+		{"oracle", "EXTRACT(YEAR FROM created_at) = 2024", "created_at >= DATE '2024-01-01' AND created_at < DATE '2025-01-01'"},
+	}
+
+	for _, tt := range tests {
+		d := dialect.GetDialect(tt.dialect)
+		stmt := parseWithDialect(t, "SELECT id FROM orders WHERE "+tt.where, tt.dialect)
+
+		var rewrite string
+		for _, suggestion := range analyzer.NewOptimizationEngine(d).AnalyzeOptimizations(stmt) {
+			if suggestion.Rule == "FUNCTION_IN_WHERE" {
+				rewrite = suggestion.FixSuggestion
+				if suggestion.ColumnName != "created_at" || !suggestion.AutoFixable {
+					t.Errorf("%s: unexpected suggestion %+v", tt.where, suggestion)
+				}
+			}
+		}
+		if rewrite != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.where, tt.expected, rewrite)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/privileges"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// collectPrivileges returns the requirements of a script as
+// "object PRIVILEGE(columns)"
+func collectPrivileges(t *testing.T, sql, dialectName string, s *schema.Schema) (*privileges.Report, []string) {
+	t.Helper()
+	d := dialect.GetDialect(dialectName)
+	stmts, err := parser.NewWithDialect(context.Background(), sql, d).ParseStatements()
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", sql, err)
+	}
+	collector := privileges.NewCollector(d, s)
+	for _, stmt := range stmts {
+		collector.Add(stmt)
+	}
+	report := collector.Report()
+
+	var requirements []string
+	for _, req := range report.Requirements {
+		requirements = append(requirements, fmt.Sprintf("%s %s(%s)", req.Object, req.Privilege, strings.Join(req.Columns, ",")))
+	}
+	return report, requirements
+}
+
+// Test the least privileges statements need, per object and column
+func TestPrivilegeRequirements(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		sql      string
+		expected []string
+	}{
+		{
+			"column reads",
+			"postgresql",
+			"SELECT u.name, COUNT(*) FROM users u JOIN orders o ON o.user_id = u.id WHERE o.status = 'paid' GROUP BY u.name",
+			[]string{"orders SELECT(user_id,status)", "users SELECT(id,name)"},
+		},
+		{
+			"star and COUNT(*)",
+			"postgresql",
+			"SELECT o.* FROM orders o JOIN users u ON u.id = o.user_id; SELECT COUNT(*) FROM sessions",
+			[]string{"orders SELECT()", "sessions SELECT()", "users SELECT(id)"},
+		},
+		{
+			"UPDATE assigns and reads columns",
+			"postgresql",
+			"UPDATE orders SET status = 'shipped', total = total * 2 WHERE id = 5 RETURNING shipped_at",
+			[]string{"orders SELECT(total,id,shipped_at)", "orders UPDATE(status,total)"},
+		},
+		{
+			"INSERT ... SELECT and upserts",
+			"mysql",
+			"INSERT INTO audit (id, msg) SELECT id, name FROM users; INSERT INTO counters (k, n) VALUES ('a', 1) ON DUPLICATE KEY UPDATE n = n + 1",
+			[]string{"audit INSERT(id,msg)", "counters SELECT(n)", "counters INSERT(k,n)", "counters UPDATE(n)", "users SELECT(id,name)"},
+		},
+		{
+			"DELETE with a subquery",
+			"mysql",
+			"DELETE FROM sessions WHERE user_id NOT IN (SELECT id FROM users WHERE active = 0)",
+			[]string{"sessions SELECT(user_id)", "sessions DELETE()", "users SELECT(id,active)"},
+		},
+		{
+			"MERGE",
+			"sqlserver",
+			"MERGE INTO inventory AS t USING shipments AS s ON t.product_id = s.product_id WHEN MATCHED THEN UPDATE SET qty = t.qty + s.qty WHEN NOT MATCHED THEN INSERT (product_id, qty) VALUES (s.product_id, s.qty);",
+			[]string{"inventory SELECT(product_id,qty)", "inventory INSERT(product_id,qty)", "inventory UPDATE(qty)", "shipments SELECT(product_id,qty)"},
+		},
+		{
+			"CTEs are not objects",
+			"postgresql",
+			"WITH recent AS (SELECT user_id FROM orders WHERE total > 10) SELECT r.user_id FROM recent r",
+			[]string{"orders SELECT(user_id,total)"},
+		},
+		{
+			"DDL",
+			"postgresql",
+			"CREATE TABLE payments (id INT, order_id INT REFERENCES orders(id)); CREATE VIEW big AS SELECT id FROM orders WHERE total > 100; CREATE INDEX idx ON users (email); ALTER TABLE users ADD COLUMN age INT; DROP TABLE legacy",
+			[]string{"big CREATE()", "legacy DROP()", "orders SELECT(id,total)", "orders REFERENCES(id)", "payments CREATE()", "users INDEX()", "users ALTER()"},
+		},
+		{
+			"procedure body with parameters",
+			"mysql",
+			"CREATE PROCEDURE purge(IN p_user INT) BEGIN IF EXISTS (SELECT 1 FROM users WHERE id = p_user) THEN UPDATE orders SET status = 'x' WHERE user_id = p_user; END IF; DELETE FROM logs; END",
+			[]string{"logs DELETE()", "orders SELECT(user_id)", "orders UPDATE(status)", "purge CREATE()", "users SELECT(id)"},
+		},
+		{
+			"trigger body",
+			"mysql",
+			"CREATE TRIGGER audit_orders AFTER INSERT ON orders FOR EACH ROW BEGIN INSERT INTO audit (id) VALUES (NEW.id); END",
+			[]string{"audit INSERT(id)", "orders TRIGGER()"},
+		},
+		{
+			"EXEC",
+			"sqlserver",
+			"EXEC dbo.refresh_totals @day = 1",
+			[]string{"dbo.refresh_totals EXECUTE()"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, requirements := collectPrivileges(t, tt.sql, tt.dialect, nil)
+			if strings.Join(requirements, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(requirements, "\n"))
+			}
+		})
+	}
+}
+
+// Test that a schema places unqualified columns of joins and subqueries
+func TestPrivilegeColumnResolution(t *testing.T) {
+	sql := "SELECT name, total FROM users, orders WHERE age > (SELECT AVG(price) FROM products WHERE category = status)"
+
+	report, requirements := collectPrivileges(t, sql, "mysql", nil)
+	if len(report.Warnings) == 0 {
+		t.Error("Expected a warning for unqualified columns without a schema")
+	}
+	if requirements[0] != "orders SELECT()" || requirements[2] != "users SELECT()" {
+		t.Errorf("Expected SELECT on whole tables without a schema, got %v", requirements)
+	}
+
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	report, requirements = collectPrivileges(t, sql, "mysql", s)
+	expected := []string{"orders SELECT(total,status)", "products SELECT(price,category)", "users SELECT(name,age)"}
+	if strings.Join(requirements, "\n") != strings.Join(expected, "\n") || len(report.Warnings) != 0 {
+		t.Errorf("Expected %v without warnings, got %v and %v", expected, requirements, report.Warnings)
+	}
+}
+
+// Test the GRANT statements of each dialect
+func TestPrivilegeGrants(t *testing.T) {
+	sql := "UPDATE orders SET status = 'x' WHERE id = 1; DELETE FROM sessions; DROP TABLE legacy; CREATE VIEW v AS SELECT id FROM users"
+
+	tests := []struct {
+		dialect  string
+		expected []string
+	}{
+		{"postgresql", []string{
+			"-- DROP on table legacy requires ownership",
+			"GRANT SELECT (id) ON orders TO app;",
+			"GRANT UPDATE (status) ON orders TO app;",
+			"GRANT DELETE ON sessions TO app;",
+			"GRANT SELECT (id) ON users TO app;",
+			"GRANT CREATE ON SCHEMA public TO app;",
+		}},
+		{"mysql", []string{
+			"GRANT DROP ON legacy TO app;",
+			"GRANT SELECT (id) ON orders TO app;",
+			"GRANT UPDATE (status) ON orders TO app;",
+			"GRANT DELETE ON sessions TO app;",
+			"GRANT SELECT (id) ON users TO app;",
+			"GRANT CREATE VIEW ON v TO app;",
+		}},
+		{"sqlserver", []string{
+			"GRANT CONTROL ON legacy TO app;",
+			"GRANT SELECT ON orders (id) TO app;",
+			"GRANT UPDATE ON orders (status) TO app;",
+			"GRANT DELETE ON sessions TO app;",
+			"GRANT SELECT ON users (id) TO app;",
+			"GRANT CREATE VIEW TO app;",
+			"GRANT ALTER ON SCHEMA::dbo TO app;",
+		}},
+		{"oracle", []string{
+			"-- DROP on table legacy requires ownership",
+			"GRANT SELECT ON orders TO app;",
+			"GRANT UPDATE (status) ON orders TO app;",
+			"GRANT DELETE ON sessions TO app;",
+			"GRANT SELECT ON users TO app;",
+			"GRANT CREATE VIEW TO app;",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			report, _ := collectPrivileges(t, sql, tt.dialect, nil)
+			grants, err := report.Grants(dialect.GetDialect(tt.dialect), "app")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Join(grants, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(grants, "\n"))
+			}
+		})
+	}
+
+	report, _ := collectPrivileges(t, "SELECT id FROM users", "sqlite", nil)
+	if _, err := report.Grants(dialect.GetDialect("sqlite"), "app"); err == nil {
+		t.Error("Expected an error for SQLite, which has no GRANT")
+	}
+}
@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+// Test parsing of two-, three- and four-part table names
+func TestQualifiedTableNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		sql     string
+		server  string
+		catalog string
+		schema  string
+		table   string
+		str     string
+	}{
+		{"Schema qualified", "postgresql", "SELECT id FROM sales.orders", "", "", "sales", "orders", "sales.orders"},
+		{"Three-part", "sqlserver", "SELECT id FROM shop.dbo.orders", "", "shop", "dbo", "orders", "shop.dbo.orders"},
+		{"Four-part", "sqlserver", "SELECT id FROM srv.shop.dbo.orders", "srv", "shop", "dbo", "orders", "srv.shop.dbo.orders"},
+		{"Default schema shorthand", "sqlserver", "SELECT id FROM shop..orders", "", "shop", "", "orders", "shop..orders"},
+		{"Project dataset table", "mysql", "SELECT id FROM analytics.events.daily", "", "analytics", "events", "daily", "analytics.events.daily"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewWithDialect(context.Background(), tt.sql, dialect.GetDialect(tt.dialect))
+			stmt, err := p.ParseStatement()
+			if err != nil {
+				t.Fatalf("Failed to parse SQL: %v", err)
+			}
+
+			ref := stmt.(*parser.SelectStatement).From.Tables[0]
+			if ref.Server != tt.server || ref.Catalog != tt.catalog || ref.Schema != tt.schema || ref.Name != tt.table {
+				t.Errorf("Unexpected parts: server=%q catalog=%q schema=%q name=%q", ref.Server, ref.Catalog, ref.Schema, ref.Name)
+			}
+			if ref.String() != tt.str {
+				t.Errorf("Expected %q, got %q", tt.str, ref.String())
+			}
+		})
+	}
+
+	// More than four parts is an error
+	p := parser.NewWithDialect(context.Background(), "SELECT id FROM a.b.c.d.e", dialect.GetDialect("sqlserver"))
+	if _, err := p.ParseStatement(); err == nil {
+		t.Error("Expected error for five-part name")
+	}
+}
+
+// Test parsing of multi-part column references
+func TestQualifiedColumnReferences(t *testing.T) {
+	p := parser.NewWithDialect(context.Background(),
+		"SELECT sales.orders.id, shop.sales.orders.total, sales.orders.* FROM sales.orders",
+		dialect.GetDialect("postgresql"))
+	stmt, err := p.ParseStatement()
+	if err != nil {
+		t.Fatalf("Failed to parse SQL: %v", err)
+	}
+	columns := stmt.(*parser.SelectStatement).Columns
+
+	id := columns[0].(*parser.ColumnReference)
+	if id.Schema != "sales" || id.Table != "orders" || id.Column != "id" {
+		t.Errorf("Unexpected column: %+v", id)
+	}
+	total := columns[1].(*parser.ColumnReference)
+	if total.String() != "shop.sales.orders.total" {
+		t.Errorf("Expected shop.sales.orders.total, got %s", total.String())
+	}
+	star := columns[2].(*parser.StarExpression)
+	if star.String() != "sales.orders.*" {
+		t.Errorf("Expected sales.orders.*, got %s", star.String())
+	}
+}
+
+// Test that tables with the same name in different schemas coexist
+func TestSchemaQualifiedTables(t *testing.T) {
+	s := schema.NewSchema("shop")
+	for _, ns := range []string{"public", "archive"} {
+		table := schema.NewTable("orders")
+		table.Schema = ns
+		table.AddColumn(&schema.Column{Name: "id", DataType: &schema.DataType{Name: "INT"}})
+		s.AddTable(table)
+	}
+
+	if len(s.Tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(s.Tables))
+	}
+	archived, ok := s.GetTable("archive.orders")
+	if !ok || archived.Schema != "archive" {
+		t.Error("Expected archive.orders")
+	}
+	if _, ok := s.GetQualifiedTable("missing", "orders"); ok {
+		t.Error("Expected missing.orders not to resolve")
+	}
+	// Unqualified lookups pick the first schema by name
+	if table, _ := s.GetTable("orders"); table.Schema != "archive" {
+		t.Errorf("Expected archive.orders for unqualified lookup, got %s", table.Schema)
+	}
+}
+
+func newResolverTestSchemas() (*schema.Schema, *schema.Schema) {
+	shop := schema.NewSchema("shop")
+	for _, ns := range []string{"dbo", "sales"} {
+		orders := schema.NewTable("orders")
+		orders.Schema = ns
+		orders.AddColumn(&schema.Column{Name: "id", DataType: &schema.DataType{Name: "INT"}})
+		orders.AddColumn(&schema.Column{Name: "total", DataType: &schema.DataType{Name: "DECIMAL", Precision: 10, Scale: 2}})
+		if ns == "sales" {
+			orders.AddColumn(&schema.Column{Name: "region", DataType: &schema.DataType{Name: "VARCHAR", Length: 20}})
+		}
+		shop.AddTable(orders)
+	}
+
+	crm := schema.NewSchema("crm")
+	customers := schema.NewTable("customers")
+	customers.Schema = "dbo"
+	customers.AddColumn(&schema.Column{Name: "id", DataType: &schema.DataType{Name: "INT"}})
+	customers.AddColumn(&schema.Column{Name: "name", DataType: &schema.DataType{Name: "VARCHAR", Length: 100}})
+	crm.AddTable(customers)
+
+	return shop, crm
+}
+
+// Test name resolution across databases, schemas and the search path
+func TestResolver(t *testing.T) {
+	shop, crm := newResolverTestSchemas()
+	r := schema.NewResolver(dialect.GetDialect("sqlserver"), shop, crm)
+
+	tests := []struct {
+		catalog, namespace, name string
+		wantSchema               string
+		wantFound                bool
+	}{
+		{"", "", "orders", "dbo", true},        // default schema
+		{"", "sales", "orders", "sales", true}, // schema.table
+		{"shop", "sales", "orders", "sales", true},
+		{"shop", "", "orders", "dbo", true},      // shop..orders
+		{"crm", "dbo", "customers", "dbo", true}, // other database
+		{"", "", "customers", "dbo", true},
+		{"shop", "dbo", "customers", "", false}, // wrong database
+		{"", "hr", "orders", "", false},
+		{"", "crm", "customers", "dbo", true}, // database.table (MySQL style)
+	}
+
+	for _, tt := range tests {
+		table, ok := r.Resolve(tt.catalog, tt.namespace, tt.name)
+		if ok != tt.wantFound {
+			t.Errorf("Resolve(%q, %q, %q): found=%v, want %v", tt.catalog, tt.namespace, tt.name, ok, tt.wantFound)
+			continue
+		}
+		if ok && table.Schema != tt.wantSchema {
+			t.Errorf("Resolve(%q, %q, %q): schema=%q, want %q", tt.catalog, tt.namespace, tt.name, table.Schema, tt.wantSchema)
+		}
+	}
+
+	// Changing the search path changes unqualified resolution
+	r.SetSearchPath("sales", "dbo")
+	if table, _ := r.Resolve("", "", "orders"); table.Schema != "sales" {
+		t.Errorf("Expected sales.orders via search path, got %s", table.Schema)
+	}
+}
+
+// Test that unqualified names off the search path only match tables declared
+// without a schema
+func TestResolverSearchPathFallback(t *testing.T) {
+	db := schema.NewSchema("shop")
+	for _, def := range []struct{ namespace, name string }{
+		{"audit", "events"},
+		{"", "settings"},
+		{"dbo", "orders"},
+	} {
+		table := schema.NewTable(def.name)
+		table.Schema = def.namespace
+		table.AddColumn(&schema.Column{Name: "id", DataType: &schema.DataType{Name: "INT"}})
+		db.AddTable(table)
+	}
+	r := schema.NewResolver(dialect.GetDialect("sqlserver"), db)
+
+	if _, ok := r.Resolve("", "", "events"); ok {
+		t.Error("Expected events, which only exists in audit, not to resolve off the search path")
+	}
+	if _, ok := r.Resolve("", "audit", "events"); !ok {
+		t.Error("Expected audit.events to resolve")
+	}
+	if table, ok := r.Resolve("", "", "settings"); !ok || table.Schema != "" {
+		t.Error("Expected settings, declared without a schema, to resolve")
+	}
+	if table, ok := r.Resolve("", "", "orders"); !ok || table.Schema != "dbo" {
+		t.Error("Expected dbo.orders via the search path")
+	}
+
+	// Without a search path, unqualified names match any schema
+	if _, ok := schema.NewResolver(nil, db).Resolve("", "", "events"); !ok {
+		t.Error("Expected events to resolve without a search path")
+	}
+}
+
+// Test that each dialect reports its default schema
+func TestDialectDefaultSchema(t *testing.T) {
+	expected := map[string]string{
+		"postgresql": "public",
+		"sqlserver":  "dbo",
+		"sqlite":     "main",
+		"mysql":      "",
+		"oracle":     "",
+	}
+	for name, want := range expected {
+		if got := dialect.GetDialect(name).DefaultSchema(); got != want {
+			t.Errorf("%s: expected default schema %q, got %q", name, want, got)
+		}
+	}
+}
+
+// Test validation of qualified names and aliases across several schemas
+func TestValidateQualifiedNames(t *testing.T) {
+	shop, crm := newResolverTestSchemas()
+	validator := schema.NewValidatorWithResolver(schema.NewResolver(dialect.GetDialect("sqlserver"), shop, crm))
+
+	tests := []struct {
+		name      string
+		sql       string
+		errorType string
+	}{
+		{"Three-part names", "SELECT o.total FROM shop.sales.orders o WHERE o.region = 'EU'", ""},
+		{"Cross-database join", "SELECT c.name, o.total FROM shop.dbo.orders o JOIN crm.dbo.customers c ON c.id = o.id", ""},
+		{"Schema-qualified column", "SELECT sales.orders.region FROM sales.orders", ""},
+		{"Column from the other schema", "SELECT o.region FROM dbo.orders o", "COLUMN_NOT_FOUND"},
+		{"Unknown database", "SELECT id FROM hr.dbo.orders", "TABLE_NOT_FOUND"},
+		{"Unknown alias", "SELECT x.id FROM dbo.orders o", "TABLE_NOT_FOUND"},
+		{"Unqualified column from join", "SELECT name FROM orders o JOIN crm.dbo.customers c ON c.id = o.id", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := parser.NewWithDialect(context.Background(), tt.sql, dialect.GetDialect("sqlserver"))
+			stmt, err := p.ParseStatement()
+			if err != nil {
+				t.Fatalf("Failed to parse SQL: %v", err)
+			}
+
+			errors := validator.ValidateStatement(stmt)
+			if tt.errorType == "" {
+				if len(errors) > 0 {
+					t.Errorf("Expected no validation errors, got: %v", errors)
+				}
+				return
+			}
+			if len(errors) == 0 {
+				t.Fatalf("Expected %s, got no errors", tt.errorType)
+			}
+			if errors[0].Type != tt.errorType {
+				t.Errorf("Expected error type '%s', got '%s'", tt.errorType, errors[0].Type)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/lexer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+)
+
+// Test that every row-limiting form parses into a RowLimit
+func TestRowLimitForms(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		sql     string
+		syntax  dialect.LimitSyntax
+		count   string
+		offset  string
+		percent bool
+		ties    bool
+	}{
+		{"Limit", "postgresql", "SELECT id FROM users ORDER BY id LIMIT 10", dialect.LimitSyntaxStandard, "10", "", false, false},
+		{"Limit offset", "postgresql", "SELECT id FROM users ORDER BY id LIMIT 10 OFFSET 20", dialect.LimitSyntaxStandard, "10", "20", false, false},
+		{"Offset limit", "postgresql", "SELECT id FROM users ORDER BY id OFFSET 20 LIMIT 10", dialect.LimitSyntaxStandard, "10", "20", false, false},
+		{"Limit all", "postgresql", "SELECT id FROM users LIMIT ALL OFFSET 5", dialect.LimitSyntaxStandard, "", "5", false, false},
+		{"MySQL offset, count", "mysql", "SELECT id FROM users ORDER BY id LIMIT 10, 20", dialect.LimitSyntaxStandard, "20", "10", false, false},
+		{"Question mark", "mysql", "SELECT id FROM users ORDER BY id LIMIT ?", dialect.LimitSyntaxStandard, "?", "", false, false},
+		{"Positional parameters", "postgresql", "SELECT id FROM users ORDER BY id LIMIT $1 OFFSET $2", dialect.LimitSyntaxStandard, "$1", "$2", false, false},
+		{"Offset fetch", "sqlserver", "SELECT id FROM users ORDER BY id OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY", dialect.LimitSyntaxFetch, "10", "5", false, false},
+		{"Fetch with ties", "postgresql", "SELECT id FROM users ORDER BY id FETCH FIRST 5 ROWS WITH TIES", dialect.LimitSyntaxFetch, "5", "", false, true},
+		{"Fetch first row", "postgresql", "SELECT id FROM users ORDER BY id FETCH FIRST ROW ONLY", dialect.LimitSyntaxFetch, "1", "", false, false},
+		{"Offset row fetch next row", "oracle", "SELECT id FROM users ORDER BY id OFFSET 1 ROW FETCH NEXT ROW ONLY", dialect.LimitSyntaxFetch, "1", "1", false, false},
+		{"Fetch percent", "oracle", "SELECT id FROM users ORDER BY id FETCH FIRST 10 PERCENT ROWS ONLY", dialect.LimitSyntaxFetch, "10", "", true, false},
+		{"Top", "sqlserver", "SELECT TOP 10 WITH TIES id FROM users ORDER BY id", dialect.LimitSyntaxSQLServer, "10", "", false, true},
+		{"Top percent", "sqlserver", "SELECT TOP (@n) PERCENT id FROM users", dialect.LimitSyntaxSQLServer, "@n", "", true, false},
+		{"Rownum", "oracle", "SELECT id FROM users WHERE ROWNUM <= 10", dialect.LimitSyntaxOracle, "10", "", false, false},
+		{"Rownum less than", "oracle", "SELECT id FROM users WHERE ROWNUM < 11", dialect.LimitSyntaxOracle, "10", "", false, false},
+		{"Rownum with filter", "oracle", "SELECT id FROM users WHERE active = 1 AND ROWNUM <= :n", dialect.LimitSyntaxOracle, ":n", "", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit := parseWithDialect(t, tt.sql, tt.dialect).(*parser.SelectStatement).Limit
+			if limit == nil {
+				t.Fatal("Expected a row limit")
+			}
+			if limit.Syntax != tt.syntax {
+				t.Errorf("Expected syntax %s, got %s", tt.syntax, limit.Syntax)
+			}
+
+			count, offset := "", ""
+			if limit.Count != nil {
+				count = limit.Count.String()
+			}
+			if limit.Offset != nil {
+				offset = limit.Offset.String()
+			}
+			if count != tt.count || offset != tt.offset {
+				t.Errorf("Expected count %q offset %q, got count %q offset %q", tt.count, tt.offset, count, offset)
+			}
+			if limit.Percent != tt.percent || limit.WithTies != tt.ties {
+				t.Errorf("Expected percent=%v ties=%v, got %+v", tt.percent, tt.ties, limit)
+			}
+		})
+	}
+
+	if n, ok := parseWithDialect(t, "SELECT id FROM users LIMIT 10, 20", "mysql").(*parser.SelectStatement).Limit.OffsetValue(); !ok || n != 10 {
+		t.Errorf("Expected OffsetValue 10, got %d (%v)", n, ok)
+	}
+
+	update := parseWithDialect(t, "UPDATE TOP (5) users SET active = 0", "sqlserver").(*parser.UpdateStatement)
+	if n, ok := update.Limit.CountValue(); !ok || n != 5 || update.Limit.Syntax != dialect.LimitSyntaxSQLServer {
+		t.Errorf("Expected UPDATE TOP (5), got %+v", update.Limit)
+	}
+}
+
+// Test row-limiting clauses that must be rejected
+func TestRowLimitErrors(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+	}{
+		{"sqlserver", "SELECT TOP 5 id FROM users LIMIT 3"},
+		{"postgresql", "SELECT id FROM users LIMIT"},
+		{"postgresql", "SELECT id FROM users FETCH FIRST 5 ROWS"},
+	}
+
+	for _, tt := range tests {
+		p := parser.NewWithDialect(context.Background(), tt.sql, dialect.GetDialect(tt.dialect))
+		if _, err := p.ParseStatement(); err == nil {
+			t.Errorf("%s: expected error for %q", tt.dialect, tt.sql)
+		}
+	}
+}
+
+// Test lexing of bind parameters
+func TestLexerParameters(t *testing.T) {
+	l := lexer.NewWithDialect("? $1 @limit @@ROWCOUNT :name", dialect.GetDialect("postgresql"))
+	for _, want := range []string{"?", "$1", "@limit", "@@ROWCOUNT", ":name"} {
+		tok := l.NextToken()
+		if tok.Type != lexer.PARAM || tok.Literal != want {
+			t.Errorf("Expected PARAM %q, got %s %q", want, tok.Type, tok.Literal)
+		}
+	}
+}
+
+// Test pagination analysis and the dialect-specific row-limit suggestions
+func TestAnalyzeRowLimit(t *testing.T) {
+	result := analyzer.New().Analyze(parseWithDialect(t, "SELECT id FROM users ORDER BY id OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY", "sqlserver"))
+	p := result.Pagination
+	if p == nil || p.Syntax != "FETCH" || p.Count != "10" || p.Offset != "5" {
+		t.Errorf("Unexpected pagination info: %+v", p)
+	}
+
+	engine := analyzer.NewOptimizationEngine(dialect.GetDialect("sqlserver"))
+	hasTopVsLimit := func(sql string) bool {
+		for _, s := range engine.AnalyzeOptimizations(parseWithDialect(t, sql, "sqlserver")) {
+			if s.Type == "SQLSERVER_TOP_VS_LIMIT" {
+				return true
+			}
+		}
+		return false
+	}
+	if hasTopVsLimit("SELECT TOP 10 id FROM users") {
+		t.Error("TOP should not trigger SQLSERVER_TOP_VS_LIMIT")
+	}
+	if !hasTopVsLimit("SELECT id FROM users LIMIT 10") {
+		t.Error("Expected SQLSERVER_TOP_VS_LIMIT for LIMIT")
+	}
+}
@@ -0,0 +1,266 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+func loadExportTestSchema(t *testing.T) *schema.Schema {
+	t.Helper()
+
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	return s
+}
+
+// Test that JSON and YAML exports load back into an equivalent schema
+func TestSchemaExportRoundTrip(t *testing.T) {
+	original := loadExportTestSchema(t)
+	exporter := schema.NewExporter(dialect.GetDialect("postgresql"))
+	loader := schema.NewSchemaLoader()
+
+	tests := []struct {
+		format schema.ExportFormat
+		load   func([]byte) (*schema.Schema, error)
+	}{
+		{schema.ExportFormatJSON, loader.LoadFromJSON},
+		{schema.ExportFormatYAML, loader.LoadFromYAML},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exporter.Export(original, tt.format, &buf); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			loaded, err := tt.load(buf.Bytes())
+			if err != nil {
+				t.Fatalf("Failed to load exported schema: %v", err)
+			}
+
+			if loaded.Name != original.Name {
+				t.Errorf("Expected schema name %q, got %q", original.Name, loaded.Name)
+			}
+			if len(loaded.Tables) != len(original.Tables) {
+				t.Fatalf("Expected %d tables, got %d", len(original.Tables), len(loaded.Tables))
+			}
+
+			orders, ok := loaded.GetTable("orders")
+			if !ok {
+				t.Fatal("Expected table 'orders' after round trip")
+			}
+			total, _ := orders.GetColumn("total")
+			if total.DataType.String() != "DECIMAL(10,2)" {
+				t.Errorf("Expected DECIMAL(10,2), got %s", total.DataType)
+			}
+			userID, _ := orders.GetColumn("user_id")
+			if userID.ForeignKey == nil || userID.ForeignKey.Table != "users" {
+				t.Error("Expected user_id foreign key to survive round trip")
+			}
+			if orders.Rows != 250000 || userID.Distinct != 8000 {
+				t.Errorf("Expected statistics to survive round trip, got %d rows and %d user ids", orders.Rows, userID.Distinct)
+			}
+
+			// Column order is preserved
+			columns := orders.OrderedColumns()
+			if columns[0].Name != "id" || columns[1].Name != "user_id" {
+				t.Errorf("Expected column order id, user_id; got %s, %s", columns[0].Name, columns[1].Name)
+			}
+		})
+	}
+}
+
+// Test DDL export in dialect-specific quoting
+func TestSchemaExportDDL(t *testing.T) {
+	s := loadExportTestSchema(t)
+
+	var buf bytes.Buffer
+	if err := schema.NewExporter(dialect.GetDialect("mysql")).ExportDDL(s, &buf); err != nil {
+		t.Fatalf("ExportDDL failed: %v", err)
+	}
+	ddl := buf.String()
+
+	expected := []string{
+		"CREATE TABLE `users` (",
+		"`email` VARCHAR(255) NOT NULL UNIQUE",
+		"`created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP",
+		"`status` VARCHAR(20) NOT NULL DEFAULT 'pending'",
+		"FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)",
+		"CREATE UNIQUE INDEX `idx_users_email` ON `users` (`email`);",
+	}
+	for _, want := range expected {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("Expected DDL to contain %q\n%s", want, ddl)
+		}
+	}
+
+	// Referenced tables are created before the tables that reference them
+	if strings.Index(ddl, "CREATE TABLE `users`") > strings.Index(ddl, "CREATE TABLE `orders`") {
+		t.Error("Expected users to be created before orders")
+	}
+}
+
+// Test Graphviz and Mermaid ER diagram export
+func TestSchemaExportDiagrams(t *testing.T) {
+	s := loadExportTestSchema(t)
+	exporter := schema.NewExporter(nil)
+
+	var dot bytes.Buffer
+	if err := exporter.ExportDOT(s, &dot); err != nil {
+		t.Fatalf("ExportDOT failed: %v", err)
+	}
+	if !strings.HasPrefix(dot.String(), `digraph "test_db" {`) {
+		t.Errorf("Unexpected DOT header: %s", dot.String())
+	}
+	if !strings.Contains(dot.String(), `"orders" -> "users"`) {
+		t.Error("Expected orders -> users edge in DOT output")
+	}
+
+	var mermaid bytes.Buffer
+	if err := exporter.ExportMermaid(s, &mermaid); err != nil {
+		t.Fatalf("ExportMermaid failed: %v", err)
+	}
+	for _, want := range []string{"erDiagram", "INT id PK", "INT user_id FK", `orders }o--|| users : "user_id"`} {
+		if !strings.Contains(mermaid.String(), want) {
+			t.Errorf("Expected Mermaid output to contain %q\n%s", want, mermaid.String())
+		}
+	}
+}
+
+// Test that every dialect has catalog queries
+func TestIntrospectionQueries(t *testing.T) {
+	for _, name := range []string{"mysql", "postgresql", "sqlserver", "sqlite", "oracle"} {
+		queries, err := schema.IntrospectionQueries(dialect.GetDialect(name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(queries) != 2 {
+			t.Fatalf("%s: expected 2 queries, got %d", name, len(queries))
+		}
+		if queries[0].Name != schema.CatalogColumnsQuery || queries[1].Name != schema.CatalogIndexesQuery {
+			t.Errorf("%s: unexpected query names %s, %s", name, queries[0].Name, queries[1].Name)
+		}
+		for _, field := range []string{"table_name", "column_name", "data_type", "fk_table"} {
+			if !strings.Contains(queries[0].SQL, field) {
+				t.Errorf("%s: columns query missing %s", name, field)
+			}
+		}
+	}
+}
+
+// Test loading saved catalog query results
+func TestLoadFromCatalogCSV(t *testing.T) {
+	columns := `table_schema,table_name,column_name,ordinal_position,data_type,length,numeric_precision,numeric_scale,nullable,primary_key,unique_key,fk_table,fk_column,column_default
+public,users,id,1,INTEGER,NULL,32,0,0,1,0,NULL,NULL,NULL
+public,users,email,2,CHARACTER VARYING,255,NULL,NULL,0,0,1,NULL,NULL,NULL
+public,orders,id,1,INTEGER,NULL,32,0,0,1,0,NULL,NULL,NULL
+public,orders,user_id,2,INTEGER,NULL,32,0,1,0,0,users,id,NULL
+public,orders,total,3,NUMERIC,NULL,10,2,0,0,0,NULL,NULL,0
+`
+	indexes := `table_schema,table_name,index_name,column_name,column_position,is_unique
+public,orders,idx_orders_user,user_id,1,0
+public,orders,idx_orders_user,total,2,0
+`
+
+	s, err := schema.NewSchemaLoader().LoadFromCatalogCSV("shop", strings.NewReader(columns), strings.NewReader(indexes))
+	if err != nil {
+		t.Fatalf("LoadFromCatalogCSV failed: %v", err)
+	}
+
+	users, ok := s.GetTable("users")
+	if !ok {
+		t.Fatal("Expected table 'users'")
+	}
+	if users.Schema != "public" {
+		t.Errorf("Expected schema 'public', got %q", users.Schema)
+	}
+	email, _ := users.GetColumn("email")
+	if email.DataType.String() != "VARCHAR(255)" || !email.IsUnique {
+		t.Errorf("Unexpected email column: %s unique=%v", email.DataType, email.IsUnique)
+	}
+
+	orders, _ := s.GetTable("orders")
+	userID, _ := orders.GetColumn("user_id")
+	if !userID.IsForeignKey || userID.ForeignKey.Table != "users" || !userID.DataType.Nullable {
+		t.Error("Expected nullable user_id foreign key to users")
+	}
+	total, _ := orders.GetColumn("total")
+	if total.DataType.String() != "NUMERIC(10,2)" {
+		t.Errorf("Expected NUMERIC(10,2), got %s", total.DataType)
+	}
+
+	idx, ok := orders.GetIndex("idx_orders_user")
+	if !ok || len(idx.Columns) != 2 || idx.Columns[0] != "user_id" {
+		t.Errorf("Unexpected index: %+v", idx)
+	}
+
+	if err := s.Validate(); err != nil {
+		t.Errorf("Expected loaded catalog schema to validate: %v", err)
+	}
+}
+
+// Test that catalog defaults, already written in SQL, export unchanged
+func TestCatalogDefaultsExport(t *testing.T) {
+	columns := []byte(`[
+		{"TABLE_NAME": "accounts", "COLUMN_NAME": "status", "DATA_TYPE": "character varying", "LENGTH": 20, "NULLABLE": 0, "COLUMN_DEFAULT": "'active'::character varying"},
+		{"TABLE_NAME": "accounts", "COLUMN_NAME": "balance", "DATA_TYPE": "integer", "NULLABLE": 0, "COLUMN_DEFAULT": "0"},
+		{"TABLE_NAME": "accounts", "COLUMN_NAME": "locked", "DATA_TYPE": "boolean", "NULLABLE": 0, "COLUMN_DEFAULT": "false"}
+	]`)
+
+	s, err := schema.NewSchemaLoader().LoadFromCatalogJSON("bank", columns, nil)
+	if err != nil {
+		t.Fatalf("LoadFromCatalogJSON failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := schema.NewExporter(dialect.GetDialect("postgresql")).ExportDDL(s, &buf); err != nil {
+		t.Fatalf("ExportDDL failed: %v", err)
+	}
+	ddl := buf.String()
+
+	for _, want := range []string{
+		"DEFAULT 'active'::character varying",
+		"DEFAULT 0",
+		"DEFAULT false",
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("Expected DDL to contain %q\n%s", want, ddl)
+		}
+	}
+	if strings.Contains(ddl, "''") {
+		t.Errorf("Expected catalog defaults not to be quoted again\n%s", ddl)
+	}
+}
+
+// Test loading catalog query results saved as JSON
+func TestLoadFromCatalogJSON(t *testing.T) {
+	columns := []byte(`[
+		{"TABLE_NAME": "products", "COLUMN_NAME": "id", "DATA_TYPE": "NUMBER", "NUMERIC_PRECISION": 10, "NUMERIC_SCALE": 0, "NULLABLE": 0, "PRIMARY_KEY": 1},
+		{"TABLE_NAME": "products", "COLUMN_NAME": "name", "DATA_TYPE": "VARCHAR2", "LENGTH": 100, "NULLABLE": 1, "PRIMARY_KEY": 0}
+	]`)
+
+	s, err := schema.NewSchemaLoader().LoadFromCatalogJSON("inventory", columns, nil)
+	if err != nil {
+		t.Fatalf("LoadFromCatalogJSON failed: %v", err)
+	}
+
+	products, ok := s.GetTable("products")
+	if !ok {
+		t.Fatal("Expected table 'products'")
+	}
+	name, _ := products.GetColumn("name")
+	if name.DataType.String() != "VARCHAR(100)" {
+		t.Errorf("Expected VARCHAR(100), got %s", name.DataType)
+	}
+	id, _ := products.GetColumn("id")
+	if !id.IsPrimaryKey {
+		t.Error("Expected id to be the primary key")
+	}
+}
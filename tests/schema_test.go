@@ -542,3 +542,21 @@ func TestDataTypeCompatibility(t *testing.T) {
 		}
 	}
 }
+
+// Test that casts and catalogs spell types the same way
+func TestNormalizeTypeName(t *testing.T) {
+	tests := map[string]string{
+		"integer":                  "INT",
+		"int4":                     "INT",
+		"character  varying":       "VARCHAR",
+		"timestamp with time zone": "TIMESTAMP",
+		"TIME WITHOUT TIME ZONE":   "TIME",
+		"unsigned":                 "BIGINT",
+		"text":                     "TEXT",
+	}
+	for spelling, want := range tests {
+		if got := schema.NormalizeTypeName(spelling); got != want {
+			t.Errorf("NormalizeTypeName(%q) = %q, want %q", spelling, got, want)
+		}
+	}
+}
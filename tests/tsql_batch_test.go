@@ -0,0 +1,216 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+const tsqlScript = `USE [Sales]
+GO
+SET NOCOUNT ON;
+DECLARE @id INT = 5, @name NVARCHAR(50), @body NVARCHAR(MAX)
+DECLARE @t TABLE (id INT PRIMARY KEY, name NVARCHAR(50))
+CREATE TABLE #orders (id INT, total DECIMAL(10,2))
+SELECT id, total INTO ##snapshot FROM orders WHERE user_id = @id
+INSERT INTO @t (id, name) SELECT id, name FROM users
+SELECT @name = name FROM @t WHERE id = @id
+SET @id = @id + 1
+EXEC dbo.usp_refresh @customer = @id, @result = @name OUTPUT
+PRINT 'done: ' + @name
+DROP TABLE #orders
+GO 2`
+
+// parseScript parses a multi-statement SQL Server script
+func parseScript(t *testing.T, sql string) []parser.Statement {
+	t.Helper()
+
+	p := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect("sqlserver"))
+	stmts, err := p.ParseStatements()
+	if err != nil {
+		t.Fatalf("Failed to parse script: %v", err)
+	}
+	return stmts
+}
+
+// Test parsing a T-SQL script into top-level statements
+func TestParseTSQLBatch(t *testing.T) {
+	stmts := parseScript(t, tsqlScript)
+
+	expected := []string{
+		"UseStatement", "GoStatement", "SetOptionStatement", "DeclareStatement", "DeclareStatement",
+		"CreateTableStatement", "SelectStatement", "InsertStatement", "SelectStatement",
+		"AssignmentStatement", "ExecStatement", "PrintStatement", "DropStatement", "GoStatement",
+	}
+	if len(stmts) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d", len(expected), len(stmts))
+	}
+	for i, stmt := range stmts {
+		if stmt.Type() != expected[i] {
+			t.Errorf("Statement %d: expected %s, got %s", i+1, expected[i], stmt.Type())
+		}
+	}
+
+	if use := stmts[0].(*parser.UseStatement); use.Database != "Sales" {
+		t.Errorf("Expected USE Sales, got %s", use.Database)
+	}
+	if set := stmts[2].(*parser.SetOptionStatement); set.Options[0] != "NOCOUNT" || set.Value != "ON" {
+		t.Errorf("Expected SET NOCOUNT ON, got %s", set.String())
+	}
+
+	declare := stmts[3].(*parser.DeclareStatement)
+	if len(declare.Variables) != 3 || declare.Variables[0].Default == nil || declare.Variables[2].DataType.String() != "NVARCHAR(MAX)" {
+		t.Errorf("Unexpected DECLARE: %s", declare.String())
+	}
+	if table := stmts[4].(*parser.DeclareStatement).Variables[0]; table.Name != "@t" || len(table.Table) != 2 {
+		t.Errorf("Expected a two-column table variable, got %+v", table)
+	}
+	if create := stmts[5].(*parser.CreateTableStatement); create.Table.Name != "#orders" {
+		t.Errorf("Expected #orders, got %s", create.Table.Name)
+	}
+	if sel := stmts[6].(*parser.SelectStatement); sel.Into == nil || sel.Into.Name != "##snapshot" {
+		t.Errorf("Expected SELECT INTO ##snapshot, got %+v", sel.Into)
+	}
+	if assign, ok := stmts[8].(*parser.SelectStatement).Columns[0].(*parser.VariableAssignment); !ok || assign.Variable != "@name" {
+		t.Errorf("Expected SELECT @name = ..., got %s", stmts[8].String())
+	}
+	if goStmt := stmts[13].(*parser.GoStatement); goStmt.Count != 2 {
+		t.Errorf("Expected GO 2, got %d", goStmt.Count)
+	}
+}
+
+// Test the EXEC forms
+func TestParseExecStatement(t *testing.T) {
+	tests := []struct {
+		sql       string
+		procedure string
+		args      int
+		dynamic   bool
+	}{
+		{"EXEC dbo.usp_refresh @customer = 1, @result = @r OUTPUT", "dbo.usp_refresh", 2, false},
+		{"EXECUTE @rc = usp_check 1, DEFAULT", "usp_check", 2, false},
+		{"EXEC sp_name @a = 1, @b OUTPUT", "sp_name", 2, false},
+		{"EXEC @proc", "@proc", 0, false},
+		{"EXEC ('SELECT * FROM ' + @table)", "", 0, true},
+		{"EXEC sp_executesql N'SELECT * FROM t WHERE id = @p', N'@p INT', @p = 1", "sp_executesql", 3, true},
+	}
+
+	for _, tt := range tests {
+		stmts := parseScript(t, tt.sql)
+		exec, ok := stmts[0].(*parser.ExecStatement)
+		if !ok {
+			t.Fatalf("%s: expected ExecStatement, got %T", tt.sql, stmts[0])
+		}
+		if exec.Procedure != tt.procedure || len(exec.Arguments) != tt.args || exec.IsDynamic() != tt.dynamic {
+			t.Errorf("%s: unexpected %+v", tt.sql, exec)
+		}
+	}
+
+	exec := parseScript(t, "EXEC @rc = usp_refresh @id = 1, @out = @r OUT")[0].(*parser.ExecStatement)
+	if exec.ReturnVariable != "@rc" || exec.Arguments[0].Name != "@id" || !exec.Arguments[1].Output {
+		t.Errorf("Unexpected EXEC arguments: %+v", exec)
+	}
+}
+
+// Test compound assignments and SET TRANSACTION
+func TestParseSetForms(t *testing.T) {
+	for _, op := range []string{"+", "-", "*", "/", "%", "&", "|", "^"} {
+		sql := "SET @x " + op + "= @y + 1"
+		stmts := parseScript(t, sql)
+		set, ok := stmts[0].(*parser.AssignmentStatement)
+		if !ok || set.Variable != "@x" || set.Operator != op || set.Value == nil {
+			t.Errorf("%s: unexpected %+v", sql, stmts[0])
+		}
+	}
+
+	for _, level := range []string{"READ UNCOMMITTED", "READ COMMITTED", "REPEATABLE READ", "SNAPSHOT", "SERIALIZABLE"} {
+		sql := "SET TRANSACTION ISOLATION LEVEL " + strings.ToLower(level) + ";\nSELECT 1"
+		stmts := parseScript(t, sql)
+		set, ok := stmts[0].(*parser.SetTransactionStatement)
+		if !ok || set.IsolationLevel != level || len(stmts) != 2 {
+			t.Errorf("%s: unexpected %+v", sql, stmts)
+		}
+	}
+
+	for _, sql := range []string{"SET TRANSACTION ISOLATION LEVEL READ SOMETHING", "SET TRANSACTION READ ONLY"} {
+		if _, err := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect("sqlserver")).ParseStatements(); err == nil {
+			t.Errorf("Expected error for %q", sql)
+		}
+	}
+}
+
+// Test that batch statements are reported in the analysis
+func TestAnalyzeTSQLStatements(t *testing.T) {
+	for sql, queryType := range map[string]string{
+		"DECLARE @id INT":             "DECLARE",
+		"SET ANSI_NULLS ON":           "SET",
+		"EXEC usp_refresh":            "EXEC",
+		"USE Sales":                   "USE",
+		"PRINT 'hello'":               "PRINT",
+		"GO":                          "GO",
+		"SELECT * INTO #t FROM users": "SELECT",
+	} {
+		result := analyzer.New().Analyze(parseScript(t, sql)[0])
+		if result.QueryType != queryType {
+			t.Errorf("%s: expected %s, got %s", sql, queryType, result.QueryType)
+		}
+	}
+
+	result := analyzer.New().Analyze(parseScript(t, "SELECT * INTO #t FROM users")[0])
+	if len(result.Tables) != 2 || result.Tables[0].Name != "#t" || result.Tables[0].Usage != "INSERT" {
+		t.Errorf("Expected the INTO target as an INSERT table, got %+v", result.Tables)
+	}
+}
+
+// Test that temp tables and table variables are known to the validator
+// for as long as they are in scope
+func TestValidateScopedObjects(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	validator := schema.NewValidator(s)
+
+	validate := func(sql string) []*schema.ValidationError {
+		var errs []*schema.ValidationError
+		for _, stmt := range parseScript(t, sql) {
+			errs = append(errs, validator.ValidateStatement(stmt)...)
+		}
+		return errs
+	}
+
+	if errs := validate(tsqlScript); len(errs) != 0 {
+		t.Errorf("Expected the script to validate, got %v", errs)
+	}
+
+	if _, scope, ok := validator.ScopedTable("##SNAPSHOT"); !ok || scope != schema.ScopeGlobal {
+		t.Errorf("Expected ##snapshot in global scope, got %v %v", scope, ok)
+	}
+	if table, _, _ := validator.ScopedTable("##snapshot"); table != nil {
+		if col, ok := table.GetColumn("total"); !ok || col.DataType.Name != "DECIMAL" {
+			t.Errorf("Expected total copied from orders, got %+v", col)
+		}
+	}
+	if _, _, ok := validator.ScopedTable("#orders"); ok {
+		t.Error("Expected #orders to be dropped")
+	}
+	if _, _, ok := validator.ScopedTable("@t"); ok {
+		t.Error("Expected @t to go out of scope after GO")
+	}
+
+	errs := validate("SELECT missing FROM ##snapshot")
+	if len(errs) != 1 || errs[0].Type != "COLUMN_NOT_FOUND" {
+		t.Errorf("Expected a missing column in ##snapshot, got %v", errs)
+	}
+	for _, name := range []string{"@t", "#orders"} {
+		errs := validate("SELECT id FROM " + name)
+		if len(errs) == 0 || errs[0].Type != "TABLE_NOT_FOUND" || !strings.Contains(errs[0].Message, name) {
+			t.Errorf("Expected %s not found, got %v", name, errs)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Chahine-tech/sql-parser-go/pkg/analyzer"
+	"github.com/Chahine-tech/sql-parser-go/pkg/dialect"
+	"github.com/Chahine-tech/sql-parser-go/pkg/parser"
+	"github.com/Chahine-tech/sql-parser-go/pkg/schema"
+)
+
+func parseWithDialect(t *testing.T, sql, dialectName string) parser.Statement {
+	t.Helper()
+
+	p := parser.NewWithDialect(context.Background(), sql, dialect.GetDialect(dialectName))
+	stmt, err := p.ParseStatement()
+	if err != nil {
+		t.Fatalf("Failed to parse %q: %v", sql, err)
+	}
+	return stmt
+}
+
+// Test PostgreSQL/SQLite ON CONFLICT
+func TestInsertOnConflict(t *testing.T) {
+	stmt := parseWithDialect(t,
+		"INSERT INTO users (id, email) VALUES (1, 'a@b.c') ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email WHERE users.id > 0 RETURNING id, email AS new_email",
+		"postgresql").(*parser.InsertStatement)
+
+	oc := stmt.OnConflict
+	if oc == nil {
+		t.Fatal("Expected ON CONFLICT clause")
+	}
+	if len(oc.Columns) != 1 || oc.Columns[0] != "id" || oc.DoNothing {
+		t.Errorf("Unexpected conflict target: %+v", oc)
+	}
+	if len(oc.Set) != 1 || oc.Set[0].Column != "email" || oc.Where == nil {
+		t.Errorf("Unexpected DO UPDATE clause: %+v", oc)
+	}
+	excluded, ok := oc.Set[0].Value.(*parser.ColumnReference)
+	if !ok || excluded.Table != "EXCLUDED" {
+		t.Errorf("Expected EXCLUDED.email, got %v", oc.Set[0].Value)
+	}
+	if stmt.Returning == nil || len(stmt.Returning.Columns) != 2 {
+		t.Fatalf("Expected 2 RETURNING columns, got %+v", stmt.Returning)
+	}
+
+	stmt = parseWithDialect(t, "INSERT INTO users (id) VALUES (1) ON CONFLICT ON CONSTRAINT users_pkey DO NOTHING", "postgresql").(*parser.InsertStatement)
+	if stmt.OnConflict.Constraint != "users_pkey" || !stmt.OnConflict.DoNothing {
+		t.Errorf("Unexpected ON CONFLICT ON CONSTRAINT: %+v", stmt.OnConflict)
+	}
+
+	stmt = parseWithDialect(t, "INSERT INTO users (id) VALUES (1) ON CONFLICT DO NOTHING RETURNING *", "sqlite").(*parser.InsertStatement)
+	if !stmt.OnConflict.DoNothing || len(stmt.OnConflict.Columns) != 0 {
+		t.Errorf("Unexpected ON CONFLICT DO NOTHING: %+v", stmt.OnConflict)
+	}
+}
+
+// Test MySQL ON DUPLICATE KEY UPDATE, INSERT IGNORE and REPLACE INTO
+func TestInsertMySQLUpsert(t *testing.T) {
+	stmt := parseWithDialect(t,
+		"INSERT INTO users (id, email) VALUES (1, 'a@b.c') ON DUPLICATE KEY UPDATE email = VALUES(email), age = age + 1",
+		"mysql").(*parser.InsertStatement)
+	if len(stmt.OnDuplicateKeyUpdate) != 2 {
+		t.Fatalf("Expected 2 ON DUPLICATE KEY UPDATE assignments, got %d", len(stmt.OnDuplicateKeyUpdate))
+	}
+	if fn, ok := stmt.OnDuplicateKeyUpdate[0].Value.(*parser.FunctionCall); !ok || fn.Name != "VALUES" {
+		t.Errorf("Expected VALUES(email), got %v", stmt.OnDuplicateKeyUpdate[0].Value)
+	}
+
+	stmt = parseWithDialect(t, "INSERT IGNORE INTO users (id) VALUES (1)", "mysql").(*parser.InsertStatement)
+	if !stmt.Ignore || stmt.Replace {
+		t.Errorf("Expected INSERT IGNORE, got %s", stmt.String())
+	}
+
+	stmt = parseWithDialect(t, "REPLACE INTO users (id) VALUES (1)", "mysql").(*parser.InsertStatement)
+	if !stmt.Replace || stmt.String() != "REPLACE INTO users (1 rows)" {
+		t.Errorf("Expected REPLACE INTO, got %s", stmt.String())
+	}
+}
+
+// Test RETURNING and OUTPUT on UPDATE and DELETE
+func TestReturningAndOutput(t *testing.T) {
+	update := parseWithDialect(t, "UPDATE users SET email = 'x' OUTPUT inserted.email, deleted.email WHERE id = 1", "sqlserver").(*parser.UpdateStatement)
+	if update.Output == nil || len(update.Output.Columns) != 2 || update.Where == nil {
+		t.Errorf("Unexpected UPDATE OUTPUT: %+v", update.Output)
+	}
+
+	del := parseWithDialect(t, "DELETE FROM users OUTPUT deleted.* INTO audit (id) WHERE id = 1", "sqlserver").(*parser.DeleteStatement)
+	if del.From.Alias != "" || del.Output == nil || del.Output.Into.Name != "audit" || len(del.Output.IntoColumns) != 1 {
+		t.Errorf("Unexpected DELETE OUTPUT: %+v", del.Output)
+	}
+
+	insert := parseWithDialect(t, "INSERT INTO users (id) OUTPUT inserted.id VALUES (1)", "sqlserver").(*parser.InsertStatement)
+	if insert.Output == nil || len(insert.Values) != 1 {
+		t.Errorf("Unexpected INSERT OUTPUT: %+v", insert.Output)
+	}
+
+	del = parseWithDialect(t, "DELETE FROM users RETURNING id", "postgresql").(*parser.DeleteStatement)
+	if del.From.Alias != "" || del.Returning == nil {
+		t.Errorf("Expected RETURNING rather than an alias: %+v", del)
+	}
+
+	update = parseWithDialect(t, "UPDATE users SET email = 'x' WHERE id = 1 RETURNING id INTO v_id", "oracle").(*parser.UpdateStatement)
+	if update.Returning == nil || len(update.Returning.Into) != 1 || update.Returning.Into[0] != "v_id" {
+		t.Errorf("Unexpected Oracle RETURNING INTO: %+v", update.Returning)
+	}
+
+	insert = parseWithDialect(t, "INSERT INTO users (email) VALUES ('x') RETURNING id, email INTO :new_id, :new_email", "oracle").(*parser.InsertStatement)
+	if insert.Returning == nil || strings.Join(insert.Returning.Into, ",") != ":new_id,:new_email" {
+		t.Errorf("Expected bind parameters after RETURNING INTO: %+v", insert.Returning)
+	}
+}
+
+// Test that clauses the dialect doesn't support are rejected
+func TestUnsupportedUpsertClauses(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+	}{
+		{"mysql", "INSERT INTO users (id) VALUES (1) ON CONFLICT DO NOTHING"},
+		{"postgresql", "INSERT INTO users (id) VALUES (1) ON DUPLICATE KEY UPDATE id = 2"},
+		{"postgresql", "INSERT IGNORE INTO users (id) VALUES (1)"},
+		{"sqlserver", "REPLACE INTO users (id) VALUES (1)"},
+		{"mysql", "DELETE FROM users RETURNING id"},
+		{"sqlserver", "UPDATE users SET id = 1 RETURNING id"},
+		{"postgresql", "DELETE FROM users OUTPUT deleted.id"},
+		{"postgresql", "INSERT INTO users (id) VALUES (1) ON CONFLICT DO UPDATE SET id = 2"},
+	}
+
+	for _, tt := range tests {
+		p := parser.NewWithDialect(context.Background(), tt.sql, dialect.GetDialect(tt.dialect))
+		if _, err := p.ParseStatement(); err == nil {
+			t.Errorf("%s: expected error for %q", tt.dialect, tt.sql)
+		}
+	}
+}
+
+// Test schema validation of upsert and RETURNING clauses
+func TestValidateUpsert(t *testing.T) {
+	s, err := schema.NewSchemaLoader().LoadFromFile("../examples/schemas/test_schema.json")
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	validator := schema.NewValidator(s)
+
+	tests := []struct {
+		name      string
+		dialect   string
+		sql       string
+		errorType string
+	}{
+		{"Conflict on primary key", "postgresql", "INSERT INTO users (id, email) VALUES (1, 'a') ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email RETURNING id", ""},
+		{"Conflict on unique column", "sqlite", "INSERT INTO users (id, email) VALUES (1, 'a') ON CONFLICT (email) DO NOTHING", ""},
+		{"Conflict without unique key", "postgresql", "INSERT INTO users (id, name) VALUES (1, 'a') ON CONFLICT (name) DO NOTHING", "NO_UNIQUE_CONSTRAINT"},
+		{"Unknown conflict column", "postgresql", "INSERT INTO users (id) VALUES (1) ON CONFLICT (missing) DO NOTHING", "COLUMN_NOT_FOUND"},
+		{"Unknown EXCLUDED column", "postgresql", "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.mail", "COLUMN_NOT_FOUND"},
+		{"Unknown duplicate key column", "mysql", "INSERT INTO users (id) VALUES (1) ON DUPLICATE KEY UPDATE mail = 'x'", "COLUMN_NOT_FOUND"},
+		{"Unknown RETURNING column", "postgresql", "DELETE FROM users WHERE id = 1 RETURNING mail", "COLUMN_NOT_FOUND"},
+		{"OUTPUT pseudo tables", "sqlserver", "UPDATE users SET email = 'x' OUTPUT inserted.email, deleted.email WHERE id = 1", ""},
+		{"Unknown OUTPUT column", "sqlserver", "DELETE FROM users OUTPUT deleted.mail WHERE id = 1", "COLUMN_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.ValidateStatement(parseWithDialect(t, tt.sql, tt.dialect))
+			if tt.errorType == "" {
+				if len(errors) > 0 {
+					t.Errorf("Expected no validation errors, got: %v", errors)
+				}
+				return
+			}
+			if len(errors) == 0 {
+				t.Fatalf("Expected %s, got no errors", tt.errorType)
+			}
+			if errors[0].Type != tt.errorType {
+				t.Errorf("Expected error type '%s', got '%s'", tt.errorType, errors[0].Type)
+			}
+		})
+	}
+}
+
+// Test that the analyzer reports upsert and RETURNING columns
+func TestAnalyzeUpsert(t *testing.T) {
+	stmt := parseWithDialect(t,
+		"INSERT INTO users (id, email) VALUES (1, 'a') ON CONFLICT (id) DO UPDATE SET email = EXCLUDED.email RETURNING id",
+		"postgresql")
+
+	result := analyzer.New().Analyze(stmt)
+
+	usages := make(map[string]bool)
+	for _, col := range result.Columns {
+		usages[col.Name+":"+col.Usage] = true
+	}
+	for _, want := range []string{"id:INSERT", "id:CONFLICT", "email:UPDATE", "id:RETURNING"} {
+		if !usages[want] {
+			t.Errorf("Expected column usage %s, got %+v", want, result.Columns)
+		}
+	}
+}